@@ -21,6 +21,8 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+
+	"github.com/bufbuild/buf/internal/pkg/normalpath"
 )
 
 const (
@@ -32,6 +34,19 @@ const (
 	FormatMSVS
 )
 
+const (
+	// SeverityError is the error severity.
+	//
+	// This is the default severity of a FileAnnotation.
+	SeverityError Severity = iota + 1
+	// SeverityWarning is the warning severity.
+	SeverityWarning
+	// SeverityInfo is the info severity.
+	//
+	// Like SeverityWarning, this does not fail a run.
+	SeverityInfo
+)
+
 var (
 	// AllFormatStrings is all format strings without aliases.
 	//
@@ -63,6 +78,12 @@ var (
 		FormatJSON: "json",
 		FormatMSVS: "msvs",
 	}
+
+	severityToString = map[Severity]string{
+		SeverityError:   "error",
+		SeverityWarning: "warning",
+		SeverityInfo:    "info",
+	}
 )
 
 // Format is a FileAnnotation format.
@@ -92,6 +113,18 @@ func ParseFormat(s string) (Format, error) {
 	return 0, fmt.Errorf("unknown format: %q", s)
 }
 
+// Severity is a FileAnnotation severity.
+type Severity int
+
+// String implements fmt.Stringer.
+func (s Severity) String() string {
+	str, ok := severityToString[s]
+	if !ok {
+		return strconv.Itoa(int(s))
+	}
+	return str
+}
+
 // FileInfo is a minimal FileInfo interface.
 type FileInfo interface {
 	Path() string
@@ -138,6 +171,10 @@ type FileAnnotation interface {
 	Type() string
 	// Message is the message of the annotation.
 	Message() string
+	// Severity is the severity of the annotation.
+	//
+	// This will be SeverityError unless otherwise specified.
+	Severity() Severity
 }
 
 // NewFileAnnotation returns a new FileAnnotation.
@@ -161,6 +198,56 @@ func NewFileAnnotation(
 	)
 }
 
+// FileAnnotationWithSeverity returns a copy of the FileAnnotation with the given Severity.
+func FileAnnotationWithSeverity(fileAnnotation FileAnnotation, severity Severity) FileAnnotation {
+	return newFileAnnotationWithSeverity(
+		fileAnnotation.FileInfo(),
+		fileAnnotation.StartLine(),
+		fileAnnotation.StartColumn(),
+		fileAnnotation.EndLine(),
+		fileAnnotation.EndColumn(),
+		fileAnnotation.Type(),
+		fileAnnotation.Message(),
+		severity,
+	)
+}
+
+// ContainsSeverity returns true if any of the FileAnnotations have the given Severity.
+func ContainsSeverity(fileAnnotations []FileAnnotation, severity Severity) bool {
+	for _, fileAnnotation := range fileAnnotations {
+		if fileAnnotation.Severity() == severity {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterFileAnnotationsForPaths returns the FileAnnotations for files with the given paths.
+//
+// A FileAnnotation matches if either its path or external path equals one of the
+// given paths after normalization. FileAnnotations without a FileInfo are dropped.
+func FilterFileAnnotationsForPaths(fileAnnotations []FileAnnotation, paths []string) []FileAnnotation {
+	pathMap := make(map[string]struct{}, len(paths))
+	for _, path := range paths {
+		pathMap[normalpath.Normalize(path)] = struct{}{}
+	}
+	var filtered []FileAnnotation
+	for _, fileAnnotation := range fileAnnotations {
+		fileInfo := fileAnnotation.FileInfo()
+		if fileInfo == nil {
+			continue
+		}
+		if _, ok := pathMap[normalpath.Normalize(fileInfo.Path())]; ok {
+			filtered = append(filtered, fileAnnotation)
+			continue
+		}
+		if _, ok := pathMap[normalpath.Normalize(fileInfo.ExternalPath())]; ok {
+			filtered = append(filtered, fileAnnotation)
+		}
+	}
+	return filtered
+}
+
 // SortFileAnnotations sorts the FileAnnotations.
 //
 // The order of sorting is:
@@ -194,6 +281,85 @@ func PrintFileAnnotations(writer io.Writer, fileAnnotations []FileAnnotation, fo
 	return nil
 }
 
+// PrintFileAnnotationsGroupedByType prints the file annotations grouped by Type,
+// after sorting.
+//
+// Each group starts with a line containing the Type, followed by the file annotations
+// of the Type indented by two spaces. The groups are sorted by Type. FormatJSON is
+// not grouped, so that every line of the output is still a FileAnnotation.
+func PrintFileAnnotationsGroupedByType(writer io.Writer, fileAnnotations []FileAnnotation, formatString string) error {
+	format, err := ParseFormat(formatString)
+	if err != nil {
+		return err
+	}
+	sorted, _ := TruncateFileAnnotations(fileAnnotations, 0)
+	if format == FormatJSON {
+		return PrintFileAnnotations(writer, sorted, formatString)
+	}
+	typeToFileAnnotations := make(map[string][]FileAnnotation)
+	for _, fileAnnotation := range sorted {
+		typeToFileAnnotations[fileAnnotation.Type()] = append(typeToFileAnnotations[fileAnnotation.Type()], fileAnnotation)
+	}
+	types := make([]string, 0, len(typeToFileAnnotations))
+	for fileAnnotationType := range typeToFileAnnotations {
+		types = append(types, fileAnnotationType)
+	}
+	sort.Strings(types)
+	for _, fileAnnotationType := range types {
+		if _, err := writer.Write([]byte(fileAnnotationType + "\n")); err != nil {
+			return err
+		}
+		for _, fileAnnotation := range typeToFileAnnotations[fileAnnotationType] {
+			s, err := FormatFileAnnotation(fileAnnotation, format)
+			if err != nil {
+				return err
+			}
+			if _, err := writer.Write([]byte("  " + s + "\n")); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// TruncateFileAnnotations sorts the FileAnnotations and returns the first maxCount
+// FileAnnotations, along with the number of FileAnnotations that were dropped.
+//
+// The given slice is not modified. If maxCount is less than or equal to 0,
+// all FileAnnotations are returned.
+func TruncateFileAnnotations(fileAnnotations []FileAnnotation, maxCount int) ([]FileAnnotation, int) {
+	sorted := make([]FileAnnotation, len(fileAnnotations))
+	copy(sorted, fileAnnotations)
+	SortFileAnnotations(sorted)
+	if maxCount <= 0 || len(sorted) <= maxCount {
+		return sorted, 0
+	}
+	return sorted[:maxCount], len(sorted) - maxCount
+}
+
+// PrintFileAnnotationsWithMaxCount prints at most maxCount of the file annotations
+// separated by newlines, after sorting.
+//
+// If any FileAnnotations are dropped, a note with the number of dropped FileAnnotations
+// is printed last. The note is not printed for FormatJSON so that every line of the
+// output is still a FileAnnotation. If maxCount is less than or equal to 0, this is
+// equivalent to PrintFileAnnotations.
+func PrintFileAnnotationsWithMaxCount(writer io.Writer, fileAnnotations []FileAnnotation, formatString string, maxCount int) error {
+	format, err := ParseFormat(formatString)
+	if err != nil {
+		return err
+	}
+	fileAnnotations, numTruncated := TruncateFileAnnotations(fileAnnotations, maxCount)
+	if err := PrintFileAnnotations(writer, fileAnnotations, formatString); err != nil {
+		return err
+	}
+	if numTruncated == 0 || format == FormatJSON {
+		return nil
+	}
+	_, err = fmt.Fprintf(writer, "... and %d more\n", numTruncated)
+	return err
+}
+
 // FormatFileAnnotation formats the FileAnnotation.
 func FormatFileAnnotation(fileAnnotation FileAnnotation, format Format) (string, error) {
 	switch format {
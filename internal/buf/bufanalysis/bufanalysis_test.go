@@ -0,0 +1,160 @@
+// Copyright 2020 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufanalysis_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/bufbuild/buf/internal/buf/bufanalysis"
+	"github.com/bufbuild/buf/internal/buf/bufanalysis/bufanalysistesting"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterFileAnnotationsForPaths(t *testing.T) {
+	t.Parallel()
+	fileAnnotations := []bufanalysis.FileAnnotation{
+		bufanalysistesting.NewFileAnnotation(t, "a/a.proto", 1, 1, 1, 1, "FOO"),
+		bufanalysistesting.NewFileAnnotation(t, "a/b.proto", 2, 1, 2, 1, "FOO"),
+		bufanalysistesting.NewFileAnnotation(t, "a/b.proto", 3, 1, 3, 1, "BAR"),
+		bufanalysistesting.NewFileAnnotation(t, "c.proto", 1, 1, 1, 1, "FOO"),
+		bufanalysistesting.NewFileAnnotationNoLocationOrPath(t, "FOO"),
+	}
+	assert.Equal(
+		t,
+		[]bufanalysis.FileAnnotation{
+			fileAnnotations[1],
+			fileAnnotations[2],
+			fileAnnotations[3],
+		},
+		bufanalysis.FilterFileAnnotationsForPaths(
+			fileAnnotations,
+			[]string{
+				"./a/b.proto",
+				"c.proto",
+				"d.proto",
+			},
+		),
+	)
+	assert.Empty(t, bufanalysis.FilterFileAnnotationsForPaths(fileAnnotations, nil))
+}
+
+func TestTruncateFileAnnotations(t *testing.T) {
+	t.Parallel()
+	fileAnnotations := []bufanalysis.FileAnnotation{
+		bufanalysistesting.NewFileAnnotation(t, "b.proto", 1, 1, 1, 1, "FOO"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 2, 1, 2, 1, "FOO"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 1, 1, 1, 1, "FOO"),
+	}
+	truncated, numTruncated := bufanalysis.TruncateFileAnnotations(fileAnnotations, 2)
+	assert.Equal(
+		t,
+		[]bufanalysis.FileAnnotation{
+			fileAnnotations[2],
+			fileAnnotations[1],
+		},
+		truncated,
+	)
+	assert.Equal(t, 1, numTruncated)
+	// the given slice is not sorted
+	assert.Equal(t, "b.proto", fileAnnotations[0].FileInfo().Path())
+	truncated, numTruncated = bufanalysis.TruncateFileAnnotations(fileAnnotations, 3)
+	assert.Len(t, truncated, 3)
+	assert.Equal(t, 0, numTruncated)
+	truncated, numTruncated = bufanalysis.TruncateFileAnnotations(fileAnnotations, 0)
+	assert.Len(t, truncated, 3)
+	assert.Equal(t, 0, numTruncated)
+}
+
+func TestPrintFileAnnotationsWithMaxCount(t *testing.T) {
+	t.Parallel()
+	fileAnnotations := []bufanalysis.FileAnnotation{
+		bufanalysistesting.NewFileAnnotation(t, "b.proto", 1, 1, 1, 1, "FOO"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 2, 1, 2, 1, "FOO"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 1, 1, 1, 1, "FOO"),
+	}
+	buffer := bytes.NewBuffer(nil)
+	assert.NoError(t, bufanalysis.PrintFileAnnotationsWithMaxCount(buffer, fileAnnotations, "text", 1))
+	assert.Equal(t, "a.proto:1:1:FOO\n... and 2 more\n", buffer.String())
+	buffer.Reset()
+	assert.NoError(t, bufanalysis.PrintFileAnnotationsWithMaxCount(buffer, fileAnnotations, "json", 1))
+	assert.NotContains(t, buffer.String(), "more")
+	buffer.Reset()
+	assert.NoError(t, bufanalysis.PrintFileAnnotationsWithMaxCount(buffer, fileAnnotations, "text", 3))
+	assert.NotContains(t, buffer.String(), "more")
+}
+
+func TestPrintFileAnnotationsWithSeverity(t *testing.T) {
+	t.Parallel()
+	fileAnnotations := []bufanalysis.FileAnnotation{
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 1, 1, 1, 1, "FOO"),
+		bufanalysis.FileAnnotationWithSeverity(
+			bufanalysistesting.NewFileAnnotation(t, "a.proto", 2, 1, 2, 1, "FOO"),
+			bufanalysis.SeverityWarning,
+		),
+		bufanalysis.FileAnnotationWithSeverity(
+			bufanalysistesting.NewFileAnnotation(t, "a.proto", 3, 1, 3, 1, "FOO"),
+			bufanalysis.SeverityInfo,
+		),
+	}
+	buffer := bytes.NewBuffer(nil)
+	assert.NoError(t, bufanalysis.PrintFileAnnotations(buffer, fileAnnotations, "text"))
+	assert.Equal(
+		t,
+		`a.proto:1:1:FOO
+a.proto:2:1:warning: FOO
+a.proto:3:1:info: FOO
+`,
+		buffer.String(),
+	)
+	buffer.Reset()
+	assert.NoError(t, bufanalysis.PrintFileAnnotations(buffer, fileAnnotations, "msvs"))
+	assert.Equal(
+		t,
+		`a.proto(1,1) : error FOO : FOO
+a.proto(2,1) : warning FOO : FOO
+a.proto(3,1) : info FOO : FOO
+`,
+		buffer.String(),
+	)
+}
+
+func TestPrintFileAnnotationsGroupedByType(t *testing.T) {
+	t.Parallel()
+	fileAnnotations := []bufanalysis.FileAnnotation{
+		bufanalysistesting.NewFileAnnotation(t, "b.proto", 1, 1, 1, 1, "FOO"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 2, 1, 2, 1, "BAR"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 3, 1, 3, 1, "FOO"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 1, 1, 1, 1, "FOO"),
+	}
+	buffer := bytes.NewBuffer(nil)
+	assert.NoError(t, bufanalysis.PrintFileAnnotationsGroupedByType(buffer, fileAnnotations, "text"))
+	assert.Equal(
+		t,
+		`BAR
+  a.proto:2:1:BAR
+FOO
+  a.proto:1:1:FOO
+  a.proto:3:1:FOO
+  b.proto:1:1:FOO
+`,
+		buffer.String(),
+	)
+	buffer.Reset()
+	assert.NoError(t, bufanalysis.PrintFileAnnotationsGroupedByType(buffer, fileAnnotations, "json"))
+	assert.NotContains(t, buffer.String(), "  ")
+	assert.Len(t, strings.Split(strings.TrimSpace(buffer.String()), "\n"), 4)
+}
@@ -28,6 +28,7 @@ type fileAnnotation struct {
 	endColumn   int
 	typeString  string
 	message     string
+	severity    Severity
 }
 
 func newFileAnnotation(
@@ -38,6 +39,28 @@ func newFileAnnotation(
 	endColumn int,
 	typeString string,
 	message string,
+) *fileAnnotation {
+	return newFileAnnotationWithSeverity(
+		fileInfo,
+		startLine,
+		startColumn,
+		endLine,
+		endColumn,
+		typeString,
+		message,
+		SeverityError,
+	)
+}
+
+func newFileAnnotationWithSeverity(
+	fileInfo FileInfo,
+	startLine int,
+	startColumn int,
+	endLine int,
+	endColumn int,
+	typeString string,
+	message string,
+	severity Severity,
 ) *fileAnnotation {
 	return &fileAnnotation{
 		fileInfo:    fileInfo,
@@ -47,6 +70,7 @@ func newFileAnnotation(
 		endColumn:   endColumn,
 		typeString:  typeString,
 		message:     message,
+		severity:    severity,
 	}
 }
 
@@ -78,6 +102,10 @@ func (f *fileAnnotation) Message() string {
 	return f.message
 }
 
+func (f *fileAnnotation) Severity() Severity {
+	return f.severity
+}
+
 func (f *fileAnnotation) String() string {
 	if f == nil {
 		return ""
@@ -109,6 +137,13 @@ func (f *fileAnnotation) String() string {
 	_, _ = buffer.WriteRune(':')
 	_, _ = buffer.WriteString(strconv.Itoa(int(column)))
 	_, _ = buffer.WriteRune(':')
+	// we only output the severity if it is not the default so that
+	// the output does not change for the default case
+	switch f.severity {
+	case SeverityWarning, SeverityInfo:
+		_, _ = buffer.WriteString(f.severity.String())
+		_, _ = buffer.WriteString(": ")
+	}
 	_, _ = buffer.WriteString(message)
 	return buffer.String()
 }
@@ -154,7 +189,15 @@ func (f *fileAnnotation) MSVSString() string {
 		_, _ = buffer.WriteRune(',')
 		_, _ = buffer.WriteString(strconv.Itoa(int(column)))
 	}
-	_, _ = buffer.WriteString(") : error ")
+	_, _ = buffer.WriteString(") : ")
+	switch f.severity {
+	case SeverityWarning:
+		_, _ = buffer.WriteString("warning ")
+	case SeverityInfo:
+		_, _ = buffer.WriteString("info ")
+	default:
+		_, _ = buffer.WriteString("error ")
+	}
 	_, _ = buffer.WriteString(typeString)
 	_, _ = buffer.WriteString(" : ")
 	_, _ = buffer.WriteString(message)
@@ -166,6 +209,12 @@ func (f *fileAnnotation) toExternalFileAnnotation() externalFileAnnotation {
 	if f.fileInfo != nil {
 		path = f.fileInfo.ExternalPath()
 	}
+	// we only output the severity if it is not the default so that
+	// the output does not change for the default case
+	severity := ""
+	if f.severity != SeverityError {
+		severity = f.severity.String()
+	}
 	return externalFileAnnotation{
 		Path:        path,
 		StartLine:   f.startLine,
@@ -174,6 +223,7 @@ func (f *fileAnnotation) toExternalFileAnnotation() externalFileAnnotation {
 		EndColumn:   f.endColumn,
 		Type:        f.typeString,
 		Message:     f.message,
+		Severity:    severity,
 	}
 }
 
@@ -185,4 +235,5 @@ type externalFileAnnotation struct {
 	EndColumn   int    `json:"end_column,omitempty" yaml:"end_column,omitempty"`
 	Type        string `json:"type,omitempty" yaml:"type,omitempty"`
 	Message     string `json:"message,omitempty" yaml:"message,omitempty"`
+	Severity    string `json:"severity,omitempty" yaml:"severity,omitempty"`
 }
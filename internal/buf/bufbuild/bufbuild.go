@@ -30,7 +30,8 @@ type Builder interface {
 	// they are unique relative to the roots.
 	//
 	// If an error is returned, it is a system error.
-	// Only one of Image and FileAnnotations will be returned.
+	// Only one of Image and FileAnnotations will be returned, unless
+	// WithPartial is given.
 	//
 	// FileAnnotations will use external file paths.
 	Build(
@@ -54,3 +55,69 @@ func WithExcludeSourceCodeInfo() BuildOption {
 		buildOptions.excludeSourceCodeInfo = true
 	}
 }
+
+// WithProgressFunc returns a BuildOption that calls progressFunc every time
+// a target file is opened for parsing.
+//
+// The first argument is the number of target files opened so far, and the
+// second argument is the total number of target files. Files that are only
+// imports are not counted. progressFunc may be called concurrently, but calls
+// are serialized.
+func WithProgressFunc(progressFunc func(parsed int, total int)) BuildOption {
+	return func(buildOptions *buildOptions) {
+		buildOptions.progressFunc = progressFunc
+	}
+}
+
+// WithoutBuiltinWKT returns a BuildOption that disables resolving imports of
+// the well-known types from the copies embedded in buf.
+//
+// By default, imports of google/protobuf/*.proto files that are not found in
+// the module are resolved from the well-known types of the vendored protobuf
+// version. With this option, such imports result in a file not found error.
+func WithoutBuiltinWKT() BuildOption {
+	return func(buildOptions *buildOptions) {
+		buildOptions.noBuiltinWKT = true
+	}
+}
+
+// WithErrorOnBOM returns a BuildOption that results in an error for files that
+// start with a UTF-8 byte order mark.
+//
+// By default, a leading UTF-8 byte order mark is stripped before parsing.
+// Files that are not valid UTF-8 always result in an error.
+func WithErrorOnBOM() BuildOption {
+	return func(buildOptions *buildOptions) {
+		buildOptions.errorOnBOM = true
+	}
+}
+
+// WithRelativeImports returns a BuildOption that resolves imports relative to
+// the directory of the importing file if they are not found in the module.
+//
+// For example, if foo/a.proto imports "b.proto" and there is no b.proto in the
+// module, foo/b.proto is used, with the file named b.proto in the resulting Image.
+// If an import resolves relative to multiple importing files to different files,
+// this results in an error. This is off by default as it makes imports ambiguous,
+// and is only meant to ease migration of existing trees.
+func WithRelativeImports() BuildOption {
+	return func(buildOptions *buildOptions) {
+		buildOptions.relativeImports = true
+	}
+}
+
+// WithPartial returns a BuildOption that continues building past errors.
+//
+// Each target file is parsed independently, and all FileAnnotations across all
+// target files are returned. The returned Image contains the target files that
+// built successfully, along with their imports, and is nil if no target files
+// built successfully. Both an Image and FileAnnotations may be returned.
+//
+// An Image from a partial build is missing any target files that had errors,
+// or that imported files with errors, and should not be used for generation.
+// This is meant for editor integrations that want all errors in one pass.
+func WithPartial() BuildOption {
+	return func(buildOptions *buildOptions) {
+		buildOptions.partial = true
+	}
+}
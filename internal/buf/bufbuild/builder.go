@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"sync"
 
 	"github.com/bufbuild/buf/internal/buf/bufanalysis"
@@ -56,6 +57,11 @@ func (b *builder) Build(
 		ctx,
 		module,
 		buildOptions.excludeSourceCodeInfo,
+		buildOptions.progressFunc,
+		buildOptions.partial,
+		buildOptions.noBuiltinWKT,
+		buildOptions.errorOnBOM,
+		buildOptions.relativeImports,
 	)
 }
 
@@ -63,10 +69,15 @@ func (b *builder) build(
 	ctx context.Context,
 	module bufcore.Module,
 	excludeSourceCodeInfo bool,
+	progressFunc func(int, int),
+	partial bool,
+	noBuiltinWKT bool,
+	errorOnBOM bool,
+	relativeImports bool,
 ) (bufcore.Image, []bufanalysis.FileAnnotation, error) {
 	defer instrument.Start(b.logger, "build").End()
 
-	parserAccessorHandler := newParserAccessorHandler(ctx, module)
+	parserAccessorHandler := newParserAccessorHandler(ctx, module, noBuiltinWKT, errorOnBOM, relativeImports)
 	targetFileInfos, err := module.TargetFileInfos(ctx)
 	if err != nil {
 		return nil, nil, err
@@ -82,8 +93,10 @@ func (b *builder) build(
 	buildResults := b.getBuildResults(
 		ctx,
 		parserAccessorHandler,
+		newProgressTracker(paths, progressFunc),
 		paths,
 		excludeSourceCodeInfo,
+		partial,
 	)
 	var buildResultErr error
 	for _, buildResult := range buildResults {
@@ -96,6 +109,16 @@ func (b *builder) build(
 	for _, buildResult := range buildResults {
 		fileAnnotations = append(fileAnnotations, buildResult.FileAnnotations...)
 	}
+	if partial {
+		return b.getPartialImage(
+			ctx,
+			excludeSourceCodeInfo,
+			buildResults,
+			paths,
+			fileAnnotations,
+			parserAccessorHandler,
+		)
+	}
 	if len(fileAnnotations) > 0 {
 		bufanalysis.SortFileAnnotations(fileAnnotations)
 		return nil, fileAnnotations, nil
@@ -117,27 +140,95 @@ func (b *builder) build(
 	return image, nil, nil
 }
 
+// getPartialImage gets the Image for the paths whose buildResults had no FileAnnotations.
+//
+// This assumes getBuildResults was called with partial set, so that each buildResult
+// is for a single path. The Image is nil if no paths built successfully.
+func (b *builder) getPartialImage(
+	ctx context.Context,
+	excludeSourceCodeInfo bool,
+	buildResults []*buildResult,
+	paths []string,
+	fileAnnotations []bufanalysis.FileAnnotation,
+	parserAccessorHandler *parserAccessorHandler,
+) (bufcore.Image, []bufanalysis.FileAnnotation, error) {
+	var successfulBuildResults []*buildResult
+	successfulPaths := make(map[string]struct{})
+	for _, buildResult := range buildResults {
+		if len(buildResult.FileAnnotations) == 0 {
+			successfulBuildResults = append(successfulBuildResults, buildResult)
+			for _, descFileDescriptor := range buildResult.DescFileDescriptors {
+				successfulPaths[descFileDescriptor.GetName()] = struct{}{}
+			}
+		}
+	}
+	// each path is parsed independently, so the same error in a common
+	// import will be reported once per path that imports it
+	fileAnnotations = dedupeFileAnnotations(fileAnnotations)
+	bufanalysis.SortFileAnnotations(fileAnnotations)
+	if len(successfulPaths) == 0 {
+		return nil, fileAnnotations, nil
+	}
+	// retain the original input order
+	sortedSuccessfulPaths := make([]string, 0, len(successfulPaths))
+	for _, path := range paths {
+		if _, ok := successfulPaths[path]; ok {
+			sortedSuccessfulPaths = append(sortedSuccessfulPaths, path)
+		}
+	}
+	descFileDescriptors, err := getDescFileDescriptorsFromBuildResults(successfulBuildResults, sortedSuccessfulPaths)
+	if err != nil {
+		return nil, nil, err
+	}
+	image, err := b.getImage(
+		ctx,
+		excludeSourceCodeInfo,
+		descFileDescriptors,
+		parserAccessorHandler,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	return image, fileAnnotations, nil
+}
+
 func (b *builder) getBuildResults(
 	ctx context.Context,
 	parserAccessorHandler *parserAccessorHandler,
+	progressTracker *progressTracker,
 	paths []string,
 	excludeSourceCodeInfo bool,
+	partial bool,
 ) []*buildResult {
 	defer instrument.Start(b.logger, "parse").End()
 
 	var buildResults []*buildResult
+	parallelism := thread.Parallelism()
 	chunkSize := 0
-	if parallelism := thread.Parallelism(); parallelism > 1 {
+	if partial {
+		// each path is parsed on its own so that an error in one path
+		// does not prevent the other paths from being built
+		chunkSize = 1
+	} else if parallelism > 1 {
 		chunkSize = len(paths) / parallelism
 	}
 	chunks := stringutil.SliceToChunks(paths, chunkSize)
 	buildResultC := make(chan *buildResult, len(chunks))
+	semaphoreSize := len(chunks)
+	if partial {
+		// there is a chunk per path, so limit the number of concurrent parses
+		semaphoreSize = parallelism
+	}
+	semaphoreC := make(chan struct{}, semaphoreSize)
 	for _, iPaths := range chunks {
 		iPaths := iPaths
 		go func() {
+			semaphoreC <- struct{}{}
+			defer func() { <-semaphoreC }()
 			buildResultC <- getBuildResult(
 				ctx,
 				parserAccessorHandler,
+				progressTracker,
 				iPaths,
 				excludeSourceCodeInfo,
 			)
@@ -157,6 +248,7 @@ func (b *builder) getBuildResults(
 func getBuildResult(
 	ctx context.Context,
 	parserAccessorHandler *parserAccessorHandler,
+	progressTracker *progressTracker,
 	paths []string,
 	excludeSourceCodeInfo bool,
 ) *buildResult {
@@ -164,7 +256,10 @@ func getBuildResult(
 	var lock sync.Mutex
 	parser := protoparse.Parser{
 		IncludeSourceCodeInfo: !excludeSourceCodeInfo,
-		Accessor:              parserAccessorHandler.Open,
+		Accessor: func(path string) (io.ReadCloser, error) {
+			progressTracker.Track(path)
+			return parserAccessorHandler.Open(path)
+		},
 		ErrorReporter: func(errorWithPos protoparse.ErrorWithPos) error {
 			// protoparse isn't concurrent right now but just to be safe
 			// for the future
@@ -225,9 +320,93 @@ func getBuildResult(
 			)
 		}
 	}
+	if parserAccessorHandler.NoBuiltinWKT() {
+		// protoparse always falls back to its own copies of the well-known types
+		// if the accessor does not find them, so we need to check for these after the fact
+		fileAnnotations, err := getBuiltinWKTFileAnnotations(
+			parserAccessorHandler,
+			descFileDescriptors,
+		)
+		if err != nil {
+			return newBuildResult(nil, nil, err)
+		}
+		if len(fileAnnotations) > 0 {
+			return newBuildResult(nil, fileAnnotations, nil)
+		}
+	}
 	return newBuildResult(descFileDescriptors, nil, nil)
 }
 
+// getBuiltinWKTFileAnnotations returns a FileAnnotation for every import that was
+// not opened by the parserAccessorHandler, i.e. that was resolved by protoparse from
+// its own copies of the well-known types.
+func getBuiltinWKTFileAnnotations(
+	parserAccessorHandler *parserAccessorHandler,
+	descFileDescriptors []*desc.FileDescriptor,
+) ([]bufanalysis.FileAnnotation, error) {
+	var fileAnnotations []bufanalysis.FileAnnotation
+	seen := make(map[string]struct{})
+	var visit func(*desc.FileDescriptor) error
+	visit = func(descFileDescriptor *desc.FileDescriptor) error {
+		path := descFileDescriptor.GetName()
+		if _, ok := seen[path]; ok {
+			return nil
+		}
+		seen[path] = struct{}{}
+		for i, dependency := range descFileDescriptor.GetDependencies() {
+			dependencyPath := dependency.GetName()
+			if !parserAccessorHandler.Opened(dependencyPath) {
+				fileInfo, err := bufcore.NewFileInfo(
+					path,
+					parserAccessorHandler.ExternalPath(path),
+					parserAccessorHandler.IsImport(path),
+				)
+				if err != nil {
+					return err
+				}
+				line, column := getDependencyLineColumn(descFileDescriptor, i)
+				fileAnnotations = append(
+					fileAnnotations,
+					bufanalysis.NewFileAnnotation(
+						fileInfo,
+						line,
+						column,
+						line,
+						column,
+						"COMPILE",
+						fmt.Sprintf("%s: file does not exist", dependencyPath),
+					),
+				)
+				continue
+			}
+			if err := visit(dependency); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, descFileDescriptor := range descFileDescriptors {
+		if err := visit(descFileDescriptor); err != nil {
+			return nil, err
+		}
+	}
+	return fileAnnotations, nil
+}
+
+// getDependencyLineColumn returns the 1-indexed line and column of the import
+// at the given index, or zero values if there is no source code info.
+func getDependencyLineColumn(descFileDescriptor *desc.FileDescriptor, index int) (int, int) {
+	for _, location := range descFileDescriptor.AsFileDescriptorProto().GetSourceCodeInfo().GetLocation() {
+		// 3 is the field number of dependency on FileDescriptorProto
+		if path := location.GetPath(); len(path) == 2 && path[0] == 3 && int(path[1]) == index {
+			if span := location.GetSpan(); len(span) >= 2 {
+				return int(span[0]) + 1, int(span[1]) + 1
+			}
+		}
+	}
+	return 0, 0
+}
+
 func getFileAnnotations(
 	ctx context.Context,
 	parserAccessorHandler *parserAccessorHandler,
@@ -248,6 +427,23 @@ func getFileAnnotations(
 	return fileAnnotations, nil
 }
 
+// dedupeFileAnnotations returns the FileAnnotations with duplicates removed.
+//
+// FileAnnotations are considered duplicates if they have the same string representation.
+func dedupeFileAnnotations(fileAnnotations []bufanalysis.FileAnnotation) []bufanalysis.FileAnnotation {
+	deduped := make([]bufanalysis.FileAnnotation, 0, len(fileAnnotations))
+	seen := make(map[string]struct{}, len(fileAnnotations))
+	for _, fileAnnotation := range fileAnnotations {
+		key := fileAnnotation.String()
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		deduped = append(deduped, fileAnnotation)
+	}
+	return deduped
+}
+
 func getFileAnnotation(
 	ctx context.Context,
 	parserAccessorHandler *parserAccessorHandler,
@@ -477,6 +673,11 @@ func newBuildResult(
 
 type buildOptions struct {
 	excludeSourceCodeInfo bool
+	progressFunc          func(int, int)
+	partial               bool
+	noBuiltinWKT          bool
+	errorOnBOM            bool
+	relativeImports       bool
 }
 
 func newBuildOptions() *buildOptions {
@@ -132,6 +132,196 @@ func TestCustomOptionsError1(t *testing.T) {
 	)
 }
 
+func TestPartial(t *testing.T) {
+	t.Parallel()
+	module := testGetModule(t, filepath.Join("testdata", "partial1"))
+	image, fileAnnotations, err := NewBuilder(zap.NewNop()).Build(
+		context.Background(),
+		module,
+		WithExcludeSourceCodeInfo(),
+		WithPartial(),
+	)
+	require.NoError(t, err)
+	testRequirePartial1FileAnnotations(t, fileAnnotations)
+	require.NotNil(t, image)
+	require.Equal(t, []string{"c.proto"}, testGetImageFilePaths(image))
+	require.Empty(t, testGetImageImportPaths(image))
+}
+
+func TestPartialNoSuccessfulFiles(t *testing.T) {
+	t.Parallel()
+	module := testGetModule(
+		t,
+		filepath.Join("testdata", "partial1"),
+		bufmod.WithPaths("a.proto", "d.proto"),
+	)
+	image, fileAnnotations, err := NewBuilder(zap.NewNop()).Build(
+		context.Background(),
+		module,
+		WithExcludeSourceCodeInfo(),
+		WithPartial(),
+	)
+	require.NoError(t, err)
+	require.Nil(t, image)
+	// the error in a.proto is only reported once even though it is
+	// parsed for both a.proto and d.proto
+	require.Equal(t, 1, len(fileAnnotations), fileAnnotations)
+	require.Equal(
+		t,
+		"testdata/partial1/a.proto:7:1:syntax error: unexpected '}', expecting ';' or '['",
+		fileAnnotations[0].String(),
+	)
+}
+
+func TestPartialNotSet(t *testing.T) {
+	t.Parallel()
+	image, fileAnnotations := testBuild(t, false, filepath.Join("testdata", "partial1"))
+	require.Nil(t, image)
+	testRequirePartial1FileAnnotations(t, fileAnnotations)
+}
+
+func TestProgressFunc(t *testing.T) {
+	t.Parallel()
+	module := testGetModule(t, filepath.Join("testdata", "progress1"))
+	targetFileInfos, err := module.TargetFileInfos(context.Background())
+	require.NoError(t, err)
+	require.Len(t, targetFileInfos, 3)
+	var parsedCounts []int
+	_, fileAnnotations, err := NewBuilder(zap.NewNop()).Build(
+		context.Background(),
+		module,
+		WithExcludeSourceCodeInfo(),
+		WithProgressFunc(func(parsed int, total int) {
+			require.Equal(t, len(targetFileInfos), total)
+			parsedCounts = append(parsedCounts, parsed)
+		}),
+	)
+	require.NoError(t, err)
+	require.Empty(t, fileAnnotations)
+	expectedParsedCounts := make([]int, len(targetFileInfos))
+	for i := range expectedParsedCounts {
+		expectedParsedCounts[i] = i + 1
+	}
+	require.Equal(t, expectedParsedCounts, parsedCounts)
+}
+
+func TestBuiltinWKT(t *testing.T) {
+	t.Parallel()
+	image, fileAnnotations := testBuild(t, false, filepath.Join("testdata", "wkt1"))
+	require.Empty(t, fileAnnotations)
+	require.NotNil(t, image)
+	require.Equal(t, []string{"a.proto", "google/protobuf/timestamp.proto"}, testGetImageFilePaths(image))
+	require.Equal(t, []string{"google/protobuf/timestamp.proto"}, testGetImageImportPaths(image))
+}
+
+func TestWithoutBuiltinWKT(t *testing.T) {
+	t.Parallel()
+	module := testGetModule(t, filepath.Join("testdata", "wkt1"))
+	image, fileAnnotations, err := NewBuilder(zap.NewNop()).Build(
+		context.Background(),
+		module,
+		WithoutBuiltinWKT(),
+	)
+	require.NoError(t, err)
+	require.Nil(t, image)
+	require.Equal(t, 1, len(fileAnnotations), fileAnnotations)
+	require.Equal(
+		t,
+		"testdata/wkt1/a.proto:5:1:google/protobuf/timestamp.proto: file does not exist",
+		fileAnnotations[0].String(),
+	)
+}
+
+func TestBOM(t *testing.T) {
+	t.Parallel()
+	image, fileAnnotations := testBuild(t, false, filepath.Join("testdata", "bom1"))
+	require.Empty(t, fileAnnotations)
+	require.NotNil(t, image)
+	require.Equal(t, []string{"a.proto"}, testGetImageFilePaths(image))
+}
+
+func TestWithErrorOnBOM(t *testing.T) {
+	t.Parallel()
+	module := testGetModule(t, filepath.Join("testdata", "bom1"))
+	_, _, err := NewBuilder(zap.NewNop()).Build(
+		context.Background(),
+		module,
+		WithErrorOnBOM(),
+	)
+	require.Error(t, err)
+	require.Equal(t, "testdata/bom1/a.proto: file starts with a UTF-8 byte order mark", err.Error())
+}
+
+func TestInvalidUTF8(t *testing.T) {
+	t.Parallel()
+	module := testGetModule(t, filepath.Join("testdata", "utf81"))
+	_, _, err := NewBuilder(zap.NewNop()).Build(
+		context.Background(),
+		module,
+	)
+	require.Error(t, err)
+	require.Equal(t, "testdata/utf81/a.proto: file is not valid UTF-8", err.Error())
+}
+
+func TestRelativeImports(t *testing.T) {
+	t.Parallel()
+	module := testGetModule(t, filepath.Join("testdata", "relative1"), bufmod.WithPaths("foo/a.proto"))
+	image, fileAnnotations, err := NewBuilder(zap.NewNop()).Build(
+		context.Background(),
+		module,
+		WithRelativeImports(),
+	)
+	require.NoError(t, err)
+	require.Empty(t, fileAnnotations)
+	require.NotNil(t, image)
+	require.Equal(t, []string{"b.proto", "foo/a.proto"}, testGetImageFilePaths(image))
+	require.Equal(t, []string{"b.proto"}, testGetImageImportPaths(image))
+}
+
+func TestRelativeImportsAmbiguous(t *testing.T) {
+	t.Parallel()
+	module := testGetModule(t, filepath.Join("testdata", "relative2"), bufmod.WithPaths("bar/a.proto", "foo/a.proto"))
+	_, _, err := NewBuilder(zap.NewNop()).Build(
+		context.Background(),
+		module,
+		WithRelativeImports(),
+	)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "b.proto: import resolves relative to the importing files to multiple files bar/b.proto, foo/b.proto")
+}
+
+func TestWithoutRelativeImports(t *testing.T) {
+	t.Parallel()
+	module := testGetModule(t, filepath.Join("testdata", "relative1"), bufmod.WithPaths("foo/a.proto"))
+	image, fileAnnotations, err := NewBuilder(zap.NewNop()).Build(
+		context.Background(),
+		module,
+	)
+	require.NoError(t, err)
+	require.Nil(t, image)
+	require.Equal(t, 1, len(fileAnnotations), fileAnnotations)
+	require.Equal(
+		t,
+		"testdata/relative1/foo/a.proto:5:8:b.proto: does not exist",
+		fileAnnotations[0].String(),
+	)
+}
+
+func testRequirePartial1FileAnnotations(t *testing.T, fileAnnotations []bufanalysis.FileAnnotation) {
+	fileAnnotationStrings := make([]string, len(fileAnnotations))
+	for i, fileAnnotation := range fileAnnotations {
+		fileAnnotationStrings[i] = fileAnnotation.String()
+	}
+	require.Equal(
+		t,
+		[]string{
+			"testdata/partial1/a.proto:7:1:syntax error: unexpected '}', expecting ';' or '['",
+			"testdata/partial1/b.proto:6:14:syntax error: unexpected ';', expecting int literal",
+		},
+		fileAnnotationStrings,
+	)
+}
+
 func testCompare(t *testing.T, relDirPath string) {
 	t.Parallel()
 	dirPath := filepath.Join("testdata", relDirPath)
@@ -278,7 +468,7 @@ func testBuildBufProtoc(
 	return bufcore.ImageToFileDescriptorSet(image)
 }
 
-func testGetModule(t *testing.T, dirPath string) bufcore.Module {
+func testGetModule(t *testing.T, dirPath string, options ...bufmod.BuildOption) bufcore.Module {
 	readWriteBucket, err := storageos.NewReadWriteBucket(dirPath)
 	require.NoError(t, err)
 	config, err := bufmod.NewConfig(bufmod.ExternalConfig{})
@@ -287,6 +477,7 @@ func testGetModule(t *testing.T, dirPath string) bufcore.Module {
 		context.Background(),
 		readWriteBucket,
 		config,
+		options...,
 	)
 	require.NoError(t, err)
 	return module
@@ -15,43 +15,92 @@
 package bufbuild
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strings"
 	"sync"
+	"unicode/utf8"
 
 	"github.com/bufbuild/buf/internal/buf/bufcore"
 	"github.com/bufbuild/buf/internal/gen/data/wkt"
+	"github.com/bufbuild/buf/internal/pkg/normalpath"
 	"github.com/bufbuild/buf/internal/pkg/storage"
 	"go.uber.org/multierr"
 )
 
+var (
+	// utf8BOM is the UTF-8 byte order mark.
+	utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+	// importRegexp matches import statements to resolve relative imports.
+	//
+	// This does not need to be exact, as any candidates that do not exist are ignored.
+	importRegexp = regexp.MustCompile(`(?m)^\s*import\s+(?:public\s+|weak\s+)?"([^"]+)"\s*;`)
+)
+
 type parserAccessorHandler struct {
 	ctx                context.Context
 	module             bufcore.Module
 	pathToExternalPath map[string]string
 	nonImportPaths     map[string]struct{}
-	lock               sync.RWMutex
+	noBuiltinWKT       bool
+	errorOnBOM         bool
+	relativeImports    bool
+	// importPathToRelativePaths are the paths relative to the importing files
+	// for each import path, only populated if relativeImports is set
+	importPathToRelativePaths map[string]map[string]struct{}
+	// importPathToResolvedPath are the paths that import paths were resolved to
+	// relative to the importing files, only populated if relativeImports is set
+	importPathToResolvedPath map[string]string
+	lock                     sync.RWMutex
 }
 
 func newParserAccessorHandler(
 	ctx context.Context,
 	module bufcore.Module,
+	noBuiltinWKT bool,
+	errorOnBOM bool,
+	relativeImports bool,
 ) *parserAccessorHandler {
 	return &parserAccessorHandler{
-		ctx:                ctx,
-		module:             module,
-		pathToExternalPath: make(map[string]string),
-		nonImportPaths:     make(map[string]struct{}),
+		ctx:                       ctx,
+		module:                    module,
+		pathToExternalPath:        make(map[string]string),
+		nonImportPaths:            make(map[string]struct{}),
+		noBuiltinWKT:              noBuiltinWKT,
+		errorOnBOM:                errorOnBOM,
+		relativeImports:           relativeImports,
+		importPathToRelativePaths: make(map[string]map[string]struct{}),
+		importPathToResolvedPath:  make(map[string]string),
 	}
 }
 
 func (p *parserAccessorHandler) Open(path string) (_ io.ReadCloser, retErr error) {
 	moduleFile, moduleErr := p.module.GetFile(p.ctx, path)
+	if moduleErr != nil && storage.IsNotExist(moduleErr) && p.relativeImports {
+		relativeModuleFile, err := p.getRelativeModuleFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if relativeModuleFile != nil {
+			p.lock.Lock()
+			p.importPathToResolvedPath[path] = relativeModuleFile.Path()
+			p.lock.Unlock()
+			moduleFile = relativeModuleFile
+			moduleErr = nil
+		}
+	}
 	if moduleErr != nil {
 		if !storage.IsNotExist(moduleErr) {
 			return nil, moduleErr
 		}
+		if p.noBuiltinWKT {
+			return nil, moduleErr
+		}
 		if wktModuleFile, wktErr := wkt.ReadBucket.Get(p.ctx, path); wktErr == nil {
 			if wktModuleFile.Path() != path {
 				// this should never happen, but just in case
@@ -65,18 +114,150 @@ func (p *parserAccessorHandler) Open(path string) (_ io.ReadCloser, retErr error
 		return nil, moduleErr
 	}
 	defer func() {
-		if retErr != nil {
-			retErr = multierr.Append(retErr, moduleFile.Close())
-		}
+		retErr = multierr.Append(retErr, moduleFile.Close())
 	}()
-	if moduleFile.Path() != path {
+	if moduleFile.Path() != path && !p.relativeImports {
 		// this should never happen, but just in case
 		return nil, fmt.Errorf("parser accessor requested path %q but got %q", path, moduleFile.Path())
 	}
 	if err := p.addPath(path, moduleFile.ExternalPath(), moduleFile.IsImport()); err != nil {
 		return nil, err
 	}
-	return moduleFile, nil
+	data, err := ioutil.ReadAll(moduleFile)
+	if err != nil {
+		return nil, err
+	}
+	data, err = p.checkEncoding(moduleFile.ExternalPath(), data)
+	if err != nil {
+		return nil, err
+	}
+	if p.relativeImports {
+		if err := p.addRelativeImports(moduleFile.Path(), data); err != nil {
+			return nil, err
+		}
+	}
+	return ioutil.NopCloser(bytes.NewReader(data)), nil
+}
+
+// getRelativeModuleFile gets the module file for the import path relative to the
+// directories of the files that import it, or nil if there is no such file.
+//
+// This errors if the import path resolves to multiple files.
+func (p *parserAccessorHandler) getRelativeModuleFile(importPath string) (bufcore.ModuleFile, error) {
+	p.lock.RLock()
+	relativePaths := make([]string, 0, len(p.importPathToRelativePaths[importPath]))
+	for relativePath := range p.importPathToRelativePaths[importPath] {
+		relativePaths = append(relativePaths, relativePath)
+	}
+	p.lock.RUnlock()
+	sort.Strings(relativePaths)
+	var moduleFiles []bufcore.ModuleFile
+	for _, relativePath := range relativePaths {
+		moduleFile, err := p.module.GetFile(p.ctx, relativePath)
+		if err != nil {
+			if storage.IsNotExist(err) {
+				continue
+			}
+			return nil, multierr.Append(err, closeModuleFiles(moduleFiles))
+		}
+		moduleFiles = append(moduleFiles, moduleFile)
+	}
+	switch len(moduleFiles) {
+	case 0:
+		return nil, nil
+	case 1:
+		return moduleFiles[0], nil
+	default:
+		paths := make([]string, len(moduleFiles))
+		for i, moduleFile := range moduleFiles {
+			paths[i] = moduleFile.Path()
+		}
+		return nil, multierr.Append(
+			fmt.Errorf("%s: import resolves relative to the importing files to multiple files %s", importPath, strings.Join(paths, ", ")),
+			closeModuleFiles(moduleFiles),
+		)
+	}
+}
+
+// addRelativeImports records the imports of the file at the given path relative to its directory.
+//
+// This errors if an import was already resolved relative to another importing file
+// to a different file than the file relative to this file.
+func (p *parserAccessorHandler) addRelativeImports(path string, data []byte) error {
+	dirPath := normalpath.Dir(path)
+	if dirPath == "." {
+		// relative to the root, which is the same as the import path
+		return nil
+	}
+	for _, match := range importRegexp.FindAllSubmatch(data, -1) {
+		importPath := string(match[1])
+		relativePath, err := normalpath.NormalizeAndValidate(normalpath.Join(dirPath, importPath))
+		if err != nil {
+			continue
+		}
+		p.lock.Lock()
+		relativePaths, ok := p.importPathToRelativePaths[importPath]
+		if !ok {
+			relativePaths = make(map[string]struct{})
+			p.importPathToRelativePaths[importPath] = relativePaths
+		}
+		relativePaths[relativePath] = struct{}{}
+		resolvedPath, resolved := p.importPathToResolvedPath[importPath]
+		p.lock.Unlock()
+		if !resolved || resolvedPath == relativePath {
+			continue
+		}
+		moduleFile, err := p.module.GetFile(p.ctx, relativePath)
+		if err != nil {
+			if storage.IsNotExist(err) {
+				continue
+			}
+			return err
+		}
+		paths := []string{resolvedPath, relativePath}
+		sort.Strings(paths)
+		return multierr.Append(
+			fmt.Errorf("%s: import resolves relative to the importing files to multiple files %s", importPath, strings.Join(paths, ", ")),
+			moduleFile.Close(),
+		)
+	}
+	return nil
+}
+
+func closeModuleFiles(moduleFiles []bufcore.ModuleFile) error {
+	var err error
+	for _, moduleFile := range moduleFiles {
+		err = multierr.Append(err, moduleFile.Close())
+	}
+	return err
+}
+
+// checkEncoding strips a leading UTF-8 byte order mark from the data, or returns
+// an error if errorOnBOM is set, and returns an error if the data is not valid UTF-8.
+//
+// The parser does not handle either, and would otherwise produce a syntax error.
+func (p *parserAccessorHandler) checkEncoding(externalPath string, data []byte) ([]byte, error) {
+	if bytes.HasPrefix(data, utf8BOM) {
+		if p.errorOnBOM {
+			return nil, fmt.Errorf("%s: file starts with a UTF-8 byte order mark", externalPath)
+		}
+		data = data[len(utf8BOM):]
+	}
+	if !utf8.Valid(data) {
+		return nil, fmt.Errorf("%s: file is not valid UTF-8", externalPath)
+	}
+	return data, nil
+}
+
+func (p *parserAccessorHandler) NoBuiltinWKT() bool {
+	return p.noBuiltinWKT
+}
+
+func (p *parserAccessorHandler) Opened(path string) bool {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	_, ok := p.pathToExternalPath[path]
+	return ok
 }
 
 func (p *parserAccessorHandler) ExternalPath(path string) string {
@@ -0,0 +1,66 @@
+// Copyright 2020 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufbuild
+
+import (
+	"sync"
+)
+
+// progressTracker tracks the number of target files opened for parsing.
+//
+// All methods are safe to call on a nil progressTracker.
+type progressTracker struct {
+	progressFunc func(int, int)
+	targetPaths  map[string]struct{}
+	parsedPaths  map[string]struct{}
+	lock         sync.Mutex
+}
+
+// newProgressTracker returns a new progressTracker.
+//
+// Returns nil if progressFunc is nil.
+func newProgressTracker(targetPaths []string, progressFunc func(int, int)) *progressTracker {
+	if progressFunc == nil {
+		return nil
+	}
+	targetPathMap := make(map[string]struct{}, len(targetPaths))
+	for _, targetPath := range targetPaths {
+		targetPathMap[targetPath] = struct{}{}
+	}
+	return &progressTracker{
+		progressFunc: progressFunc,
+		targetPaths:  targetPathMap,
+		parsedPaths:  make(map[string]struct{}, len(targetPathMap)),
+	}
+}
+
+// Track records that the path was opened for parsing.
+//
+// Paths that are not target paths, and paths that were already recorded, are ignored.
+func (p *progressTracker) Track(path string) {
+	if p == nil {
+		return
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if _, ok := p.targetPaths[path]; !ok {
+		return
+	}
+	if _, ok := p.parsedPaths[path]; ok {
+		return
+	}
+	p.parsedPaths[path] = struct{}{}
+	p.progressFunc(len(p.parsedPaths), len(p.targetPaths))
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/bufbuild/buf/internal/buf/bufcheck/internal"
 	"github.com/bufbuild/buf/internal/buf/bufcore"
 	"github.com/bufbuild/buf/internal/buf/bufcore/bufcoreutil"
+	"github.com/bufbuild/buf/internal/pkg/instrument"
 	"github.com/bufbuild/buf/internal/pkg/protosource"
 	"go.uber.org/zap"
 )
@@ -45,13 +46,15 @@ func (h *handler) Check(
 	previousImage bufcore.Image,
 	image bufcore.Image,
 ) ([]bufanalysis.FileAnnotation, error) {
-	previousFiles, err := protosource.NewFilesUnstable(ctx, bufcoreutil.NewInputFiles(previousImage.Files())...)
+	timer := instrument.Start(h.logger, "new_files")
+	previousFiles, err := protosource.NewFiles(ctx, bufcoreutil.NewInputFiles(previousImage.Files())...)
 	if err != nil {
 		return nil, err
 	}
-	files, err := protosource.NewFilesUnstable(ctx, bufcoreutil.NewInputFiles(image.Files())...)
+	files, err := protosource.NewFiles(ctx, bufcoreutil.NewInputFiles(image.Files())...)
 	if err != nil {
 		return nil, err
 	}
+	timer.End(zap.Int("num_files", len(previousFiles)+len(files)))
 	return h.runner.Check(ctx, configToInternalConfig(config), previousFiles, files)
 }
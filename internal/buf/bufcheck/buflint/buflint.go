@@ -20,7 +20,10 @@ package buflint
 import (
 	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"io"
+	"reflect"
 	"sort"
 	"strings"
 
@@ -28,9 +31,18 @@ import (
 	"github.com/bufbuild/buf/internal/buf/bufcheck"
 	"github.com/bufbuild/buf/internal/buf/bufcheck/internal"
 	"github.com/bufbuild/buf/internal/buf/bufcore"
+	"github.com/bufbuild/buf/internal/pkg/normalpath"
+	"github.com/bufbuild/buf/internal/pkg/protosource"
+	"github.com/bufbuild/buf/internal/pkg/stringutil"
 	"go.uber.org/zap"
 )
 
+// defaultIgnorePathPrefixes are the path prefixes of files not linted if
+// ignore_path_prefixes is not set.
+var defaultIgnorePathPrefixes = []string{
+	"google/protobuf/",
+}
+
 // AllFormatStrings are all format strings.
 var AllFormatStrings = append(
 	bufanalysis.AllFormatStrings,
@@ -52,8 +64,52 @@ type Handler interface {
 }
 
 // NewHandler returns a new Handler.
-func NewHandler(logger *zap.Logger) Handler {
-	return newHandler(logger)
+func NewHandler(logger *zap.Logger, options ...HandlerOption) Handler {
+	return newHandler(logger, options...)
+}
+
+// HandlerOption is an option for a new Handler.
+type HandlerOption func(*handler)
+
+// HandlerWithCustomCheckers returns a new HandlerOption that runs the given
+// Checkers on every Check, in addition to the Checkers in the Config.
+//
+// Custom Checkers should be created with NewCustomChecker.
+// Multiple calls to this option will append to previous calls.
+func HandlerWithCustomCheckers(checkers ...Checker) HandlerOption {
+	return func(handler *handler) {
+		handler.customCheckers = append(handler.customCheckers, checkers...)
+	}
+}
+
+// HandlerWithFileContentFunc returns a new HandlerOption that uses the given
+// function to get the raw content of each ImageFile.
+//
+// The function should return nil if the content is not available.
+// This is required for Checkers that operate on raw file content, such as FILE_NO_CRLF,
+// and Check returns an error if such a Checker is used and the content of a file is
+// not available, unless the Config is strict.
+func HandlerWithFileContentFunc(fileContentFunc func(context.Context, bufcore.ImageFile) ([]byte, error)) HandlerOption {
+	return func(handler *handler) {
+		handler.fileContentFunc = fileContentFunc
+	}
+}
+
+// HandlerWithFileAnnotationsFunc returns a new HandlerOption that calls the given
+// function with the FileAnnotations of each file as soon as they are complete.
+//
+// The function is called once per file that has FileAnnotations, in the order of the
+// sorted FileAnnotations, so that concatenating the FileAnnotations of every call results
+// in the FileAnnotations returned from Check. If the function returns an error, Check
+// stops and returns the error.
+//
+// The Checkers that check multiple files together, such as PACKAGE_SAME_DIRECTORY, are
+// run first, after which the other Checkers are run file by file, so that the function
+// is called for each file while the next files are not yet checked.
+func HandlerWithFileAnnotationsFunc(fileAnnotationsFunc func([]bufanalysis.FileAnnotation) error) HandlerOption {
+	return func(handler *handler) {
+		handler.fileAnnotationsFunc = fileAnnotationsFunc
+	}
 }
 
 // Checker is a checker.
@@ -72,7 +128,71 @@ type Config struct {
 	Checkers            []Checker
 	IgnoreIDToRootPaths map[string]map[string]struct{}
 	IgnoreRootPaths     map[string]struct{}
+	// IgnorePathPrefixes are the path prefixes of files that are not linted at all.
+	//
+	// This is for vendored files, and is checked by a plain string prefix match
+	// on the normalized path of each file.
+	IgnorePathPrefixes  []string
 	AllowCommentIgnores bool
+	// Strict is true if every Checker was enabled with strict.
+	//
+	// Checkers that operate on raw file content, such as FILE_NO_CRLF, are then
+	// not run if the content is not available instead of resulting in an error.
+	Strict bool
+	// ErrorIDs are the IDs of the Checkers that produce FileAnnotations with error severity.
+	//
+	// If empty, all FileAnnotations have error severity. Otherwise, FileAnnotations
+	// produced by Checkers not in ErrorIDs have warning severity.
+	ErrorIDs map[string]struct{}
+	// InfoIDs are the IDs of the Checkers that produce FileAnnotations with info severity.
+	//
+	// This is for gradually adopting Checkers, as info FileAnnotations are printed
+	// but never result in a failure.
+	InfoIDs map[string]struct{}
+	// DirPathToConfig are the Configs of the overrides, keyed by normalized directory path.
+	//
+	// Files in one of these directories are checked with the Config of the nearest
+	// containing directory instead of this Config. All files are still checked
+	// together, so the Checkers that check multiple files together see every file.
+	DirPathToConfig map[string]*Config
+}
+
+// AddFunc adds a FileAnnotation for a custom Checker.
+//
+// The format and args are passed to fmt.Sprintf to produce the message.
+// Both the Descriptor and Location can be nil.
+type AddFunc func(descriptor protosource.Descriptor, location protosource.Location, format string, args ...interface{})
+
+// CustomCheckFunc is a custom check function that is called for every File.
+//
+// Failures should be reported with add. Returned errors are system errors.
+type CustomCheckFunc func(add AddFunc, file protosource.File) error
+
+// NewCustomChecker returns a new Checker for the given CustomCheckFunc.
+//
+// The id must be UPPER_SNAKE_CASE and must not be the ID of a built-in Checker.
+// The purpose will have "Checks that " prepended and "." appended.
+// Custom Checkers have no categories.
+//
+// The returned Checker respects comment ignores for its id in the same manner
+// as the built-in Checkers.
+func NewCustomChecker(id string, purpose string, customCheckFunc CustomCheckFunc) (Checker, error) {
+	if id == "" {
+		return nil, errors.New("custom checker id is empty")
+	}
+	if id != stringutil.ToUpperSnakeCase(id) {
+		return nil, fmt.Errorf("custom checker id %q is not UPPER_SNAKE_CASE", id)
+	}
+	if _, ok := v1IDToCategories[id]; ok {
+		return nil, fmt.Errorf("custom checker id %q conflicts with a built-in checker", id)
+	}
+	if purpose == "" {
+		return nil, fmt.Errorf("custom checker %q has an empty purpose", id)
+	}
+	if customCheckFunc == nil {
+		return nil, fmt.Errorf("custom checker %q has a nil check function", id)
+	}
+	return newCustomChecker(id, purpose, customCheckFunc)
 }
 
 // GetCheckers returns the checkers for the given categories.
@@ -84,19 +204,107 @@ func (c *Config) GetCheckers(categories ...string) ([]bufcheck.Checker, error) {
 	return checkersToBufcheckCheckers(c.Checkers, categories)
 }
 
+// RequiresFileContent returns true if any Checker of the Config or of its
+// overrides operates on raw file content, such as FILE_NO_CRLF.
+//
+// The raw content only needs to be read for HandlerWithFileContentFunc if this is true.
+func (c *Config) RequiresFileContent() bool {
+	for _, checker := range c.Checkers {
+		if _, ok := fileContentCheckerIDs[checker.ID()]; ok {
+			return true
+		}
+	}
+	for _, dirConfig := range c.DirPathToConfig {
+		if dirConfig.RequiresFileContent() {
+			return true
+		}
+	}
+	return false
+}
+
 // NewConfig returns a new Config.
 func NewConfig(externalConfig ExternalConfig) (*Config, error) {
+	if externalConfig.Extends != "" {
+		return nil, fmt.Errorf("extends %q must be resolved before creating a config", externalConfig.Extends)
+	}
+	if len(externalConfig.IgnoreRemove) > 0 {
+		return nil, errors.New("ignore_remove can only be set with extends")
+	}
+	if externalConfig.FileHeaderPath != "" && externalConfig.FileHeaderPathContent == "" {
+		return nil, fmt.Errorf("file_header_path %q must be resolved before creating a config", externalConfig.FileHeaderPath)
+	}
+	use := externalConfig.Use
+	except := externalConfig.Except
+	if boolValue(externalConfig.Strict) {
+		use = v1AllCategories
+		except = nil
+	}
 	internalConfig, err := internal.ConfigBuilder{
-		Use:                                  externalConfig.Use,
-		Except:                               externalConfig.Except,
+		Use:                                  use,
+		Except:                               except,
 		IgnoreRootPaths:                      externalConfig.Ignore,
 		IgnoreIDOrCategoryToRootPaths:        externalConfig.IgnoreOnly,
-		AllowCommentIgnores:                  externalConfig.AllowCommentIgnores,
+		AllowCommentIgnores:                  boolValue(externalConfig.AllowCommentIgnores),
+		ErrorIDsOrCategories:                 externalConfig.Error,
+		InfoIDsOrCategories:                  externalConfig.Info,
+		CommentEnumValuePackages:             externalConfig.CommentEnumValuePackages,
+		CommentEnumValueAllowZero:            boolValue(externalConfig.CommentEnumValueAllowZero),
+		CommentMessagePackages:               externalConfig.CommentMessagePackages,
+		CommentMessageTopLevelOnly:           boolValue(externalConfig.CommentMessageTopLevelOnly),
+		DeprecationCommentRegex:              externalConfig.DeprecationCommentRegex,
 		EnumZeroValueSuffix:                  externalConfig.EnumZeroValueSuffix,
-		RPCAllowSameRequestResponse:          externalConfig.RPCAllowSameRequestResponse,
-		RPCAllowGoogleProtobufEmptyRequests:  externalConfig.RPCAllowGoogleProtobufEmptyRequests,
-		RPCAllowGoogleProtobufEmptyResponses: externalConfig.RPCAllowGoogleProtobufEmptyResponses,
+		EnumValueMaxCount:                    externalConfig.EnumValueMaxCount,
+		EnumValueMaxCountDistinctNumbers:     boolValue(externalConfig.EnumValueMaxCountDistinctNumbers),
+		FieldMaxNumber:                       externalConfig.FieldMaxNumber,
+		FieldMaxNumberMessageRegex:           externalConfig.FieldMaxNumberMessageRegex,
+		FieldNumbersAscendingIgnoreOneofs:    boolValue(externalConfig.FieldNumbersAscendingIgnoreOneofs),
+		FieldDeprecatedReserveSeverity:       externalConfig.FieldDeprecatedReserveSeverity,
+		FieldNoAnyPackages:                   externalConfig.FieldNoAnyPackages,
+		FieldOptionConsistentOption:          externalConfig.FieldOptionConsistentOption,
+		FieldOptionConsistentOptionNumber:    externalConfig.FieldOptionConsistentOptionNumber,
+		FieldOptionConsistentTypes:           externalConfig.FieldOptionConsistentTypes,
+		FieldPresenceCommentPackages:         externalConfig.FieldPresenceCommentPackages,
+		FieldProto3Optional:                  externalConfig.FieldProto3Optional,
+		FieldProto3OptionalPackages:          externalConfig.FieldProto3OptionalPackages,
+		FieldNoWrapperTypePackages:           externalConfig.FieldNoWrapperTypePackages,
+		FileHeader:                           externalConfig.FileHeader,
+		FileHeaderRegex:                      externalConfig.FileHeaderRegex,
+		FileHeaderPath:                       externalConfig.FileHeaderPath,
+		FileHeaderPathContent:                externalConfig.FileHeaderPathContent,
+		FileNameRegex:                        externalConfig.FileNameRegex,
+		PackageFileMaxCount:                  externalConfig.PackageFileMaxCount,
+		PackageFileMaxCountFirstFileOnly:     boolValue(externalConfig.PackageFileMaxCountFirstFileOnly),
+		RPCAllowSameRequestResponse:          boolValue(externalConfig.RPCAllowSameRequestResponse),
+		RPCAllowGoogleProtobufEmptyRequests:  boolValue(externalConfig.RPCAllowGoogleProtobufEmptyRequests),
+		RPCAllowGoogleProtobufEmptyResponses: boolValue(externalConfig.RPCAllowGoogleProtobufEmptyResponses),
 		ServiceSuffix:                        externalConfig.ServiceSuffix,
+		AllowEmptyTypes:                      externalConfig.AllowEmptyTypes,
+		AllowEmptyServices:                   externalConfig.AllowEmptyServices,
+		ServiceStreamingConsistentDirection:  boolValue(externalConfig.ServiceStreamingConsistentDirection),
+		RPCRequiredOption:                    externalConfig.RPCRequiredOption,
+		RPCRequiredOptionNumber:              externalConfig.RPCRequiredOptionNumber,
+		RPCRequiredOptionServices:            externalConfig.RPCRequiredOptionServices,
+		RPCRequiredOptionPackages:            externalConfig.RPCRequiredOptionPackages,
+		RPCIdempotencyLevelServices:          externalConfig.RPCIdempotencyLevelServices,
+		RPCIdempotencyLevelPackages:          externalConfig.RPCIdempotencyLevelPackages,
+		RPCPaginationMethodRegex:             externalConfig.RPCPaginationMethodRegex,
+		ImportForbiddenPaths:                 externalConfig.ImportForbiddenPaths,
+		ImportSortedGroups:                   externalConfig.ImportSortedGroups,
+		AllowWKTNameTypes:                    externalConfig.AllowWKTNameTypes,
+		AllowStutterFields:                   externalConfig.AllowStutterFields,
+		AllowAnyFields:                       externalConfig.AllowAnyFields,
+		AllowNestedEnums:                     externalConfig.AllowNestedEnums,
+		AllowInconsistentFieldLabels:         externalConfig.AllowInconsistentFieldLabels,
+		MapKeyForbiddenTypes:                 externalConfig.MapKeyForbiddenTypes,
+		NameMaxLength:                        externalConfig.NameMaxLength,
+		MessageFieldNumbersDenseFactor:       externalConfig.MessageFieldNumbersDenseFactor,
+		FieldNamesDistinctMaxDistance:        externalConfig.FieldNamesDistinctMaxDistance,
+		TypeNamePackageMaxCount:              externalConfig.TypeNamePackageMaxCount,
+		NestedTypeReferencedScope:            externalConfig.NestedTypeReferencedScope,
+		AllowMapWrapperTypes:                 externalConfig.AllowMapWrapperTypes,
+		Acronyms:                             externalConfig.Acronyms,
+		ReservedWordLanguages:                externalConfig.ReservedWordLanguages,
+		FieldBoolNegativePrefixes:            externalConfig.FieldBoolNegativePrefixes,
 	}.NewConfig(
 		v1CheckerBuilders,
 		v1IDToCategories,
@@ -105,7 +313,40 @@ func NewConfig(externalConfig ExternalConfig) (*Config, error) {
 	if err != nil {
 		return nil, err
 	}
-	return internalConfigToConfig(internalConfig), nil
+	ignorePathPrefixes, err := getIgnorePathPrefixes(externalConfig.IgnorePathPrefixes)
+	if err != nil {
+		return nil, err
+	}
+	config := internalConfigToConfig(internalConfig)
+	config.IgnorePathPrefixes = ignorePathPrefixes
+	config.Strict = boolValue(externalConfig.Strict)
+	if err := foldV1FieldDeprecatedReserveSeverity(config, externalConfig.FieldDeprecatedReserveSeverity); err != nil {
+		return nil, err
+	}
+	if len(externalConfig.Overrides) > 0 {
+		config.DirPathToConfig = make(map[string]*Config, len(externalConfig.Overrides))
+		for dirPath, overrideExternalConfig := range externalConfig.Overrides {
+			normalDirPath, err := normalpath.NormalizeAndValidate(dirPath)
+			if err != nil {
+				return nil, fmt.Errorf("invalid overrides directory %q: %v", dirPath, err)
+			}
+			if normalDirPath == "." {
+				return nil, fmt.Errorf("overrides directory %q is the root directory", dirPath)
+			}
+			if _, ok := config.DirPathToConfig[normalDirPath]; ok {
+				return nil, fmt.Errorf("duplicate overrides directory %q", dirPath)
+			}
+			if len(overrideExternalConfig.Overrides) > 0 {
+				return nil, fmt.Errorf("overrides for directory %q cannot have overrides", dirPath)
+			}
+			overrideConfig, err := NewConfig(mergeExternalConfigs(externalConfig, overrideExternalConfig))
+			if err != nil {
+				return nil, fmt.Errorf("overrides for directory %q: %v", dirPath, err)
+			}
+			config.DirPathToConfig[normalDirPath] = overrideConfig
+		}
+	}
+	return config, nil
 }
 
 // GetAllCheckers gets all known checkers for the given categories.
@@ -127,14 +368,141 @@ type ExternalConfig struct {
 	Except []string `json:"except,omitempty" yaml:"except,omitempty"`
 	// IgnoreRootPaths
 	Ignore []string `json:"ignore,omitempty" yaml:"ignore,omitempty"`
+	// ErrorIDsOrCategories
+	Error []string `json:"error,omitempty" yaml:"error,omitempty"`
+	// InfoIDsOrCategories
+	Info []string `json:"info,omitempty" yaml:"info,omitempty"`
 	// IgnoreIDOrCategoryToRootPaths
 	IgnoreOnly                           map[string][]string `json:"ignore_only,omitempty" yaml:"ignore_only,omitempty"`
+	CommentEnumValuePackages             []string            `json:"comment_enum_value_packages,omitempty" yaml:"comment_enum_value_packages,omitempty"`
+	CommentEnumValueAllowZero            *bool               `json:"comment_enum_value_allow_zero,omitempty" yaml:"comment_enum_value_allow_zero,omitempty"`
+	CommentMessagePackages               []string            `json:"comment_message_packages,omitempty" yaml:"comment_message_packages,omitempty"`
+	CommentMessageTopLevelOnly           *bool               `json:"comment_message_top_level_only,omitempty" yaml:"comment_message_top_level_only,omitempty"`
+	DeprecationCommentRegex              string              `json:"deprecation_comment_regex,omitempty" yaml:"deprecation_comment_regex,omitempty"`
 	EnumZeroValueSuffix                  string              `json:"enum_zero_value_suffix,omitempty" yaml:"enum_zero_value_suffix,omitempty"`
-	RPCAllowSameRequestResponse          bool                `json:"rpc_allow_same_request_response,omitempty" yaml:"rpc_allow_same_request_response,omitempty"`
-	RPCAllowGoogleProtobufEmptyRequests  bool                `json:"rpc_allow_google_protobuf_empty_requests,omitempty" yaml:"rpc_allow_google_protobuf_empty_requests,omitempty"`
-	RPCAllowGoogleProtobufEmptyResponses bool                `json:"rpc_allow_google_protobuf_empty_responses,omitempty" yaml:"rpc_allow_google_protobuf_empty_responses,omitempty"`
-	ServiceSuffix                        string              `json:"service_suffix,omitempty" yaml:"service_suffix,omitempty"`
-	AllowCommentIgnores                  bool                `json:"allow_comment_ignores,omitempty" yaml:"allow_comment_ignores,omitempty"`
+	EnumValueMaxCount                    int                 `json:"enum_value_max_count,omitempty" yaml:"enum_value_max_count,omitempty"`
+	EnumValueMaxCountDistinctNumbers     *bool               `json:"enum_value_max_count_distinct_numbers,omitempty" yaml:"enum_value_max_count_distinct_numbers,omitempty"`
+	FieldMaxNumber                       int                 `json:"field_max_number,omitempty" yaml:"field_max_number,omitempty"`
+	FieldMaxNumberMessageRegex           string              `json:"field_max_number_message_regex,omitempty" yaml:"field_max_number_message_regex,omitempty"`
+	FieldNumbersAscendingIgnoreOneofs    *bool               `json:"field_numbers_ascending_ignore_oneofs,omitempty" yaml:"field_numbers_ascending_ignore_oneofs,omitempty"`
+	FieldDeprecatedReserveSeverity       string              `json:"field_deprecated_reserve_severity,omitempty" yaml:"field_deprecated_reserve_severity,omitempty"`
+	FieldNoAnyPackages                   []string            `json:"field_no_any_packages,omitempty" yaml:"field_no_any_packages,omitempty"`
+	FieldOptionConsistentOption          string              `json:"field_option_consistent_option,omitempty" yaml:"field_option_consistent_option,omitempty"`
+	FieldOptionConsistentOptionNumber    int                 `json:"field_option_consistent_option_number,omitempty" yaml:"field_option_consistent_option_number,omitempty"`
+	FieldOptionConsistentTypes           []string            `json:"field_option_consistent_types,omitempty" yaml:"field_option_consistent_types,omitempty"`
+	FieldPresenceCommentPackages         []string            `json:"field_presence_comment_packages,omitempty" yaml:"field_presence_comment_packages,omitempty"`
+	FieldProto3Optional                  string              `json:"field_proto3_optional,omitempty" yaml:"field_proto3_optional,omitempty"`
+	FieldProto3OptionalPackages          []string            `json:"field_proto3_optional_packages,omitempty" yaml:"field_proto3_optional_packages,omitempty"`
+	FieldNoWrapperTypePackages           []string            `json:"field_no_wrapper_type_packages,omitempty" yaml:"field_no_wrapper_type_packages,omitempty"`
+	FileHeader                           string              `json:"file_header,omitempty" yaml:"file_header,omitempty"`
+	FileHeaderRegex                      string              `json:"file_header_regex,omitempty" yaml:"file_header_regex,omitempty"`
+	FileHeaderPath                       string              `json:"file_header_path,omitempty" yaml:"file_header_path,omitempty"`
+	FileNameRegex                        string              `json:"file_name_regex,omitempty" yaml:"file_name_regex,omitempty"`
+	PackageFileMaxCount                  int                 `json:"package_file_max_count,omitempty" yaml:"package_file_max_count,omitempty"`
+	PackageFileMaxCountFirstFileOnly     *bool               `json:"package_file_max_count_first_file_only,omitempty" yaml:"package_file_max_count_first_file_only,omitempty"`
+	RPCAllowSameRequestResponse          *bool               `json:"rpc_allow_same_request_response,omitempty" yaml:"rpc_allow_same_request_response,omitempty"`
+	RPCAllowGoogleProtobufEmptyRequests  *bool               `json:"rpc_allow_google_protobuf_empty_requests,omitempty" yaml:"rpc_allow_google_protobuf_empty_requests,omitempty"`
+	RPCAllowGoogleProtobufEmptyResponses *bool               `json:"rpc_allow_google_protobuf_empty_responses,omitempty" yaml:"rpc_allow_google_protobuf_empty_responses,omitempty"`
+	ServiceSuffix                        *string             `json:"service_suffix,omitempty" yaml:"service_suffix,omitempty"`
+	AllowEmptyTypes                      []string            `json:"allow_empty_types,omitempty" yaml:"allow_empty_types,omitempty"`
+	AllowEmptyServices                   []string            `json:"allow_empty_services,omitempty" yaml:"allow_empty_services,omitempty"`
+	ServiceStreamingConsistentDirection  *bool               `json:"service_streaming_consistent_direction,omitempty" yaml:"service_streaming_consistent_direction,omitempty"`
+	RPCRequiredOption                    string              `json:"rpc_required_option,omitempty" yaml:"rpc_required_option,omitempty"`
+	RPCRequiredOptionNumber              int                 `json:"rpc_required_option_number,omitempty" yaml:"rpc_required_option_number,omitempty"`
+	RPCRequiredOptionServices            []string            `json:"rpc_required_option_services,omitempty" yaml:"rpc_required_option_services,omitempty"`
+	RPCRequiredOptionPackages            []string            `json:"rpc_required_option_packages,omitempty" yaml:"rpc_required_option_packages,omitempty"`
+	RPCIdempotencyLevelServices          []string            `json:"rpc_idempotency_level_services,omitempty" yaml:"rpc_idempotency_level_services,omitempty"`
+	RPCIdempotencyLevelPackages          []string            `json:"rpc_idempotency_level_packages,omitempty" yaml:"rpc_idempotency_level_packages,omitempty"`
+	RPCPaginationMethodRegex             string              `json:"rpc_pagination_method_regex,omitempty" yaml:"rpc_pagination_method_regex,omitempty"`
+	ImportForbiddenPaths                 map[string][]string `json:"import_forbidden_paths,omitempty" yaml:"import_forbidden_paths,omitempty"`
+	ImportSortedGroups                   []string            `json:"import_sorted_groups,omitempty" yaml:"import_sorted_groups,omitempty"`
+	AllowWKTNameTypes                    []string            `json:"allow_wkt_name_types,omitempty" yaml:"allow_wkt_name_types,omitempty"`
+	AllowStutterFields                   []string            `json:"allow_stutter_fields,omitempty" yaml:"allow_stutter_fields,omitempty"`
+	AllowAnyFields                       []string            `json:"allow_any_fields,omitempty" yaml:"allow_any_fields,omitempty"`
+	AllowNestedEnums                     []string            `json:"allow_nested_enums,omitempty" yaml:"allow_nested_enums,omitempty"`
+	AllowInconsistentFieldLabels         []string            `json:"allow_inconsistent_field_labels,omitempty" yaml:"allow_inconsistent_field_labels,omitempty"`
+	MapKeyForbiddenTypes                 []string            `json:"map_key_forbidden_types,omitempty" yaml:"map_key_forbidden_types,omitempty"`
+	NameMaxLength                        int                 `json:"name_max_length,omitempty" yaml:"name_max_length,omitempty"`
+	MessageFieldNumbersDenseFactor       int                 `json:"message_field_numbers_dense_factor,omitempty" yaml:"message_field_numbers_dense_factor,omitempty"`
+	FieldNamesDistinctMaxDistance        int                 `json:"field_names_distinct_max_distance,omitempty" yaml:"field_names_distinct_max_distance,omitempty"`
+	TypeNamePackageMaxCount              int                 `json:"type_name_package_max_count,omitempty" yaml:"type_name_package_max_count,omitempty"`
+	AllowMapWrapperTypes                 []string            `json:"allow_map_wrapper_types,omitempty" yaml:"allow_map_wrapper_types,omitempty"`
+	NestedTypeReferencedScope            string              `json:"nested_type_referenced_scope,omitempty" yaml:"nested_type_referenced_scope,omitempty"`
+	IgnorePathPrefixes                   []string            `json:"ignore_path_prefixes,omitempty" yaml:"ignore_path_prefixes,omitempty"`
+	Acronyms                             []string            `json:"acronyms,omitempty" yaml:"acronyms,omitempty"`
+	ReservedWordLanguages                []string            `json:"reserved_word_languages,omitempty" yaml:"reserved_word_languages,omitempty"`
+	FieldBoolNegativePrefixes            []string            `json:"field_bool_negative_prefixes,omitempty" yaml:"field_bool_negative_prefixes,omitempty"`
+	AllowCommentIgnores                  *bool               `json:"allow_comment_ignores,omitempty" yaml:"allow_comment_ignores,omitempty"`
+	// Strict enables every checker, overriding Use and Except.
+	Strict *bool `json:"strict,omitempty" yaml:"strict,omitempty"`
+	// Overrides are the configs for files in the given directories.
+	//
+	// Each override is merged with this config, i.e. every key set in the override
+	// replaces the same key of this config, and every other key is inherited.
+	// The nearest directory containing a file applies.
+	Overrides map[string]ExternalConfig `json:"overrides,omitempty" yaml:"overrides,omitempty"`
+	// Extends is the path or https URL of a config file with a lint config that
+	// this config extends, see ExtendExternalConfig.
+	//
+	// Relative paths are relative to this config file, and are only supported
+	// for configs in local directories.
+	//
+	// This is resolved by the config provider, and must be empty for NewConfig.
+	Extends string `json:"extends,omitempty" yaml:"extends,omitempty"`
+	// IgnoreRemove are the paths to remove from the inherited Ignore.
+	IgnoreRemove []string `json:"ignore_remove,omitempty" yaml:"ignore_remove,omitempty"`
+	// FileHeaderPathContent is the content of the file at FileHeaderPath.
+	//
+	// This is read by the config provider relative to the root of the config,
+	// and must be set for NewConfig if FileHeaderPath is set.
+	FileHeaderPathContent string `json:"-" yaml:"-"`
+}
+
+// ExtendExternalConfig returns the base ExternalConfig extended with the local ExternalConfig.
+//
+// The Use, Except, Ignore, IgnoreOnly, Error, and Info of the local ExternalConfig are
+// added to those of the base ExternalConfig, after the IgnoreRemove paths of the
+// local ExternalConfig are removed from the inherited Ignore and IgnoreOnly.
+// Every other key set in the local ExternalConfig replaces the same key of the
+// base ExternalConfig, the Overrides of the local ExternalConfig replace those of
+// the base ExternalConfig for the same directories.
+//
+// The result has no Extends, the base ExternalConfig must already be resolved.
+func ExtendExternalConfig(base ExternalConfig, local ExternalConfig) (ExternalConfig, error) {
+	if base.Extends != "" {
+		return ExternalConfig{}, fmt.Errorf("extends %q of the base config must be resolved before extending it", base.Extends)
+	}
+	extended := mergeExternalConfigs(base, local)
+	removeIgnore := stringutil.SliceToMap(local.IgnoreRemove)
+	extended.Use = appendUnique(base.Use, local.Use)
+	extended.Except = appendUnique(base.Except, local.Except)
+	extended.Ignore = appendUnique(removeStrings(base.Ignore, removeIgnore), local.Ignore)
+	extended.Error = appendUnique(base.Error, local.Error)
+	extended.Info = appendUnique(base.Info, local.Info)
+	extended.IgnoreOnly = make(map[string][]string)
+	for idOrCategory, paths := range base.IgnoreOnly {
+		if paths := removeStrings(paths, removeIgnore); len(paths) > 0 {
+			extended.IgnoreOnly[idOrCategory] = paths
+		}
+	}
+	for idOrCategory, paths := range local.IgnoreOnly {
+		extended.IgnoreOnly[idOrCategory] = appendUnique(extended.IgnoreOnly[idOrCategory], paths)
+	}
+	if len(extended.IgnoreOnly) == 0 {
+		extended.IgnoreOnly = nil
+	}
+	extended.Overrides = make(map[string]ExternalConfig)
+	for _, overrides := range []map[string]ExternalConfig{base.Overrides, local.Overrides} {
+		for dirPath, override := range overrides {
+			extended.Overrides[dirPath] = override
+		}
+	}
+	if len(extended.Overrides) == 0 {
+		extended.Overrides = nil
+	}
+	extended.Extends = ""
+	extended.IgnoreRemove = nil
+	return extended, nil
 }
 
 // PrintFileAnnotations prints the FileAnnotations to the Writer.
@@ -208,21 +576,95 @@ func printFileAnnotationsConfigIgnoreYAML(
 	return err
 }
 
+// mergeExternalConfigs returns the base ExternalConfig with every key set in the
+// override ExternalConfig replaced.
+//
+// A key is set if it is not the zero value. As pointers, slices, and maps are
+// only nil if unset, an explicit false or empty list also replaces the key.
+//
+// The Overrides of the base ExternalConfig are not included in the result.
+func mergeExternalConfigs(base ExternalConfig, override ExternalConfig) ExternalConfig {
+	base.Overrides = nil
+	mergedValue := reflect.ValueOf(&base).Elem()
+	overrideValue := reflect.ValueOf(override)
+	for i := 0; i < overrideValue.NumField(); i++ {
+		if field := overrideValue.Field(i); !field.IsZero() {
+			mergedValue.Field(i).Set(field)
+		}
+	}
+	return base
+}
+
+// appendUnique returns the values not in base appended to base.
+func appendUnique(base []string, values []string) []string {
+	seen := stringutil.SliceToMap(base)
+	result := append([]string(nil), base...)
+	for _, value := range values {
+		if _, ok := seen[value]; !ok {
+			seen[value] = struct{}{}
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+// removeStrings returns the values not in remove.
+func removeStrings(values []string, remove map[string]struct{}) []string {
+	var result []string
+	for _, value := range values {
+		if _, ok := remove[value]; !ok {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
 func internalConfigToConfig(internalConfig *internal.Config) *Config {
 	return &Config{
 		Checkers:            internalCheckersToCheckers(internalConfig.Checkers),
 		IgnoreIDToRootPaths: internalConfig.IgnoreIDToRootPaths,
 		IgnoreRootPaths:     internalConfig.IgnoreRootPaths,
 		AllowCommentIgnores: internalConfig.AllowCommentIgnores,
+		ErrorIDs:            internalConfig.ErrorIDs,
+		InfoIDs:             internalConfig.InfoIDs,
 	}
 }
 
+// getIgnorePathPrefixes validates the ignore_path_prefixes.
+//
+// A nil slice results in the default, while an explicitly empty slice
+// results in no prefixes.
+func getIgnorePathPrefixes(ignorePathPrefixes []string) ([]string, error) {
+	if ignorePathPrefixes == nil {
+		return defaultIgnorePathPrefixes, nil
+	}
+	validated := make([]string, 0, len(ignorePathPrefixes))
+	for _, ignorePathPrefix := range ignorePathPrefixes {
+		if ignorePathPrefix == "" {
+			return nil, errors.New("ignore_path_prefixes contains an empty prefix")
+		}
+		normalized, err := normalpath.NormalizeAndValidate(ignorePathPrefix)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ignore_path_prefixes value %q: %v", ignorePathPrefix, err)
+		}
+		// normalization removes trailing separators, which would otherwise
+		// allow "google/protobuf" to match "google/protobufs/a.proto"
+		if strings.HasSuffix(ignorePathPrefix, "/") {
+			normalized += "/"
+		}
+		validated = append(validated, normalized)
+	}
+	return validated, nil
+}
+
 func configToInternalConfig(config *Config) *internal.Config {
 	return &internal.Config{
 		Checkers:            checkersToInternalCheckers(config.Checkers),
 		IgnoreIDToRootPaths: config.IgnoreIDToRootPaths,
 		IgnoreRootPaths:     config.IgnoreRootPaths,
 		AllowCommentIgnores: config.AllowCommentIgnores,
+		ErrorIDs:            config.ErrorIDs,
+		InfoIDs:             config.InfoIDs,
 	}
 }
 
@@ -239,3 +681,7 @@ func checkersToBufcheckCheckers(checkers []Checker, categories []string) ([]bufc
 	}
 	return internal.GetCheckersForCategories(s, v1AllCategories, categories)
 }
+
+func boolValue(value *bool) bool {
+	return value != nil && *value
+}
@@ -16,7 +16,10 @@ package buflint_test
 
 import (
 	"context"
+	"io/ioutil"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -27,11 +30,14 @@ import (
 	"github.com/bufbuild/buf/internal/buf/bufconfig"
 	"github.com/bufbuild/buf/internal/buf/bufcore"
 	"github.com/bufbuild/buf/internal/buf/bufmod"
+	"github.com/bufbuild/buf/internal/pkg/protosource"
 	"github.com/bufbuild/buf/internal/pkg/storage"
 	"github.com/bufbuild/buf/internal/pkg/storage/storageos"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
 )
 
 // Hint on how to get these:
@@ -142,6 +148,42 @@ func TestRunComments(t *testing.T) {
 	)
 }
 
+func TestRunDeprecationComment(t *testing.T) {
+	testLint(
+		t,
+		"deprecation_comment",
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 12, 3, 12, 39, "DEPRECATION_COMMENT"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 13, 3, 13, 37, "DEPRECATION_COMMENT"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 15, 3, 17, 4, "DEPRECATION_COMMENT"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 27, 1, 31, 2, "DEPRECATION_COMMENT"),
+	)
+}
+
+func TestRunDeprecationCommentRegex(t *testing.T) {
+	testLintExternalConfigModifier(
+		t,
+		"deprecation_comment",
+		func(externalConfig *bufconfig.ExternalConfig) {
+			externalConfig.Lint.DeprecationCommentRegex = "^ Deprecated:"
+		},
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 10, 3, 10, 39, "DEPRECATION_COMMENT"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 12, 3, 12, 39, "DEPRECATION_COMMENT"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 13, 3, 13, 37, "DEPRECATION_COMMENT"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 15, 3, 17, 4, "DEPRECATION_COMMENT"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 27, 1, 31, 2, "DEPRECATION_COMMENT"),
+	)
+}
+
+func TestRunDirectoryPackageMajority(t *testing.T) {
+	testLint(
+		t,
+		"directory_package_majority",
+		bufanalysistesting.NewFileAnnotation(t, "tie/a.proto", 3, 1, 3, 15, "DIRECTORY_PACKAGE_MAJORITY"),
+		bufanalysistesting.NewFileAnnotation(t, "tie/b.proto", 3, 1, 3, 15, "DIRECTORY_PACKAGE_MAJORITY"),
+		bufanalysistesting.NewFileAnnotation(t, "typo/c.proto", 3, 1, 3, 16, "DIRECTORY_PACKAGE_MAJORITY"),
+	)
+}
+
 func TestRunDirectorySamePackage(t *testing.T) {
 	testLint(
 		t,
@@ -153,6 +195,38 @@ func TestRunDirectorySamePackage(t *testing.T) {
 	)
 }
 
+func TestRunImportNoForbiddenPath(t *testing.T) {
+	testLint(
+		t,
+		"import_no_forbidden_path",
+		bufanalysistesting.NewFileAnnotation(t, "api/v1/api.proto", 5, 1, 5, 34, "IMPORT_NO_FORBIDDEN_PATH"),
+	)
+}
+
+func TestRunImportNoForbiddenPathShared(t *testing.T) {
+	testLintExternalConfigModifier(
+		t,
+		"import_no_forbidden_path",
+		func(externalConfig *bufconfig.ExternalConfig) {
+			externalConfig.Lint.ImportForbiddenPaths = map[string][]string{
+				"**": {"shared/*.proto"},
+			}
+		},
+		bufanalysistesting.NewFileAnnotation(t, "api/v1/api.proto", 6, 1, 6, 30, "IMPORT_NO_FORBIDDEN_PATH"),
+		bufanalysistesting.NewFileAnnotation(t, "internal/internal.proto", 5, 1, 5, 30, "IMPORT_NO_FORBIDDEN_PATH"),
+	)
+}
+
+func TestRunImportNoForbiddenPathNone(t *testing.T) {
+	testLintExternalConfigModifier(
+		t,
+		"import_no_forbidden_path",
+		func(externalConfig *bufconfig.ExternalConfig) {
+			externalConfig.Lint.ImportForbiddenPaths = nil
+		},
+	)
+}
+
 func TestRunImportNoPublic(t *testing.T) {
 	testLint(
 		t,
@@ -173,6 +247,15 @@ func TestRunImportNoWeak(t *testing.T) {
 	)
 }
 
+func TestRunEnumAllowAliasUsed(t *testing.T) {
+	testLint(
+		t,
+		"enum_allow_alias_used",
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 13, 3, 13, 29, "ENUM_ALLOW_ALIAS_USED"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 25, 5, 25, 31, "ENUM_ALLOW_ALIAS_USED"),
+	)
+}
+
 func TestRunEnumFirstValueZero(t *testing.T) {
 	testLint(
 		t,
@@ -199,6 +282,26 @@ func TestRunEnumNoAllowAlias(t *testing.T) {
 	)
 }
 
+func TestRunEnumNotNested(t *testing.T) {
+	testLint(
+		t,
+		"enum_not_nested",
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 10, 8, 10, 14, "ENUM_NOT_NESTED"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 14, 10, 14, 17, "ENUM_NOT_NESTED"),
+	)
+}
+
+func TestRunEnumNotNestedAllowNestedEnums(t *testing.T) {
+	testLintExternalConfigModifier(
+		t,
+		"enum_not_nested",
+		func(externalConfig *bufconfig.ExternalConfig) {
+			externalConfig.Lint.AllowNestedEnums = []string{".a.One.Two.Allowed"}
+		},
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 10, 8, 10, 14, "ENUM_NOT_NESTED"),
+	)
+}
+
 func TestRunEnumPascalCase(t *testing.T) {
 	testLint(
 		t,
@@ -218,6 +321,28 @@ func TestRunEnumPascalCase(t *testing.T) {
 	)
 }
 
+func TestRunEnumValueMaxCount(t *testing.T) {
+	testLint(
+		t,
+		"enum_value_max_count",
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 11, 6, 11, 15, "ENUM_VALUE_MAX_COUNT"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 18, 6, 18, 13, "ENUM_VALUE_MAX_COUNT"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 27, 8, 27, 23, "ENUM_VALUE_MAX_COUNT"),
+	)
+}
+
+func TestRunEnumValueMaxCountDistinctNumbers(t *testing.T) {
+	testLintExternalConfigModifier(
+		t,
+		"enum_value_max_count",
+		func(externalConfig *bufconfig.ExternalConfig) {
+			externalConfig.Lint.EnumValueMaxCountDistinctNumbers = proto.Bool(true)
+		},
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 11, 6, 11, 15, "ENUM_VALUE_MAX_COUNT"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 27, 8, 27, 23, "ENUM_VALUE_MAX_COUNT"),
+	)
+}
+
 func TestRunEnumValuePrefix(t *testing.T) {
 	testLint(
 		t,
@@ -281,6 +406,33 @@ func TestRunEnumZeroValueSuffixCustom(t *testing.T) {
 	)
 }
 
+func TestRunFieldLabelConsistent(t *testing.T) {
+	testLint(
+		t,
+		"field_label_consistent",
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 6, 10, 6, 13, "FIELD_LABEL_CONSISTENT"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 7, 19, 7, 23, "FIELD_LABEL_CONSISTENT"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 8, 19, 8, 24, "FIELD_LABEL_CONSISTENT"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 9, 10, 9, 16, "FIELD_LABEL_CONSISTENT"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 13, 10, 13, 13, "FIELD_LABEL_CONSISTENT"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 14, 19, 14, 23, "FIELD_LABEL_CONSISTENT"),
+	)
+}
+
+func TestRunFieldLabelConsistentAllowInconsistentFieldLabels(t *testing.T) {
+	testLintExternalConfigModifier(
+		t,
+		"field_label_consistent",
+		func(externalConfig *bufconfig.ExternalConfig) {
+			externalConfig.Lint.AllowInconsistentFieldLabels = []string{".a.Allowed.tags"}
+		},
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 6, 10, 6, 13, "FIELD_LABEL_CONSISTENT"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 7, 19, 7, 23, "FIELD_LABEL_CONSISTENT"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 8, 19, 8, 24, "FIELD_LABEL_CONSISTENT"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 9, 10, 9, 16, "FIELD_LABEL_CONSISTENT"),
+	)
+}
+
 func TestRunFieldLowerSnakeCase(t *testing.T) {
 	testLint(
 		t,
@@ -303,6 +455,52 @@ func TestRunFieldLowerSnakeCase(t *testing.T) {
 	)
 }
 
+func TestRunFieldMaxNumber(t *testing.T) {
+	testLint(
+		t,
+		"field_max_number",
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 8, 20, 8, 22, "FIELD_MAX_NUMBER"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 11, 22, 11, 25, "FIELD_MAX_NUMBER"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 17, 20, 17, 22, "FIELD_MAX_NUMBER"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 19, 24, 19, 26, "FIELD_MAX_NUMBER"),
+	)
+}
+
+func TestRunFieldMaxNumberMessageRegex(t *testing.T) {
+	testLintExternalConfigModifier(
+		t,
+		"field_max_number",
+		func(externalConfig *bufconfig.ExternalConfig) {
+			externalConfig.Lint.FieldMaxNumberMessageRegex = `^a\.Hot`
+		},
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 8, 20, 8, 22, "FIELD_MAX_NUMBER"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 11, 22, 11, 25, "FIELD_MAX_NUMBER"),
+	)
+}
+
+func TestRunFieldNamesDistinct(t *testing.T) {
+	testLint(
+		t,
+		"field_names_distinct",
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 6, 10, 6, 21, "FIELD_NAMES_DISTINCT"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 7, 10, 7, 20, "FIELD_NAMES_DISTINCT"),
+	)
+}
+
+func TestRunFieldNamesDistinctMaxDistance(t *testing.T) {
+	testLintExternalConfigModifier(
+		t,
+		"field_names_distinct",
+		func(externalConfig *bufconfig.ExternalConfig) {
+			externalConfig.Lint.FieldNamesDistinctMaxDistance = 4
+		},
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 6, 10, 6, 21, "FIELD_NAMES_DISTINCT"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 7, 10, 7, 20, "FIELD_NAMES_DISTINCT"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 12, 10, 12, 14, "FIELD_NAMES_DISTINCT"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 13, 10, 13, 15, "FIELD_NAMES_DISTINCT"),
+	)
+}
+
 func TestRunFieldNoDescriptor(t *testing.T) {
 	testLint(
 		t,
@@ -342,6 +540,51 @@ func TestRunFieldNoDescriptor(t *testing.T) {
 	)
 }
 
+func TestRunFileNoCRLF(t *testing.T) {
+	testLintExternalConfigModifierHandlerOptions(
+		t,
+		"file_no_crlf",
+		nil,
+		[]buflint.HandlerOption{
+			buflint.HandlerWithFileContentFunc(
+				func(_ context.Context, imageFile bufcore.ImageFile) ([]byte, error) {
+					return ioutil.ReadFile(filepath.Join("testdata", "file_no_crlf", imageFile.Path()))
+				},
+			),
+		},
+		bufanalysistesting.NewFileAnnotation(t, "b.proto", 0, 0, 0, 0, "FILE_NO_CRLF"),
+	)
+}
+
+func TestRunFileNoCRLFNoContent(t *testing.T) {
+	t.Parallel()
+	// without the raw content, such as when linting images, the files cannot be checked
+	config, err := buflint.NewConfig(buflint.ExternalConfig{Use: []string{"FILE_NO_CRLF"}})
+	require.NoError(t, err)
+	imageFile, err := bufcore.NewImageFile(&descriptorpb.FileDescriptorProto{Name: proto.String("a.proto")}, "", false)
+	require.NoError(t, err)
+	image, err := bufcore.NewImage([]bufcore.ImageFile{imageFile})
+	require.NoError(t, err)
+	for _, handlerOptions := range [][]buflint.HandlerOption{
+		nil,
+		{
+			buflint.HandlerWithFileContentFunc(
+				func(context.Context, bufcore.ImageFile) ([]byte, error) {
+					return nil, nil
+				},
+			),
+		},
+	} {
+		_, err := buflint.NewHandler(zap.NewNop(), handlerOptions...).Check(context.Background(), config, image)
+		assert.Error(t, err)
+	}
+	// strict enables FILE_NO_CRLF regardless of the input, so it is not run instead
+	strictConfig, err := buflint.NewConfig(buflint.ExternalConfig{Strict: proto.Bool(true)})
+	require.NoError(t, err)
+	_, err = buflint.NewHandler(zap.NewNop()).Check(context.Background(), strictConfig, image)
+	assert.NoError(t, err)
+}
+
 func TestRunFileLowerSnakeCase(t *testing.T) {
 	testLint(
 		t,
@@ -354,6 +597,96 @@ func TestRunFileLowerSnakeCase(t *testing.T) {
 	)
 }
 
+func TestRunFileLowerSnakeCaseFileNameRegex(t *testing.T) {
+	testLintExternalConfigModifier(
+		t,
+		"file_lower_snake_case",
+		func(externalConfig *bufconfig.ExternalConfig) {
+			externalConfig.Lint.FileNameRegex = "^[a-z]+$"
+		},
+		bufanalysistesting.NewFileAnnotationNoLocation(t, "1.proto", "FILE_LOWER_SNAKE_CASE"),
+		bufanalysistesting.NewFileAnnotationNoLocation(t, "B.proto", "FILE_LOWER_SNAKE_CASE"),
+		bufanalysistesting.NewFileAnnotationNoLocation(t, "Foo.proto", "FILE_LOWER_SNAKE_CASE"),
+		bufanalysistesting.NewFileAnnotationNoLocation(t, "aBc.proto", "FILE_LOWER_SNAKE_CASE"),
+		bufanalysistesting.NewFileAnnotationNoLocation(t, "ab_c.proto", "FILE_LOWER_SNAKE_CASE"),
+		bufanalysistesting.NewFileAnnotationNoLocation(t, "ab_c_.proto", "FILE_LOWER_SNAKE_CASE"),
+		bufanalysistesting.NewFileAnnotationNoLocation(t, "fooBar.proto", "FILE_LOWER_SNAKE_CASE"),
+	)
+}
+
+func TestRunImportSorted(t *testing.T) {
+	testLint(
+		t,
+		"import_sorted",
+		bufanalysistesting.NewFileAnnotation(t, "b.proto", 6, 1, 6, 41, "IMPORT_SORTED"),
+		bufanalysistesting.NewFileAnnotation(t, "c.proto", 6, 1, 6, 22, "IMPORT_SORTED"),
+	)
+}
+
+func TestRunImportSortedGroups(t *testing.T) {
+	testLintExternalConfigModifier(
+		t,
+		"import_sorted",
+		func(externalConfig *bufconfig.ExternalConfig) {
+			externalConfig.Lint.ImportSortedGroups = []string{"google/", "", "acme/"}
+		},
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 6, 1, 6, 22, "IMPORT_SORTED"),
+		bufanalysistesting.NewFileAnnotation(t, "b.proto", 6, 1, 6, 41, "IMPORT_SORTED"),
+	)
+}
+
+func TestRunMapValueNoMapWrapper(t *testing.T) {
+	testLint(
+		t,
+		"map_value_no_map_wrapper",
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 19, 3, 19, 33, "MAP_VALUE_NO_MAP_WRAPPER"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 21, 3, 21, 37, "MAP_VALUE_NO_MAP_WRAPPER"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 27, 5, 27, 34, "MAP_VALUE_NO_MAP_WRAPPER"),
+	)
+}
+
+func TestRunMapValueNoMapWrapperAllowed(t *testing.T) {
+	testLintExternalConfigModifier(
+		t,
+		"map_value_no_map_wrapper",
+		func(externalConfig *bufconfig.ExternalConfig) {
+			externalConfig.Lint.AllowMapWrapperTypes = []string{".a.Allowed"}
+		},
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 19, 3, 19, 33, "MAP_VALUE_NO_MAP_WRAPPER"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 27, 5, 27, 34, "MAP_VALUE_NO_MAP_WRAPPER"),
+	)
+}
+
+func TestRunMessageFieldNumbersDense(t *testing.T) {
+	testLint(
+		t,
+		"message_field_numbers_dense",
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 12, 9, 12, 15, "MESSAGE_FIELD_NUMBERS_DENSE"),
+	)
+}
+
+func TestRunMessageFieldNumbersDenseFactor(t *testing.T) {
+	testLintExternalConfigModifier(
+		t,
+		"message_field_numbers_dense",
+		func(externalConfig *bufconfig.ExternalConfig) {
+			externalConfig.Lint.MessageFieldNumbersDenseFactor = 1000
+		},
+	)
+}
+
+func TestRunMessageFieldNumbersDenseFactorOne(t *testing.T) {
+	testLintExternalConfigModifier(
+		t,
+		"message_field_numbers_dense",
+		func(externalConfig *bufconfig.ExternalConfig) {
+			externalConfig.Lint.MessageFieldNumbersDenseFactor = 1
+		},
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 5, 9, 5, 14, "MESSAGE_FIELD_NUMBERS_DENSE"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 12, 9, 12, 15, "MESSAGE_FIELD_NUMBERS_DENSE"),
+	)
+}
+
 func TestRunMessagePascalCase(t *testing.T) {
 	testLint(
 		t,
@@ -371,107 +704,316 @@ func TestRunMessagePascalCase(t *testing.T) {
 	)
 }
 
-func TestRunOneofLowerSnakeCase(t *testing.T) {
+func TestRunFieldBoolAffirmative(t *testing.T) {
 	testLint(
 		t,
-		"oneof_lower_snake_case",
-		bufanalysistesting.NewFileAnnotation(t, "a.proto", 12, 9, 12, 13, "ONEOF_LOWER_SNAKE_CASE"),
-		bufanalysistesting.NewFileAnnotation(t, "a.proto", 15, 9, 15, 16, "ONEOF_LOWER_SNAKE_CASE"),
-		bufanalysistesting.NewFileAnnotation(t, "a.proto", 18, 9, 18, 18, "ONEOF_LOWER_SNAKE_CASE"),
-		bufanalysistesting.NewFileAnnotation(t, "a.proto", 21, 9, 21, 19, "ONEOF_LOWER_SNAKE_CASE"),
-		bufanalysistesting.NewFileAnnotation(t, "a.proto", 24, 9, 24, 19, "ONEOF_LOWER_SNAKE_CASE"),
-		bufanalysistesting.NewFileAnnotation(t, "a.proto", 38, 13, 38, 17, "ONEOF_LOWER_SNAKE_CASE"),
-		bufanalysistesting.NewFileAnnotation(t, "a.proto", 41, 13, 41, 20, "ONEOF_LOWER_SNAKE_CASE"),
-		bufanalysistesting.NewFileAnnotation(t, "a.proto", 44, 13, 44, 22, "ONEOF_LOWER_SNAKE_CASE"),
-		bufanalysistesting.NewFileAnnotation(t, "a.proto", 47, 13, 47, 23, "ONEOF_LOWER_SNAKE_CASE"),
-		bufanalysistesting.NewFileAnnotation(t, "a.proto", 50, 13, 50, 23, "ONEOF_LOWER_SNAKE_CASE"),
-		bufanalysistesting.NewFileAnnotation(t, "a.proto", 60, 11, 60, 15, "ONEOF_LOWER_SNAKE_CASE"),
-		bufanalysistesting.NewFileAnnotation(t, "a.proto", 63, 11, 63, 18, "ONEOF_LOWER_SNAKE_CASE"),
-		bufanalysistesting.NewFileAnnotation(t, "a.proto", 66, 11, 66, 20, "ONEOF_LOWER_SNAKE_CASE"),
-		bufanalysistesting.NewFileAnnotation(t, "a.proto", 69, 11, 69, 21, "ONEOF_LOWER_SNAKE_CASE"),
-		bufanalysistesting.NewFileAnnotation(t, "a.proto", 72, 11, 72, 21, "ONEOF_LOWER_SNAKE_CASE"),
+		"field_bool_affirmative",
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 6, 8, 6, 16, "FIELD_BOOL_AFFIRMATIVE"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 7, 8, 7, 17, "FIELD_BOOL_AFFIRMATIVE"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 8, 8, 8, 16, "FIELD_BOOL_AFFIRMATIVE"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 9, 8, 9, 23, "FIELD_BOOL_AFFIRMATIVE"),
 	)
 }
 
-func TestRunPackageDefined(t *testing.T) {
-	testLint(
+func TestRunFieldBoolAffirmativeNegativePrefixes(t *testing.T) {
+	testLintExternalConfigModifier(
 		t,
-		"package_defined",
-		bufanalysistesting.NewFileAnnotationNoLocation(t, "a/no_package.proto", "PACKAGE_DEFINED"),
-		bufanalysistesting.NewFileAnnotationNoLocation(t, "no_package.proto", "PACKAGE_DEFINED"),
+		"field_bool_affirmative",
+		func(externalConfig *bufconfig.ExternalConfig) {
+			externalConfig.Lint.FieldBoolNegativePrefixes = []string{"skip_"}
+		},
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 10, 8, 10, 23, "FIELD_BOOL_AFFIRMATIVE"),
 	)
 }
 
-func TestRunPackageDirectoryMatch(t *testing.T) {
+func TestRunFieldJSONNameNoConflict(t *testing.T) {
 	testLint(
 		t,
-		"package_directory_match",
-		bufanalysistesting.NewFileAnnotation(t, "a/b/a_c.proto", 3, 1, 3, 13, "PACKAGE_DIRECTORY_MATCH"),
-		bufanalysistesting.NewFileAnnotation(t, "sub/a/b/a_b.proto", 3, 1, 3, 13, "PACKAGE_DIRECTORY_MATCH"),
+		"field_json_name_no_conflict",
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 7, 9, 7, 15, "FIELD_JSON_NAME_NO_CONFLICT"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 12, 18, 12, 38, "FIELD_JSON_NAME_NO_CONFLICT"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 17, 9, 17, 12, "FIELD_JSON_NAME_NO_CONFLICT"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 22, 18, 22, 40, "FIELD_JSON_NAME_NO_CONFLICT"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 27, 9, 27, 12, "FIELD_JSON_NAME_NO_CONFLICT"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 31, 26, 31, 46, "FIELD_JSON_NAME_NO_CONFLICT"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 40, 18, 40, 35, "FIELD_JSON_NAME_NO_CONFLICT"),
 	)
 }
 
-func TestRunPackageLowerSnakeCase(t *testing.T) {
+func TestRunNameMaxLength(t *testing.T) {
 	testLint(
 		t,
-		"package_lower_snake_case",
-		bufanalysistesting.NewFileAnnotation(t, "5.proto", 3, 1, 3, 19, "PACKAGE_LOWER_SNAKE_CASE"),
-		bufanalysistesting.NewFileAnnotation(t, "6.proto", 3, 1, 3, 19, "PACKAGE_LOWER_SNAKE_CASE"),
-		bufanalysistesting.NewFileAnnotation(t, "7.proto", 3, 1, 3, 18, "PACKAGE_LOWER_SNAKE_CASE"),
-		bufanalysistesting.NewFileAnnotation(t, "8.proto", 3, 1, 3, 20, "PACKAGE_LOWER_SNAKE_CASE"),
+		"name_max_length",
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 7, 10, 7, 21, "NAME_MAX_LENGTH"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 11, 9, 11, 20, "NAME_MAX_LENGTH"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 17, 3, 17, 14, "NAME_MAX_LENGTH"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 20, 6, 20, 17, "NAME_MAX_LENGTH"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 25, 7, 25, 18, "NAME_MAX_LENGTH"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 29, 9, 29, 20, "NAME_MAX_LENGTH"),
 	)
 }
 
-func TestRunPackageSameDirectory(t *testing.T) {
-	testLint(
+func TestRunNameMaxLengthConfigured(t *testing.T) {
+	testLintExternalConfigModifier(
 		t,
-		"package_same_directory",
-		bufanalysistesting.NewFileAnnotation(t, "a.proto", 3, 1, 3, 11, "PACKAGE_SAME_DIRECTORY"),
-		bufanalysistesting.NewFileAnnotation(t, "one/a.proto", 3, 1, 3, 11, "PACKAGE_SAME_DIRECTORY"),
+		"name_max_length",
+		func(externalConfig *bufconfig.ExternalConfig) {
+			externalConfig.Lint.NameMaxLength = 11
+		},
 	)
 }
 
-func TestRunPackageSameDirectoryNoPackage(t *testing.T) {
+func TestRunNameNoLeadingUnderscore(t *testing.T) {
 	testLint(
 		t,
-		"package_same_directory_no_package",
-		bufanalysistesting.NewFileAnnotationNoLocation(t, "no_package.proto", "PACKAGE_SAME_DIRECTORY"),
-		bufanalysistesting.NewFileAnnotationNoLocation(t, "one/no_package.proto", "PACKAGE_SAME_DIRECTORY"),
+		"name_no_leading_underscore",
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 5, 9, 5, 13, "NAME_NO_LEADING_UNDERSCORE"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 6, 9, 6, 13, "NAME_NO_LEADING_UNDERSCORE"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 8, 11, 8, 18, "NAME_NO_LEADING_UNDERSCORE"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 9, 11, 9, 15, "NAME_NO_LEADING_UNDERSCORE"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 11, 8, 11, 19, "NAME_NO_LEADING_UNDERSCORE"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 14, 22, 14, 26, "NAME_NO_LEADING_UNDERSCORE"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 17, 6, 17, 10, "NAME_NO_LEADING_UNDERSCORE"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 25, 9, 25, 20, "NAME_NO_LEADING_UNDERSCORE"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 26, 7, 26, 11, "NAME_NO_LEADING_UNDERSCORE"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 31, 7, 31, 14, "NAME_NO_LEADING_UNDERSCORE"),
 	)
 }
 
-func TestRunPackageSameOptionValue(t *testing.T) {
+func TestRunNameNoReservedWord(t *testing.T) {
 	testLint(
 		t,
-		"package_same_option_value",
-		bufanalysistesting.NewFileAnnotation(t, "a.proto", 5, 1, 5, 33, "PACKAGE_SAME_CSHARP_NAMESPACE"),
-		bufanalysistesting.NewFileAnnotation(t, "a.proto", 6, 1, 6, 35, "PACKAGE_SAME_JAVA_MULTIPLE_FILES"),
-		bufanalysistesting.NewFileAnnotation(t, "a.proto", 7, 1, 7, 29, "PACKAGE_SAME_JAVA_PACKAGE"),
-		bufanalysistesting.NewFileAnnotation(t, "a.proto", 8, 1, 8, 27, "PACKAGE_SAME_GO_PACKAGE"),
-		bufanalysistesting.NewFileAnnotation(t, "a.proto", 9, 1, 9, 30, "PACKAGE_SAME_PHP_NAMESPACE"),
-		bufanalysistesting.NewFileAnnotation(t, "a.proto", 10, 1, 10, 29, "PACKAGE_SAME_RUBY_PACKAGE"),
-		bufanalysistesting.NewFileAnnotation(t, "a.proto", 11, 1, 11, 29, "PACKAGE_SAME_SWIFT_PREFIX"),
-		bufanalysistesting.NewFileAnnotation(t, "b.proto", 5, 1, 5, 33, "PACKAGE_SAME_CSHARP_NAMESPACE"),
-		bufanalysistesting.NewFileAnnotation(t, "b.proto", 6, 1, 6, 36, "PACKAGE_SAME_JAVA_MULTIPLE_FILES"),
-		bufanalysistesting.NewFileAnnotation(t, "b.proto", 7, 1, 7, 29, "PACKAGE_SAME_JAVA_PACKAGE"),
-		bufanalysistesting.NewFileAnnotation(t, "b.proto", 8, 1, 8, 27, "PACKAGE_SAME_GO_PACKAGE"),
-		bufanalysistesting.NewFileAnnotation(t, "b.proto", 9, 1, 9, 30, "PACKAGE_SAME_PHP_NAMESPACE"),
-		bufanalysistesting.NewFileAnnotation(t, "b.proto", 10, 1, 10, 29, "PACKAGE_SAME_RUBY_PACKAGE"),
-		bufanalysistesting.NewFileAnnotation(t, "b.proto", 11, 1, 11, 29, "PACKAGE_SAME_SWIFT_PREFIX"),
-		bufanalysistesting.NewFileAnnotation(t, "sub/a.proto", 6, 1, 6, 33, "PACKAGE_SAME_CSHARP_NAMESPACE"),
-		bufanalysistesting.NewFileAnnotation(t, "sub/a.proto", 7, 1, 7, 35, "PACKAGE_SAME_JAVA_MULTIPLE_FILES"),
-		bufanalysistesting.NewFileAnnotation(t, "sub/a.proto", 8, 1, 8, 29, "PACKAGE_SAME_JAVA_PACKAGE"),
-		bufanalysistesting.NewFileAnnotation(t, "sub/a.proto", 9, 1, 9, 27, "PACKAGE_SAME_GO_PACKAGE"),
-		bufanalysistesting.NewFileAnnotation(t, "sub/a.proto", 10, 1, 10, 30, "PACKAGE_SAME_PHP_NAMESPACE"),
-		bufanalysistesting.NewFileAnnotation(t, "sub/a.proto", 11, 1, 11, 29, "PACKAGE_SAME_RUBY_PACKAGE"),
-		bufanalysistesting.NewFileAnnotation(t, "sub/a.proto", 12, 1, 12, 29, "PACKAGE_SAME_SWIFT_PREFIX"),
-		bufanalysistesting.NewFileAnnotationNoLocation(t, "sub/b.proto", "PACKAGE_SAME_CSHARP_NAMESPACE"),
-		bufanalysistesting.NewFileAnnotationNoLocation(t, "sub/b.proto", "PACKAGE_SAME_GO_PACKAGE"),
-		bufanalysistesting.NewFileAnnotationNoLocation(t, "sub/b.proto", "PACKAGE_SAME_JAVA_MULTIPLE_FILES"),
-		bufanalysistesting.NewFileAnnotationNoLocation(t, "sub/b.proto", "PACKAGE_SAME_JAVA_PACKAGE"),
-		bufanalysistesting.NewFileAnnotationNoLocation(t, "sub/b.proto", "PACKAGE_SAME_PHP_NAMESPACE"),
-		bufanalysistesting.NewFileAnnotationNoLocation(t, "sub/b.proto", "PACKAGE_SAME_RUBY_PACKAGE"),
-		bufanalysistesting.NewFileAnnotationNoLocation(t, "sub/b.proto", "PACKAGE_SAME_SWIFT_PREFIX"),
+		"name_no_reserved_word",
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 6, 10, 6, 13, "NAME_NO_RESERVED_WORD"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 7, 10, 7, 14, "NAME_NO_RESERVED_WORD"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 8, 10, 8, 22, "NAME_NO_RESERVED_WORD"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 9, 10, 9, 16, "NAME_NO_RESERVED_WORD"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 15, 11, 15, 16, "NAME_NO_RESERVED_WORD"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 18, 6, 18, 10, "NAME_NO_RESERVED_WORD"),
+	)
+}
+
+func TestRunNameNoReservedWordLanguages(t *testing.T) {
+	// def and yield are only reserved in python, synchronized is only reserved in java
+	testLintExternalConfigModifier(
+		t,
+		"name_no_reserved_word",
+		func(externalConfig *bufconfig.ExternalConfig) {
+			externalConfig.Lint.ReservedWordLanguages = []string{"go"}
+		},
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 7, 10, 7, 14, "NAME_NO_RESERVED_WORD"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 9, 10, 9, 16, "NAME_NO_RESERVED_WORD"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 18, 6, 18, 10, "NAME_NO_RESERVED_WORD"),
+	)
+}
+
+func TestRunNestedTypeReferenced(t *testing.T) {
+	testLint(
+		t,
+		"nested_type_referenced",
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 7, 3, 7, 20, "NESTED_TYPE_REFERENCED"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 8, 3, 10, 4, "NESTED_TYPE_REFERENCED"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 14, 3, 16, 4, "NESTED_TYPE_REFERENCED"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 17, 3, 17, 27, "NESTED_TYPE_REFERENCED"),
+	)
+}
+
+func TestRunNestedTypeReferencedExtension(t *testing.T) {
+	testLint(
+		t,
+		"nested_type_referenced_extension",
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 10, 3, 10, 20, "NESTED_TYPE_REFERENCED"),
+	)
+}
+
+func TestRunNestedTypeReferencedImageScope(t *testing.T) {
+	testLintExternalConfigModifier(
+		t,
+		"nested_type_referenced",
+		func(externalConfig *bufconfig.ExternalConfig) {
+			externalConfig.Lint.NestedTypeReferencedScope = "image"
+		},
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 7, 3, 7, 20, "NESTED_TYPE_REFERENCED"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 8, 3, 10, 4, "NESTED_TYPE_REFERENCED"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 14, 3, 16, 4, "NESTED_TYPE_REFERENCED"),
+	)
+}
+
+func TestRunFileAnnotationsFunc(t *testing.T) {
+	var paths []string
+	var streamedFileAnnotations []bufanalysis.FileAnnotation
+	testLintExternalConfigModifierHandlerOptions(
+		t,
+		"file_lower_snake_case",
+		nil,
+		[]buflint.HandlerOption{
+			buflint.HandlerWithFileAnnotationsFunc(
+				func(fileAnnotations []bufanalysis.FileAnnotation) error {
+					require.NotEmpty(t, fileAnnotations)
+					for _, fileAnnotation := range fileAnnotations {
+						require.Equal(t, fileAnnotations[0].FileInfo().Path(), fileAnnotation.FileInfo().Path())
+					}
+					paths = append(paths, fileAnnotations[0].FileInfo().Path())
+					streamedFileAnnotations = append(streamedFileAnnotations, fileAnnotations...)
+					return nil
+				},
+			),
+		},
+		bufanalysistesting.NewFileAnnotationNoLocation(t, "B.proto", "FILE_LOWER_SNAKE_CASE"),
+		bufanalysistesting.NewFileAnnotationNoLocation(t, "Foo.proto", "FILE_LOWER_SNAKE_CASE"),
+		bufanalysistesting.NewFileAnnotationNoLocation(t, "aBc.proto", "FILE_LOWER_SNAKE_CASE"),
+		bufanalysistesting.NewFileAnnotationNoLocation(t, "ab_c_.proto", "FILE_LOWER_SNAKE_CASE"),
+		bufanalysistesting.NewFileAnnotationNoLocation(t, "fooBar.proto", "FILE_LOWER_SNAKE_CASE"),
+	)
+	assert.Equal(t, []string{"B.proto", "Foo.proto", "aBc.proto", "ab_c_.proto", "fooBar.proto"}, paths)
+	bufanalysistesting.AssertFileAnnotationsEqual(
+		t,
+		[]bufanalysis.FileAnnotation{
+			bufanalysistesting.NewFileAnnotationNoLocation(t, "B.proto", "FILE_LOWER_SNAKE_CASE"),
+			bufanalysistesting.NewFileAnnotationNoLocation(t, "Foo.proto", "FILE_LOWER_SNAKE_CASE"),
+			bufanalysistesting.NewFileAnnotationNoLocation(t, "aBc.proto", "FILE_LOWER_SNAKE_CASE"),
+			bufanalysistesting.NewFileAnnotationNoLocation(t, "ab_c_.proto", "FILE_LOWER_SNAKE_CASE"),
+			bufanalysistesting.NewFileAnnotationNoLocation(t, "fooBar.proto", "FILE_LOWER_SNAKE_CASE"),
+		},
+		streamedFileAnnotations,
+	)
+}
+
+func TestRunOneofLowerSnakeCase(t *testing.T) {
+	testLint(
+		t,
+		"oneof_lower_snake_case",
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 12, 9, 12, 13, "ONEOF_LOWER_SNAKE_CASE"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 15, 9, 15, 16, "ONEOF_LOWER_SNAKE_CASE"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 18, 9, 18, 18, "ONEOF_LOWER_SNAKE_CASE"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 21, 9, 21, 19, "ONEOF_LOWER_SNAKE_CASE"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 24, 9, 24, 19, "ONEOF_LOWER_SNAKE_CASE"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 38, 13, 38, 17, "ONEOF_LOWER_SNAKE_CASE"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 41, 13, 41, 20, "ONEOF_LOWER_SNAKE_CASE"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 44, 13, 44, 22, "ONEOF_LOWER_SNAKE_CASE"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 47, 13, 47, 23, "ONEOF_LOWER_SNAKE_CASE"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 50, 13, 50, 23, "ONEOF_LOWER_SNAKE_CASE"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 60, 11, 60, 15, "ONEOF_LOWER_SNAKE_CASE"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 63, 11, 63, 18, "ONEOF_LOWER_SNAKE_CASE"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 66, 11, 66, 20, "ONEOF_LOWER_SNAKE_CASE"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 69, 11, 69, 21, "ONEOF_LOWER_SNAKE_CASE"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 72, 11, 72, 21, "ONEOF_LOWER_SNAKE_CASE"),
+	)
+}
+
+func TestRunPackageDefined(t *testing.T) {
+	testLint(
+		t,
+		"package_defined",
+		bufanalysistesting.NewFileAnnotationNoLocation(t, "a/no_package.proto", "PACKAGE_DEFINED"),
+		bufanalysistesting.NewFileAnnotationNoLocation(t, "no_package.proto", "PACKAGE_DEFINED"),
+	)
+}
+
+func TestRunPackageDefinedIgnoreOnly(t *testing.T) {
+	testLintExternalConfigModifier(
+		t,
+		"package_defined",
+		func(externalConfig *bufconfig.ExternalConfig) {
+			externalConfig.Lint.IgnoreOnly = map[string][]string{
+				"PACKAGE_DEFINED": {"a"},
+			}
+		},
+		bufanalysistesting.NewFileAnnotationNoLocation(t, "no_package.proto", "PACKAGE_DEFINED"),
+	)
+}
+
+func TestRunPackageDirectoryMatch(t *testing.T) {
+	testLint(
+		t,
+		"package_directory_match",
+		bufanalysistesting.NewFileAnnotation(t, "a/b/a_c.proto", 3, 1, 3, 13, "PACKAGE_DIRECTORY_MATCH"),
+		bufanalysistesting.NewFileAnnotation(t, "sub/a/b/a_b.proto", 3, 1, 3, 13, "PACKAGE_DIRECTORY_MATCH"),
+	)
+}
+
+func TestRunPackageFileMaxCount(t *testing.T) {
+	testLint(
+		t,
+		"package_file_max_count",
+		bufanalysistesting.NewFileAnnotation(t, "b/b1.proto", 3, 1, 3, 11, "PACKAGE_FILE_MAX_COUNT"),
+		bufanalysistesting.NewFileAnnotation(t, "b/b2.proto", 3, 1, 3, 11, "PACKAGE_FILE_MAX_COUNT"),
+		bufanalysistesting.NewFileAnnotation(t, "b/b3.proto", 3, 1, 3, 11, "PACKAGE_FILE_MAX_COUNT"),
+	)
+}
+
+func TestRunPackageFileMaxCountFirstFileOnly(t *testing.T) {
+	testLintExternalConfigModifier(
+		t,
+		"package_file_max_count",
+		func(externalConfig *bufconfig.ExternalConfig) {
+			externalConfig.Lint.PackageFileMaxCountFirstFileOnly = proto.Bool(true)
+		},
+		bufanalysistesting.NewFileAnnotation(t, "b/b1.proto", 3, 1, 3, 11, "PACKAGE_FILE_MAX_COUNT"),
+	)
+}
+
+func TestRunPackageLowerSnakeCase(t *testing.T) {
+	testLint(
+		t,
+		"package_lower_snake_case",
+		bufanalysistesting.NewFileAnnotation(t, "5.proto", 3, 1, 3, 19, "PACKAGE_LOWER_SNAKE_CASE"),
+		bufanalysistesting.NewFileAnnotation(t, "6.proto", 3, 1, 3, 19, "PACKAGE_LOWER_SNAKE_CASE"),
+		bufanalysistesting.NewFileAnnotation(t, "7.proto", 3, 1, 3, 18, "PACKAGE_LOWER_SNAKE_CASE"),
+		bufanalysistesting.NewFileAnnotation(t, "8.proto", 3, 1, 3, 20, "PACKAGE_LOWER_SNAKE_CASE"),
+	)
+}
+
+func TestRunPackageSameDirectory(t *testing.T) {
+	testLint(
+		t,
+		"package_same_directory",
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 3, 1, 3, 11, "PACKAGE_SAME_DIRECTORY"),
+		bufanalysistesting.NewFileAnnotation(t, "one/a.proto", 3, 1, 3, 11, "PACKAGE_SAME_DIRECTORY"),
+	)
+}
+
+func TestRunPackageSameDirectoryNoPackage(t *testing.T) {
+	testLint(
+		t,
+		"package_same_directory_no_package",
+		bufanalysistesting.NewFileAnnotationNoLocation(t, "no_package.proto", "PACKAGE_SAME_DIRECTORY"),
+		bufanalysistesting.NewFileAnnotationNoLocation(t, "one/no_package.proto", "PACKAGE_SAME_DIRECTORY"),
+	)
+}
+
+func TestRunPackageSameOptionValue(t *testing.T) {
+	testLint(
+		t,
+		"package_same_option_value",
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 5, 1, 5, 33, "PACKAGE_SAME_CSHARP_NAMESPACE"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 6, 1, 6, 35, "PACKAGE_SAME_JAVA_MULTIPLE_FILES"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 7, 1, 7, 29, "PACKAGE_SAME_JAVA_PACKAGE"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 8, 1, 8, 27, "PACKAGE_SAME_GO_PACKAGE"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 9, 1, 9, 30, "PACKAGE_SAME_PHP_NAMESPACE"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 10, 1, 10, 29, "PACKAGE_SAME_RUBY_PACKAGE"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 11, 1, 11, 29, "PACKAGE_SAME_SWIFT_PREFIX"),
+		bufanalysistesting.NewFileAnnotation(t, "b.proto", 5, 1, 5, 33, "PACKAGE_SAME_CSHARP_NAMESPACE"),
+		bufanalysistesting.NewFileAnnotation(t, "b.proto", 6, 1, 6, 36, "PACKAGE_SAME_JAVA_MULTIPLE_FILES"),
+		bufanalysistesting.NewFileAnnotation(t, "b.proto", 7, 1, 7, 29, "PACKAGE_SAME_JAVA_PACKAGE"),
+		bufanalysistesting.NewFileAnnotation(t, "b.proto", 8, 1, 8, 27, "PACKAGE_SAME_GO_PACKAGE"),
+		bufanalysistesting.NewFileAnnotation(t, "b.proto", 9, 1, 9, 30, "PACKAGE_SAME_PHP_NAMESPACE"),
+		bufanalysistesting.NewFileAnnotation(t, "b.proto", 10, 1, 10, 29, "PACKAGE_SAME_RUBY_PACKAGE"),
+		bufanalysistesting.NewFileAnnotation(t, "b.proto", 11, 1, 11, 29, "PACKAGE_SAME_SWIFT_PREFIX"),
+		bufanalysistesting.NewFileAnnotation(t, "sub/a.proto", 6, 1, 6, 33, "PACKAGE_SAME_CSHARP_NAMESPACE"),
+		bufanalysistesting.NewFileAnnotation(t, "sub/a.proto", 7, 1, 7, 35, "PACKAGE_SAME_JAVA_MULTIPLE_FILES"),
+		bufanalysistesting.NewFileAnnotation(t, "sub/a.proto", 8, 1, 8, 29, "PACKAGE_SAME_JAVA_PACKAGE"),
+		bufanalysistesting.NewFileAnnotation(t, "sub/a.proto", 9, 1, 9, 27, "PACKAGE_SAME_GO_PACKAGE"),
+		bufanalysistesting.NewFileAnnotation(t, "sub/a.proto", 10, 1, 10, 30, "PACKAGE_SAME_PHP_NAMESPACE"),
+		bufanalysistesting.NewFileAnnotation(t, "sub/a.proto", 11, 1, 11, 29, "PACKAGE_SAME_RUBY_PACKAGE"),
+		bufanalysistesting.NewFileAnnotation(t, "sub/a.proto", 12, 1, 12, 29, "PACKAGE_SAME_SWIFT_PREFIX"),
+		bufanalysistesting.NewFileAnnotationNoLocation(t, "sub/b.proto", "PACKAGE_SAME_CSHARP_NAMESPACE"),
+		bufanalysistesting.NewFileAnnotationNoLocation(t, "sub/b.proto", "PACKAGE_SAME_GO_PACKAGE"),
+		bufanalysistesting.NewFileAnnotationNoLocation(t, "sub/b.proto", "PACKAGE_SAME_JAVA_MULTIPLE_FILES"),
+		bufanalysistesting.NewFileAnnotationNoLocation(t, "sub/b.proto", "PACKAGE_SAME_JAVA_PACKAGE"),
+		bufanalysistesting.NewFileAnnotationNoLocation(t, "sub/b.proto", "PACKAGE_SAME_PHP_NAMESPACE"),
+		bufanalysistesting.NewFileAnnotationNoLocation(t, "sub/b.proto", "PACKAGE_SAME_RUBY_PACKAGE"),
+		bufanalysistesting.NewFileAnnotationNoLocation(t, "sub/b.proto", "PACKAGE_SAME_SWIFT_PREFIX"),
 	)
 }
 
@@ -489,6 +1031,40 @@ func TestRunPackageVersionSuffix(t *testing.T) {
 	)
 }
 
+func TestRunRPCIdempotencyLevelSpecified(t *testing.T) {
+	testLint(
+		t,
+		"rpc_idempotency_level_specified",
+		bufanalysistesting.NewFileAnnotation(t, "a/a.proto", 15, 7, 15, 13, "RPC_IDEMPOTENCY_LEVEL_SPECIFIED"),
+		bufanalysistesting.NewFileAnnotation(t, "a/a.proto", 16, 7, 16, 13, "RPC_IDEMPOTENCY_LEVEL_SPECIFIED"),
+		bufanalysistesting.NewFileAnnotation(t, "a/a.proto", 22, 7, 22, 13, "RPC_IDEMPOTENCY_LEVEL_SPECIFIED"),
+		bufanalysistesting.NewFileAnnotation(t, "b/b.proto", 8, 7, 8, 13, "RPC_IDEMPOTENCY_LEVEL_SPECIFIED"),
+	)
+}
+
+func TestRunRPCIdempotencyLevelSpecifiedServices(t *testing.T) {
+	testLintExternalConfigModifier(
+		t,
+		"rpc_idempotency_level_specified",
+		func(externalConfig *bufconfig.ExternalConfig) {
+			externalConfig.Lint.RPCIdempotencyLevelServices = []string{".a.FooService"}
+		},
+		bufanalysistesting.NewFileAnnotation(t, "a/a.proto", 15, 7, 15, 13, "RPC_IDEMPOTENCY_LEVEL_SPECIFIED"),
+		bufanalysistesting.NewFileAnnotation(t, "a/a.proto", 16, 7, 16, 13, "RPC_IDEMPOTENCY_LEVEL_SPECIFIED"),
+	)
+}
+
+func TestRunRPCIdempotencyLevelSpecifiedPackages(t *testing.T) {
+	testLintExternalConfigModifier(
+		t,
+		"rpc_idempotency_level_specified",
+		func(externalConfig *bufconfig.ExternalConfig) {
+			externalConfig.Lint.RPCIdempotencyLevelPackages = []string{"b"}
+		},
+		bufanalysistesting.NewFileAnnotation(t, "b/b.proto", 8, 7, 8, 13, "RPC_IDEMPOTENCY_LEVEL_SPECIFIED"),
+	)
+}
+
 func TestRunRPCNoStreaming(t *testing.T) {
 	testLint(
 		t,
@@ -500,6 +1076,30 @@ func TestRunRPCNoStreaming(t *testing.T) {
 	)
 }
 
+func TestRunRPCPaginationFields(t *testing.T) {
+	testLint(
+		t,
+		"rpc_pagination_fields",
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 33, 16, 33, 31, "RPC_PAGINATION_FIELDS"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 33, 42, 33, 58, "RPC_PAGINATION_FIELDS"),
+	)
+}
+
+func TestRunRPCPaginationFieldsMethodRegex(t *testing.T) {
+	testLintExternalConfigModifier(
+		t,
+		"rpc_pagination_fields",
+		func(externalConfig *bufconfig.ExternalConfig) {
+			externalConfig.Lint.RPCPaginationMethodRegex = "^(List|Search)"
+		},
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 33, 16, 33, 31, "RPC_PAGINATION_FIELDS"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 33, 42, 33, 58, "RPC_PAGINATION_FIELDS"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 35, 18, 35, 35, "RPC_PAGINATION_FIELDS"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 35, 18, 35, 35, "RPC_PAGINATION_FIELDS"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 35, 46, 35, 64, "RPC_PAGINATION_FIELDS"),
+	)
+}
+
 func TestRunRPCPascalCase(t *testing.T) {
 	testLint(
 		t,
@@ -622,6 +1222,51 @@ func TestRunRPCRequestResponseUniqueAllowSameAndEmptyRequestResponses(t *testing
 	)
 }
 
+func TestRunRPCRequiredOption(t *testing.T) {
+	testLint(
+		t,
+		"rpc_required_option",
+		bufanalysistesting.NewFileAnnotation(t, "a/a.proto", 24, 7, 24, 11, "RPC_REQUIRED_OPTION"),
+		bufanalysistesting.NewFileAnnotation(t, "a/a.proto", 25, 7, 25, 14, "RPC_REQUIRED_OPTION"),
+		bufanalysistesting.NewFileAnnotation(t, "b/b.proto", 8, 7, 8, 11, "RPC_REQUIRED_OPTION"),
+	)
+}
+
+func TestRunRPCRequiredOptionServices(t *testing.T) {
+	testLintExternalConfigModifier(
+		t,
+		"rpc_required_option",
+		func(externalConfig *bufconfig.ExternalConfig) {
+			externalConfig.Lint.RPCRequiredOptionServices = []string{"a.SuccessService", "a.FailService"}
+		},
+		bufanalysistesting.NewFileAnnotation(t, "a/a.proto", 24, 7, 24, 11, "RPC_REQUIRED_OPTION"),
+		bufanalysistesting.NewFileAnnotation(t, "a/a.proto", 25, 7, 25, 14, "RPC_REQUIRED_OPTION"),
+	)
+}
+
+func TestRunRPCRequiredOptionServicesLeadingDot(t *testing.T) {
+	testLintExternalConfigModifier(
+		t,
+		"rpc_required_option",
+		func(externalConfig *bufconfig.ExternalConfig) {
+			externalConfig.Lint.RPCRequiredOptionServices = []string{".a.SuccessService", ".a.FailService"}
+		},
+		bufanalysistesting.NewFileAnnotation(t, "a/a.proto", 24, 7, 24, 11, "RPC_REQUIRED_OPTION"),
+		bufanalysistesting.NewFileAnnotation(t, "a/a.proto", 25, 7, 25, 14, "RPC_REQUIRED_OPTION"),
+	)
+}
+
+func TestRunRPCRequiredOptionPackages(t *testing.T) {
+	testLintExternalConfigModifier(
+		t,
+		"rpc_required_option",
+		func(externalConfig *bufconfig.ExternalConfig) {
+			externalConfig.Lint.RPCRequiredOptionPackages = []string{"b"}
+		},
+		bufanalysistesting.NewFileAnnotation(t, "b/b.proto", 8, 7, 8, 11, "RPC_REQUIRED_OPTION"),
+	)
+}
+
 func TestRunRPCStandardName(t *testing.T) {
 	testLint(
 		t,
@@ -642,6 +1287,26 @@ func TestRunRPCStandardNameAllowEmpty(t *testing.T) {
 	)
 }
 
+func TestRunServiceNoEmpty(t *testing.T) {
+	testLint(
+		t,
+		"service_no_empty",
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 7, 9, 7, 14, "SERVICE_NO_EMPTY"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 9, 9, 9, 20, "SERVICE_NO_EMPTY"),
+	)
+}
+
+func TestRunServiceNoEmptyAllowEmptyServices(t *testing.T) {
+	testLintExternalConfigModifier(
+		t,
+		"service_no_empty",
+		func(externalConfig *bufconfig.ExternalConfig) {
+			externalConfig.Lint.AllowEmptyServices = []string{".a.Placeholder"}
+		},
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 7, 9, 7, 14, "SERVICE_NO_EMPTY"),
+	)
+}
+
 func TestRunServicePascalCase(t *testing.T) {
 	testLint(
 		t,
@@ -653,6 +1318,26 @@ func TestRunServicePascalCase(t *testing.T) {
 	)
 }
 
+func TestRunServiceStreamingConsistent(t *testing.T) {
+	testLint(
+		t,
+		"service_streaming_consistent",
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 23, 9, 23, 14, "SERVICE_STREAMING_CONSISTENT"),
+	)
+}
+
+func TestRunServiceStreamingConsistentDirection(t *testing.T) {
+	testLintExternalConfigModifier(
+		t,
+		"service_streaming_consistent",
+		func(externalConfig *bufconfig.ExternalConfig) {
+			externalConfig.Lint.ServiceStreamingConsistentDirection = proto.Bool(true)
+		},
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 12, 9, 12, 21, "SERVICE_STREAMING_CONSISTENT"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 23, 9, 23, 14, "SERVICE_STREAMING_CONSISTENT"),
+	)
+}
+
 func TestRunServiceSuffix(t *testing.T) {
 	testLint(
 		t,
@@ -674,13 +1359,180 @@ func TestRunServiceSuffixCustom(t *testing.T) {
 	)
 }
 
-func TestRunIgnores1(t *testing.T) {
+func TestRunServiceSuffixEmpty(t *testing.T) {
+	// an explicitly empty suffix disables SERVICE_SUFFIX
 	testLint(
 		t,
-		"ignores",
-		bufanalysistesting.NewFileAnnotation(t, "buf/bar/bar.proto", 6, 9, 6, 15, "FIELD_LOWER_SNAKE_CASE"),
-		bufanalysistesting.NewFileAnnotation(t, "buf/bar/bar.proto", 9, 9, 9, 12, "MESSAGE_PASCAL_CASE"),
-		bufanalysistesting.NewFileAnnotation(t, "buf/bar/bar.proto", 13, 6, 13, 9, "ENUM_PASCAL_CASE"),
+		"service_suffix_empty",
+	)
+}
+
+func TestRunServiceSuffixExcept(t *testing.T) {
+	testLintExternalConfigModifier(
+		t,
+		"service_suffix",
+		func(externalConfig *bufconfig.ExternalConfig) {
+			externalConfig.Lint.Use = []string{"SERVICE_PASCAL_CASE", "SERVICE_SUFFIX"}
+			externalConfig.Lint.Except = []string{"SERVICE_SUFFIX"}
+		},
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 7, 9, 7, 29, "SERVICE_PASCAL_CASE"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 10, 9, 10, 21, "SERVICE_PASCAL_CASE"),
+	)
+}
+
+func TestRunServiceSuffixCommentIgnores(t *testing.T) {
+	testLint(
+		t,
+		"service_suffix_ignores",
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 8, 9, 8, 13, "SERVICE_SUFFIX"),
+	)
+}
+
+func TestRunTypeNamePackageMaxCount(t *testing.T) {
+	testLint(
+		t,
+		"type_name_package_max_count",
+		bufanalysistesting.NewFileAnnotation(t, "a/a.proto", 5, 9, 5, 15, "TYPE_NAME_PACKAGE_MAX_COUNT"),
+		bufanalysistesting.NewFileAnnotation(t, "a/a.proto", 11, 6, 11, 12, "TYPE_NAME_PACKAGE_MAX_COUNT"),
+		bufanalysistesting.NewFileAnnotation(t, "b/b.proto", 5, 9, 5, 15, "TYPE_NAME_PACKAGE_MAX_COUNT"),
+		bufanalysistesting.NewFileAnnotation(t, "b/b.proto", 9, 9, 9, 15, "TYPE_NAME_PACKAGE_MAX_COUNT"),
+		bufanalysistesting.NewFileAnnotation(t, "c/c.proto", 5, 9, 5, 15, "TYPE_NAME_PACKAGE_MAX_COUNT"),
+	)
+}
+
+func TestRunTypeNamePackageMaxCountTwo(t *testing.T) {
+	testLintExternalConfigModifier(
+		t,
+		"type_name_package_max_count",
+		func(externalConfig *bufconfig.ExternalConfig) {
+			externalConfig.Lint.TypeNamePackageMaxCount = 2
+		},
+		bufanalysistesting.NewFileAnnotation(t, "a/a.proto", 5, 9, 5, 15, "TYPE_NAME_PACKAGE_MAX_COUNT"),
+		bufanalysistesting.NewFileAnnotation(t, "b/b.proto", 5, 9, 5, 15, "TYPE_NAME_PACKAGE_MAX_COUNT"),
+		bufanalysistesting.NewFileAnnotation(t, "c/c.proto", 5, 9, 5, 15, "TYPE_NAME_PACKAGE_MAX_COUNT"),
+	)
+}
+
+func TestRunTypeNoEmpty(t *testing.T) {
+	testLint(
+		t,
+		"type_no_empty",
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 21, 9, 21, 13, "TYPE_NO_EMPTY"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 23, 9, 23, 19, "TYPE_NO_EMPTY"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 25, 11, 25, 21, "TYPE_NO_EMPTY"),
+	)
+}
+
+func TestRunTypeNoEmptyNoAllow(t *testing.T) {
+	testLintExternalConfigModifier(
+		t,
+		"type_no_empty",
+		func(externalConfig *bufconfig.ExternalConfig) {
+			externalConfig.Lint.AllowEmptyTypes = nil
+		},
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 10, 11, 10, 18, "TYPE_NO_EMPTY"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 19, 9, 19, 16, "TYPE_NO_EMPTY"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 21, 9, 21, 13, "TYPE_NO_EMPTY"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 23, 9, 23, 19, "TYPE_NO_EMPTY"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 25, 11, 25, 21, "TYPE_NO_EMPTY"),
+	)
+}
+
+func TestRunFileAnnotationsFuncStreamed(t *testing.T) {
+	// the FileAnnotations of the first file are streamed before the last file is checked
+	var lock sync.Mutex
+	var events []string
+	customChecker, err := buflint.NewCustomChecker(
+		"FILE_CHECKED",
+		"files are checked",
+		func(_ buflint.AddFunc, file protosource.File) error {
+			lock.Lock()
+			defer lock.Unlock()
+			events = append(events, "check "+file.Path())
+			return nil
+		},
+	)
+	require.NoError(t, err)
+	testLintExternalConfigModifierHandlerOptions(
+		t,
+		"file_lower_snake_case",
+		nil,
+		[]buflint.HandlerOption{
+			buflint.HandlerWithCustomCheckers(customChecker),
+			buflint.HandlerWithFileAnnotationsFunc(
+				func(fileAnnotations []bufanalysis.FileAnnotation) error {
+					lock.Lock()
+					defer lock.Unlock()
+					events = append(events, "stream "+fileAnnotations[0].FileInfo().Path())
+					return nil
+				},
+			),
+		},
+		bufanalysistesting.NewFileAnnotationNoLocation(t, "B.proto", "FILE_LOWER_SNAKE_CASE"),
+		bufanalysistesting.NewFileAnnotationNoLocation(t, "Foo.proto", "FILE_LOWER_SNAKE_CASE"),
+		bufanalysistesting.NewFileAnnotationNoLocation(t, "aBc.proto", "FILE_LOWER_SNAKE_CASE"),
+		bufanalysistesting.NewFileAnnotationNoLocation(t, "ab_c_.proto", "FILE_LOWER_SNAKE_CASE"),
+		bufanalysistesting.NewFileAnnotationNoLocation(t, "fooBar.proto", "FILE_LOWER_SNAKE_CASE"),
+	)
+	assert.Equal(
+		t,
+		[]string{
+			"check 1.proto",
+			"check B.proto",
+			"stream B.proto",
+			"check Foo.proto",
+			"stream Foo.proto",
+			"check a.proto",
+			"check aBc.proto",
+			"stream aBc.proto",
+			"check ab.proto",
+			"check ab_c.proto",
+			"check ab_c_.proto",
+			"stream ab_c_.proto",
+			"check fooBar.proto",
+			"stream fooBar.proto",
+		},
+		events,
+	)
+}
+
+func TestRunCustomChecker(t *testing.T) {
+	customChecker, err := buflint.NewCustomChecker(
+		"SERVICE_NO_FAIL_PREFIX",
+		"services are not prefixed with Fail",
+		func(add buflint.AddFunc, file protosource.File) error {
+			for _, service := range file.Services() {
+				if strings.HasPrefix(service.Name(), "Fail") {
+					add(service, service.NameLocation(), "Service name %q should not be prefixed with %q.", service.Name(), "Fail")
+				}
+			}
+			return nil
+		},
+	)
+	require.NoError(t, err)
+	testLintExternalConfigModifierHandlerOptions(
+		t,
+		"service_suffix",
+		nil,
+		[]buflint.HandlerOption{
+			buflint.HandlerWithCustomCheckers(customChecker),
+		},
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 8, 9, 8, 13, "SERVICE_NO_FAIL_PREFIX"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 8, 9, 8, 13, "SERVICE_SUFFIX"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 9, 9, 9, 16, "SERVICE_NO_FAIL_PREFIX"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 9, 9, 9, 16, "SERVICE_SUFFIX"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 10, 9, 10, 21, "SERVICE_NO_FAIL_PREFIX"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 10, 9, 10, 21, "SERVICE_SUFFIX"),
+	)
+}
+
+func TestRunIgnores1(t *testing.T) {
+	testLint(
+		t,
+		"ignores",
+		bufanalysistesting.NewFileAnnotation(t, "buf/bar/bar.proto", 6, 9, 6, 15, "FIELD_LOWER_SNAKE_CASE"),
+		bufanalysistesting.NewFileAnnotation(t, "buf/bar/bar.proto", 9, 9, 9, 12, "MESSAGE_PASCAL_CASE"),
+		bufanalysistesting.NewFileAnnotation(t, "buf/bar/bar.proto", 13, 6, 13, 9, "ENUM_PASCAL_CASE"),
 		bufanalysistesting.NewFileAnnotation(t, "buf/bar/bar2.proto", 6, 9, 6, 15, "FIELD_LOWER_SNAKE_CASE"),
 		bufanalysistesting.NewFileAnnotation(t, "buf/bar/bar2.proto", 9, 9, 9, 13, "MESSAGE_PASCAL_CASE"),
 		bufanalysistesting.NewFileAnnotation(t, "buf/bar/bar2.proto", 13, 6, 13, 10, "ENUM_PASCAL_CASE"),
@@ -797,8 +1649,679 @@ func TestCommentIgnoresOn(t *testing.T) {
 		t,
 		"comment_ignores",
 		func(externalConfig *bufconfig.ExternalConfig) {
-			externalConfig.Lint.AllowCommentIgnores = true
+			externalConfig.Lint.AllowCommentIgnores = proto.Bool(true)
+		},
+	)
+}
+
+func TestRunTypeNoWKTName(t *testing.T) {
+	testLint(
+		t,
+		"type_no_wkt_name",
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 10, 9, 10, 18, "TYPE_NO_WKT_NAME"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 13, 11, 13, 19, "TYPE_NO_WKT_NAME"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 14, 8, 14, 17, "TYPE_NO_WKT_NAME"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 21, 6, 21, 12, "TYPE_NO_WKT_NAME"),
+	)
+}
+
+func TestRunTypeNoWKTNameNoAllow(t *testing.T) {
+	testLintExternalConfigModifier(
+		t,
+		"type_no_wkt_name",
+		func(externalConfig *bufconfig.ExternalConfig) {
+			externalConfig.Lint.AllowWKTNameTypes = nil
+		},
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 10, 9, 10, 18, "TYPE_NO_WKT_NAME"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 13, 11, 13, 19, "TYPE_NO_WKT_NAME"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 14, 8, 14, 17, "TYPE_NO_WKT_NAME"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 19, 9, 19, 12, "TYPE_NO_WKT_NAME"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 21, 6, 21, 12, "TYPE_NO_WKT_NAME"),
+	)
+}
+
+func TestRunFieldNumbersAscending(t *testing.T) {
+	testLint(
+		t,
+		"field_numbers_ascending",
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 23, 15, 23, 16, "FIELD_NUMBERS_ASCENDING"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 32, 17, 32, 18, "FIELD_NUMBERS_ASCENDING"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 41, 17, 41, 18, "FIELD_NUMBERS_ASCENDING"),
+	)
+}
+
+func TestRunFieldNumbersAscendingIgnoreOneofs(t *testing.T) {
+	testLintExternalConfigModifier(
+		t,
+		"field_numbers_ascending",
+		func(externalConfig *bufconfig.ExternalConfig) {
+			externalConfig.Lint.FieldNumbersAscendingIgnoreOneofs = proto.Bool(true)
+		},
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 23, 15, 23, 16, "FIELD_NUMBERS_ASCENDING"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 41, 17, 41, 18, "FIELD_NUMBERS_ASCENDING"),
+	)
+}
+
+func TestRunMapKeyNoForbiddenType(t *testing.T) {
+	testLint(
+		t,
+		"map_key_no_forbidden_type",
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 8, 5, 8, 42, "MAP_KEY_NO_FORBIDDEN_TYPE"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 12, 3, 12, 42, "MAP_KEY_NO_FORBIDDEN_TYPE"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 13, 3, 13, 37, "MAP_KEY_NO_FORBIDDEN_TYPE"),
+	)
+}
+
+func TestRunMapKeyNoForbiddenTypeNoneForbidden(t *testing.T) {
+	testLintExternalConfigModifier(
+		t,
+		"map_key_no_forbidden_type",
+		func(externalConfig *bufconfig.ExternalConfig) {
+			externalConfig.Lint.MapKeyForbiddenTypes = nil
+		},
+	)
+}
+
+func TestRunFileHeader(t *testing.T) {
+	testLint(
+		t,
+		"file_header",
+		bufanalysistesting.NewFileAnnotation(t, "c.proto", 0, 0, 0, 0, "FILE_HEADER"),
+		bufanalysistesting.NewFileAnnotation(t, "d.proto", 0, 0, 0, 0, "FILE_HEADER"),
+	)
+}
+
+func TestRunFileHeaderRegex(t *testing.T) {
+	testLintExternalConfigModifier(
+		t,
+		"file_header",
+		func(externalConfig *bufconfig.ExternalConfig) {
+			externalConfig.Lint.FileHeader = ""
+			externalConfig.Lint.FileHeaderRegex = `^Copyright \d{4} `
+		},
+		bufanalysistesting.NewFileAnnotation(t, "d.proto", 0, 0, 0, 0, "FILE_HEADER"),
+	)
+}
+
+func TestRunFileHeaderPath(t *testing.T) {
+	testLintExternalConfigModifier(
+		t,
+		"file_header",
+		func(externalConfig *bufconfig.ExternalConfig) {
+			externalConfig.Lint.FileHeader = ""
+			// relative to the root of the module, not the current directory
+			externalConfig.Lint.FileHeaderPath = "header.txt"
+		},
+		bufanalysistesting.NewFileAnnotation(t, "c.proto", 0, 0, 0, 0, "FILE_HEADER"),
+		bufanalysistesting.NewFileAnnotation(t, "d.proto", 0, 0, 0, 0, "FILE_HEADER"),
+	)
+}
+
+func TestRunEnumValueNoGap(t *testing.T) {
+	testLint(
+		t,
+		"enum_value_no_gap",
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 19, 6, 19, 9, "ENUM_VALUE_NO_GAP"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 25, 6, 25, 23, "ENUM_VALUE_NO_GAP"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 41, 8, 41, 17, "ENUM_VALUE_NO_GAP"),
+	)
+}
+
+func TestRunIgnorePathPrefixes(t *testing.T) {
+	testLint(
+		t,
+		"ignore_path_prefixes",
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 5, 9, 5, 12, "MESSAGE_PASCAL_CASE"),
+		bufanalysistesting.NewFileAnnotation(t, "vendored/c.proto", 5, 9, 5, 12, "MESSAGE_PASCAL_CASE"),
+	)
+}
+
+func TestRunIgnorePathPrefixesEmpty(t *testing.T) {
+	testLintExternalConfigModifier(
+		t,
+		"ignore_path_prefixes",
+		func(externalConfig *bufconfig.ExternalConfig) {
+			externalConfig.Lint.IgnorePathPrefixes = []string{}
+		},
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 5, 9, 5, 12, "MESSAGE_PASCAL_CASE"),
+		bufanalysistesting.NewFileAnnotation(t, "vendor/acme/b.proto", 5, 9, 5, 12, "MESSAGE_PASCAL_CASE"),
+		bufanalysistesting.NewFileAnnotation(t, "vendored/c.proto", 5, 9, 5, 12, "MESSAGE_PASCAL_CASE"),
+	)
+}
+
+func TestRunIgnorePathPrefixesNoTrailingSlash(t *testing.T) {
+	testLintExternalConfigModifier(
+		t,
+		"ignore_path_prefixes",
+		func(externalConfig *bufconfig.ExternalConfig) {
+			externalConfig.Lint.IgnorePathPrefixes = []string{"vendor"}
+		},
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 5, 9, 5, 12, "MESSAGE_PASCAL_CASE"),
+	)
+}
+
+func TestRunAcronymCasing(t *testing.T) {
+	testLint(
+		t,
+		"acronym_casing",
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 7, 10, 7, 20, "ACRONYM_CASING"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 10, 10, 10, 16, "ACRONYM_CASING"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 15, 9, 15, 21, "ACRONYM_CASING"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 20, 24, 20, 45, "ACRONYM_CASING"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 27, 6, 27, 16, "ACRONYM_CASING"),
+	)
+}
+
+func TestRunAcronymCasingOtherAcronyms(t *testing.T) {
+	testLintExternalConfigModifier(
+		t,
+		"acronym_casing",
+		func(externalConfig *bufconfig.ExternalConfig) {
+			externalConfig.Lint.Acronyms = []string{"API"}
+		},
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 23, 9, 23, 15, "ACRONYM_CASING"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 31, 6, 31, 13, "ACRONYM_CASING"),
+	)
+}
+
+func TestRunAcronymCasingPascalCaseAcronyms(t *testing.T) {
+	testLintExternalConfigModifier(
+		t,
+		"acronym_casing",
+		func(externalConfig *bufconfig.ExternalConfig) {
+			externalConfig.Lint.Acronyms = []string{"Http", "Url", "Id"}
+		},
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 5, 9, 5, 20, "ACRONYM_CASING"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 8, 28, 8, 52, "ACRONYM_CASING"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 19, 9, 19, 15, "ACRONYM_CASING"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 23, 9, 23, 15, "ACRONYM_CASING"),
+	)
+}
+
+func TestRunFieldDeprecatedReserve(t *testing.T) {
+	testLint(
+		t,
+		"field_deprecated_reserve",
+		bufanalysis.FileAnnotationWithSeverity(
+			bufanalysistesting.NewFileAnnotation(t, "a.proto", 7, 18, 7, 35, "FIELD_DEPRECATED_RESERVE"),
+			bufanalysis.SeverityInfo,
+		),
+	)
+}
+
+func TestRunFieldDeprecatedReserveSeverityInfo(t *testing.T) {
+	testLintExternalConfigModifier(
+		t,
+		"field_deprecated_reserve",
+		func(externalConfig *bufconfig.ExternalConfig) {
+			externalConfig.Lint.FieldDeprecatedReserveSeverity = "info"
+		},
+		bufanalysis.FileAnnotationWithSeverity(
+			bufanalysistesting.NewFileAnnotation(t, "a.proto", 7, 18, 7, 35, "FIELD_DEPRECATED_RESERVE"),
+			bufanalysis.SeverityInfo,
+		),
+	)
+}
+
+func TestRunFieldDeprecatedReserveSeverityWarning(t *testing.T) {
+	testLintExternalConfigModifier(
+		t,
+		"field_deprecated_reserve",
+		func(externalConfig *bufconfig.ExternalConfig) {
+			externalConfig.Lint.FieldDeprecatedReserveSeverity = "warning"
+		},
+		bufanalysis.FileAnnotationWithSeverity(
+			bufanalysistesting.NewFileAnnotation(t, "a.proto", 7, 18, 7, 35, "FIELD_DEPRECATED_RESERVE"),
+			bufanalysis.SeverityWarning,
+		),
+	)
+}
+
+func TestRunFieldDeprecatedReserveSeverityError(t *testing.T) {
+	testLintExternalConfigModifier(
+		t,
+		"field_deprecated_reserve",
+		func(externalConfig *bufconfig.ExternalConfig) {
+			externalConfig.Lint.FieldDeprecatedReserveSeverity = "error"
+		},
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 7, 18, 7, 35, "FIELD_DEPRECATED_RESERVE"),
+	)
+}
+
+func TestRunFieldDeprecatedReserveSeverityErrorWithErrorIDs(t *testing.T) {
+	testLintExternalConfigModifier(
+		t,
+		"field_deprecated_reserve",
+		func(externalConfig *bufconfig.ExternalConfig) {
+			externalConfig.Lint.Error = []string{"FIELD_LOWER_SNAKE_CASE"}
+			externalConfig.Lint.FieldDeprecatedReserveSeverity = "error"
+		},
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 7, 18, 7, 35, "FIELD_DEPRECATED_RESERVE"),
+	)
+}
+
+func TestRunFieldNoGroup(t *testing.T) {
+	testLint(
+		t,
+		"field_no_group",
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 7, 3, 9, 4, "FIELD_NO_GROUP"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 10, 3, 12, 4, "FIELD_NO_GROUP"),
+	)
+}
+
+func TestRunCommentEnumValue(t *testing.T) {
+	testLint(
+		t,
+		"comment_enum_value",
+		bufanalysistesting.NewFileAnnotation(t, "a/a.proto", 6, 3, 6, 23, "COMMENT_ENUM_VALUE"),
+		bufanalysistesting.NewFileAnnotation(t, "a/a.proto", 9, 3, 9, 24, "COMMENT_ENUM_VALUE"),
+		bufanalysistesting.NewFileAnnotation(t, "b/b.proto", 8, 3, 8, 24, "COMMENT_ENUM_VALUE"),
+	)
+}
+
+func TestRunCommentEnumValuePackages(t *testing.T) {
+	testLintExternalConfigModifier(
+		t,
+		"comment_enum_value",
+		func(externalConfig *bufconfig.ExternalConfig) {
+			externalConfig.Lint.CommentEnumValuePackages = []string{"a"}
 		},
+		bufanalysistesting.NewFileAnnotation(t, "a/a.proto", 6, 3, 6, 23, "COMMENT_ENUM_VALUE"),
+		bufanalysistesting.NewFileAnnotation(t, "a/a.proto", 9, 3, 9, 24, "COMMENT_ENUM_VALUE"),
+	)
+}
+
+func TestRunCommentEnumValueAllowZero(t *testing.T) {
+	testLintExternalConfigModifier(
+		t,
+		"comment_enum_value",
+		func(externalConfig *bufconfig.ExternalConfig) {
+			externalConfig.Lint.CommentEnumValueAllowZero = proto.Bool(true)
+		},
+		bufanalysistesting.NewFileAnnotation(t, "a/a.proto", 9, 3, 9, 24, "COMMENT_ENUM_VALUE"),
+		bufanalysistesting.NewFileAnnotation(t, "b/b.proto", 8, 3, 8, 24, "COMMENT_ENUM_VALUE"),
+	)
+}
+
+func TestRunCommentMessage(t *testing.T) {
+	testLint(
+		t,
+		"comment_message",
+		bufanalysistesting.NewFileAnnotation(t, "a/a.proto", 7, 3, 7, 20, "COMMENT_MESSAGE"),
+		bufanalysistesting.NewFileAnnotation(t, "a/a.proto", 12, 1, 14, 2, "COMMENT_MESSAGE"),
+		bufanalysistesting.NewFileAnnotation(t, "b/b.proto", 5, 1, 7, 2, "COMMENT_MESSAGE"),
+		bufanalysistesting.NewFileAnnotation(t, "b/b.proto", 6, 3, 6, 20, "COMMENT_MESSAGE"),
+	)
+}
+
+func TestRunCommentMessagePackages(t *testing.T) {
+	testLintExternalConfigModifier(
+		t,
+		"comment_message",
+		func(externalConfig *bufconfig.ExternalConfig) {
+			externalConfig.Lint.CommentMessagePackages = []string{"b"}
+		},
+		bufanalysistesting.NewFileAnnotation(t, "b/b.proto", 5, 1, 7, 2, "COMMENT_MESSAGE"),
+		bufanalysistesting.NewFileAnnotation(t, "b/b.proto", 6, 3, 6, 20, "COMMENT_MESSAGE"),
+	)
+}
+
+func TestRunCommentMessageTopLevelOnly(t *testing.T) {
+	testLintExternalConfigModifier(
+		t,
+		"comment_message",
+		func(externalConfig *bufconfig.ExternalConfig) {
+			externalConfig.Lint.CommentMessageTopLevelOnly = proto.Bool(true)
+		},
+		bufanalysistesting.NewFileAnnotation(t, "a/a.proto", 12, 1, 14, 2, "COMMENT_MESSAGE"),
+		bufanalysistesting.NewFileAnnotation(t, "b/b.proto", 5, 1, 7, 2, "COMMENT_MESSAGE"),
+	)
+}
+
+func TestRunFieldOptionConsistent(t *testing.T) {
+	testLint(
+		t,
+		"field_option_consistent",
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 9, 3, 9, 18, "FIELD_OPTION_CONSISTENT"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 11, 3, 11, 39, "FIELD_OPTION_CONSISTENT"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 12, 3, 12, 21, "FIELD_OPTION_CONSISTENT"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 23, 5, 23, 19, "FIELD_OPTION_CONSISTENT"),
+	)
+}
+
+func TestRunFieldOptionConsistentTypes(t *testing.T) {
+	testLintExternalConfigModifier(
+		t,
+		"field_option_consistent",
+		func(externalConfig *bufconfig.ExternalConfig) {
+			externalConfig.Lint.FieldOptionConsistentTypes = []string{"string", "int32"}
+		},
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 9, 3, 9, 18, "FIELD_OPTION_CONSISTENT"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 11, 3, 11, 39, "FIELD_OPTION_CONSISTENT"),
+	)
+}
+
+func TestRunFieldOptionConsistentOptionNumber(t *testing.T) {
+	testLintExternalConfigModifier(
+		t,
+		"field_option_consistent",
+		func(externalConfig *bufconfig.ExternalConfig) {
+			externalConfig.Lint.FieldOptionConsistentOption = "acme.rules"
+			externalConfig.Lint.FieldOptionConsistentOptionNumber = 1071
+		},
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 9, 3, 9, 18, "FIELD_OPTION_CONSISTENT"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 11, 3, 11, 39, "FIELD_OPTION_CONSISTENT"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 12, 3, 12, 21, "FIELD_OPTION_CONSISTENT"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 23, 5, 23, 19, "FIELD_OPTION_CONSISTENT"),
+	)
+}
+
+func TestRunFieldPresenceComment(t *testing.T) {
+	testLint(
+		t,
+		"field_presence_comment",
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 9, 3, 9, 26, "FIELD_PRESENCE_COMMENT"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 10, 3, 10, 17, "FIELD_PRESENCE_COMMENT"),
+		bufanalysistesting.NewFileAnnotation(t, "b.proto", 6, 3, 6, 27, "FIELD_PRESENCE_COMMENT"),
+	)
+}
+
+func TestRunFieldPresenceCommentPackages(t *testing.T) {
+	testLintExternalConfigModifier(
+		t,
+		"field_presence_comment",
+		func(externalConfig *bufconfig.ExternalConfig) {
+			externalConfig.Lint.FieldPresenceCommentPackages = []string{"b"}
+		},
+		bufanalysistesting.NewFileAnnotation(t, "b.proto", 6, 3, 6, 27, "FIELD_PRESENCE_COMMENT"),
+	)
+}
+
+func TestRunFieldProto3OptionalAlways(t *testing.T) {
+	testLint(
+		t,
+		"field_proto3_optional",
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 7, 3, 7, 17, "FIELD_PROTO3_OPTIONAL"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 15, 3, 15, 19, "FIELD_PROTO3_OPTIONAL"),
+		bufanalysistesting.NewFileAnnotation(t, "b.proto", 7, 3, 7, 17, "FIELD_PROTO3_OPTIONAL"),
+	)
+}
+
+func TestRunFieldProto3OptionalNever(t *testing.T) {
+	testLintExternalConfigModifier(
+		t,
+		"field_proto3_optional",
+		func(externalConfig *bufconfig.ExternalConfig) {
+			externalConfig.Lint.FieldProto3Optional = "never"
+		},
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 6, 3, 6, 26, "FIELD_PROTO3_OPTIONAL"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 14, 3, 14, 29, "FIELD_PROTO3_OPTIONAL"),
+		bufanalysistesting.NewFileAnnotation(t, "b.proto", 6, 3, 6, 26, "FIELD_PROTO3_OPTIONAL"),
+	)
+}
+
+func TestRunFieldProto3OptionalPackages(t *testing.T) {
+	testLintExternalConfigModifier(
+		t,
+		"field_proto3_optional",
+		func(externalConfig *bufconfig.ExternalConfig) {
+			externalConfig.Lint.FieldProto3OptionalPackages = []string{"b"}
+		},
+		bufanalysistesting.NewFileAnnotation(t, "b.proto", 7, 3, 7, 17, "FIELD_PROTO3_OPTIONAL"),
+	)
+}
+
+func TestRunFieldProto3OptionalUnset(t *testing.T) {
+	testLintExternalConfigModifier(
+		t,
+		"field_proto3_optional",
+		func(externalConfig *bufconfig.ExternalConfig) {
+			externalConfig.Lint.FieldProto3Optional = ""
+		},
+	)
+}
+
+func TestRunFieldNoAny(t *testing.T) {
+	testLint(
+		t,
+		"field_no_any",
+		bufanalysistesting.NewFileAnnotation(t, "a/a.proto", 8, 3, 8, 22, "FIELD_NO_ANY"),
+		bufanalysistesting.NewFileAnnotation(t, "a/a.proto", 9, 12, 9, 31, "FIELD_NO_ANY"),
+		bufanalysistesting.NewFileAnnotation(t, "a/a.proto", 10, 3, 10, 35, "FIELD_NO_ANY"),
+		bufanalysistesting.NewFileAnnotation(t, "a/a.proto", 11, 3, 11, 22, "FIELD_NO_ANY"),
+		bufanalysistesting.NewFileAnnotation(t, "b/b.proto", 8, 3, 8, 22, "FIELD_NO_ANY"),
+	)
+}
+
+func TestRunFieldNoAnyPackagesAllowed(t *testing.T) {
+	testLintExternalConfigModifier(
+		t,
+		"field_no_any",
+		func(externalConfig *bufconfig.ExternalConfig) {
+			externalConfig.Lint.FieldNoAnyPackages = []string{"a"}
+			externalConfig.Lint.AllowAnyFields = []string{".a.Foo.allowed"}
+		},
+		bufanalysistesting.NewFileAnnotation(t, "a/a.proto", 8, 3, 8, 22, "FIELD_NO_ANY"),
+		bufanalysistesting.NewFileAnnotation(t, "a/a.proto", 9, 12, 9, 31, "FIELD_NO_ANY"),
+		bufanalysistesting.NewFileAnnotation(t, "a/a.proto", 10, 3, 10, 35, "FIELD_NO_ANY"),
+	)
+}
+
+func TestRunFieldNoStutter(t *testing.T) {
+	testLint(
+		t,
+		"field_no_stutter",
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 6, 10, 6, 20, "FIELD_NO_STUTTER"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 7, 10, 7, 14, "FIELD_NO_STUTTER"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 10, 10, 10, 17, "FIELD_NO_STUTTER"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 11, 23, 11, 34, "FIELD_NO_STUTTER"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 15, 10, 15, 25, "FIELD_NO_STUTTER"),
+	)
+}
+
+func TestRunFieldNoStutterAllowStutterFields(t *testing.T) {
+	testLintExternalConfigModifier(
+		t,
+		"field_no_stutter",
+		func(externalConfig *bufconfig.ExternalConfig) {
+			externalConfig.Lint.AllowStutterFields = []string{
+				"a.Book.book_id",
+				".a.BookShelf.book_shelf_name",
+			}
+		},
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 6, 10, 6, 20, "FIELD_NO_STUTTER"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 7, 10, 7, 14, "FIELD_NO_STUTTER"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 11, 23, 11, 34, "FIELD_NO_STUTTER"),
+	)
+}
+
+func TestRunFieldNoWrapperType(t *testing.T) {
+	testLint(
+		t,
+		"field_no_wrapper_type",
+		bufanalysistesting.NewFileAnnotation(t, "a/a.proto", 9, 3, 9, 40, "FIELD_NO_WRAPPER_TYPE"),
+		bufanalysistesting.NewFileAnnotation(t, "a/a.proto", 11, 3, 11, 40, "FIELD_NO_WRAPPER_TYPE"),
+		bufanalysistesting.NewFileAnnotation(t, "a/a.proto", 16, 5, 16, 41, "FIELD_NO_WRAPPER_TYPE"),
+		bufanalysistesting.NewFileAnnotation(t, "b/b.proto", 8, 3, 8, 40, "FIELD_NO_WRAPPER_TYPE"),
+	)
+}
+
+func TestRunFieldNoWrapperTypePackages(t *testing.T) {
+	testLintExternalConfigModifier(
+		t,
+		"field_no_wrapper_type",
+		func(externalConfig *bufconfig.ExternalConfig) {
+			externalConfig.Lint.FieldNoWrapperTypePackages = []string{"b"}
+		},
+		bufanalysistesting.NewFileAnnotation(t, "b/b.proto", 8, 3, 8, 40, "FIELD_NO_WRAPPER_TYPE"),
+	)
+}
+
+func TestRunFieldNoReserved(t *testing.T) {
+	// the compiler rejects fields that use reserved numbers or names, so
+	// this can only be hit by images that were built elsewhere
+	testLintFileDescriptorProtos(
+		t,
+		buflint.ExternalConfig{
+			Use: []string{"FIELD_NO_RESERVED"},
+		},
+		[]*descriptorpb.FileDescriptorProto{
+			{
+				Name:    proto.String("a.proto"),
+				Package: proto.String("a"),
+				Syntax:  proto.String("proto3"),
+				MessageType: []*descriptorpb.DescriptorProto{
+					{
+						Name: proto.String("Foo"),
+						Field: []*descriptorpb.FieldDescriptorProto{
+							testNewInt32FieldDescriptorProto("one", 1),
+							testNewInt32FieldDescriptorProto("two", 2),
+							testNewInt32FieldDescriptorProto("three", 3),
+							testNewInt32FieldDescriptorProto("six", 6),
+						},
+						ReservedRange: []*descriptorpb.DescriptorProto_ReservedRange{
+							{
+								Start: proto.Int32(2),
+								End:   proto.Int32(3),
+							},
+							{
+								Start: proto.Int32(4),
+								End:   proto.Int32(6),
+							},
+						},
+						ReservedName: []string{
+							"three",
+						},
+					},
+				},
+			},
+		},
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 0, 0, 0, 0, "FIELD_NO_RESERVED"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 0, 0, 0, 0, "FIELD_NO_RESERVED"),
+	)
+}
+
+func TestRunStrictDisabled(t *testing.T) {
+	testLint(
+		t,
+		"strict",
+	)
+}
+
+func TestRunStrict(t *testing.T) {
+	// NAME_NO_RESERVED_WORD and COMMENT_MESSAGE are not in DEFAULT, and
+	// COMMENT_MESSAGE is additionally in except, but comment ignores still apply
+	testLintExternalConfigModifier(
+		t,
+		"strict",
+		func(externalConfig *bufconfig.ExternalConfig) {
+			externalConfig.Lint.Strict = proto.Bool(true)
+		},
+		bufanalysistesting.NewFileAnnotation(t, "a/v1/a.proto", 10, 10, 10, 14, "NAME_NO_RESERVED_WORD"),
+		bufanalysistesting.NewFileAnnotation(t, "a/v1/a.proto", 13, 1, 16, 2, "COMMENT_MESSAGE"),
+	)
+}
+
+func TestRunOverrides(t *testing.T) {
+	// experimental relaxes FIELD_LOWER_SNAKE_CASE, while experimental/strict
+	// is merged with the top-level config and adds COMMENT_MESSAGE
+	testLint(
+		t,
+		"overrides",
+		bufanalysistesting.NewFileAnnotation(t, "api/v1/a.proto", 5, 9, 5, 12, "MESSAGE_PASCAL_CASE"),
+		bufanalysistesting.NewFileAnnotation(t, "api/v1/a.proto", 6, 9, 6, 12, "FIELD_LOWER_SNAKE_CASE"),
+		bufanalysistesting.NewFileAnnotation(t, "experimental/strict/v1/a.proto", 5, 1, 7, 2, "COMMENT_MESSAGE"),
+		bufanalysistesting.NewFileAnnotation(t, "experimental/strict/v1/a.proto", 5, 9, 5, 12, "MESSAGE_PASCAL_CASE"),
+		bufanalysistesting.NewFileAnnotation(t, "experimental/strict/v1/a.proto", 6, 9, 6, 12, "FIELD_LOWER_SNAKE_CASE"),
+		bufanalysistesting.NewFileAnnotation(t, "experimental/v1/a.proto", 5, 9, 5, 12, "MESSAGE_PASCAL_CASE"),
+	)
+}
+
+func TestRunOverridesUnset(t *testing.T) {
+	testLintExternalConfigModifier(
+		t,
+		"overrides",
+		func(externalConfig *bufconfig.ExternalConfig) {
+			externalConfig.Lint.Overrides = nil
+		},
+		bufanalysistesting.NewFileAnnotation(t, "api/v1/a.proto", 5, 9, 5, 12, "MESSAGE_PASCAL_CASE"),
+		bufanalysistesting.NewFileAnnotation(t, "api/v1/a.proto", 6, 9, 6, 12, "FIELD_LOWER_SNAKE_CASE"),
+		bufanalysistesting.NewFileAnnotation(t, "experimental/strict/v1/a.proto", 5, 9, 5, 12, "MESSAGE_PASCAL_CASE"),
+		bufanalysistesting.NewFileAnnotation(t, "experimental/strict/v1/a.proto", 6, 9, 6, 12, "FIELD_LOWER_SNAKE_CASE"),
+		bufanalysistesting.NewFileAnnotation(t, "experimental/v1/a.proto", 5, 9, 5, 12, "MESSAGE_PASCAL_CASE"),
+		bufanalysistesting.NewFileAnnotation(t, "experimental/v1/a.proto", 6, 9, 6, 12, "FIELD_LOWER_SNAKE_CASE"),
+	)
+}
+
+func TestRunOverridesMultiFile(t *testing.T) {
+	// PACKAGE_SAME_DIRECTORY is only used in bar, but still sees the files in foo
+	testLint(
+		t,
+		"overrides_multi_file",
+		bufanalysistesting.NewFileAnnotation(t, "bar/b.proto", 3, 1, 3, 13, "PACKAGE_SAME_DIRECTORY"),
+		bufanalysistesting.NewFileAnnotation(t, "bar/b.proto", 5, 9, 5, 12, "MESSAGE_PASCAL_CASE"),
+		bufanalysistesting.NewFileAnnotation(t, "foo/a.proto", 5, 9, 5, 12, "MESSAGE_PASCAL_CASE"),
+	)
+}
+
+func TestRunOverridesMerge(t *testing.T) {
+	// strict overrides allow_comment_ignores to false and ignore_path_prefixes to empty
+	testLint(
+		t,
+		"overrides_merge",
+		bufanalysistesting.NewFileAnnotation(t, "strict/a.proto", 6, 9, 6, 12, "MESSAGE_PASCAL_CASE"),
+		bufanalysistesting.NewFileAnnotation(t, "strict/vendor/b.proto", 5, 9, 5, 12, "MESSAGE_PASCAL_CASE"),
+	)
+}
+
+func TestRunExtends(t *testing.T) {
+	testLint(
+		t,
+		filepath.Join("extends", "local"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 5, 9, 5, 12, "MESSAGE_PASCAL_CASE"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 6, 10, 6, 13, "FIELD_LOWER_SNAKE_CASE"),
+		bufanalysistesting.NewFileAnnotation(t, "a.proto", 9, 6, 9, 9, "ENUM_PASCAL_CASE"),
+		bufanalysistesting.NewFileAnnotation(t, "unignored/a.proto", 5, 9, 5, 12, "MESSAGE_PASCAL_CASE"),
+		bufanalysistesting.NewFileAnnotation(t, "unignored/a.proto", 6, 10, 6, 13, "FIELD_LOWER_SNAKE_CASE"),
+		bufanalysistesting.NewFileAnnotation(t, "unignored/a.proto", 9, 6, 9, 9, "ENUM_PASCAL_CASE"),
+	)
+}
+
+func TestRunExtendsCycle(t *testing.T) {
+	t.Parallel()
+	readWriteBucket, err := storageos.NewReadWriteBucket(filepath.Join("testdata", "extends_cycle"))
+	require.NoError(t, err)
+	_, err = bufconfig.NewProvider(zap.NewNop()).GetConfig(context.Background(), readWriteBucket, bufconfig.GetConfigWithLocalDir())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "lint extends cycle")
+}
+
+func TestRunExtendsRelativeNotLocalDir(t *testing.T) {
+	t.Parallel()
+	readWriteBucket, err := storageos.NewReadWriteBucket(filepath.Join("testdata", "extends", "local"))
+	require.NoError(t, err)
+	// the bucket could be an archive or a git repository, so the relative path cannot be resolved
+	_, err = bufconfig.NewProvider(zap.NewNop()).GetConfig(context.Background(), readWriteBucket)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "only supported for local directories")
+}
+
+func TestRunExtendsHTTP(t *testing.T) {
+	t.Parallel()
+	_, err := bufconfig.NewProvider(zap.NewNop()).GetConfigForData(
+		context.Background(),
+		[]byte(`{"lint":{"extends":"http://example.com/buf.yaml"}}`),
+	)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "must be an https URL")
+}
+
+func TestRunSyntaxSpecified(t *testing.T) {
+	testLint(
+		t,
+		"syntax_specified",
+		bufanalysistesting.NewFileAnnotationNoLocation(t, "b.proto", "SYNTAX_SPECIFIED"),
+		bufanalysistesting.NewFileAnnotationNoLocation(t, "d.proto", "SYNTAX_SPECIFIED"),
 	)
 }
 
@@ -820,6 +2343,22 @@ func testLintExternalConfigModifier(
 	relDirPath string,
 	modifier func(*bufconfig.ExternalConfig),
 	expectedFileAnnotations ...bufanalysis.FileAnnotation,
+) {
+	testLintExternalConfigModifierHandlerOptions(
+		t,
+		relDirPath,
+		modifier,
+		nil,
+		expectedFileAnnotations...,
+	)
+}
+
+func testLintExternalConfigModifierHandlerOptions(
+	t *testing.T,
+	relDirPath string,
+	modifier func(*bufconfig.ExternalConfig),
+	handlerOptions []buflint.HandlerOption,
+	expectedFileAnnotations ...bufanalysis.FileAnnotation,
 ) {
 	t.Parallel()
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -860,7 +2399,7 @@ func testLintExternalConfigModifier(
 	require.Empty(t, fileAnnotations)
 	image = bufcore.ImageWithoutImports(image)
 
-	handler := buflint.NewHandler(logger)
+	handler := buflint.NewHandler(logger, handlerOptions...)
 	fileAnnotations, err = handler.Check(
 		ctx,
 		config.Lint,
@@ -882,11 +2421,47 @@ func testGetConfig(
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 	defer cancel()
 
-	data, err := storage.ReadPath(ctx, readBucket, bufconfig.ConfigFilePath)
-	if err != nil && !storage.IsNotExist(err) {
+	config, err := configProvider.GetConfig(ctx, readBucket, bufconfig.GetConfigWithLocalDir())
+	require.NoError(t, err)
+	return config
+}
+
+func testLintFileDescriptorProtos(
+	t *testing.T,
+	externalConfig buflint.ExternalConfig,
+	fileDescriptorProtos []*descriptorpb.FileDescriptorProto,
+	expectedFileAnnotations ...bufanalysis.FileAnnotation,
+) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	config, err := buflint.NewConfig(externalConfig)
+	require.NoError(t, err)
+	imageFiles := make([]bufcore.ImageFile, len(fileDescriptorProtos))
+	for i, fileDescriptorProto := range fileDescriptorProtos {
+		imageFile, err := bufcore.NewImageFile(fileDescriptorProto, "", false)
 		require.NoError(t, err)
+		imageFiles[i] = imageFile
 	}
-	config, err := configProvider.GetConfigForData(data)
+	image, err := bufcore.NewImage(imageFiles)
 	require.NoError(t, err)
-	return config
+
+	fileAnnotations, err := buflint.NewHandler(zap.NewNop()).Check(ctx, config, image)
+	assert.NoError(t, err)
+	bufanalysistesting.AssertFileAnnotationsEqual(
+		t,
+		expectedFileAnnotations,
+		fileAnnotations,
+	)
+}
+
+func testNewInt32FieldDescriptorProto(name string, number int32) *descriptorpb.FieldDescriptorProto {
+	return &descriptorpb.FieldDescriptorProto{
+		Name:     proto.String(name),
+		Number:   proto.Int32(number),
+		Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+		Type:     descriptorpb.FieldDescriptorProto_TYPE_INT32.Enum(),
+		JsonName: proto.String(name),
+	}
 }
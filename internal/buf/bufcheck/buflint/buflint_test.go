@@ -18,6 +18,10 @@ import (
 	"testing"
 
 	"github.com/bufbuild/buf/internal/buf/bufcheck/internal/internaltesting"
+	"github.com/bufbuild/buf/internal/pkg/protosource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
 )
 
 func TestDefaultConfigBuilder(t *testing.T) {
@@ -39,3 +43,220 @@ func TestCheckerBuilders(t *testing.T) {
 		v1AllCategories,
 	)
 }
+
+func TestNewCustomChecker(t *testing.T) {
+	t.Parallel()
+	customCheckFunc := func(AddFunc, protosource.File) error { return nil }
+	checker, err := NewCustomChecker("FOO_BAR", "foo is bar", customCheckFunc)
+	require.NoError(t, err)
+	assert.Equal(t, "FOO_BAR", checker.ID())
+	assert.Equal(t, "Checks that foo is bar.", checker.Purpose())
+	assert.Empty(t, checker.Categories())
+	_, err = NewCustomChecker("", "foo is bar", customCheckFunc)
+	assert.Error(t, err)
+	_, err = NewCustomChecker("fooBar", "foo is bar", customCheckFunc)
+	assert.Error(t, err)
+	_, err = NewCustomChecker("SERVICE_SUFFIX", "foo is bar", customCheckFunc)
+	assert.Error(t, err)
+	_, err = NewCustomChecker("FOO_BAR", "", customCheckFunc)
+	assert.Error(t, err)
+	_, err = NewCustomChecker("FOO_BAR", "foo is bar", nil)
+	assert.Error(t, err)
+}
+
+func TestNewConfigOverrides(t *testing.T) {
+	t.Parallel()
+	config, err := NewConfig(
+		ExternalConfig{
+			Use:                 []string{"MESSAGE_PASCAL_CASE", "FIELD_LOWER_SNAKE_CASE"},
+			EnumZeroValueSuffix: "_NONE",
+			IgnorePathPrefixes:  []string{},
+			Overrides: map[string]ExternalConfig{
+				"./foo/": {
+					Use: []string{"MESSAGE_PASCAL_CASE"},
+				},
+			},
+		},
+	)
+	require.NoError(t, err)
+	require.Len(t, config.DirPathToConfig, 1)
+	overrideConfig := config.DirPathToConfig["foo"]
+	require.NotNil(t, overrideConfig)
+	require.Len(t, overrideConfig.Checkers, 1)
+	assert.Equal(t, "MESSAGE_PASCAL_CASE", overrideConfig.Checkers[0].ID())
+	assert.Empty(t, overrideConfig.IgnorePathPrefixes)
+	assert.Empty(t, overrideConfig.DirPathToConfig)
+	_, err = NewConfig(ExternalConfig{Overrides: map[string]ExternalConfig{".": {}}})
+	assert.Error(t, err)
+	_, err = NewConfig(ExternalConfig{Overrides: map[string]ExternalConfig{"../foo": {}}})
+	assert.Error(t, err)
+	_, err = NewConfig(ExternalConfig{Overrides: map[string]ExternalConfig{"foo": {}, "foo/": {}}})
+	assert.Error(t, err)
+	_, err = NewConfig(
+		ExternalConfig{
+			Overrides: map[string]ExternalConfig{
+				"foo": {
+					Overrides: map[string]ExternalConfig{"foo/bar": {}},
+				},
+			},
+		},
+	)
+	assert.Error(t, err)
+}
+
+func TestConfigRequiresFileContent(t *testing.T) {
+	t.Parallel()
+	config, err := NewConfig(ExternalConfig{Use: []string{"MESSAGE_PASCAL_CASE"}})
+	require.NoError(t, err)
+	assert.False(t, config.RequiresFileContent())
+	config, err = NewConfig(ExternalConfig{Use: []string{"MESSAGE_PASCAL_CASE", "FILE_NO_CRLF"}})
+	require.NoError(t, err)
+	assert.True(t, config.RequiresFileContent())
+	config, err = NewConfig(
+		ExternalConfig{
+			Use: []string{"MESSAGE_PASCAL_CASE"},
+			Overrides: map[string]ExternalConfig{
+				"foo": {
+					Use: []string{"FILE_NO_CRLF"},
+				},
+			},
+		},
+	)
+	require.NoError(t, err)
+	assert.True(t, config.RequiresFileContent())
+}
+
+func TestExtendExternalConfig(t *testing.T) {
+	t.Parallel()
+	extended, err := ExtendExternalConfig(
+		ExternalConfig{
+			Use:                 []string{"DEFAULT"},
+			Ignore:              []string{"foo", "bar"},
+			EnumZeroValueSuffix: "_NONE",
+			IgnoreOnly: map[string][]string{
+				"ENUM_PASCAL_CASE":    {"foo"},
+				"MESSAGE_PASCAL_CASE": {"foo", "bar"},
+			},
+			Overrides: map[string]ExternalConfig{
+				"foo": {
+					Use: []string{"COMMENTS"},
+				},
+			},
+		},
+		ExternalConfig{
+			Use:                 []string{"COMMENTS", "DEFAULT"},
+			Ignore:              []string{"baz"},
+			AllowCommentIgnores: proto.Bool(true),
+			IgnoreOnly: map[string][]string{
+				"ENUM_PASCAL_CASE": {"baz"},
+			},
+			Overrides: map[string]ExternalConfig{
+				"foo": {},
+			},
+			Extends:      "base.yaml",
+			IgnoreRemove: []string{"foo"},
+		},
+	)
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		ExternalConfig{
+			Use:                 []string{"DEFAULT", "COMMENTS"},
+			Ignore:              []string{"bar", "baz"},
+			EnumZeroValueSuffix: "_NONE",
+			AllowCommentIgnores: proto.Bool(true),
+			IgnoreOnly: map[string][]string{
+				"ENUM_PASCAL_CASE":    {"baz"},
+				"MESSAGE_PASCAL_CASE": {"bar"},
+			},
+			Overrides: map[string]ExternalConfig{
+				"foo": {},
+			},
+		},
+		extended,
+	)
+	_, err = ExtendExternalConfig(ExternalConfig{Extends: "base.yaml"}, ExternalConfig{})
+	assert.Error(t, err)
+}
+
+func TestNewConfigExtendsUnresolved(t *testing.T) {
+	t.Parallel()
+	_, err := NewConfig(ExternalConfig{Extends: "base.yaml"})
+	assert.Error(t, err)
+	_, err = NewConfig(ExternalConfig{IgnoreRemove: []string{"foo"}})
+	assert.Error(t, err)
+}
+
+func TestNewConfigInfo(t *testing.T) {
+	t.Parallel()
+	config, err := NewConfig(
+		ExternalConfig{
+			Use:  []string{"BASIC"},
+			Info: []string{"FIELD_LOWER_SNAKE_CASE"},
+		},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]struct{}{"FIELD_LOWER_SNAKE_CASE": {}}, config.InfoIDs)
+	_, err = NewConfig(
+		ExternalConfig{
+			Use:   []string{"BASIC"},
+			Error: []string{"FIELD_LOWER_SNAKE_CASE"},
+			Info:  []string{"FIELD_LOWER_SNAKE_CASE"},
+		},
+	)
+	assert.Error(t, err)
+}
+
+func TestNewConfigFieldDeprecatedReserveSeverity(t *testing.T) {
+	t.Parallel()
+	for _, severity := range []string{"", "info", "warning", "error"} {
+		_, err := NewConfig(
+			ExternalConfig{
+				Use:                            []string{"FIELD_DEPRECATED_RESERVE"},
+				FieldDeprecatedReserveSeverity: severity,
+			},
+		)
+		assert.NoError(t, err, severity)
+	}
+	_, err := NewConfig(
+		ExternalConfig{
+			Use:                            []string{"FIELD_DEPRECATED_RESERVE"},
+			FieldDeprecatedReserveSeverity: "fatal",
+		},
+	)
+	assert.Error(t, err)
+}
+
+func TestMergeExternalConfigs(t *testing.T) {
+	t.Parallel()
+	merged := mergeExternalConfigs(
+		ExternalConfig{
+			Use:                 []string{"DEFAULT"},
+			Except:              []string{"ENUM_ZERO_VALUE_SUFFIX"},
+			EnumZeroValueSuffix: "_NONE",
+			IgnorePathPrefixes:  []string{"vendor/"},
+			Strict:              proto.Bool(true),
+			Overrides: map[string]ExternalConfig{
+				"foo": {},
+			},
+		},
+		ExternalConfig{
+			Except:              []string{"FIELD_LOWER_SNAKE_CASE"},
+			IgnorePathPrefixes:  []string{},
+			AllowCommentIgnores: proto.Bool(true),
+			Strict:              proto.Bool(false),
+		},
+	)
+	assert.Equal(
+		t,
+		ExternalConfig{
+			Use:                 []string{"DEFAULT"},
+			Except:              []string{"FIELD_LOWER_SNAKE_CASE"},
+			EnumZeroValueSuffix: "_NONE",
+			IgnorePathPrefixes:  []string{},
+			AllowCommentIgnores: proto.Bool(true),
+			Strict:              proto.Bool(false),
+		},
+		merged,
+	)
+}
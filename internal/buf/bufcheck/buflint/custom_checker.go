@@ -0,0 +1,39 @@
+// Copyright 2020 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buflint
+
+import (
+	"github.com/bufbuild/buf/internal/buf/bufcheck/buflint/internal"
+	bufcheckinternal "github.com/bufbuild/buf/internal/buf/bufcheck/internal"
+	"github.com/bufbuild/buf/internal/pkg/protosource"
+)
+
+func newCustomChecker(id string, purpose string, customCheckFunc CustomCheckFunc) (*checker, error) {
+	internalChecker, err := bufcheckinternal.NewNopCheckerBuilder(
+		id,
+		purpose,
+		newAdapter(
+			internal.NewCustomFileCheckFunc(
+				func(add func(protosource.Descriptor, protosource.Location, string, ...interface{}), file protosource.File) error {
+					return customCheckFunc(add, file)
+				},
+			),
+		),
+	).NewChecker(bufcheckinternal.ConfigBuilder{}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return newChecker(internalChecker), nil
+}
@@ -16,27 +16,45 @@ package buflint
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/bufbuild/buf/internal/buf/bufanalysis"
 	"github.com/bufbuild/buf/internal/buf/bufcheck/internal"
 	"github.com/bufbuild/buf/internal/buf/bufcore"
 	"github.com/bufbuild/buf/internal/buf/bufcore/bufcoreutil"
+	"github.com/bufbuild/buf/internal/pkg/instrument"
+	"github.com/bufbuild/buf/internal/pkg/normalpath"
 	"github.com/bufbuild/buf/internal/pkg/protosource"
 	"go.uber.org/zap"
 )
 
 const globalIgnorePrefix = "buf:lint:ignore"
 
+// fileContentCheckerIDs are the IDs of the Checkers that operate on raw file content.
+var fileContentCheckerIDs = map[string]struct{}{
+	"FILE_NO_CRLF": {},
+}
+
 type handler struct {
-	logger *zap.Logger
-	runner *internal.Runner
+	logger          *zap.Logger
+	runner          *internal.Runner
+	customCheckers  []Checker
+	fileContentFunc func(context.Context, bufcore.ImageFile) ([]byte, error)
+	// fileAnnotationsFunc is called with the FileAnnotations of each file
+	fileAnnotationsFunc func([]bufanalysis.FileAnnotation) error
 }
 
-func newHandler(logger *zap.Logger) *handler {
-	return &handler{
+func newHandler(logger *zap.Logger, options ...HandlerOption) *handler {
+	handler := &handler{
 		logger: logger,
 		runner: internal.NewRunner(logger, globalIgnorePrefix),
 	}
+	for _, option := range options {
+		option(handler)
+	}
+	return handler
 }
 
 func (h *handler) Check(
@@ -44,9 +62,158 @@ func (h *handler) Check(
 	config *Config,
 	image bufcore.Image,
 ) ([]bufanalysis.FileAnnotation, error) {
-	files, err := protosource.NewFilesUnstable(ctx, bufcoreutil.NewInputFiles(image.Files())...)
+	// the Checkers are run once over all files, with the Config of the nearest
+	// overrides directory applied to each file, so that the Checkers that check
+	// multiple files together see the files of every directory
+	configs := []*Config{config}
+	dirPaths := make([]string, 0, len(config.DirPathToConfig))
+	for dirPath := range config.DirPathToConfig {
+		dirPaths = append(dirPaths, dirPath)
+	}
+	sort.Strings(dirPaths)
+	for _, dirPath := range dirPaths {
+		configs = append(configs, config.DirPathToConfig[dirPath])
+	}
+	internalConfigs := make(map[*Config]*internal.Config, len(configs))
+	withFileContent := false
+	for _, checkConfig := range configs {
+		internalConfig := configToInternalConfig(checkConfig)
+		internalConfigs[checkConfig] = internalConfig
+		if getFileContentCheckerID(internalConfig.Checkers) != "" {
+			withFileContent = true
+		}
+	}
+	var imageFiles []bufcore.ImageFile
+	pathToInternalConfig := make(map[string]*internal.Config)
+	for _, imageFile := range image.Files() {
+		fileConfig := config
+		if dirPath := getOverrideDirPath(config.DirPathToConfig, imageFile.Path()); dirPath != "" {
+			fileConfig = config.DirPathToConfig[dirPath]
+		}
+		if pathHasAnyPrefix(imageFile.Path(), fileConfig.IgnorePathPrefixes) {
+			continue
+		}
+		imageFiles = append(imageFiles, imageFile)
+		if fileConfig != config {
+			pathToInternalConfig[imageFile.Path()] = internalConfigs[fileConfig]
+		}
+	}
+	timer := instrument.Start(h.logger, "new_files")
+	inputFiles, hasFileContent, err := h.getInputFiles(ctx, imageFiles, withFileContent)
+	if err != nil {
+		return nil, err
+	}
+	if withFileContent && !hasFileContent {
+		var skippedFileContentCheckerID string
+		for _, checkConfig := range configs {
+			internalConfig := internalConfigs[checkConfig]
+			fileContentCheckerID := getFileContentCheckerID(internalConfig.Checkers)
+			if fileContentCheckerID == "" {
+				continue
+			}
+			if !checkConfig.Strict {
+				return nil, fmt.Errorf("%s requires the raw content of files, which is only available when linting sources", fileContentCheckerID)
+			}
+			// strict enables every Checker regardless of the input, so the Checkers
+			// that require the raw content are not run instead of failing
+			skippedFileContentCheckerID = fileContentCheckerID
+			internalConfig.Checkers = getNonFileContentCheckers(internalConfig.Checkers)
+		}
+		if skippedFileContentCheckerID != "" {
+			h.logger.Sugar().Warnf(
+				"The raw content of files is only available when linting sources, so %s is not checked.",
+				skippedFileContentCheckerID,
+			)
+		}
+	}
+	files, err := protosource.NewFiles(ctx, inputFiles...)
 	if err != nil {
 		return nil, err
 	}
-	return h.runner.Check(ctx, configToInternalConfig(config), nil, files)
+	timer.End(zap.Int("num_files", len(files)))
+	if len(h.customCheckers) > 0 {
+		customCheckers := checkersToInternalCheckers(h.customCheckers)
+		for _, internalConfig := range internalConfigs {
+			internalConfig.Checkers = append(internalConfig.Checkers, customCheckers...)
+		}
+	}
+	return h.runner.CheckFiles(
+		ctx,
+		internalConfigs[config],
+		pathToInternalConfig,
+		files,
+		v1MultiFileIDs,
+		h.fileAnnotationsFunc,
+	)
+}
+
+// getInputFiles gets the InputFiles for the ImageFiles.
+//
+// The raw content of the files is only read if withFileContent is true.
+// The returned bool is true if the raw content of every file is available.
+func (h *handler) getInputFiles(
+	ctx context.Context,
+	imageFiles []bufcore.ImageFile,
+	withFileContent bool,
+) ([]protosource.InputFile, bool, error) {
+	inputFiles := bufcoreutil.NewInputFiles(imageFiles)
+	if !withFileContent {
+		return inputFiles, false, nil
+	}
+	if h.fileContentFunc == nil {
+		return inputFiles, false, nil
+	}
+	for i, imageFile := range imageFiles {
+		content, err := h.fileContentFunc(ctx, imageFile)
+		if err != nil {
+			return nil, false, err
+		}
+		if content == nil {
+			return inputFiles, false, nil
+		}
+		inputFiles[i] = protosource.NewInputFileWithContent(inputFiles[i], content)
+	}
+	return inputFiles, true, nil
+}
+
+// getFileContentCheckerID returns the ID of the first Checker that operates on
+// raw file content, or empty if there is no such Checker.
+func getFileContentCheckerID(checkers []*internal.Checker) string {
+	for _, checker := range checkers {
+		if _, ok := fileContentCheckerIDs[checker.ID()]; ok {
+			return checker.ID()
+		}
+	}
+	return ""
+}
+
+func getNonFileContentCheckers(checkers []*internal.Checker) []*internal.Checker {
+	nonFileContentCheckers := make([]*internal.Checker, 0, len(checkers))
+	for _, checker := range checkers {
+		if _, ok := fileContentCheckerIDs[checker.ID()]; !ok {
+			nonFileContentCheckers = append(nonFileContentCheckers, checker)
+		}
+	}
+	return nonFileContentCheckers
+}
+
+// getOverrideDirPath returns the nearest directory in dirPathToConfig that contains
+// the path, or empty if there is no such directory.
+func getOverrideDirPath(dirPathToConfig map[string]*Config, path string) string {
+	var overrideDirPath string
+	for dirPath := range dirPathToConfig {
+		if normalpath.ContainsPath(dirPath, path, normalpath.Relative) && len(dirPath) > len(overrideDirPath) {
+			overrideDirPath = dirPath
+		}
+	}
+	return overrideDirPath
+}
+
+func pathHasAnyPrefix(path string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
 }
@@ -15,7 +15,10 @@
 package internal
 
 import (
+	"bytes"
 	"errors"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -29,12 +32,8 @@ import (
 var (
 	// CheckCommentEnum is a check function.
 	CheckCommentEnum = newEnumCheckFunc(checkCommentEnum)
-	// CheckCommentEnumValue is a check function.
-	CheckCommentEnumValue = newEnumValueCheckFunc(checkCommentEnumValue)
 	// CheckCommentField is a check function.
 	CheckCommentField = newFieldCheckFunc(checkCommentField)
-	// CheckCommentMessage is a check function.
-	CheckCommentMessage = newMessageCheckFunc(checkCommentMessage)
 	// CheckCommentOneof is a check function.
 	CheckCommentOneof = newOneofCheckFunc(checkCommentOneof)
 	// CheckCommentService is a check function.
@@ -47,7 +46,28 @@ func checkCommentEnum(add addFunc, value protosource.Enum) error {
 	return checkCommentNamedDescriptor(add, value, "Enum")
 }
 
-func checkCommentEnumValue(add addFunc, value protosource.EnumValue) error {
+// CheckCommentEnumValue is a check function.
+var CheckCommentEnumValue = func(
+	id string,
+	ignoreFunc internal.IgnoreFunc,
+	files []protosource.File,
+	packages []string,
+	allowZero bool,
+) ([]bufanalysis.FileAnnotation, error) {
+	return newEnumValueCheckFunc(
+		func(add addFunc, enumValue protosource.EnumValue) error {
+			return checkCommentEnumValue(add, enumValue, packages, allowZero)
+		},
+	)(id, ignoreFunc, files)
+}
+
+func checkCommentEnumValue(add addFunc, value protosource.EnumValue, packages []string, allowZero bool) error {
+	if allowZero && value.Number() == 0 {
+		return nil
+	}
+	if len(packages) > 0 && !packageMatchesAny(value.File().Package(), packages) {
+		return nil
+	}
 	return checkCommentNamedDescriptor(add, value, "Enum value")
 }
 
@@ -55,7 +75,28 @@ func checkCommentField(add addFunc, value protosource.Field) error {
 	return checkCommentNamedDescriptor(add, value, "Field")
 }
 
-func checkCommentMessage(add addFunc, value protosource.Message) error {
+// CheckCommentMessage is a check function.
+var CheckCommentMessage = func(
+	id string,
+	ignoreFunc internal.IgnoreFunc,
+	files []protosource.File,
+	packages []string,
+	topLevelOnly bool,
+) ([]bufanalysis.FileAnnotation, error) {
+	return newMessageCheckFunc(
+		func(add addFunc, message protosource.Message) error {
+			return checkCommentMessage(add, message, packages, topLevelOnly)
+		},
+	)(id, ignoreFunc, files)
+}
+
+func checkCommentMessage(add addFunc, value protosource.Message, packages []string, topLevelOnly bool) error {
+	if topLevelOnly && value.Parent() != nil {
+		return nil
+	}
+	if len(packages) > 0 && !packageMatchesAny(value.File().Package(), packages) {
+		return nil
+	}
 	return checkCommentNamedDescriptor(add, value, "Message")
 }
 
@@ -87,6 +128,232 @@ func checkCommentNamedDescriptor(
 	return nil
 }
 
+// NewCustomFileCheckFunc returns a new check function that calls f for every File.
+//
+// This is used for custom checkers that are registered outside of this package.
+func NewCustomFileCheckFunc(
+	f func(func(protosource.Descriptor, protosource.Location, string, ...interface{}), protosource.File) error,
+) func(string, internal.IgnoreFunc, []protosource.File) ([]bufanalysis.FileAnnotation, error) {
+	return newFileCheckFunc(
+		func(add addFunc, file protosource.File) error {
+			return f(add, file)
+		},
+	)
+}
+
+// CheckAcronymCasing is a check function.
+var CheckAcronymCasing = func(
+	id string,
+	ignoreFunc internal.IgnoreFunc,
+	files []protosource.File,
+	acronyms []string,
+) ([]bufanalysis.FileAnnotation, error) {
+	return newFileCheckFunc(
+		func(add addFunc, file protosource.File) error {
+			if err := protosource.ForEachMessage(
+				func(message protosource.Message) error {
+					return checkAcronymCasing(add, message, acronyms)
+				},
+				file,
+			); err != nil {
+				return err
+			}
+			return protosource.ForEachEnum(
+				func(enum protosource.Enum) error {
+					return checkEnumAcronymCasing(add, enum, acronyms)
+				},
+				file,
+			)
+		},
+	)(id, ignoreFunc, files)
+}
+
+func checkEnumAcronymCasing(add addFunc, enum protosource.Enum, acronyms []string) error {
+	if found, acronym, ok := findMiscasedAcronym(enum.Name(), acronyms, false, false); ok {
+		add(enum, enum.NameLocation(), "Enum name %q should use %q instead of %q.", enum.Name(), acronym, found)
+	}
+	return nil
+}
+
+func checkAcronymCasing(add addFunc, message protosource.Message, acronyms []string) error {
+	// map entry names are generated from the field name
+	if message.IsMapEntry() {
+		return nil
+	}
+	if found, acronym, ok := findMiscasedAcronym(message.Name(), acronyms, false, false); ok {
+		add(message, message.NameLocation(), "Message name %q should use %q instead of %q.", message.Name(), acronym, found)
+	}
+	for _, field := range message.Fields() {
+		// lower_snake_case field names are fine, the acronym casing then applies to the json_name
+		if found, acronym, ok := findMiscasedAcronym(field.Name(), acronyms, true, true); ok {
+			add(field, field.NameLocation(), "Field name %q should use %q instead of %q.", field.Name(), acronym, found)
+			continue
+		}
+		if found, acronym, ok := findMiscasedAcronym(field.JSONName(), acronyms, false, true); ok {
+			location := field.JSONNameLocation()
+			if location == nil {
+				location = field.NameLocation()
+			}
+			add(field, location, "Field %q has JSON name %q which should use %q instead of %q, set json_name to fix this.", field.Name(), field.JSONName(), acronym, found)
+		}
+	}
+	return nil
+}
+
+// findMiscasedAcronym returns the first word in name that is one of the acronyms
+// with a different casing than the acronym, along with that acronym.
+//
+// Words are delimited by underscores, digits, and uppercase characters.
+// If allowLowerWords is true, all lowercase words are allowed, as is required
+// for lower_snake_case names. If allowLowerFirstWord is true, an all lowercase
+// first word is allowed, as is required for lowerCamelCase names.
+func findMiscasedAcronym(name string, acronyms []string, allowLowerWords bool, allowLowerFirstWord bool) (string, string, bool) {
+	for _, acronym := range acronyms {
+		for start := 0; start+len(acronym) <= len(name); start++ {
+			end := start + len(acronym)
+			found := name[start:end]
+			if found == acronym || !strings.EqualFold(found, acronym) {
+				continue
+			}
+			if !isAcronymWordStart(name, start) || !isAcronymWordEnd(name, end) {
+				continue
+			}
+			if found == strings.ToLower(found) && (allowLowerWords || (allowLowerFirstWord && start == 0)) {
+				continue
+			}
+			return found, acronym, true
+		}
+	}
+	return "", "", false
+}
+
+func isAcronymWordStart(name string, start int) bool {
+	if start == 0 {
+		return true
+	}
+	previous := name[start-1]
+	return previous == '_' || isASCIIDigit(previous) || isASCIIUpper(name[start])
+}
+
+func isAcronymWordEnd(name string, end int) bool {
+	if end == len(name) {
+		return true
+	}
+	next := name[end]
+	return next == '_' || isASCIIDigit(next) || isASCIIUpper(next)
+}
+
+func isASCIIUpper(c byte) bool {
+	return 'A' <= c && c <= 'Z'
+}
+
+func isASCIIDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// CheckDeprecationComment is a check function.
+var CheckDeprecationComment = func(
+	id string,
+	ignoreFunc internal.IgnoreFunc,
+	files []protosource.File,
+	commentRegexp *regexp.Regexp,
+) ([]bufanalysis.FileAnnotation, error) {
+	return newFileCheckFunc(
+		func(add addFunc, file protosource.File) error {
+			return checkDeprecationComment(add, file, commentRegexp)
+		},
+	)(id, ignoreFunc, files)
+}
+
+func checkDeprecationComment(add addFunc, file protosource.File, commentRegexp *regexp.Regexp) error {
+	if err := protosource.ForEachEnum(
+		func(enum protosource.Enum) error {
+			if enum.Deprecated() {
+				checkDeprecationCommentNamedDescriptor(add, enum, commentRegexp, "Enum")
+			}
+			return nil
+		},
+		file,
+	); err != nil {
+		return err
+	}
+	return protosource.ForEachMessage(
+		func(message protosource.Message) error {
+			if message.Deprecated() {
+				checkDeprecationCommentNamedDescriptor(add, message, commentRegexp, "Message")
+			}
+			for _, field := range message.Fields() {
+				if field.Deprecated() {
+					checkDeprecationCommentNamedDescriptor(add, field, commentRegexp, "Field")
+				}
+			}
+			for _, extension := range message.Extensions() {
+				if extension.Deprecated() {
+					checkDeprecationCommentNamedDescriptor(add, extension, commentRegexp, "Extension")
+				}
+			}
+			return nil
+		},
+		file,
+	)
+}
+
+func checkDeprecationCommentNamedDescriptor(
+	add addFunc,
+	namedDescriptor protosource.NamedDescriptor,
+	commentRegexp *regexp.Regexp,
+	typeName string,
+) {
+	location := namedDescriptor.Location()
+	if location == nil {
+		// comments can only be checked with source code info
+		return
+	}
+	if !commentRegexp.MatchString(location.LeadingComments()) {
+		add(namedDescriptor, location, "%s %q is deprecated but its leading comment does not match %q.", typeName, namedDescriptor.Name(), commentRegexp.String())
+	}
+}
+
+// CheckDirectoryPackageMajority is a check function.
+var CheckDirectoryPackageMajority = newDirToFilesCheckFunc(checkDirectoryPackageMajority)
+
+func checkDirectoryPackageMajority(add addFunc, dirPath string, files []protosource.File) error {
+	pkgToCount := make(map[string]int)
+	for _, file := range files {
+		// works for no package set as this will result in "" which is a valid map key
+		pkgToCount[file.Package()]++
+	}
+	if len(pkgToCount) < 2 {
+		return nil
+	}
+	pkgs := make([]string, 0, len(pkgToCount))
+	for pkg := range pkgToCount {
+		pkgs = append(pkgs, pkg)
+	}
+	sort.Strings(pkgs)
+	var majorityPkg string
+	majorityCount := 0
+	tied := false
+	for _, pkg := range pkgs {
+		switch count := pkgToCount[pkg]; {
+		case count > majorityCount:
+			majorityPkg = pkg
+			majorityCount = count
+			tied = false
+		case count == majorityCount:
+			tied = true
+		}
+	}
+	for _, file := range files {
+		if tied {
+			add(file, file.PackageLocation(), "Multiple packages %q are used by the same number of files within directory %q.", strings.Join(pkgs, ","), dirPath)
+		} else if file.Package() != majorityPkg {
+			add(file, file.PackageLocation(), "Package %q differs from package %q used by most files within directory %q.", file.Package(), majorityPkg, dirPath)
+		}
+	}
+	return nil
+}
+
 // CheckDirectorySamePackage is a check function.
 var CheckDirectorySamePackage = newDirToFilesCheckFunc(checkDirectorySamePackage)
 
@@ -105,6 +372,24 @@ func checkDirectorySamePackage(add addFunc, dirPath string, files []protosource.
 	return nil
 }
 
+// CheckEnumAllowAliasUsed is a check function.
+var CheckEnumAllowAliasUsed = newEnumCheckFunc(checkEnumAllowAliasUsed)
+
+func checkEnumAllowAliasUsed(add addFunc, enum protosource.Enum) error {
+	if !enum.AllowAlias() {
+		return nil
+	}
+	numbers := make(map[int]struct{}, len(enum.Values()))
+	for _, enumValue := range enum.Values() {
+		if _, ok := numbers[enumValue.Number()]; ok {
+			return nil
+		}
+		numbers[enumValue.Number()] = struct{}{}
+	}
+	add(enum, enum.AllowAliasLocation(), `Enum option "allow_alias" on enum %q is set but no two values of the enum have the same number.`, enum.Name())
+	return nil
+}
+
 // CheckEnumNoAllowAlias is a check function.
 var CheckEnumNoAllowAlias = newEnumCheckFunc(checkEnumNoAllowAlias)
 
@@ -115,6 +400,30 @@ func checkEnumNoAllowAlias(add addFunc, enum protosource.Enum) error {
 	return nil
 }
 
+// CheckEnumNotNested is a check function.
+var CheckEnumNotNested = func(
+	id string,
+	ignoreFunc internal.IgnoreFunc,
+	files []protosource.File,
+	allowEnums map[string]struct{},
+) ([]bufanalysis.FileAnnotation, error) {
+	return newMessageCheckFunc(
+		func(add addFunc, message protosource.Message) error {
+			return checkEnumNotNested(add, message, allowEnums)
+		},
+	)(id, ignoreFunc, files)
+}
+
+func checkEnumNotNested(add addFunc, message protosource.Message, allowEnums map[string]struct{}) error {
+	for _, enum := range message.Enums() {
+		if _, ok := allowEnums[enum.FullName()]; ok {
+			continue
+		}
+		add(enum, enum.NameLocation(), "Enum %q should be defined at the top level instead of in message %q.", enum.Name(), message.Name())
+	}
+	return nil
+}
+
 // CheckEnumPascalCase is a check function.
 var CheckEnumPascalCase = newEnumCheckFunc(checkEnumPascalCase)
 
@@ -140,6 +449,98 @@ func checkEnumFirstValueZero(add addFunc, enum protosource.Enum) error {
 	return nil
 }
 
+// CheckEnumValueMaxCount is a check function.
+var CheckEnumValueMaxCount = func(
+	id string,
+	ignoreFunc internal.IgnoreFunc,
+	files []protosource.File,
+	maxCount int,
+	distinctNumbers bool,
+) ([]bufanalysis.FileAnnotation, error) {
+	return newEnumCheckFunc(
+		func(add addFunc, enum protosource.Enum) error {
+			return checkEnumValueMaxCount(add, enum, maxCount, distinctNumbers)
+		},
+	)(id, ignoreFunc, files)
+}
+
+func checkEnumValueMaxCount(add addFunc, enum protosource.Enum, maxCount int, distinctNumbers bool) error {
+	values := enum.Values()
+	count := len(values)
+	if distinctNumbers {
+		// aliases share a number and are only counted once
+		numbers := make(map[int]struct{}, len(values))
+		for _, value := range values {
+			numbers[value.Number()] = struct{}{}
+		}
+		count = len(numbers)
+	}
+	if count > maxCount {
+		add(enum, enum.NameLocation(), "Enum %q has %d values, which exceeds the maximum of %d.", enum.Name(), count, maxCount)
+	}
+	return nil
+}
+
+// CheckEnumValueNoGap is a check function.
+var CheckEnumValueNoGap = newEnumCheckFunc(checkEnumValueNoGap)
+
+func checkEnumValueNoGap(add addFunc, enum protosource.Enum) error {
+	numberToSeen := make(map[int]struct{})
+	var numbers []int
+	for _, value := range enum.Values() {
+		if _, ok := numberToSeen[value.Number()]; !ok {
+			numberToSeen[value.Number()] = struct{}{}
+			numbers = append(numbers, value.Number())
+		}
+	}
+	sort.Ints(numbers)
+	reservedRanges := make([]protosource.TagRange, 0, len(enum.ReservedEnumRanges()))
+	for _, reservedEnumRange := range enum.ReservedEnumRanges() {
+		reservedRanges = append(reservedRanges, reservedEnumRange)
+	}
+	for i := 1; i < len(numbers); i++ {
+		if gapStart, gapEnd, ok := getUnreservedGap(numbers[i-1]+1, numbers[i]-1, reservedRanges); ok {
+			// only the first gap is reported so that there is one annotation per enum
+			if gapStart == gapEnd {
+				add(enum, enum.NameLocation(), "Enum %q skips value number %d, which should be reserved if it was previously used.", enum.Name(), gapStart)
+			} else {
+				add(enum, enum.NameLocation(), "Enum %q skips value numbers %d to %d, which should be reserved if they were previously used.", enum.Name(), gapStart, gapEnd)
+			}
+			return nil
+		}
+	}
+	return nil
+}
+
+// getUnreservedGap returns the first sub-range of [start, end] not covered by the reserved ranges.
+//
+// Returns false if [start, end] is empty or fully covered.
+func getUnreservedGap(start int, end int, reservedRanges []protosource.TagRange) (int, int, bool) {
+	for start <= end {
+		covered := false
+		for _, reservedRange := range reservedRanges {
+			if reservedRange.Start() <= start && start <= reservedRange.End() {
+				start = reservedRange.End() + 1
+				covered = true
+				break
+			}
+		}
+		if covered {
+			continue
+		}
+		// find where the uncovered gap ends, which is either the end or
+		// right before the next reserved range
+		gapEnd := end
+		for _, reservedRange := range reservedRanges {
+			if start < reservedRange.Start() && reservedRange.Start()-1 < gapEnd {
+				gapEnd = reservedRange.Start() - 1
+			}
+		}
+		return start, gapEnd, true
+	}
+	return 0, 0, false
+}
+
 // CheckEnumValuePrefix is a check function.
 var CheckEnumValuePrefix = newEnumValueCheckFunc(checkEnumValuePrefix)
 
@@ -189,6 +590,99 @@ func checkEnumZeroValueSuffix(add addFunc, enumValue protosource.EnumValue, suff
 	return nil
 }
 
+// CheckFieldJSONNameNoConflict is a check function.
+var CheckFieldJSONNameNoConflict = newMessageCheckFunc(checkFieldJSONNameNoConflict)
+
+func checkFieldJSONNameNoConflict(add addFunc, message protosource.Message) error {
+	if message.IsMapEntry() {
+		// map entries are generated by the compiler
+		return nil
+	}
+	fields := message.Fields()
+	for i, field := range fields {
+		jsonName := getJSONName(field)
+		defaultJSONName := toDefaultJSONName(field.Name())
+		jsonNameLocation := field.JSONNameLocation()
+		if jsonNameLocation == nil {
+			jsonNameLocation = field.NameLocation()
+		}
+		// the default JSON names are compared as well, as the explicit json_name
+		// of one field can conflict with the default JSON name of another field
+		for _, otherField := range fields[:i] {
+			otherJSONName := getJSONName(otherField)
+			otherDefaultJSONName := toDefaultJSONName(otherField.Name())
+			if jsonName == otherJSONName {
+				add(field, jsonNameLocation, "Field %q has JSON name %q which conflicts with the JSON name of field %q.", field.Name(), jsonName, otherField.Name())
+				break
+			}
+			if jsonName == otherDefaultJSONName {
+				add(field, jsonNameLocation, "Field %q has JSON name %q which conflicts with the default JSON name of field %q.", field.Name(), jsonName, otherField.Name())
+				break
+			}
+			if defaultJSONName == otherJSONName {
+				add(field, field.NameLocation(), "Field %q has default JSON name %q which conflicts with the JSON name of field %q.", field.Name(), defaultJSONName, otherField.Name())
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// CheckFieldLabelConsistent is a check function.
+var CheckFieldLabelConsistent = func(
+	id string,
+	ignoreFunc internal.IgnoreFunc,
+	files []protosource.File,
+	allowFields map[string]struct{},
+) ([]bufanalysis.FileAnnotation, error) {
+	return newMessageCheckFunc(
+		func(add addFunc, message protosource.Message) error {
+			return checkFieldLabelConsistent(add, message, allowFields)
+		},
+	)(id, ignoreFunc, files)
+}
+
+func checkFieldLabelConsistent(add addFunc, message protosource.Message, allowFields map[string]struct{}) error {
+	if message.IsMapEntry() {
+		// map entries are generated by the compiler
+		return nil
+	}
+	nameToField := make(map[string]protosource.Field, len(message.Fields()))
+	for _, field := range message.Fields() {
+		nameToField[field.Name()] = field
+	}
+	// this is a heuristic, only names that differ by a trailing "s" are compared
+	for _, pluralField := range message.Fields() {
+		singularName := strings.TrimSuffix(pluralField.Name(), "s")
+		if singularName == pluralField.Name() || singularName == "" {
+			continue
+		}
+		singularField, ok := nameToField[singularName]
+		if !ok {
+			continue
+		}
+		pluralRepeated := pluralField.Label() == protosource.FieldDescriptorProtoLabelRepeated
+		singularRepeated := singularField.Label() == protosource.FieldDescriptorProtoLabelRepeated
+		if pluralRepeated == singularRepeated {
+			continue
+		}
+		if _, ok := allowFields[singularField.FullName()]; ok {
+			continue
+		}
+		if _, ok := allowFields[pluralField.FullName()]; ok {
+			continue
+		}
+		repeatedName := pluralField.Name()
+		if singularRepeated {
+			repeatedName = singularField.Name()
+		}
+		for _, field := range []protosource.Field{singularField, pluralField} {
+			add(field, field.NameLocation(), "Fields %q and %q differ only by a plural \"s\" but only %q is repeated.", singularField.Name(), pluralField.Name(), repeatedName)
+		}
+	}
+	return nil
+}
+
 // CheckFieldLowerSnakeCase is a check function.
 var CheckFieldLowerSnakeCase = newFieldCheckFunc(checkFieldLowerSnakeCase)
 
@@ -210,36 +704,626 @@ func checkFieldLowerSnakeCase(add addFunc, field protosource.Field) error {
 	return nil
 }
 
-// CheckFieldNoDescriptor is a check function.
-var CheckFieldNoDescriptor = newFieldCheckFunc(checkFieldNoDescriptor)
+// CheckFieldBoolAffirmative is a check function.
+var CheckFieldBoolAffirmative = func(
+	id string,
+	ignoreFunc internal.IgnoreFunc,
+	files []protosource.File,
+	negativePrefixes []string,
+) ([]bufanalysis.FileAnnotation, error) {
+	// the longest prefix is matched first so that "disabled" is preferred over "disable"
+	sortedNegativePrefixes := append([]string{}, negativePrefixes...)
+	sort.SliceStable(
+		sortedNegativePrefixes,
+		func(i int, j int) bool {
+			return len(sortedNegativePrefixes[i]) > len(sortedNegativePrefixes[j])
+		},
+	)
+	return newFieldCheckFunc(
+		func(add addFunc, field protosource.Field) error {
+			return checkFieldBoolAffirmative(add, field, sortedNegativePrefixes)
+		},
+	)(id, ignoreFunc, files)
+}
 
-func checkFieldNoDescriptor(add addFunc, field protosource.Field) error {
+func checkFieldBoolAffirmative(add addFunc, field protosource.Field, negativePrefixes []string) error {
+	if field.Type() != protosource.FieldDescriptorProtoTypeBool {
+		return nil
+	}
 	name := field.Name()
-	if strings.ToLower(strings.Trim(name, "_")) == "descriptor" {
-		add(field, field.NameLocation(), `Field name %q cannot be any capitalization of "descriptor" with any number of prefix or suffix underscores.`, name)
+	for _, negativePrefix := range negativePrefixes {
+		if !strings.HasPrefix(name, negativePrefix) {
+			continue
+		}
+		if affirmativeName := getFieldBoolAffirmativeName(name, negativePrefix); affirmativeName != "" {
+			add(field, field.NameLocation(), "Bool field %q should have an affirmative name such as %q instead of starting with %q.", name, affirmativeName, negativePrefix)
+		} else {
+			add(field, field.NameLocation(), "Bool field %q should have an affirmative name instead of starting with %q.", name, negativePrefix)
+		}
+		return nil
 	}
 	return nil
 }
 
-// CheckFileLowerSnakeCase is a check function.
-var CheckFileLowerSnakeCase = newFileCheckFunc(checkFileLowerSnakeCase)
+// CheckFieldDeprecatedReserve is a check function.
+var CheckFieldDeprecatedReserve = newFieldCheckFunc(checkFieldDeprecatedReserve)
 
-func checkFileLowerSnakeCase(add addFunc, file protosource.File) error {
-	filename := file.Path()
-	base := normalpath.Base(filename)
-	ext := normalpath.Ext(filename)
-	baseWithoutExt := strings.TrimSuffix(base, ext)
-	expectedBaseWithoutExt := stringutil.ToLowerSnakeCase(baseWithoutExt)
-	if baseWithoutExt != expectedBaseWithoutExt {
-		add(file, nil, `Filename %q should be lower_snake_case%s, such as "%s%s".`, base, ext, expectedBaseWithoutExt, ext)
+func checkFieldDeprecatedReserve(add addFunc, field protosource.Field) error {
+	if !field.Deprecated() {
+		return nil
+	}
+	location := field.DeprecatedLocation()
+	if location == nil {
+		location = field.Location()
 	}
+	add(field, location, "Field %q is deprecated. When it is deleted, reserve its number %d and name %q so that they are not reused.", field.Name(), field.Number(), field.Name())
 	return nil
 }
 
-var (
-	// CheckImportNoPublic is a check function.
-	CheckImportNoPublic = newFileImportCheckFunc(checkImportNoPublic)
-	// CheckImportNoWeak is a check function.
+// CheckFieldOptionConsistent is a check function.
+var CheckFieldOptionConsistent = func(
+	id string,
+	ignoreFunc internal.IgnoreFunc,
+	files []protosource.File,
+	optionName string,
+	optionNumber int,
+	fieldTypes map[string]struct{},
+) ([]bufanalysis.FileAnnotation, error) {
+	return newMessageCheckFunc(
+		func(add addFunc, message protosource.Message) error {
+			return checkFieldOptionConsistent(add, message, optionName, optionNumber, fieldTypes)
+		},
+	)(id, ignoreFunc, files)
+}
+
+func checkFieldOptionConsistent(
+	add addFunc,
+	message protosource.Message,
+	optionName string,
+	optionNumber int,
+	fieldTypes map[string]struct{},
+) error {
+	if message.IsMapEntry() {
+		// map entries are generated, so options cannot be set on their fields
+		return nil
+	}
+	var fieldTypeNames []string
+	fieldTypeNameToFieldsWithOption := make(map[string][]protosource.Field)
+	fieldTypeNameToFieldsWithoutOption := make(map[string][]protosource.Field)
+	for _, field := range message.Fields() {
+		fieldTypeName := field.Type().String()
+		// if no types are specified, all types are checked
+		if len(fieldTypes) > 0 {
+			if _, ok := fieldTypes[fieldTypeName]; !ok {
+				continue
+			}
+		}
+		_, withOption := fieldTypeNameToFieldsWithOption[fieldTypeName]
+		_, withoutOption := fieldTypeNameToFieldsWithoutOption[fieldTypeName]
+		if !withOption && !withoutOption {
+			fieldTypeNames = append(fieldTypeNames, fieldTypeName)
+		}
+		if field.HasOptionExtension(optionNumber) {
+			fieldTypeNameToFieldsWithOption[fieldTypeName] = append(fieldTypeNameToFieldsWithOption[fieldTypeName], field)
+		} else {
+			fieldTypeNameToFieldsWithoutOption[fieldTypeName] = append(fieldTypeNameToFieldsWithoutOption[fieldTypeName], field)
+		}
+	}
+	// the order of the fields is kept so that annotations are stable
+	for _, fieldTypeName := range fieldTypeNames {
+		fieldsWithOption := fieldTypeNameToFieldsWithOption[fieldTypeName]
+		if len(fieldsWithOption) == 0 {
+			continue
+		}
+		for _, field := range fieldTypeNameToFieldsWithoutOption[fieldTypeName] {
+			add(field, field.Location(), "Field %q of type %q should have the option (%s) set as field %q of the same type in message %q does.", field.Name(), fieldTypeName, optionName, fieldsWithOption[0].Name(), message.Name())
+		}
+	}
+	return nil
+}
+
+// CheckFieldPresenceComment is a check function.
+var CheckFieldPresenceComment = func(
+	id string,
+	ignoreFunc internal.IgnoreFunc,
+	files []protosource.File,
+	packages []string,
+) ([]bufanalysis.FileAnnotation, error) {
+	return newFieldCheckFunc(
+		func(add addFunc, field protosource.Field) error {
+			return checkFieldPresenceComment(add, field, packages)
+		},
+	)(id, ignoreFunc, files)
+}
+
+func checkFieldPresenceComment(add addFunc, field protosource.Field, packages []string) error {
+	file := field.File()
+	if file.Syntax() != protosource.SyntaxProto3 {
+		return nil
+	}
+	if len(packages) > 0 && !packageMatchesAny(file.Package(), packages) {
+		return nil
+	}
+	if field.Proto3Optional() || field.Label() == protosource.FieldDescriptorProtoLabelRepeated {
+		return nil
+	}
+	if _, ok := field.OneofIndex(); ok {
+		// fields in a oneof always have presence
+		return nil
+	}
+	switch field.Type() {
+	case protosource.FieldDescriptorProtoTypeMessage,
+		protosource.FieldDescriptorProtoTypeGroup,
+		protosource.FieldDescriptorProtoTypeEnum,
+		protosource.FieldDescriptorProtoTypeString,
+		protosource.FieldDescriptorProtoTypeBytes:
+		// only numeric and bool fields are checked
+		return nil
+	}
+	location := field.Location()
+	if location == nil {
+		// also skips map entry fields
+		return nil
+	}
+	if strings.TrimSpace(location.LeadingComments()) == "" && strings.TrimSpace(location.TrailingComments()) == "" {
+		add(field, location, "Field %q should either use the optional label or have a non-empty comment documenting the meaning of its zero value.", field.Name())
+	}
+	return nil
+}
+
+// CheckFieldProto3Optional is a check function.
+var CheckFieldProto3Optional = func(
+	id string,
+	ignoreFunc internal.IgnoreFunc,
+	files []protosource.File,
+	always bool,
+	packages []string,
+) ([]bufanalysis.FileAnnotation, error) {
+	return newFieldCheckFunc(
+		func(add addFunc, field protosource.Field) error {
+			return checkFieldProto3Optional(add, field, always, packages)
+		},
+	)(id, ignoreFunc, files)
+}
+
+func checkFieldProto3Optional(add addFunc, field protosource.Field, always bool, packages []string) error {
+	file := field.File()
+	if file.Syntax() != protosource.SyntaxProto3 {
+		return nil
+	}
+	if len(packages) > 0 && !packageMatchesAny(file.Package(), packages) {
+		return nil
+	}
+	if !always {
+		if field.Proto3Optional() {
+			add(field, field.Location(), "Field %q should not use the optional label.", field.Name())
+		}
+		return nil
+	}
+	if field.Proto3Optional() || field.Label() == protosource.FieldDescriptorProtoLabelRepeated {
+		return nil
+	}
+	if field.Message().IsMapEntry() {
+		// map key and value fields cannot use the optional label
+		return nil
+	}
+	switch field.Type() {
+	case protosource.FieldDescriptorProtoTypeMessage, protosource.FieldDescriptorProtoTypeGroup:
+		// message fields always have presence
+		return nil
+	}
+	if _, ok := field.OneofIndex(); ok {
+		// fields in a oneof always have presence
+		return nil
+	}
+	add(field, field.Location(), "Field %q should use the optional label.", field.Name())
+	return nil
+}
+
+// CheckFieldNamesDistinct is a check function.
+var CheckFieldNamesDistinct = func(
+	id string,
+	ignoreFunc internal.IgnoreFunc,
+	files []protosource.File,
+	maxDistance int,
+) ([]bufanalysis.FileAnnotation, error) {
+	return newMessageCheckFunc(
+		func(add addFunc, message protosource.Message) error {
+			return checkFieldNamesDistinct(add, message, maxDistance)
+		},
+	)(id, ignoreFunc, files)
+}
+
+func checkFieldNamesDistinct(add addFunc, message protosource.Message, maxDistance int) error {
+	if message.IsMapEntry() {
+		// map entries are generated by the compiler
+		return nil
+	}
+	fields := message.Fields()
+	for i, field := range fields {
+		for _, otherField := range fields[i+1:] {
+			if !fieldNamesSimilar(field.Name(), otherField.Name(), maxDistance) {
+				continue
+			}
+			add(field, field.NameLocation(), "Field %q is similar to field %q, which may be a typo.", field.Name(), otherField.Name())
+			add(otherField, otherField.NameLocation(), "Field %q is similar to field %q, which may be a typo.", otherField.Name(), field.Name())
+		}
+	}
+	return nil
+}
+
+// CheckFieldNoDescriptor is a check function.
+var CheckFieldNoDescriptor = newFieldCheckFunc(checkFieldNoDescriptor)
+
+func checkFieldNoDescriptor(add addFunc, field protosource.Field) error {
+	name := field.Name()
+	if strings.ToLower(strings.Trim(name, "_")) == "descriptor" {
+		add(field, field.NameLocation(), `Field name %q cannot be any capitalization of "descriptor" with any number of prefix or suffix underscores.`, name)
+	}
+	return nil
+}
+
+// CheckFieldNoGroup is a check function.
+var CheckFieldNoGroup = newFieldCheckFunc(checkFieldNoGroup)
+
+func checkFieldNoGroup(add addFunc, field protosource.Field) error {
+	if field.Type() == protosource.FieldDescriptorProtoTypeGroup {
+		add(field, field.Location(), "Field %q is a group, which is deprecated. Use a nested message instead.", field.Name())
+	}
+	return nil
+}
+
+// CheckFieldNoReserved is a check function.
+var CheckFieldNoReserved = newMessageCheckFunc(checkFieldNoReserved)
+
+func checkFieldNoReserved(add addFunc, message protosource.Message) error {
+	reservedTagRanges := message.ReservedTagRanges()
+	reservedNames := message.ReservedNames()
+	for _, field := range message.Fields() {
+		if protosource.NumberInReservedRanges(field.Number(), reservedTagRanges...) {
+			add(field, field.NumberLocation(), "Field %q on message %q uses number %d, which is reserved.", field.Name(), message.Name(), field.Number())
+		}
+		if protosource.NameInReservedNames(field.Name(), reservedNames...) {
+			add(field, field.NameLocation(), "Field %q on message %q uses a name which is reserved.", field.Name(), message.Name())
+		}
+	}
+	return nil
+}
+
+// CheckFieldNoAny is a check function.
+//
+// If packages is non-empty, only fields in the given packages or their sub-packages are checked.
+var CheckFieldNoAny = func(
+	id string,
+	ignoreFunc internal.IgnoreFunc,
+	files []protosource.File,
+	packages []string,
+	allowFields map[string]struct{},
+) ([]bufanalysis.FileAnnotation, error) {
+	return newFieldCheckFunc(
+		func(add addFunc, field protosource.Field) error {
+			return checkFieldNoAny(add, field, packages, allowFields)
+		},
+	)(id, ignoreFunc, files)
+}
+
+func checkFieldNoAny(add addFunc, field protosource.Field, packages []string, allowFields map[string]struct{}) error {
+	message := field.Message()
+	if message.IsMapEntry() {
+		// map entries are generated by the compiler, the map field itself is checked
+		return nil
+	}
+	if len(packages) > 0 && !packageMatchesAny(field.File().Package(), packages) {
+		return nil
+	}
+	if _, ok := allowFields[field.FullName()]; ok {
+		return nil
+	}
+	typeName := strings.TrimPrefix(field.TypeName(), ".")
+	for _, nestedMessage := range message.Messages() {
+		if nestedMessage.IsMapEntry() && nestedMessage.FullName() == typeName {
+			typeName = ""
+			for _, entryField := range nestedMessage.Fields() {
+				if entryField.Number() == 2 {
+					typeName = strings.TrimPrefix(entryField.TypeName(), ".")
+				}
+			}
+			break
+		}
+	}
+	if typeName == "google.protobuf.Any" {
+		location := field.TypeNameLocation()
+		if location == nil {
+			location = field.Location()
+		}
+		add(field, location, "Field %q should not use %q, use a concrete type instead.", field.Name(), typeName)
+	}
+	return nil
+}
+
+// CheckFieldNoStutter is a check function.
+var CheckFieldNoStutter = func(
+	id string,
+	ignoreFunc internal.IgnoreFunc,
+	files []protosource.File,
+	allowFields map[string]struct{},
+) ([]bufanalysis.FileAnnotation, error) {
+	return newFieldCheckFunc(
+		func(add addFunc, field protosource.Field) error {
+			return checkFieldNoStutter(add, field, allowFields)
+		},
+	)(id, ignoreFunc, files)
+}
+
+func checkFieldNoStutter(add addFunc, field protosource.Field, allowFields map[string]struct{}) error {
+	message := field.Message()
+	if message.IsMapEntry() {
+		// map entries are generated by the compiler
+		return nil
+	}
+	if _, ok := allowFields[field.FullName()]; ok {
+		return nil
+	}
+	prefix := stringutil.ToLowerSnakeCase(message.Name())
+	if prefix == "" {
+		return nil
+	}
+	name := field.Name()
+	if name == prefix || strings.HasPrefix(name, prefix+"_") {
+		add(field, field.NameLocation(), "Field name %q should not begin with the name of its message %q.", name, message.Name())
+	}
+	return nil
+}
+
+// CheckFieldMaxNumber is a check function.
+//
+// If messageRegexp is not nil, only fields of messages with a fully-qualified
+// name matching messageRegexp are checked.
+var CheckFieldMaxNumber = func(
+	id string,
+	ignoreFunc internal.IgnoreFunc,
+	files []protosource.File,
+	maxNumber int,
+	messageRegexp *regexp.Regexp,
+) ([]bufanalysis.FileAnnotation, error) {
+	return newFieldCheckFunc(
+		func(add addFunc, field protosource.Field) error {
+			return checkFieldMaxNumber(add, field, maxNumber, messageRegexp)
+		},
+	)(id, ignoreFunc, files)
+}
+
+func checkFieldMaxNumber(add addFunc, field protosource.Field, maxNumber int, messageRegexp *regexp.Regexp) error {
+	if messageRegexp != nil && !messageRegexp.MatchString(field.Message().FullName()) {
+		return nil
+	}
+	if field.Number() > maxNumber {
+		add(field, field.NumberLocation(), "Field %q has number %d, which exceeds the maximum of %d.", field.Name(), field.Number(), maxNumber)
+	}
+	return nil
+}
+
+// wrapperTypeNameToScalarTypeName maps the fully-qualified names of the
+// wrapper well-known types to the names of the scalar types they wrap.
+var wrapperTypeNameToScalarTypeName = map[string]string{
+	".google.protobuf.DoubleValue": "double",
+	".google.protobuf.FloatValue":  "float",
+	".google.protobuf.Int64Value":  "int64",
+	".google.protobuf.UInt64Value": "uint64",
+	".google.protobuf.Int32Value":  "int32",
+	".google.protobuf.UInt32Value": "uint32",
+	".google.protobuf.BoolValue":   "bool",
+	".google.protobuf.StringValue": "string",
+	".google.protobuf.BytesValue":  "bytes",
+}
+
+// CheckFieldNoWrapperType is a check function.
+var CheckFieldNoWrapperType = func(
+	id string,
+	ignoreFunc internal.IgnoreFunc,
+	files []protosource.File,
+	packages []string,
+) ([]bufanalysis.FileAnnotation, error) {
+	return newFieldCheckFunc(
+		func(add addFunc, field protosource.Field) error {
+			return checkFieldNoWrapperType(add, field, packages)
+		},
+	)(id, ignoreFunc, files)
+}
+
+func checkFieldNoWrapperType(add addFunc, field protosource.Field, packages []string) error {
+	if len(packages) > 0 && !packageMatchesAny(field.File().Package(), packages) {
+		return nil
+	}
+	scalarTypeName, ok := wrapperTypeNameToScalarTypeName[field.TypeName()]
+	if !ok {
+		return nil
+	}
+	if field.Label() == protosource.FieldDescriptorProtoLabelRepeated {
+		// repeated scalars cannot have presence
+		return nil
+	}
+	if field.Message().IsMapEntry() {
+		// map values cannot have presence
+		return nil
+	}
+	if _, ok := field.OneofIndex(); ok {
+		add(field, field.Location(), "Field %q should use the scalar type %q instead of the wrapper type %q, as fields in a oneof always have presence.", field.Name(), scalarTypeName, strings.TrimPrefix(field.TypeName(), "."))
+		return nil
+	}
+	add(field, field.Location(), "Field %q should use an optional %q instead of the wrapper type %q.", field.Name(), scalarTypeName, strings.TrimPrefix(field.TypeName(), "."))
+	return nil
+}
+
+// CheckFieldNumbersAscending is a check function.
+var CheckFieldNumbersAscending = func(
+	id string,
+	ignoreFunc internal.IgnoreFunc,
+	files []protosource.File,
+	ignoreOneofs bool,
+) ([]bufanalysis.FileAnnotation, error) {
+	return newMessageCheckFunc(
+		func(add addFunc, message protosource.Message) error {
+			return checkFieldNumbersAscending(add, message, ignoreOneofs)
+		},
+	)(id, ignoreFunc, files)
+}
+
+func checkFieldNumbersAscending(add addFunc, message protosource.Message, ignoreOneofs bool) error {
+	if message.IsMapEntry() {
+		// map entries are synthesized by the compiler
+		return nil
+	}
+	var previousField protosource.Field
+	for _, field := range message.Fields() {
+		if _, ok := field.OneofIndex(); ok && ignoreOneofs {
+			continue
+		}
+		if previousField != nil && field.Number() < previousField.Number() {
+			// only the first out-of-order field is reported, as every
+			// following field would likely be reported otherwise
+			add(
+				field,
+				field.NumberLocation(),
+				"Field %q with number %d should be declared before field %q with number %d, as field numbers should be declared in ascending order.",
+				field.Name(),
+				field.Number(),
+				previousField.Name(),
+				previousField.Number(),
+			)
+			return nil
+		}
+		previousField = field
+	}
+	return nil
+}
+
+// CheckFileHeader is a check function.
+//
+// If headerRegexp is set, the normalized header comment must match headerRegexp.
+// Otherwise, the normalized header comment must be equal to header, which is
+// expected to already be normalized with NormalizeFileHeader.
+var CheckFileHeader = func(
+	id string,
+	ignoreFunc internal.IgnoreFunc,
+	files []protosource.File,
+	header string,
+	headerRegexp *regexp.Regexp,
+) ([]bufanalysis.FileAnnotation, error) {
+	return newFileCheckFunc(
+		func(add addFunc, file protosource.File) error {
+			return checkFileHeader(add, file, header, headerRegexp)
+		},
+	)(id, ignoreFunc, files)
+}
+
+func checkFileHeader(add addFunc, file protosource.File, header string, headerRegexp *regexp.Regexp) error {
+	location := file.SyntaxLocation()
+	if location == nil {
+		// files without a syntax statement start with the package
+		location = file.PackageLocation()
+	}
+	if location == nil {
+		// no source code info, or a file with neither a syntax nor a package
+		return nil
+	}
+	// a header followed by a blank line is detached, otherwise
+	// it is attached to the first statement
+	var comment string
+	if leadingDetachedComments := location.LeadingDetachedComments(); len(leadingDetachedComments) > 0 {
+		comment = leadingDetachedComments[0]
+	} else {
+		comment = location.LeadingComments()
+	}
+	comment = NormalizeFileHeader(comment)
+	if comment == "" {
+		add(file, nil, "Files must begin with a header comment.")
+		return nil
+	}
+	if headerRegexp != nil {
+		if !headerRegexp.MatchString(comment) {
+			add(file, nil, "File header comment does not match the regular expression %q.", headerRegexp.String())
+		}
+		return nil
+	}
+	if comment != header {
+		add(file, nil, "File header comment does not match the expected header.")
+	}
+	return nil
+}
+
+// NormalizeFileHeader normalizes a header comment for comparison.
+//
+// Surrounding whitespace and any leading "//" are removed from each line,
+// and leading and trailing empty lines are removed. This allows the
+// expected header to be given either as plain text or as a comment.
+func NormalizeFileHeader(header string) string {
+	lines := strings.Split(header, "\n")
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		line = strings.TrimPrefix(line, "//")
+		lines[i] = strings.TrimSpace(line)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// CheckFileNoCRLF is a check function.
+var CheckFileNoCRLF = newFileCheckFunc(checkFileNoCRLF)
+
+func checkFileNoCRLF(add addFunc, file protosource.File) error {
+	// the handler makes sure that the raw content is available
+	if bytes.Contains(file.Content(), []byte("\r\n")) {
+		// a nil location results in the annotation being on line 1
+		add(file, nil, "Files should use LF line endings but %q contains CRLF line endings.", file.Path())
+	}
+	return nil
+}
+
+// CheckFileLowerSnakeCase is a check function.
+var CheckFileLowerSnakeCase = newFileCheckFunc(checkFileLowerSnakeCase)
+
+func checkFileLowerSnakeCase(add addFunc, file protosource.File) error {
+	filename := file.Path()
+	base := normalpath.Base(filename)
+	ext := normalpath.Ext(filename)
+	baseWithoutExt := strings.TrimSuffix(base, ext)
+	expectedBaseWithoutExt := stringutil.ToLowerSnakeCase(baseWithoutExt)
+	if baseWithoutExt != expectedBaseWithoutExt {
+		add(file, nil, `Filename %q should be lower_snake_case%s, such as "%s%s".`, base, ext, expectedBaseWithoutExt, ext)
+	}
+	return nil
+}
+
+// CheckFileNameRegex is a check function.
+//
+// The filename without the extension must match fileNameRegexp.
+var CheckFileNameRegex = func(
+	id string,
+	ignoreFunc internal.IgnoreFunc,
+	files []protosource.File,
+	fileNameRegexp *regexp.Regexp,
+) ([]bufanalysis.FileAnnotation, error) {
+	return newFileCheckFunc(
+		func(add addFunc, file protosource.File) error {
+			return checkFileNameRegex(add, file, fileNameRegexp)
+		},
+	)(id, ignoreFunc, files)
+}
+
+func checkFileNameRegex(add addFunc, file protosource.File, fileNameRegexp *regexp.Regexp) error {
+	base := normalpath.Base(file.Path())
+	baseWithoutExt := strings.TrimSuffix(base, normalpath.Ext(base))
+	if !fileNameRegexp.MatchString(baseWithoutExt) {
+		// a nil location results in the annotation being on line 1
+		add(file, nil, "Filename %q should match the pattern %q.", base, fileNameRegexp.String())
+	}
+	return nil
+}
+
+var (
+	// CheckImportNoPublic is a check function.
+	CheckImportNoPublic = newFileImportCheckFunc(checkImportNoPublic)
+	// CheckImportNoWeak is a check function.
 	CheckImportNoWeak = newFileImportCheckFunc(checkImportNoWeak)
 )
 
@@ -258,6 +1342,388 @@ func checkImportNoPublicWeak(add addFunc, fileImport protosource.FileImport, val
 	return nil
 }
 
+// CheckImportSorted is a check function.
+//
+// groups are the import path prefixes of each group of imports in order. An empty
+// prefix is the group of the imports that do not match any other prefix. If there
+// is no empty prefix, imports that do not match any prefix are last.
+var CheckImportSorted = func(
+	id string,
+	ignoreFunc internal.IgnoreFunc,
+	files []protosource.File,
+	groups []string,
+) ([]bufanalysis.FileAnnotation, error) {
+	return newFileCheckFunc(
+		func(add addFunc, file protosource.File) error {
+			return checkImportSorted(add, file, groups)
+		},
+	)(id, ignoreFunc, files)
+}
+
+func checkImportSorted(add addFunc, file protosource.File, groups []string) error {
+	fileImports := file.FileImports()
+	for i := 1; i < len(fileImports); i++ {
+		previous := fileImports[i-1]
+		fileImport := fileImports[i]
+		previousGroup := getImportGroup(previous.Import(), groups)
+		group := getImportGroup(fileImport.Import(), groups)
+		if group < previousGroup || (group == previousGroup && fileImport.Import() < previous.Import()) {
+			// only the first out-of-order import is reported, as reporting the
+			// remaining imports is mostly noise until the first is fixed
+			add(fileImport, fileImport.Location(), "Import %q should be before import %q.", fileImport.Import(), previous.Import())
+			return nil
+		}
+	}
+	return nil
+}
+
+// getImportGroup returns the index of the group with the longest prefix of the import path.
+func getImportGroup(importPath string, groups []string) int {
+	group := len(groups)
+	longestPrefixLen := -1
+	for i, prefix := range groups {
+		if prefix == "" {
+			if longestPrefixLen < 0 {
+				group = i
+			}
+			continue
+		}
+		if strings.HasPrefix(importPath, prefix) && len(prefix) > longestPrefixLen {
+			group = i
+			longestPrefixLen = len(prefix)
+		}
+	}
+	return group
+}
+
+// CheckImportNoForbiddenPath is a check function.
+var CheckImportNoForbiddenPath = func(
+	id string,
+	ignoreFunc internal.IgnoreFunc,
+	files []protosource.File,
+	fromGlobToForbiddenGlobs map[string][]string,
+) ([]bufanalysis.FileAnnotation, error) {
+	fromGlobs := make([]string, 0, len(fromGlobToForbiddenGlobs))
+	for fromGlob := range fromGlobToForbiddenGlobs {
+		fromGlobs = append(fromGlobs, fromGlob)
+	}
+	sort.Strings(fromGlobs)
+	return newFileImportCheckFunc(
+		func(add addFunc, fileImport protosource.FileImport) error {
+			return checkImportNoForbiddenPath(add, fileImport, fromGlobs, fromGlobToForbiddenGlobs)
+		},
+	)(id, ignoreFunc, files)
+}
+
+func checkImportNoForbiddenPath(
+	add addFunc,
+	fileImport protosource.FileImport,
+	fromGlobs []string,
+	fromGlobToForbiddenGlobs map[string][]string,
+) error {
+	for _, fromGlob := range fromGlobs {
+		matched, err := normalpath.MatchGlob(fromGlob, fileImport.File().Path())
+		if err != nil {
+			return err
+		}
+		if !matched {
+			continue
+		}
+		for _, forbiddenGlob := range fromGlobToForbiddenGlobs[fromGlob] {
+			matched, err := normalpath.MatchGlob(forbiddenGlob, fileImport.Import())
+			if err != nil {
+				return err
+			}
+			if matched {
+				// only report the first match for each import
+				add(fileImport, fileImport.Location(), `Import %q matches %q, which files matching %q must not import.`, fileImport.Import(), forbiddenGlob, fromGlob)
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+// CheckMapKeyNoForbiddenType is a check function.
+var CheckMapKeyNoForbiddenType = func(
+	id string,
+	ignoreFunc internal.IgnoreFunc,
+	files []protosource.File,
+	forbiddenTypes map[string]struct{},
+) ([]bufanalysis.FileAnnotation, error) {
+	return newMessageCheckFunc(
+		func(add addFunc, message protosource.Message) error {
+			return checkMapKeyNoForbiddenType(add, message, forbiddenTypes)
+		},
+	)(id, ignoreFunc, files)
+}
+
+func checkMapKeyNoForbiddenType(add addFunc, message protosource.Message, forbiddenTypes map[string]struct{}) error {
+	// map entries are always nested in the message that has the map field
+	mapEntryFullNameToKeyType := make(map[string]string)
+	for _, nestedMessage := range message.Messages() {
+		if !nestedMessage.IsMapEntry() {
+			continue
+		}
+		for _, field := range nestedMessage.Fields() {
+			if field.Number() == 1 {
+				mapEntryFullNameToKeyType[nestedMessage.FullName()] = field.Type().String()
+			}
+		}
+	}
+	if len(mapEntryFullNameToKeyType) == 0 {
+		return nil
+	}
+	for _, field := range message.Fields() {
+		if field.Type() != protosource.FieldDescriptorProtoTypeMessage {
+			continue
+		}
+		keyType, ok := mapEntryFullNameToKeyType[strings.TrimPrefix(field.TypeName(), ".")]
+		if !ok {
+			continue
+		}
+		if _, ok := forbiddenTypes[keyType]; ok {
+			add(field, field.Location(), "Map field %q has key type %q which is forbidden.", field.Name(), keyType)
+		}
+	}
+	return nil
+}
+
+// CheckMapValueNoMapWrapper is a check function.
+var CheckMapValueNoMapWrapper = func(
+	id string,
+	ignoreFunc internal.IgnoreFunc,
+	files []protosource.File,
+	allowTypes map[string]struct{},
+) ([]bufanalysis.FileAnnotation, error) {
+	return newFilesCheckFunc(
+		func(add addFunc, files []protosource.File) error {
+			return checkMapValueNoMapWrapper(add, files, allowTypes)
+		},
+	)(id, ignoreFunc, files)
+}
+
+func checkMapValueNoMapWrapper(add addFunc, files []protosource.File, allowTypes map[string]struct{}) error {
+	fullNameToMessage, err := protosource.FullNameToMessage(files...)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if err := protosource.ForEachMessage(
+			func(message protosource.Message) error {
+				for _, field := range message.Fields() {
+					valueMessage := getMapValueMessage(field, fullNameToMessage)
+					if valueMessage == nil {
+						continue
+					}
+					if _, ok := allowTypes[valueMessage.FullName()]; ok {
+						continue
+					}
+					valueFields := valueMessage.Fields()
+					if len(valueFields) == 1 && getMapEntry(valueFields[0], fullNameToMessage) != nil {
+						add(field, field.Location(), "Map field %q has value type %q which only wraps the map field %q, which is likely a nested map.", field.Name(), valueMessage.FullName(), valueFields[0].Name())
+					}
+				}
+				return nil
+			},
+			file,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getMapEntry returns the map entry message for the field, or nil if the field is not a map field.
+func getMapEntry(field protosource.Field, fullNameToMessage map[string]protosource.Message) protosource.Message {
+	if field.Type() != protosource.FieldDescriptorProtoTypeMessage {
+		return nil
+	}
+	message, ok := fullNameToMessage[strings.TrimPrefix(field.TypeName(), ".")]
+	if !ok || !message.IsMapEntry() {
+		return nil
+	}
+	return message
+}
+
+// getMapValueMessage returns the value message of the map field, or nil if the field is not a map
+// field, the value is not a message, or the value message is not within the given files.
+func getMapValueMessage(field protosource.Field, fullNameToMessage map[string]protosource.Message) protosource.Message {
+	mapEntry := getMapEntry(field, fullNameToMessage)
+	if mapEntry == nil {
+		return nil
+	}
+	for _, entryField := range mapEntry.Fields() {
+		if entryField.Number() == 2 && entryField.Type() == protosource.FieldDescriptorProtoTypeMessage {
+			if valueMessage, ok := fullNameToMessage[strings.TrimPrefix(entryField.TypeName(), ".")]; ok {
+				return valueMessage
+			}
+		}
+	}
+	return nil
+}
+
+// CheckNestedTypeReferenced is a check function.
+//
+// If imageScope is set, references from all files count, otherwise
+// only references from the file that defines the nested type count.
+var CheckNestedTypeReferenced = func(
+	id string,
+	ignoreFunc internal.IgnoreFunc,
+	files []protosource.File,
+	imageScope bool,
+) ([]bufanalysis.FileAnnotation, error) {
+	return newFilesCheckFunc(
+		func(add addFunc, files []protosource.File) error {
+			return checkNestedTypeReferenced(add, files, imageScope)
+		},
+	)(id, ignoreFunc, files)
+}
+
+func checkNestedTypeReferenced(add addFunc, files []protosource.File, imageScope bool) error {
+	var imageTypeNameToReferrers map[string][]string
+	if imageScope {
+		// types are referenced by full name, so this also covers files that only
+		// see the nested type through a public import of the defining file
+		imageTypeNameToReferrers = getTypeNameToReferrers(files...)
+	}
+	for _, file := range files {
+		typeNameToReferrers := imageTypeNameToReferrers
+		if !imageScope {
+			typeNameToReferrers = getTypeNameToReferrers(file)
+		}
+		if err := protosource.ForEachMessage(
+			func(message protosource.Message) error {
+				if message.Parent() == nil || message.IsMapEntry() {
+					return nil
+				}
+				if !isNestedTypeReferenced(message.FullName(), typeNameToReferrers) {
+					add(message, message.Location(), "Nested message %q is not referenced by any field or RPC.", message.NestedName())
+				}
+				return nil
+			},
+			file,
+		); err != nil {
+			return err
+		}
+		if err := protosource.ForEachEnum(
+			func(enum protosource.Enum) error {
+				if enum.NestedName() == enum.Name() {
+					// top-level
+					return nil
+				}
+				if !isNestedTypeReferenced(enum.FullName(), typeNameToReferrers) {
+					add(enum, enum.Location(), "Nested enum %q is not referenced by any field.", enum.NestedName())
+				}
+				return nil
+			},
+			file,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getTypeNameToReferrers returns a map from the full names of the types referenced
+// by the fields, extensions, and RPCs in the files to the full names of the referring messages.
+//
+// Both the types of extensions and the messages they extend are referenced.
+//
+// The referrer is empty for RPCs and extensions declared at the top level of a file.
+func getTypeNameToReferrers(files ...protosource.File) map[string][]string {
+	typeNameToReferrers := make(map[string][]string)
+	addField := func(field protosource.Field, referrer string) {
+		for _, typeName := range []string{field.TypeName(), field.Extendee()} {
+			if typeName = strings.TrimPrefix(typeName, "."); typeName != "" {
+				typeNameToReferrers[typeName] = append(typeNameToReferrers[typeName], referrer)
+			}
+		}
+	}
+	for _, file := range files {
+		// never returns error as the function never returns error
+		_ = protosource.ForEachMessage(
+			func(message protosource.Message) error {
+				for _, fields := range [][]protosource.Field{message.Fields(), message.Extensions()} {
+					for _, field := range fields {
+						addField(field, message.FullName())
+					}
+				}
+				return nil
+			},
+			file,
+		)
+		for _, extension := range file.Extensions() {
+			addField(extension, "")
+		}
+		for _, service := range file.Services() {
+			for _, method := range service.Methods() {
+				for _, typeName := range []string{method.InputTypeName(), method.OutputTypeName()} {
+					typeName = strings.TrimPrefix(typeName, ".")
+					typeNameToReferrers[typeName] = append(typeNameToReferrers[typeName], "")
+				}
+			}
+		}
+	}
+	return typeNameToReferrers
+}
+
+// isNestedTypeReferenced returns true if the type is referenced from outside of itself.
+//
+// References from within the type, such as recursive fields, do not count.
+func isNestedTypeReferenced(fullName string, typeNameToReferrers map[string][]string) bool {
+	for _, referrer := range typeNameToReferrers[fullName] {
+		if referrer != fullName && !strings.HasPrefix(referrer, fullName+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckMessageFieldNumbersDense is a check function.
+var CheckMessageFieldNumbersDense = func(
+	id string,
+	ignoreFunc internal.IgnoreFunc,
+	files []protosource.File,
+	factor int,
+) ([]bufanalysis.FileAnnotation, error) {
+	return newMessageCheckFunc(
+		func(add addFunc, message protosource.Message) error {
+			return checkMessageFieldNumbersDense(add, message, factor)
+		},
+	)(id, ignoreFunc, files)
+}
+
+func checkMessageFieldNumbersDense(add addFunc, message protosource.Message, factor int) error {
+	if message.IsMapEntry() {
+		// map entries are generated by the compiler
+		return nil
+	}
+	fields := message.Fields()
+	if len(fields) == 0 {
+		return nil
+	}
+	maxNumber := 0
+	for _, field := range fields {
+		if field.Number() > maxNumber {
+			maxNumber = field.Number()
+		}
+	}
+	// int64 so that large factors cannot overflow
+	if int64(maxNumber) > int64(len(fields))*int64(factor) {
+		add(
+			message,
+			message.NameLocation(),
+			"Message %q has a highest field number of %d, which is more than %d times its %d fields. Field numbers may be sparse or accidental.",
+			message.Name(),
+			maxNumber,
+			factor,
+			len(fields),
+		)
+	}
+	return nil
+}
+
 // CheckMessagePascalCase is a check function.
 var CheckMessagePascalCase = newMessageCheckFunc(checkMessagePascalCase)
 
@@ -274,6 +1740,151 @@ func checkMessagePascalCase(add addFunc, message protosource.Message) error {
 	return nil
 }
 
+// CheckNameMaxLength is a check function.
+var CheckNameMaxLength = func(
+	id string,
+	ignoreFunc internal.IgnoreFunc,
+	files []protosource.File,
+	maxLength int,
+) ([]bufanalysis.FileAnnotation, error) {
+	return newFileCheckFunc(
+		func(add addFunc, file protosource.File) error {
+			return checkNameMaxLength(add, file, maxLength)
+		},
+	)(id, ignoreFunc, files)
+}
+
+func checkNameMaxLength(add addFunc, file protosource.File, maxLength int) error {
+	check := func(descriptor protosource.NamedDescriptor, descriptorType string) {
+		if length := len(descriptor.Name()); length > maxLength {
+			add(descriptor, descriptor.NameLocation(), "%s name %q has length %d, which exceeds the maximum of %d.", descriptorType, descriptor.Name(), length, maxLength)
+		}
+	}
+	if err := protosource.ForEachMessage(
+		func(message protosource.Message) error {
+			if message.IsMapEntry() {
+				// map entries are generated by the compiler
+				return nil
+			}
+			check(message, "Message")
+			for _, field := range message.Fields() {
+				check(field, "Field")
+			}
+			return nil
+		},
+		file,
+	); err != nil {
+		return err
+	}
+	if err := protosource.ForEachEnum(
+		func(enum protosource.Enum) error {
+			check(enum, "Enum")
+			for _, enumValue := range enum.Values() {
+				check(enumValue, "Enum value")
+			}
+			return nil
+		},
+		file,
+	); err != nil {
+		return err
+	}
+	for _, service := range file.Services() {
+		check(service, "Service")
+		for _, method := range service.Methods() {
+			check(method, "RPC")
+		}
+	}
+	return nil
+}
+
+// CheckNameNoLeadingUnderscore is a check function.
+var CheckNameNoLeadingUnderscore = newFileCheckFunc(checkNameNoLeadingUnderscore)
+
+func checkNameNoLeadingUnderscore(add addFunc, file protosource.File) error {
+	check := func(descriptor protosource.NamedDescriptor, descriptorType string) {
+		if strings.HasPrefix(descriptor.Name(), "_") {
+			add(descriptor, descriptor.NameLocation(), "%s name %q should not start with an underscore.", descriptorType, descriptor.Name())
+		}
+	}
+	if err := protosource.ForEachMessage(
+		func(message protosource.Message) error {
+			if message.IsMapEntry() {
+				// map entries are generated by the compiler
+				return nil
+			}
+			check(message, "Message")
+			for _, field := range message.Fields() {
+				check(field, "Field")
+			}
+			return nil
+		},
+		file,
+	); err != nil {
+		return err
+	}
+	if err := protosource.ForEachEnum(
+		func(enum protosource.Enum) error {
+			check(enum, "Enum")
+			return nil
+		},
+		file,
+	); err != nil {
+		return err
+	}
+	for _, service := range file.Services() {
+		check(service, "Service")
+		for _, method := range service.Methods() {
+			check(method, "RPC")
+		}
+	}
+	return nil
+}
+
+// CheckNameNoReservedWord is a check function.
+var CheckNameNoReservedWord = func(
+	id string,
+	ignoreFunc internal.IgnoreFunc,
+	files []protosource.File,
+	languages []string,
+) ([]bufanalysis.FileAnnotation, error) {
+	return newFileCheckFunc(
+		func(add addFunc, file protosource.File) error {
+			return checkNameNoReservedWord(add, file, languages)
+		},
+	)(id, ignoreFunc, files)
+}
+
+func checkNameNoReservedWord(add addFunc, file protosource.File, languages []string) error {
+	check := func(descriptor protosource.NamedDescriptor, descriptorType string) {
+		if reservedLanguages := reservedWordLanguagesForName(descriptor.Name(), languages); len(reservedLanguages) > 0 {
+			add(descriptor, descriptor.NameLocation(), "%s name %q is a reserved word in %s.", descriptorType, descriptor.Name(), strings.Join(reservedLanguages, ", "))
+		}
+	}
+	if err := protosource.ForEachMessage(
+		func(message protosource.Message) error {
+			if message.IsMapEntry() {
+				// map entries are generated by the compiler
+				return nil
+			}
+			check(message, "Message")
+			for _, field := range message.Fields() {
+				check(field, "Field")
+			}
+			return nil
+		},
+		file,
+	); err != nil {
+		return err
+	}
+	return protosource.ForEachEnum(
+		func(enum protosource.Enum) error {
+			check(enum, "Enum")
+			return nil
+		},
+		file,
+	)
+}
+
 // CheckOneofLowerSnakeCase is a check function.
 var CheckOneofLowerSnakeCase = newOneofCheckFunc(checkOneofLowerSnakeCase)
 
@@ -314,6 +1925,37 @@ func checkPackageDirectoryMatch(add addFunc, file protosource.File) error {
 	return nil
 }
 
+// CheckPackageFileMaxCount is a check function.
+var CheckPackageFileMaxCount = func(
+	id string,
+	ignoreFunc internal.IgnoreFunc,
+	files []protosource.File,
+	maxCount int,
+	firstFileOnly bool,
+) ([]bufanalysis.FileAnnotation, error) {
+	return newPackageToFilesCheckFunc(
+		func(add addFunc, pkg string, files []protosource.File) error {
+			return checkPackageFileMaxCount(add, pkg, files, maxCount, firstFileOnly)
+		},
+	)(id, ignoreFunc, files)
+}
+
+func checkPackageFileMaxCount(add addFunc, pkg string, files []protosource.File, maxCount int, firstFileOnly bool) error {
+	if len(files) <= maxCount {
+		return nil
+	}
+	sortedFiles := make([]protosource.File, len(files))
+	copy(sortedFiles, files)
+	sort.Slice(sortedFiles, func(i int, j int) bool { return sortedFiles[i].Path() < sortedFiles[j].Path() })
+	if firstFileOnly {
+		sortedFiles = sortedFiles[:1]
+	}
+	for _, file := range sortedFiles {
+		add(file, file.PackageLocation(), "Package %q has %d files, which exceeds the maximum of %d.", pkg, len(files), maxCount)
+	}
+	return nil
+}
+
 // CheckPackageLowerSnakeCase is a check function.
 var CheckPackageLowerSnakeCase = newFileCheckFunc(checkPackageLowerSnakeCase)
 
@@ -445,6 +2087,45 @@ func checkPackageVersionSuffix(add addFunc, file protosource.File) error {
 	return nil
 }
 
+// CheckRPCIdempotencyLevelSpecified is a check function.
+var CheckRPCIdempotencyLevelSpecified = func(
+	id string,
+	ignoreFunc internal.IgnoreFunc,
+	files []protosource.File,
+	serviceNames map[string]struct{},
+	packages []string,
+) ([]bufanalysis.FileAnnotation, error) {
+	return newMethodCheckFunc(
+		func(add addFunc, method protosource.Method) error {
+			return checkRPCIdempotencyLevelSpecified(add, method, serviceNames, packages)
+		},
+	)(id, ignoreFunc, files)
+}
+
+func checkRPCIdempotencyLevelSpecified(
+	add addFunc,
+	method protosource.Method,
+	serviceNames map[string]struct{},
+	packages []string,
+) error {
+	service := method.Service()
+	if service == nil {
+		// just a sanity check
+		return errors.New("method.Service() is nil")
+	}
+	// if no services or packages are specified, all services are checked
+	if len(serviceNames) > 0 || len(packages) > 0 {
+		_, serviceMatches := serviceNames[service.FullName()]
+		if !serviceMatches && !packageMatchesAny(method.File().Package(), packages) {
+			return nil
+		}
+	}
+	if method.IdempotencyLevel() == protosource.MethodOptionsIdempotencyLevelIdempotencyUnknown {
+		add(method, method.NameLocation(), "RPC %q should have the option idempotency_level set to NO_SIDE_EFFECTS or IDEMPOTENT.", method.Name())
+	}
+	return nil
+}
+
 // CheckRPCNoClientStreaming is a check function.
 var CheckRPCNoClientStreaming = newMethodCheckFunc(checkRPCNoClientStreaming)
 
@@ -465,6 +2146,55 @@ func checkRPCNoServerStreaming(add addFunc, method protosource.Method) error {
 	return nil
 }
 
+var (
+	// rpcPaginationRequestFieldNames are the field names a paginated request must have.
+	rpcPaginationRequestFieldNames = []string{"page_size", "page_token"}
+	// rpcPaginationResponseFieldNames are the field names a paginated response must have.
+	rpcPaginationResponseFieldNames = []string{"next_page_token"}
+)
+
+// CheckRPCPaginationFields is a check function.
+var CheckRPCPaginationFields = func(
+	id string,
+	ignoreFunc internal.IgnoreFunc,
+	files []protosource.File,
+	methodRegexp *regexp.Regexp,
+) ([]bufanalysis.FileAnnotation, error) {
+	return newFilesCheckFunc(
+		func(add addFunc, files []protosource.File) error {
+			return checkRPCPaginationFields(add, files, methodRegexp)
+		},
+	)(id, ignoreFunc, files)
+}
+
+func checkRPCPaginationFields(add addFunc, files []protosource.File, methodRegexp *regexp.Regexp) error {
+	fullNameToMessage, err := protosource.FullNameToMessage(files...)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		for _, service := range file.Services() {
+			for _, method := range service.Methods() {
+				if !methodRegexp.MatchString(method.Name()) {
+					continue
+				}
+				// messages outside of the linted files, such as imports, are not checked
+				if request, ok := fullNameToMessage[strings.TrimPrefix(method.InputTypeName(), ".")]; ok {
+					for _, fieldName := range getMissingFieldNames(request, rpcPaginationRequestFieldNames) {
+						add(method, method.InputTypeLocation(), "RPC %q request type %q should have the pagination field %q.", method.Name(), request.FullName(), fieldName)
+					}
+				}
+				if response, ok := fullNameToMessage[strings.TrimPrefix(method.OutputTypeName(), ".")]; ok {
+					for _, fieldName := range getMissingFieldNames(response, rpcPaginationResponseFieldNames) {
+						add(method, method.OutputTypeLocation(), "RPC %q response type %q should have the pagination field %q.", method.Name(), response.FullName(), fieldName)
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
 // CheckRPCPascalCase is a check function.
 var CheckRPCPascalCase = newMethodCheckFunc(checkRPCPascalCase)
 
@@ -614,6 +2344,49 @@ func checkRPCRequestStandardName(add addFunc, method protosource.Method, allowGo
 	return nil
 }
 
+// CheckRPCRequiredOption is a check function.
+var CheckRPCRequiredOption = func(
+	id string,
+	ignoreFunc internal.IgnoreFunc,
+	files []protosource.File,
+	optionName string,
+	optionNumber int,
+	serviceNames map[string]struct{},
+	packages []string,
+) ([]bufanalysis.FileAnnotation, error) {
+	return newMethodCheckFunc(
+		func(add addFunc, method protosource.Method) error {
+			return checkRPCRequiredOption(add, method, optionName, optionNumber, serviceNames, packages)
+		},
+	)(id, ignoreFunc, files)
+}
+
+func checkRPCRequiredOption(
+	add addFunc,
+	method protosource.Method,
+	optionName string,
+	optionNumber int,
+	serviceNames map[string]struct{},
+	packages []string,
+) error {
+	service := method.Service()
+	if service == nil {
+		// just a sanity check
+		return errors.New("method.Service() is nil")
+	}
+	// if no services or packages are specified, all services are checked
+	if len(serviceNames) > 0 || len(packages) > 0 {
+		_, serviceMatches := serviceNames[service.FullName()]
+		if !serviceMatches && !packageMatchesAny(method.File().Package(), packages) {
+			return nil
+		}
+	}
+	if !method.HasOptionExtension(optionNumber) {
+		add(method, method.NameLocation(), "RPC %q should have the option (%s) set.", method.Name(), optionName)
+	}
+	return nil
+}
+
 // CheckRPCResponseStandardName is a check function.
 var CheckRPCResponseStandardName = func(
 	id string,
@@ -649,6 +2422,70 @@ func checkRPCResponseStandardName(add addFunc, method protosource.Method, allowG
 	return nil
 }
 
+// CheckServiceNoEmpty is a check function.
+var CheckServiceNoEmpty = func(
+	id string,
+	ignoreFunc internal.IgnoreFunc,
+	files []protosource.File,
+	allowServices map[string]struct{},
+) ([]bufanalysis.FileAnnotation, error) {
+	return newServiceCheckFunc(
+		func(add addFunc, service protosource.Service) error {
+			return checkServiceNoEmpty(add, service, allowServices)
+		},
+	)(id, ignoreFunc, files)
+}
+
+func checkServiceNoEmpty(add addFunc, service protosource.Service, allowServices map[string]struct{}) error {
+	if _, ok := allowServices[service.FullName()]; ok {
+		return nil
+	}
+	if len(service.Methods()) == 0 {
+		add(service, service.NameLocation(), "Service %q should have at least one RPC.", service.Name())
+	}
+	return nil
+}
+
+// CheckServiceStreamingConsistent is a check function.
+var CheckServiceStreamingConsistent = func(
+	id string,
+	ignoreFunc internal.IgnoreFunc,
+	files []protosource.File,
+	direction bool,
+) ([]bufanalysis.FileAnnotation, error) {
+	return newServiceCheckFunc(
+		func(add addFunc, service protosource.Service) error {
+			return checkServiceStreamingConsistent(add, service, direction)
+		},
+	)(id, ignoreFunc, files)
+}
+
+func checkServiceStreamingConsistent(add addFunc, service protosource.Service, direction bool) error {
+	methods := service.Methods()
+	if len(methods) < 2 {
+		return nil
+	}
+	first := methods[0]
+	for _, method := range methods[1:] {
+		if direction {
+			if method.ClientStreaming() != first.ClientStreaming() || method.ServerStreaming() != first.ServerStreaming() {
+				add(service, service.NameLocation(), "Service %q mixes RPCs with different streaming directions, such as %q (%s) and %q (%s), which may indicate that the service should be split.", service.Name(), first.Name(), getMethodStreamingDescription(first), method.Name(), getMethodStreamingDescription(method))
+				return nil
+			}
+			continue
+		}
+		if isMethodStreaming(method) != isMethodStreaming(first) {
+			streamingMethod, unaryMethod := method, first
+			if isMethodStreaming(first) {
+				streamingMethod, unaryMethod = first, method
+			}
+			add(service, service.NameLocation(), "Service %q mixes streaming RPCs such as %q with unary RPCs such as %q, which may indicate that the service should be split.", service.Name(), streamingMethod.Name(), unaryMethod.Name())
+			return nil
+		}
+	}
+	return nil
+}
+
 // CheckServicePascalCase is a check function.
 var CheckServicePascalCase = newServiceCheckFunc(checkServicePascalCase)
 
@@ -682,3 +2519,188 @@ func checkServiceSuffix(add addFunc, service protosource.Service, suffix string)
 	}
 	return nil
 }
+
+// CheckSyntaxSpecified is a check function.
+var CheckSyntaxSpecified = newFileCheckFunc(checkSyntaxSpecified)
+
+func checkSyntaxSpecified(add addFunc, file protosource.File) error {
+	if file.IsSyntaxUnspecified() {
+		add(file, nil, `Files must have a syntax explicitly specified. If no syntax is specified, the file defaults to "proto2".`)
+	}
+	return nil
+}
+
+// CheckTypeNoEmpty is a check function.
+var CheckTypeNoEmpty = func(
+	id string,
+	ignoreFunc internal.IgnoreFunc,
+	files []protosource.File,
+	allowEmptyTypes map[string]struct{},
+) ([]bufanalysis.FileAnnotation, error) {
+	return newFileCheckFunc(
+		func(add addFunc, file protosource.File) error {
+			return checkTypeNoEmpty(add, file, allowEmptyTypes)
+		},
+	)(id, ignoreFunc, files)
+}
+
+func checkTypeNoEmpty(add addFunc, file protosource.File, allowEmptyTypes map[string]struct{}) error {
+	if err := protosource.ForEachMessage(
+		func(message protosource.Message) error {
+			if _, ok := allowEmptyTypes[message.FullName()]; ok {
+				return nil
+			}
+			if len(message.Fields()) == 0 && len(message.Oneofs()) == 0 {
+				add(message, message.NameLocation(), "Message %q should not be empty.", message.Name())
+			}
+			return nil
+		},
+		file,
+	); err != nil {
+		return err
+	}
+	return protosource.ForEachEnum(
+		func(enum protosource.Enum) error {
+			if _, ok := allowEmptyTypes[enum.FullName()]; ok {
+				return nil
+			}
+			if len(enum.Values()) == 0 {
+				add(enum, enum.NameLocation(), "Enum %q should not be empty.", enum.Name())
+			}
+			return nil
+		},
+		file,
+	)
+}
+
+// wellKnownTypeNames are the simple names of the messages and enums defined
+// in the well-known types in the google.protobuf package.
+var wellKnownTypeNames = map[string]struct{}{
+	"Any":           {},
+	"Api":           {},
+	"BoolValue":     {},
+	"BytesValue":    {},
+	"DoubleValue":   {},
+	"Duration":      {},
+	"Empty":         {},
+	"Enum":          {},
+	"EnumValue":     {},
+	"Field":         {},
+	"FieldMask":     {},
+	"FloatValue":    {},
+	"Int32Value":    {},
+	"Int64Value":    {},
+	"ListValue":     {},
+	"Method":        {},
+	"Mixin":         {},
+	"NullValue":     {},
+	"Option":        {},
+	"SourceContext": {},
+	"StringValue":   {},
+	"Struct":        {},
+	"Syntax":        {},
+	"Timestamp":     {},
+	"Type":          {},
+	"UInt32Value":   {},
+	"UInt64Value":   {},
+	"Value":         {},
+}
+
+// CheckTypeNamePackageMaxCount is a check function.
+var CheckTypeNamePackageMaxCount = func(
+	id string,
+	ignoreFunc internal.IgnoreFunc,
+	files []protosource.File,
+	maxCount int,
+) ([]bufanalysis.FileAnnotation, error) {
+	return newFilesCheckFunc(
+		func(add addFunc, files []protosource.File) error {
+			return checkTypeNamePackageMaxCount(add, files, maxCount)
+		},
+	)(id, ignoreFunc, files)
+}
+
+func checkTypeNamePackageMaxCount(add addFunc, files []protosource.File, maxCount int) error {
+	// only top-level types are considered, nested types such as Request or
+	// Value are routinely reused and are qualified by their parent anyways
+	nameToDescriptors := make(map[string][]protosource.NamedDescriptor)
+	nameToPackages := make(map[string]map[string]struct{})
+	addDescriptor := func(file protosource.File, namedDescriptor protosource.NamedDescriptor) {
+		name := namedDescriptor.Name()
+		nameToDescriptors[name] = append(nameToDescriptors[name], namedDescriptor)
+		packages, ok := nameToPackages[name]
+		if !ok {
+			packages = make(map[string]struct{})
+			nameToPackages[name] = packages
+		}
+		packages[file.Package()] = struct{}{}
+	}
+	for _, file := range files {
+		for _, message := range file.Messages() {
+			addDescriptor(file, message)
+		}
+		for _, enum := range file.Enums() {
+			addDescriptor(file, enum)
+		}
+	}
+	for name, namedDescriptors := range nameToDescriptors {
+		numPackages := len(nameToPackages[name])
+		if numPackages <= maxCount {
+			continue
+		}
+		for _, namedDescriptor := range namedDescriptors {
+			add(namedDescriptor, namedDescriptor.NameLocation(), "Type name %q is defined in %d packages, which exceeds the maximum of %d.", name, numPackages, maxCount)
+		}
+	}
+	return nil
+}
+
+// CheckTypeNoWKTName is a check function.
+var CheckTypeNoWKTName = func(
+	id string,
+	ignoreFunc internal.IgnoreFunc,
+	files []protosource.File,
+	allowTypes map[string]struct{},
+) ([]bufanalysis.FileAnnotation, error) {
+	return newFileCheckFunc(
+		func(add addFunc, file protosource.File) error {
+			return checkTypeNoWKTName(add, file, allowTypes)
+		},
+	)(id, ignoreFunc, files)
+}
+
+func checkTypeNoWKTName(add addFunc, file protosource.File, allowTypes map[string]struct{}) error {
+	if file.Package() == "google.protobuf" {
+		// these are the well-known types themselves
+		return nil
+	}
+	if err := protosource.ForEachMessage(
+		func(message protosource.Message) error {
+			if message.IsMapEntry() {
+				return nil
+			}
+			if _, ok := allowTypes[message.FullName()]; ok {
+				return nil
+			}
+			if _, ok := wellKnownTypeNames[message.Name()]; ok {
+				add(message, message.NameLocation(), "Message name %q should not be the same as the well-known type %q.", message.Name(), "google.protobuf."+message.Name())
+			}
+			return nil
+		},
+		file,
+	); err != nil {
+		return err
+	}
+	return protosource.ForEachEnum(
+		func(enum protosource.Enum) error {
+			if _, ok := allowTypes[enum.FullName()]; ok {
+				return nil
+			}
+			if _, ok := wellKnownTypeNames[enum.Name()]; ok {
+				add(enum, enum.NameLocation(), "Enum name %q should not be the same as the well-known type %q.", enum.Name(), "google.protobuf."+enum.Name())
+			}
+			return nil
+		},
+		file,
+	)
+}
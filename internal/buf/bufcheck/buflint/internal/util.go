@@ -15,6 +15,7 @@
 package internal
 
 import (
+	"sort"
 	"strconv"
 	"strings"
 
@@ -102,6 +103,16 @@ func packageVersionIsValidAlphaOrBeta(version string, name string) bool {
 	return stringIsPositiveNumber(split[1])
 }
 
+// packageMatchesAny returns true if pkg is equal to or a sub-package of any of packages.
+func packageMatchesAny(pkg string, packages []string) bool {
+	for _, otherPkg := range packages {
+		if pkg == otherPkg || strings.HasPrefix(pkg, otherPkg+".") {
+			return true
+		}
+	}
+	return false
+}
+
 func stringIsPositiveNumber(s string) bool {
 	if s == "" {
 		return false
@@ -303,3 +314,197 @@ func newMethodCheckFunc(
 		},
 	)
 }
+
+// languageToReservedWords maps the languages supported by
+// NAME_NO_RESERVED_WORD to their reserved words.
+var languageToReservedWords = map[string]map[string]struct{}{
+	"go": stringutil.SliceToMap([]string{
+		"break", "case", "chan", "const", "continue", "default", "defer",
+		"else", "fallthrough", "for", "func", "go", "goto", "if", "import",
+		"interface", "map", "package", "range", "return", "select", "struct",
+		"switch", "type", "var",
+	}),
+	"java": stringutil.SliceToMap([]string{
+		"abstract", "assert", "boolean", "break", "byte", "case", "catch",
+		"char", "class", "const", "continue", "default", "do", "double",
+		"else", "enum", "extends", "false", "final", "finally", "float",
+		"for", "goto", "if", "implements", "import", "instanceof", "int",
+		"interface", "long", "native", "new", "null", "package", "private",
+		"protected", "public", "return", "short", "static", "strictfp",
+		"super", "switch", "synchronized", "this", "throw", "throws",
+		"transient", "true", "try", "void", "volatile", "while",
+	}),
+	"python": stringutil.SliceToMap([]string{
+		"False", "None", "True", "and", "as", "assert", "async", "await",
+		"break", "class", "continue", "def", "del", "elif", "else", "except",
+		"finally", "for", "from", "global", "if", "import", "in", "is",
+		"lambda", "nonlocal", "not", "or", "pass", "raise", "return", "try",
+		"while", "with", "yield",
+	}),
+}
+
+// FieldBoolNegativePrefixes returns the default negative prefixes of FIELD_BOOL_AFFIRMATIVE.
+func FieldBoolNegativePrefixes() []string {
+	return []string{
+		"no_",
+		"not_",
+		"disable",
+		"disabled",
+	}
+}
+
+// getFieldBoolAffirmativeName returns the suggested affirmative name for a
+// bool field name that starts with the negative prefix.
+//
+// Returns empty if there is no suggestion.
+func getFieldBoolAffirmativeName(name string, negativePrefix string) string {
+	affirmativeName := strings.TrimPrefix(name, negativePrefix)
+	switch negativePrefix {
+	case "disable", "disabled":
+		return "en" + strings.TrimPrefix(negativePrefix, "dis") + affirmativeName
+	}
+	return strings.TrimLeft(affirmativeName, "_")
+}
+
+// ReservedWordLanguages returns the sorted languages supported by NAME_NO_RESERVED_WORD.
+func ReservedWordLanguages() []string {
+	languages := make([]string, 0, len(languageToReservedWords))
+	for language := range languageToReservedWords {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// reservedWordLanguagesForName returns the languages that the name is a reserved word in.
+//
+// Languages that are not supported are ignored.
+func reservedWordLanguagesForName(name string, languages []string) []string {
+	var reservedLanguages []string
+	for _, language := range languages {
+		if _, ok := languageToReservedWords[language][name]; ok {
+			reservedLanguages = append(reservedLanguages, language)
+		}
+	}
+	return reservedLanguages
+}
+
+// getJSONName returns the JSON name of the field, falling back to the
+// default JSON name if json_name was not populated.
+func getJSONName(field protosource.Field) string {
+	if jsonName := field.JSONName(); jsonName != "" {
+		return jsonName
+	}
+	return toDefaultJSONName(field.Name())
+}
+
+// fieldNamesSimilarMinLength is the minimum length of field names that are
+// compared by fieldNamesSimilar, as short names such as "x" and "y" are
+// commonly within a small edit distance of each other.
+const fieldNamesSimilarMinLength = 4
+
+// fieldNamesSimilar returns true if the names are different but within
+// maxDistance of each other, and are not just numbered variants such as
+// "line_1" and "line_2".
+func fieldNamesSimilar(name string, otherName string, maxDistance int) bool {
+	if len(name) < fieldNamesSimilarMinLength || len(otherName) < fieldNamesSimilarMinLength {
+		return false
+	}
+	if strings.TrimRight(name, "0123456789") == strings.TrimRight(otherName, "0123456789") {
+		return false
+	}
+	distance := levenshteinDistance(name, otherName)
+	return distance > 0 && distance <= maxDistance
+}
+
+// levenshteinDistance returns the minimum number of single character insertions,
+// deletions, and substitutions to change one into two.
+func levenshteinDistance(one string, two string) int {
+	oneRunes := []rune(one)
+	twoRunes := []rune(two)
+	// only the previous row of the matrix is needed to compute the current row
+	previous := make([]int, len(twoRunes)+1)
+	current := make([]int, len(twoRunes)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(oneRunes); i++ {
+		current[0] = i
+		for j := 1; j <= len(twoRunes); j++ {
+			substitutionCost := 1
+			if oneRunes[i-1] == twoRunes[j-1] {
+				substitutionCost = 0
+			}
+			current[j] = minInt(
+				previous[j]+1,
+				current[j-1]+1,
+				previous[j-1]+substitutionCost,
+			)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(twoRunes)]
+}
+
+func minInt(values ...int) int {
+	min := values[0]
+	for _, value := range values[1:] {
+		if value < min {
+			min = value
+		}
+	}
+	return min
+}
+
+// toDefaultJSONName returns the JSON name protoc assigns to a field with the given name,
+// that is the name with each underscore removed and the following letter capitalized.
+func toDefaultJSONName(name string) string {
+	var builder strings.Builder
+	capitalizeNext := false
+	for _, r := range name {
+		if r == '_' {
+			capitalizeNext = true
+			continue
+		}
+		if capitalizeNext && 'a' <= r && r <= 'z' {
+			r -= 'a' - 'A'
+		}
+		capitalizeNext = false
+		_, _ = builder.WriteRune(r)
+	}
+	return builder.String()
+}
+
+// getMissingFieldNames returns the field names that the message does not have, in order.
+func getMissingFieldNames(message protosource.Message, fieldNames []string) []string {
+	messageFieldNames := make(map[string]struct{}, len(message.Fields()))
+	for _, field := range message.Fields() {
+		messageFieldNames[field.Name()] = struct{}{}
+	}
+	var missingFieldNames []string
+	for _, fieldName := range fieldNames {
+		if _, ok := messageFieldNames[fieldName]; !ok {
+			missingFieldNames = append(missingFieldNames, fieldName)
+		}
+	}
+	return missingFieldNames
+}
+
+// isMethodStreaming returns true if the method is streaming in either direction.
+func isMethodStreaming(method protosource.Method) bool {
+	return method.ClientStreaming() || method.ServerStreaming()
+}
+
+// getMethodStreamingDescription returns the streaming direction of the method for annotations.
+func getMethodStreamingDescription(method protosource.Method) string {
+	switch {
+	case method.ClientStreaming() && method.ServerStreaming():
+		return "bidirectional streaming"
+	case method.ClientStreaming():
+		return "client streaming"
+	case method.ServerStreaming():
+		return "server streaming"
+	default:
+		return "unary"
+	}
+}
@@ -74,6 +74,17 @@ func TestPackageHasVersionSuffix(t *testing.T) {
 	testPackageHasVersionSuffix(t, false, "foo.bar.v1aalpha1")
 }
 
+func TestLevenshteinDistance(t *testing.T) {
+	assert.Equal(t, 0, levenshteinDistance("", ""))
+	assert.Equal(t, 3, levenshteinDistance("", "foo"))
+	assert.Equal(t, 3, levenshteinDistance("foo", ""))
+	assert.Equal(t, 0, levenshteinDistance("foo", "foo"))
+	assert.Equal(t, 1, levenshteinDistance("description", "descrption"))
+	assert.Equal(t, 1, levenshteinDistance("foo", "fob"))
+	assert.Equal(t, 2, levenshteinDistance("foo", "ofo"))
+	assert.Equal(t, 3, levenshteinDistance("kitten", "sitting"))
+}
+
 func testPackageHasVersionSuffix(t *testing.T, expected bool, pkg string) {
 	assert.Equal(t, expected, packageHasVersionSuffix(pkg), pkg)
 }
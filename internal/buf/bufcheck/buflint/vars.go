@@ -16,16 +16,22 @@ package buflint
 
 import (
 	"errors"
+	"fmt"
+	"regexp"
+	"strings"
 
 	"github.com/bufbuild/buf/internal/buf/bufanalysis"
 	"github.com/bufbuild/buf/internal/buf/bufcheck/buflint/internal"
 	bufcheckinternal "github.com/bufbuild/buf/internal/buf/bufcheck/internal"
+	"github.com/bufbuild/buf/internal/pkg/normalpath"
 	"github.com/bufbuild/buf/internal/pkg/protosource"
+	"github.com/bufbuild/buf/internal/pkg/stringutil"
 )
 
 var (
 	// v1CheckerBuilders are the checker builders.
 	v1CheckerBuilders = []*bufcheckinternal.CheckerBuilder{
+		v1AcronymCasingCheckerBuilder,
 		v1CommentEnumCheckerBuilder,
 		v1CommentEnumValueCheckerBuilder,
 		v1CommentFieldCheckerBuilder,
@@ -33,22 +39,55 @@ var (
 		v1CommentOneofCheckerBuilder,
 		v1CommentRPCCheckerBuilder,
 		v1CommentServiceCheckerBuilder,
+		v1DeprecationCommentCheckerBuilder,
+		v1DirectoryPackageMajorityCheckerBuilder,
 		v1DirectorySamePackageCheckerBuilder,
+		v1EnumAllowAliasUsedCheckerBuilder,
 		v1EnumFirstValueZeroCheckerBuilder,
+		v1EnumNotNestedCheckerBuilder,
 		v1EnumNoAllowAliasCheckerBuilder,
 		v1EnumPascalCaseCheckerBuilder,
+		v1EnumValueMaxCountCheckerBuilder,
+		v1EnumValueNoGapCheckerBuilder,
 		v1EnumValuePrefixCheckerBuilder,
 		v1EnumValueUpperSnakeCaseCheckerBuilder,
 		v1EnumZeroValueSuffixCheckerBuilder,
+		v1FieldBoolAffirmativeCheckerBuilder,
+		v1FieldDeprecatedReserveCheckerBuilder,
+		v1FieldJSONNameNoConflictCheckerBuilder,
+		v1FieldLabelConsistentCheckerBuilder,
 		v1FieldLowerSnakeCaseCheckerBuilder,
+		v1FieldMaxNumberCheckerBuilder,
+		v1FieldNamesDistinctCheckerBuilder,
 		v1FieldNoDescriptorCheckerBuilder,
+		v1FieldNoGroupCheckerBuilder,
+		v1FieldNoReservedCheckerBuilder,
+		v1FieldNoAnyCheckerBuilder,
+		v1FieldNoStutterCheckerBuilder,
+		v1FieldNoWrapperTypeCheckerBuilder,
+		v1FieldNumbersAscendingCheckerBuilder,
+		v1FieldOptionConsistentCheckerBuilder,
+		v1FieldPresenceCommentCheckerBuilder,
+		v1FieldProto3OptionalCheckerBuilder,
+		v1FileHeaderCheckerBuilder,
 		v1FileLowerSnakeCaseCheckerBuilder,
+		v1FileNoCRLFCheckerBuilder,
+		v1ImportNoForbiddenPathCheckerBuilder,
 		v1ImportNoPublicCheckerBuilder,
 		v1ImportNoWeakCheckerBuilder,
+		v1ImportSortedCheckerBuilder,
+		v1MapKeyNoForbiddenTypeCheckerBuilder,
+		v1MapValueNoMapWrapperCheckerBuilder,
+		v1MessageFieldNumbersDenseCheckerBuilder,
 		v1MessagePascalCaseCheckerBuilder,
+		v1NameMaxLengthCheckerBuilder,
+		v1NameNoLeadingUnderscoreCheckerBuilder,
+		v1NameNoReservedWordCheckerBuilder,
+		v1NestedTypeReferencedCheckerBuilder,
 		v1OneofLowerSnakeCaseCheckerBuilder,
 		v1PackageDefinedCheckerBuilder,
 		v1PackageDirectoryMatchCheckerBuilder,
+		v1PackageFileMaxCountCheckerBuilder,
 		v1PackageLowerSnakeCaseCheckerBuilder,
 		v1PackageSameCsharpNamespaceCheckerBuilder,
 		v1PackageSameDirectoryCheckerBuilder,
@@ -59,16 +98,97 @@ var (
 		v1PackageSameRubyPackageCheckerBuilder,
 		v1PackageSameSwiftPrefixCheckerBuilder,
 		v1PackageVersionSuffixCheckerBuilder,
+		v1RPCIdempotencyLevelSpecifiedCheckerBuilder,
 		v1RPCNoClientStreamingCheckerBuilder,
 		v1RPCNoServerStreamingCheckerBuilder,
+		v1RPCPaginationFieldsCheckerBuilder,
 		v1RPCPascalCaseCheckerBuilder,
 		v1RPCRequestResponseUniqueCheckerBuilder,
 		v1RPCRequestStandardNameCheckerBuilder,
+		v1RPCRequiredOptionCheckerBuilder,
 		v1RPCResponseStandardNameCheckerBuilder,
+		v1ServiceNoEmptyCheckerBuilder,
 		v1ServicePascalCaseCheckerBuilder,
+		v1ServiceStreamingConsistentCheckerBuilder,
 		v1ServiceSuffixCheckerBuilder,
+		v1SyntaxSpecifiedCheckerBuilder,
+		v1TypeNamePackageMaxCountCheckerBuilder,
+		v1TypeNoEmptyCheckerBuilder,
+		v1TypeNoWKTNameCheckerBuilder,
 	}
 
+	// v1KnownMethodOptionNameToNumber are the field numbers of well-known method option
+	// extensions, so that users do not need to specify them.
+	v1KnownMethodOptionNameToNumber = map[string]int{
+		"google.api.http": 72295728,
+	}
+
+	// v1KnownFieldOptionNameToNumber are the field numbers of well-known field option
+	// extensions, so that users do not need to specify them.
+	v1KnownFieldOptionNameToNumber = map[string]int{
+		// https://github.com/envoyproxy/protoc-gen-validate
+		"validate.rules": 1071,
+	}
+
+	// v1FieldTypes are the type names that can be given to field_option_consistent_types.
+	v1FieldTypes = map[string]struct{}{
+		"double":   {},
+		"float":    {},
+		"int64":    {},
+		"uint64":   {},
+		"int32":    {},
+		"fixed64":  {},
+		"fixed32":  {},
+		"bool":     {},
+		"string":   {},
+		"group":    {},
+		"message":  {},
+		"bytes":    {},
+		"uint32":   {},
+		"enum":     {},
+		"sfixed32": {},
+		"sfixed64": {},
+		"sint32":   {},
+		"sint64":   {},
+	}
+
+	// v1MapKeyTypes are the type names that can be given to map_key_forbidden_types.
+	//
+	// This includes all scalar types, not just the types the compiler allows as map
+	// keys, so that configurations remain valid across implementations.
+	v1MapKeyTypes = map[string]struct{}{
+		"double":   {},
+		"float":    {},
+		"int64":    {},
+		"uint64":   {},
+		"int32":    {},
+		"fixed64":  {},
+		"fixed32":  {},
+		"bool":     {},
+		"string":   {},
+		"bytes":    {},
+		"uint32":   {},
+		"sfixed32": {},
+		"sfixed64": {},
+		"sint32":   {},
+		"sint64":   {},
+	}
+
+	// v1FieldProto3OptionalAlways is the field_proto3_optional value that requires the optional label.
+	v1FieldProto3OptionalAlways = "always"
+	// v1FieldProto3OptionalNever is the field_proto3_optional value that forbids the optional label.
+	v1FieldProto3OptionalNever = "never"
+
+	// v1NestedTypeReferencedScopeFile is the nested_type_referenced_scope value that
+	// only counts references from the file that defines the nested type.
+	v1NestedTypeReferencedScopeFile = "file"
+	// v1NestedTypeReferencedScopeImage is the nested_type_referenced_scope value that
+	// counts references from all files.
+	v1NestedTypeReferencedScopeImage = "image"
+
+	// v1AcronymRegexp is the regexp that values given to acronyms must match.
+	v1AcronymRegexp = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*$`)
+
 	// v1DefaultCategories are the default categories.
 	v1DefaultCategories = []string{
 		"DEFAULT",
@@ -87,8 +207,34 @@ var (
 		"STYLE_DEFAULT",
 		"OTHER",
 	}
+	// v1MultiFileIDs are the IDs of the Checkers that check multiple files together,
+	// such as all files of a package or directory.
+	//
+	// Every other Checker must only check each file individually, as these are
+	// run file by file so that the FileAnnotations of each file can be streamed.
+	v1MultiFileIDs = map[string]struct{}{
+		"DIRECTORY_PACKAGE_MAJORITY":       {},
+		"DIRECTORY_SAME_PACKAGE":           {},
+		"MAP_VALUE_NO_MAP_WRAPPER":         {},
+		"NESTED_TYPE_REFERENCED":           {},
+		"PACKAGE_FILE_MAX_COUNT":           {},
+		"PACKAGE_SAME_CSHARP_NAMESPACE":    {},
+		"PACKAGE_SAME_DIRECTORY":           {},
+		"PACKAGE_SAME_GO_PACKAGE":          {},
+		"PACKAGE_SAME_JAVA_MULTIPLE_FILES": {},
+		"PACKAGE_SAME_JAVA_PACKAGE":        {},
+		"PACKAGE_SAME_PHP_NAMESPACE":       {},
+		"PACKAGE_SAME_RUBY_PACKAGE":        {},
+		"PACKAGE_SAME_SWIFT_PREFIX":        {},
+		"RPC_PAGINATION_FIELDS":            {},
+		"RPC_REQUEST_RESPONSE_UNIQUE":      {},
+		"TYPE_NAME_PACKAGE_MAX_COUNT":      {},
+	}
 	// v1IDToCategories are the ID to categories.
 	v1IDToCategories = map[string][]string{
+		"ACRONYM_CASING": {
+			"OTHER",
+		},
 		"COMMENT_ENUM": {
 			"COMMENTS",
 		},
@@ -110,15 +256,27 @@ var (
 		"COMMENT_SERVICE": {
 			"COMMENTS",
 		},
+		"DEPRECATION_COMMENT": {
+			"OTHER",
+		},
+		"DIRECTORY_PACKAGE_MAJORITY": {
+			"OTHER",
+		},
 		"DIRECTORY_SAME_PACKAGE": {
 			"MINIMAL",
 			"BASIC",
 			"DEFAULT",
 			"FILE_LAYOUT",
 		},
+		"ENUM_ALLOW_ALIAS_USED": {
+			"OTHER",
+		},
 		"ENUM_FIRST_VALUE_ZERO": {
 			"OTHER",
 		},
+		"ENUM_NOT_NESTED": {
+			"OTHER",
+		},
 		"ENUM_NO_ALLOW_ALIAS": {
 			"MINIMAL",
 			"BASIC",
@@ -131,6 +289,12 @@ var (
 			"STYLE_BASIC",
 			"STYLE_DEFAULT",
 		},
+		"ENUM_VALUE_MAX_COUNT": {
+			"OTHER",
+		},
+		"ENUM_VALUE_NO_GAP": {
+			"OTHER",
+		},
 		"ENUM_VALUE_PREFIX": {
 			"DEFAULT",
 			"STYLE_DEFAULT",
@@ -145,22 +309,76 @@ var (
 			"DEFAULT",
 			"STYLE_DEFAULT",
 		},
+		"FIELD_BOOL_AFFIRMATIVE": {
+			"OTHER",
+		},
+		"FIELD_DEPRECATED_RESERVE": {
+			"OTHER",
+		},
+		"FIELD_JSON_NAME_NO_CONFLICT": {
+			"OTHER",
+		},
+		"FIELD_LABEL_CONSISTENT": {
+			"OTHER",
+		},
 		"FIELD_LOWER_SNAKE_CASE": {
 			"BASIC",
 			"DEFAULT",
 			"STYLE_BASIC",
 			"STYLE_DEFAULT",
 		},
+		"FIELD_MAX_NUMBER": {
+			"OTHER",
+		},
+		"FIELD_NAMES_DISTINCT": {
+			"OTHER",
+		},
 		"FIELD_NO_DESCRIPTOR": {
 			"MINIMAL",
 			"BASIC",
 			"DEFAULT",
 			"SENSIBLE",
 		},
+		"FIELD_NO_GROUP": {
+			"OTHER",
+		},
+		"FIELD_NO_RESERVED": {
+			"OTHER",
+		},
+		"FIELD_NO_ANY": {
+			"OTHER",
+		},
+		"FIELD_NO_STUTTER": {
+			"OTHER",
+		},
+		"FIELD_NO_WRAPPER_TYPE": {
+			"OTHER",
+		},
+		"FIELD_NUMBERS_ASCENDING": {
+			"OTHER",
+		},
+		"FIELD_OPTION_CONSISTENT": {
+			"OTHER",
+		},
+		"FIELD_PRESENCE_COMMENT": {
+			"OTHER",
+		},
+		"FIELD_PROTO3_OPTIONAL": {
+			"OTHER",
+		},
+		"FILE_HEADER": {
+			"OTHER",
+		},
 		"FILE_LOWER_SNAKE_CASE": {
 			"DEFAULT",
 			"STYLE_DEFAULT",
 		},
+		"FILE_NO_CRLF": {
+			"OTHER",
+		},
+		"IMPORT_NO_FORBIDDEN_PATH": {
+			"OTHER",
+		},
 		"IMPORT_NO_PUBLIC": {
 			"MINIMAL",
 			"BASIC",
@@ -173,12 +391,36 @@ var (
 			"DEFAULT",
 			"SENSIBLE",
 		},
+		"IMPORT_SORTED": {
+			"OTHER",
+		},
+		"MAP_KEY_NO_FORBIDDEN_TYPE": {
+			"OTHER",
+		},
+		"MAP_VALUE_NO_MAP_WRAPPER": {
+			"OTHER",
+		},
+		"MESSAGE_FIELD_NUMBERS_DENSE": {
+			"OTHER",
+		},
 		"MESSAGE_PASCAL_CASE": {
 			"BASIC",
 			"DEFAULT",
 			"STYLE_BASIC",
 			"STYLE_DEFAULT",
 		},
+		"NAME_MAX_LENGTH": {
+			"OTHER",
+		},
+		"NAME_NO_LEADING_UNDERSCORE": {
+			"OTHER",
+		},
+		"NAME_NO_RESERVED_WORD": {
+			"OTHER",
+		},
+		"NESTED_TYPE_REFERENCED": {
+			"OTHER",
+		},
 		"ONEOF_LOWER_SNAKE_CASE": {
 			"BASIC",
 			"DEFAULT",
@@ -197,6 +439,9 @@ var (
 			"DEFAULT",
 			"FILE_LAYOUT",
 		},
+		"PACKAGE_FILE_MAX_COUNT": {
+			"OTHER",
+		},
 		"PACKAGE_LOWER_SNAKE_CASE": {
 			"BASIC",
 			"DEFAULT",
@@ -255,12 +500,18 @@ var (
 			"DEFAULT",
 			"STYLE_DEFAULT",
 		},
+		"RPC_IDEMPOTENCY_LEVEL_SPECIFIED": {
+			"OTHER",
+		},
 		"RPC_NO_CLIENT_STREAMING": {
 			"UNARY_RPC",
 		},
 		"RPC_NO_SERVER_STREAMING": {
 			"UNARY_RPC",
 		},
+		"RPC_PAGINATION_FIELDS": {
+			"OTHER",
+		},
 		"RPC_PASCAL_CASE": {
 			"BASIC",
 			"DEFAULT",
@@ -275,41 +526,113 @@ var (
 			"DEFAULT",
 			"STYLE_DEFAULT",
 		},
+		"RPC_REQUIRED_OPTION": {
+			"OTHER",
+		},
 		"RPC_RESPONSE_STANDARD_NAME": {
 			"DEFAULT",
 			"STYLE_DEFAULT",
 		},
+		"SERVICE_NO_EMPTY": {
+			"OTHER",
+		},
 		"SERVICE_PASCAL_CASE": {
 			"BASIC",
 			"DEFAULT",
 			"STYLE_BASIC",
 			"STYLE_DEFAULT",
 		},
+		"SERVICE_STREAMING_CONSISTENT": {
+			"OTHER",
+		},
 		"SERVICE_SUFFIX": {
 			"DEFAULT",
 			"STYLE_DEFAULT",
 		},
+		"SYNTAX_SPECIFIED": {
+			"OTHER",
+		},
+		"TYPE_NAME_PACKAGE_MAX_COUNT": {
+			"OTHER",
+		},
+		"TYPE_NO_EMPTY": {
+			"OTHER",
+		},
+		"TYPE_NO_WKT_NAME": {
+			"OTHER",
+		},
 	}
 
+	v1AcronymCasingCheckerBuilder = bufcheckinternal.NewCheckerBuilder(
+		"ACRONYM_CASING",
+		func(configBuilder bufcheckinternal.ConfigBuilder) (string, error) {
+			if len(configBuilder.Acronyms) == 0 {
+				return "message names, enum names, field names, and field JSON names use the configured casing of acronyms (no acronyms are currently configured, acronyms are configurable)", nil
+			}
+			return fmt.Sprintf("message names, enum names, field names, and field JSON names use the casing %s for acronyms (acronyms are configurable)", strings.Join(configBuilder.Acronyms, ", ")), nil
+		},
+		func(configBuilder bufcheckinternal.ConfigBuilder) (bufcheckinternal.CheckFunc, error) {
+			seen := make(map[string]struct{}, len(configBuilder.Acronyms))
+			for _, acronym := range configBuilder.Acronyms {
+				if !v1AcronymRegexp.MatchString(acronym) {
+					return nil, fmt.Errorf("acronyms contains %q which must start with a letter and only contain letters and digits", acronym)
+				}
+				if _, ok := seen[strings.ToLower(acronym)]; ok {
+					return nil, fmt.Errorf("acronyms contains %q more than once", acronym)
+				}
+				seen[strings.ToLower(acronym)] = struct{}{}
+			}
+			acronyms := configBuilder.Acronyms
+			return bufcheckinternal.CheckFunc(func(id string, ignoreFunc bufcheckinternal.IgnoreFunc, _ []protosource.File, files []protosource.File) ([]bufanalysis.FileAnnotation, error) {
+				return internal.CheckAcronymCasing(id, ignoreFunc, files, acronyms)
+			}), nil
+		},
+	)
 	v1CommentEnumCheckerBuilder = bufcheckinternal.NewNopCheckerBuilder(
 		"COMMENT_ENUM",
 		"enums have non-empty comments",
 		newAdapter(internal.CheckCommentEnum),
 	)
-	v1CommentEnumValueCheckerBuilder = bufcheckinternal.NewNopCheckerBuilder(
+	v1CommentEnumValueCheckerBuilder = bufcheckinternal.NewCheckerBuilder(
 		"COMMENT_ENUM_VALUE",
-		"enum values have non-empty comments",
-		newAdapter(internal.CheckCommentEnumValue),
+		func(configBuilder bufcheckinternal.ConfigBuilder) (string, error) {
+			var scope string
+			if configBuilder.CommentEnumValueAllowZero {
+				scope = " other than the zero value"
+			}
+			if len(configBuilder.CommentEnumValuePackages) > 0 {
+				scope += fmt.Sprintf(" in packages %s", strings.Join(configBuilder.CommentEnumValuePackages, ", "))
+			}
+			return fmt.Sprintf("enum values%s have non-empty comments", scope), nil
+		},
+		func(configBuilder bufcheckinternal.ConfigBuilder) (bufcheckinternal.CheckFunc, error) {
+			return bufcheckinternal.CheckFunc(func(id string, ignoreFunc bufcheckinternal.IgnoreFunc, _ []protosource.File, files []protosource.File) ([]bufanalysis.FileAnnotation, error) {
+				return internal.CheckCommentEnumValue(id, ignoreFunc, files, configBuilder.CommentEnumValuePackages, configBuilder.CommentEnumValueAllowZero)
+			}), nil
+		},
 	)
 	v1CommentFieldCheckerBuilder = bufcheckinternal.NewNopCheckerBuilder(
 		"COMMENT_FIELD",
 		"fields have non-empty comments",
 		newAdapter(internal.CheckCommentField),
 	)
-	v1CommentMessageCheckerBuilder = bufcheckinternal.NewNopCheckerBuilder(
+	v1CommentMessageCheckerBuilder = bufcheckinternal.NewCheckerBuilder(
 		"COMMENT_MESSAGE",
-		"messages have non-empty comments",
-		newAdapter(internal.CheckCommentMessage),
+		func(configBuilder bufcheckinternal.ConfigBuilder) (string, error) {
+			var scope string
+			if configBuilder.CommentMessageTopLevelOnly {
+				scope = " that are not nested"
+			}
+			if len(configBuilder.CommentMessagePackages) > 0 {
+				scope += fmt.Sprintf(" in packages %s", strings.Join(configBuilder.CommentMessagePackages, ", "))
+			}
+			return fmt.Sprintf("messages%s have non-empty comments", scope), nil
+		},
+		func(configBuilder bufcheckinternal.ConfigBuilder) (bufcheckinternal.CheckFunc, error) {
+			return bufcheckinternal.CheckFunc(func(id string, ignoreFunc bufcheckinternal.IgnoreFunc, _ []protosource.File, files []protosource.File) ([]bufanalysis.FileAnnotation, error) {
+				return internal.CheckCommentMessage(id, ignoreFunc, files, configBuilder.CommentMessagePackages, configBuilder.CommentMessageTopLevelOnly)
+			}), nil
+		},
 	)
 	v1CommentOneofCheckerBuilder = bufcheckinternal.NewNopCheckerBuilder(
 		"COMMENT_ONEOF",
@@ -326,16 +649,56 @@ var (
 		"services have non-empty comments",
 		newAdapter(internal.CheckCommentService),
 	)
+	v1DeprecationCommentCheckerBuilder = bufcheckinternal.NewCheckerBuilder(
+		"DEPRECATION_COMMENT",
+		func(configBuilder bufcheckinternal.ConfigBuilder) (string, error) {
+			return fmt.Sprintf("deprecated messages, enums, and fields have leading comments matching %q (the pattern is configurable)", configBuilder.DeprecationCommentRegex), nil
+		},
+		func(configBuilder bufcheckinternal.ConfigBuilder) (bufcheckinternal.CheckFunc, error) {
+			commentRegexp, err := regexp.Compile(configBuilder.DeprecationCommentRegex)
+			if err != nil {
+				return nil, fmt.Errorf("invalid deprecation_comment_regex %q: %v", configBuilder.DeprecationCommentRegex, err)
+			}
+			return bufcheckinternal.CheckFunc(func(id string, ignoreFunc bufcheckinternal.IgnoreFunc, _ []protosource.File, files []protosource.File) ([]bufanalysis.FileAnnotation, error) {
+				return internal.CheckDeprecationComment(id, ignoreFunc, files, commentRegexp)
+			}), nil
+		},
+	)
+	v1DirectoryPackageMajorityCheckerBuilder = bufcheckinternal.NewNopCheckerBuilder(
+		"DIRECTORY_PACKAGE_MAJORITY",
+		"all files in a given directory are in the package used by most files in the directory",
+		newAdapter(internal.CheckDirectoryPackageMajority),
+	)
 	v1DirectorySamePackageCheckerBuilder = bufcheckinternal.NewNopCheckerBuilder(
 		"DIRECTORY_SAME_PACKAGE",
 		"all files in a given directory are in the same package",
 		newAdapter(internal.CheckDirectorySamePackage),
 	)
+	v1EnumAllowAliasUsedCheckerBuilder = bufcheckinternal.NewNopCheckerBuilder(
+		"ENUM_ALLOW_ALIAS_USED",
+		"enums that have the allow_alias option set have at least two values with the same number",
+		newAdapter(internal.CheckEnumAllowAliasUsed),
+	)
 	v1EnumFirstValueZeroCheckerBuilder = bufcheckinternal.NewNopCheckerBuilder(
 		"ENUM_FIRST_VALUE_ZERO",
 		"all first values of enums have a numeric value of 0",
 		newAdapter(internal.CheckEnumFirstValueZero),
 	)
+	v1EnumNotNestedCheckerBuilder = bufcheckinternal.NewCheckerBuilder(
+		"ENUM_NOT_NESTED",
+		func(configBuilder bufcheckinternal.ConfigBuilder) (string, error) {
+			return "enums are not nested in messages (allowed enums are configurable)", nil
+		},
+		func(configBuilder bufcheckinternal.ConfigBuilder) (bufcheckinternal.CheckFunc, error) {
+			allowEnums := make(map[string]struct{}, len(configBuilder.AllowNestedEnums))
+			for _, allowEnum := range configBuilder.AllowNestedEnums {
+				allowEnums[strings.TrimPrefix(allowEnum, ".")] = struct{}{}
+			}
+			return bufcheckinternal.CheckFunc(func(id string, ignoreFunc bufcheckinternal.IgnoreFunc, _ []protosource.File, files []protosource.File) ([]bufanalysis.FileAnnotation, error) {
+				return internal.CheckEnumNotNested(id, ignoreFunc, files, allowEnums)
+			}), nil
+		},
+	)
 	v1EnumNoAllowAliasCheckerBuilder = bufcheckinternal.NewNopCheckerBuilder(
 		"ENUM_NO_ALLOW_ALIAS",
 		"enums do not have the allow_alias option set",
@@ -346,6 +709,32 @@ var (
 		"enums are PascalCase",
 		newAdapter(internal.CheckEnumPascalCase),
 	)
+	v1EnumValueMaxCountCheckerBuilder = bufcheckinternal.NewCheckerBuilder(
+		"ENUM_VALUE_MAX_COUNT",
+		func(configBuilder bufcheckinternal.ConfigBuilder) (string, error) {
+			if configBuilder.EnumValueMaxCount <= 0 {
+				return "", errors.New("enum_value_max_count must be positive")
+			}
+			counted := "all value names"
+			if configBuilder.EnumValueMaxCountDistinctNumbers {
+				counted = "distinct value numbers"
+			}
+			return fmt.Sprintf("enums have at most %d values, counting %s (maximum and counting are configurable)", configBuilder.EnumValueMaxCount, counted), nil
+		},
+		func(configBuilder bufcheckinternal.ConfigBuilder) (bufcheckinternal.CheckFunc, error) {
+			if configBuilder.EnumValueMaxCount <= 0 {
+				return nil, errors.New("enum_value_max_count must be positive")
+			}
+			return bufcheckinternal.CheckFunc(func(id string, ignoreFunc bufcheckinternal.IgnoreFunc, _ []protosource.File, files []protosource.File) ([]bufanalysis.FileAnnotation, error) {
+				return internal.CheckEnumValueMaxCount(id, ignoreFunc, files, configBuilder.EnumValueMaxCount, configBuilder.EnumValueMaxCountDistinctNumbers)
+			}), nil
+		},
+	)
+	v1EnumValueNoGapCheckerBuilder = bufcheckinternal.NewNopCheckerBuilder(
+		"ENUM_VALUE_NO_GAP",
+		"enum value numbers are contiguous, with any skipped numbers reserved",
+		newAdapter(internal.CheckEnumValueNoGap),
+	)
 	v1EnumValuePrefixCheckerBuilder = bufcheckinternal.NewNopCheckerBuilder(
 		"ENUM_VALUE_PREFIX",
 		"enum values are prefixed with ENUM_NAME_UPPER_SNAKE_CASE",
@@ -373,20 +762,384 @@ var (
 			}), nil
 		},
 	)
+	v1FieldBoolAffirmativeCheckerBuilder = bufcheckinternal.NewCheckerBuilder(
+		"FIELD_BOOL_AFFIRMATIVE",
+		func(configBuilder bufcheckinternal.ConfigBuilder) (string, error) {
+			negativePrefixes := configBuilder.FieldBoolNegativePrefixes
+			if len(negativePrefixes) == 0 {
+				negativePrefixes = internal.FieldBoolNegativePrefixes()
+			}
+			return fmt.Sprintf("bool field names do not start with the negative prefixes %s (prefixes are configurable)", strings.Join(negativePrefixes, ", ")), nil
+		},
+		func(configBuilder bufcheckinternal.ConfigBuilder) (bufcheckinternal.CheckFunc, error) {
+			negativePrefixes := configBuilder.FieldBoolNegativePrefixes
+			if len(negativePrefixes) == 0 {
+				negativePrefixes = internal.FieldBoolNegativePrefixes()
+			}
+			for _, negativePrefix := range negativePrefixes {
+				if negativePrefix == "" {
+					return nil, errors.New("field_bool_negative_prefixes cannot contain an empty prefix")
+				}
+			}
+			return bufcheckinternal.CheckFunc(func(id string, ignoreFunc bufcheckinternal.IgnoreFunc, _ []protosource.File, files []protosource.File) ([]bufanalysis.FileAnnotation, error) {
+				return internal.CheckFieldBoolAffirmative(id, ignoreFunc, files, negativePrefixes)
+			}), nil
+		},
+	)
+	v1FieldDeprecatedReserveCheckerBuilder = bufcheckinternal.NewCheckerBuilder(
+		"FIELD_DEPRECATED_RESERVE",
+		func(configBuilder bufcheckinternal.ConfigBuilder) (string, error) {
+			severity, err := getV1FieldDeprecatedReserveSeverity(configBuilder)
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("deprecated fields are reported with %s severity as a reminder to reserve them when deleted (the severity is configurable)", severity.String()), nil
+		},
+		func(configBuilder bufcheckinternal.ConfigBuilder) (bufcheckinternal.CheckFunc, error) {
+			severity, err := getV1FieldDeprecatedReserveSeverity(configBuilder)
+			if err != nil {
+				return nil, err
+			}
+			return bufcheckinternal.CheckFunc(func(id string, ignoreFunc bufcheckinternal.IgnoreFunc, _ []protosource.File, files []protosource.File) ([]bufanalysis.FileAnnotation, error) {
+				fileAnnotations, err := internal.CheckFieldDeprecatedReserve(id, ignoreFunc, files)
+				if err != nil {
+					return nil, err
+				}
+				for i, fileAnnotation := range fileAnnotations {
+					fileAnnotations[i] = bufanalysis.FileAnnotationWithSeverity(fileAnnotation, severity)
+				}
+				return fileAnnotations, nil
+			}), nil
+		},
+	)
+	v1FieldJSONNameNoConflictCheckerBuilder = bufcheckinternal.NewNopCheckerBuilder(
+		"FIELD_JSON_NAME_NO_CONFLICT",
+		"the JSON names of fields do not conflict with the explicit or default JSON names of other fields in the same message",
+		newAdapter(internal.CheckFieldJSONNameNoConflict),
+	)
+	v1FieldLabelConsistentCheckerBuilder = bufcheckinternal.NewCheckerBuilder(
+		"FIELD_LABEL_CONSISTENT",
+		func(configBuilder bufcheckinternal.ConfigBuilder) (string, error) {
+			return `fields with names that differ only by a plural "s" are either both repeated or both not repeated (allowed fields are configurable)`, nil
+		},
+		func(configBuilder bufcheckinternal.ConfigBuilder) (bufcheckinternal.CheckFunc, error) {
+			allowFields := make(map[string]struct{}, len(configBuilder.AllowInconsistentFieldLabels))
+			for _, allowField := range configBuilder.AllowInconsistentFieldLabels {
+				allowFields[strings.TrimPrefix(allowField, ".")] = struct{}{}
+			}
+			return bufcheckinternal.CheckFunc(func(id string, ignoreFunc bufcheckinternal.IgnoreFunc, _ []protosource.File, files []protosource.File) ([]bufanalysis.FileAnnotation, error) {
+				return internal.CheckFieldLabelConsistent(id, ignoreFunc, files, allowFields)
+			}), nil
+		},
+	)
 	v1FieldLowerSnakeCaseCheckerBuilder = bufcheckinternal.NewNopCheckerBuilder(
 		"FIELD_LOWER_SNAKE_CASE",
 		"field names are lower_snake_case",
 		newAdapter(internal.CheckFieldLowerSnakeCase),
 	)
+	v1FieldMaxNumberCheckerBuilder = bufcheckinternal.NewCheckerBuilder(
+		"FIELD_MAX_NUMBER",
+		func(configBuilder bufcheckinternal.ConfigBuilder) (string, error) {
+			if configBuilder.FieldMaxNumber <= 0 {
+				return "", errors.New("field_max_number must be positive")
+			}
+			var scope string
+			if configBuilder.FieldMaxNumberMessageRegex != "" {
+				scope = fmt.Sprintf(" of messages matching %q", configBuilder.FieldMaxNumberMessageRegex)
+			}
+			return fmt.Sprintf("fields%s have numbers of at most %d (the maximum and pattern are configurable)", scope, configBuilder.FieldMaxNumber), nil
+		},
+		func(configBuilder bufcheckinternal.ConfigBuilder) (bufcheckinternal.CheckFunc, error) {
+			if configBuilder.FieldMaxNumber <= 0 {
+				return nil, errors.New("field_max_number must be positive")
+			}
+			var messageRegexp *regexp.Regexp
+			if configBuilder.FieldMaxNumberMessageRegex != "" {
+				var err error
+				messageRegexp, err = regexp.Compile(configBuilder.FieldMaxNumberMessageRegex)
+				if err != nil {
+					return nil, fmt.Errorf("invalid field_max_number_message_regex %q: %v", configBuilder.FieldMaxNumberMessageRegex, err)
+				}
+			}
+			return bufcheckinternal.CheckFunc(func(id string, ignoreFunc bufcheckinternal.IgnoreFunc, _ []protosource.File, files []protosource.File) ([]bufanalysis.FileAnnotation, error) {
+				return internal.CheckFieldMaxNumber(id, ignoreFunc, files, configBuilder.FieldMaxNumber, messageRegexp)
+			}), nil
+		},
+	)
+	v1FieldNamesDistinctCheckerBuilder = bufcheckinternal.NewCheckerBuilder(
+		"FIELD_NAMES_DISTINCT",
+		func(configBuilder bufcheckinternal.ConfigBuilder) (string, error) {
+			if configBuilder.FieldNamesDistinctMaxDistance <= 0 {
+				return "", errors.New("field_names_distinct_max_distance must be positive")
+			}
+			return fmt.Sprintf("field names in the same message are not within an edit distance of %d of each other (the distance is configurable)", configBuilder.FieldNamesDistinctMaxDistance), nil
+		},
+		func(configBuilder bufcheckinternal.ConfigBuilder) (bufcheckinternal.CheckFunc, error) {
+			if configBuilder.FieldNamesDistinctMaxDistance <= 0 {
+				return nil, errors.New("field_names_distinct_max_distance must be positive")
+			}
+			return bufcheckinternal.CheckFunc(func(id string, ignoreFunc bufcheckinternal.IgnoreFunc, _ []protosource.File, files []protosource.File) ([]bufanalysis.FileAnnotation, error) {
+				return internal.CheckFieldNamesDistinct(id, ignoreFunc, files, configBuilder.FieldNamesDistinctMaxDistance)
+			}), nil
+		},
+	)
 	v1FieldNoDescriptorCheckerBuilder = bufcheckinternal.NewNopCheckerBuilder(
 		"FIELD_NO_DESCRIPTOR",
 		`field names are are not name capitalization of "descriptor" with any number of prefix or suffix underscores`,
 		newAdapter(internal.CheckFieldNoDescriptor),
 	)
-	v1FileLowerSnakeCaseCheckerBuilder = bufcheckinternal.NewNopCheckerBuilder(
+	v1FieldNoGroupCheckerBuilder = bufcheckinternal.NewNopCheckerBuilder(
+		"FIELD_NO_GROUP",
+		"fields are not groups",
+		newAdapter(internal.CheckFieldNoGroup),
+	)
+	v1FieldNoReservedCheckerBuilder = bufcheckinternal.NewNopCheckerBuilder(
+		"FIELD_NO_RESERVED",
+		"field numbers and names are not in the reserved ranges and names of their message",
+		newAdapter(internal.CheckFieldNoReserved),
+	)
+	v1FieldNoAnyCheckerBuilder = bufcheckinternal.NewCheckerBuilder(
+		"FIELD_NO_ANY",
+		func(configBuilder bufcheckinternal.ConfigBuilder) (string, error) {
+			scope := ""
+			if len(configBuilder.FieldNoAnyPackages) > 0 {
+				scope = fmt.Sprintf(" in packages %s", strings.Join(configBuilder.FieldNoAnyPackages, ", "))
+			}
+			return fmt.Sprintf("fields%s do not use google.protobuf.Any (packages and allowed fields are configurable)", scope), nil
+		},
+		func(configBuilder bufcheckinternal.ConfigBuilder) (bufcheckinternal.CheckFunc, error) {
+			allowFields := make(map[string]struct{}, len(configBuilder.AllowAnyFields))
+			for _, allowField := range configBuilder.AllowAnyFields {
+				allowFields[strings.TrimPrefix(allowField, ".")] = struct{}{}
+			}
+			packages := configBuilder.FieldNoAnyPackages
+			return bufcheckinternal.CheckFunc(func(id string, ignoreFunc bufcheckinternal.IgnoreFunc, _ []protosource.File, files []protosource.File) ([]bufanalysis.FileAnnotation, error) {
+				return internal.CheckFieldNoAny(id, ignoreFunc, files, packages, allowFields)
+			}), nil
+		},
+	)
+	v1FieldNoStutterCheckerBuilder = bufcheckinternal.NewCheckerBuilder(
+		"FIELD_NO_STUTTER",
+		func(configBuilder bufcheckinternal.ConfigBuilder) (string, error) {
+			return "field names do not begin with the lower_snake_case name of their message (allowed fields are configurable)", nil
+		},
+		func(configBuilder bufcheckinternal.ConfigBuilder) (bufcheckinternal.CheckFunc, error) {
+			allowFields := make(map[string]struct{}, len(configBuilder.AllowStutterFields))
+			for _, allowField := range configBuilder.AllowStutterFields {
+				allowFields[strings.TrimPrefix(allowField, ".")] = struct{}{}
+			}
+			return bufcheckinternal.CheckFunc(func(id string, ignoreFunc bufcheckinternal.IgnoreFunc, _ []protosource.File, files []protosource.File) ([]bufanalysis.FileAnnotation, error) {
+				return internal.CheckFieldNoStutter(id, ignoreFunc, files, allowFields)
+			}), nil
+		},
+	)
+	v1FieldNoWrapperTypeCheckerBuilder = bufcheckinternal.NewCheckerBuilder(
+		"FIELD_NO_WRAPPER_TYPE",
+		func(configBuilder bufcheckinternal.ConfigBuilder) (string, error) {
+			var scope string
+			if len(configBuilder.FieldNoWrapperTypePackages) > 0 {
+				scope = fmt.Sprintf(" in packages %s", strings.Join(configBuilder.FieldNoWrapperTypePackages, ", "))
+			}
+			return fmt.Sprintf("singular fields%s use optional scalar types instead of wrapper types such as google.protobuf.Int32Value (packages are configurable)", scope), nil
+		},
+		func(configBuilder bufcheckinternal.ConfigBuilder) (bufcheckinternal.CheckFunc, error) {
+			packages := configBuilder.FieldNoWrapperTypePackages
+			return bufcheckinternal.CheckFunc(func(id string, ignoreFunc bufcheckinternal.IgnoreFunc, _ []protosource.File, files []protosource.File) ([]bufanalysis.FileAnnotation, error) {
+				return internal.CheckFieldNoWrapperType(id, ignoreFunc, files, packages)
+			}), nil
+		},
+	)
+	v1FieldNumbersAscendingCheckerBuilder = bufcheckinternal.NewCheckerBuilder(
+		"FIELD_NUMBERS_ASCENDING",
+		func(configBuilder bufcheckinternal.ConfigBuilder) (string, error) {
+			if configBuilder.FieldNumbersAscendingIgnoreOneofs {
+				return "fields are declared in ascending order of field number, not counting oneof fields (whether oneof fields are counted is configurable)", nil
+			}
+			return "fields are declared in ascending order of field number, including oneof fields (whether oneof fields are counted is configurable)", nil
+		},
+		func(configBuilder bufcheckinternal.ConfigBuilder) (bufcheckinternal.CheckFunc, error) {
+			return bufcheckinternal.CheckFunc(func(id string, ignoreFunc bufcheckinternal.IgnoreFunc, _ []protosource.File, files []protosource.File) ([]bufanalysis.FileAnnotation, error) {
+				return internal.CheckFieldNumbersAscending(id, ignoreFunc, files, configBuilder.FieldNumbersAscendingIgnoreOneofs)
+			}), nil
+		},
+	)
+	v1FieldOptionConsistentCheckerBuilder = bufcheckinternal.NewCheckerBuilder(
+		"FIELD_OPTION_CONSISTENT",
+		func(configBuilder bufcheckinternal.ConfigBuilder) (string, error) {
+			if configBuilder.FieldOptionConsistentOption == "" {
+				return "", errors.New("field_option_consistent_option is empty")
+			}
+			scope := "any type"
+			if len(configBuilder.FieldOptionConsistentTypes) > 0 {
+				scope = "types " + strings.Join(configBuilder.FieldOptionConsistentTypes, ", ")
+			}
+			return fmt.Sprintf("if any field of a type in a message has the option (%s) set, all fields of that type in the message do, for fields of %s (option and types are configurable)", configBuilder.FieldOptionConsistentOption, scope), nil
+		},
+		func(configBuilder bufcheckinternal.ConfigBuilder) (bufcheckinternal.CheckFunc, error) {
+			if configBuilder.FieldOptionConsistentOption == "" {
+				return nil, errors.New("field_option_consistent_option is empty")
+			}
+			optionNumber := configBuilder.FieldOptionConsistentOptionNumber
+			if optionNumber == 0 {
+				knownOptionNumber, ok := v1KnownFieldOptionNameToNumber[configBuilder.FieldOptionConsistentOption]
+				if !ok {
+					return nil, fmt.Errorf("field_option_consistent_option_number must be set for unknown option %q", configBuilder.FieldOptionConsistentOption)
+				}
+				optionNumber = knownOptionNumber
+			}
+			if optionNumber < 0 {
+				return nil, fmt.Errorf("field_option_consistent_option_number must be positive but was %d", optionNumber)
+			}
+			fieldTypes := make(map[string]struct{}, len(configBuilder.FieldOptionConsistentTypes))
+			for _, fieldType := range configBuilder.FieldOptionConsistentTypes {
+				if _, ok := v1FieldTypes[fieldType]; !ok {
+					return nil, fmt.Errorf("field_option_consistent_types contains %q which is not a field type", fieldType)
+				}
+				fieldTypes[fieldType] = struct{}{}
+			}
+			return bufcheckinternal.CheckFunc(func(id string, ignoreFunc bufcheckinternal.IgnoreFunc, _ []protosource.File, files []protosource.File) ([]bufanalysis.FileAnnotation, error) {
+				return internal.CheckFieldOptionConsistent(
+					id,
+					ignoreFunc,
+					files,
+					configBuilder.FieldOptionConsistentOption,
+					optionNumber,
+					fieldTypes,
+				)
+			}), nil
+		},
+	)
+	v1FieldPresenceCommentCheckerBuilder = bufcheckinternal.NewCheckerBuilder(
+		"FIELD_PRESENCE_COMMENT",
+		func(configBuilder bufcheckinternal.ConfigBuilder) (string, error) {
+			var scope string
+			if len(configBuilder.FieldPresenceCommentPackages) > 0 {
+				scope = fmt.Sprintf(" in packages %s", strings.Join(configBuilder.FieldPresenceCommentPackages, ", "))
+			}
+			return fmt.Sprintf("proto3 singular numeric and bool fields without the optional label have a non-empty comment documenting their zero value%s (the packages are configurable)", scope), nil
+		},
+		func(configBuilder bufcheckinternal.ConfigBuilder) (bufcheckinternal.CheckFunc, error) {
+			return bufcheckinternal.CheckFunc(func(id string, ignoreFunc bufcheckinternal.IgnoreFunc, _ []protosource.File, files []protosource.File) ([]bufanalysis.FileAnnotation, error) {
+				return internal.CheckFieldPresenceComment(id, ignoreFunc, files, configBuilder.FieldPresenceCommentPackages)
+			}), nil
+		},
+	)
+	v1FieldProto3OptionalCheckerBuilder = bufcheckinternal.NewCheckerBuilder(
+		"FIELD_PROTO3_OPTIONAL",
+		func(configBuilder bufcheckinternal.ConfigBuilder) (string, error) {
+			var scope string
+			if len(configBuilder.FieldProto3OptionalPackages) > 0 {
+				scope = fmt.Sprintf(" in packages %s", strings.Join(configBuilder.FieldProto3OptionalPackages, ", "))
+			}
+			switch configBuilder.FieldProto3Optional {
+			case "":
+				return "proto3 fields consistently use the optional label (not currently enforced, the mode is configurable)", nil
+			case v1FieldProto3OptionalAlways:
+				return fmt.Sprintf("proto3 singular scalar fields outside of oneofs use the optional label%s (the mode is configurable)", scope), nil
+			case v1FieldProto3OptionalNever:
+				return fmt.Sprintf("proto3 fields do not use the optional label%s (the mode is configurable)", scope), nil
+			default:
+				return "", newV1FieldProto3OptionalInvalidError(configBuilder.FieldProto3Optional)
+			}
+		},
+		func(configBuilder bufcheckinternal.ConfigBuilder) (bufcheckinternal.CheckFunc, error) {
+			var always bool
+			switch configBuilder.FieldProto3Optional {
+			case "":
+				return bufcheckinternal.CheckFunc(func(string, bufcheckinternal.IgnoreFunc, []protosource.File, []protosource.File) ([]bufanalysis.FileAnnotation, error) {
+					return nil, nil
+				}), nil
+			case v1FieldProto3OptionalAlways:
+				always = true
+			case v1FieldProto3OptionalNever:
+			default:
+				return nil, newV1FieldProto3OptionalInvalidError(configBuilder.FieldProto3Optional)
+			}
+			packages := configBuilder.FieldProto3OptionalPackages
+			return bufcheckinternal.CheckFunc(func(id string, ignoreFunc bufcheckinternal.IgnoreFunc, _ []protosource.File, files []protosource.File) ([]bufanalysis.FileAnnotation, error) {
+				return internal.CheckFieldProto3Optional(id, ignoreFunc, files, always, packages)
+			}), nil
+		},
+	)
+	v1FileHeaderCheckerBuilder = bufcheckinternal.NewCheckerBuilder(
+		"FILE_HEADER",
+		func(configBuilder bufcheckinternal.ConfigBuilder) (string, error) {
+			switch {
+			case configBuilder.FileHeaderRegex != "":
+				return fmt.Sprintf("files begin with a header comment matching %q (the header is configurable)", configBuilder.FileHeaderRegex), nil
+			case configBuilder.FileHeaderPath != "":
+				return fmt.Sprintf("files begin with the header comment in %q (the header is configurable)", configBuilder.FileHeaderPath), nil
+			case configBuilder.FileHeader != "":
+				return "files begin with the configured header comment (the header is configurable)", nil
+			default:
+				return "files begin with a header comment (no header is currently configured, the header is configurable)", nil
+			}
+		},
+		func(configBuilder bufcheckinternal.ConfigBuilder) (bufcheckinternal.CheckFunc, error) {
+			header, headerRegexp, err := getV1FileHeader(configBuilder)
+			if err != nil {
+				return nil, err
+			}
+			if header == "" && headerRegexp == nil {
+				return bufcheckinternal.CheckFunc(func(string, bufcheckinternal.IgnoreFunc, []protosource.File, []protosource.File) ([]bufanalysis.FileAnnotation, error) {
+					return nil, nil
+				}), nil
+			}
+			return bufcheckinternal.CheckFunc(func(id string, ignoreFunc bufcheckinternal.IgnoreFunc, _ []protosource.File, files []protosource.File) ([]bufanalysis.FileAnnotation, error) {
+				return internal.CheckFileHeader(id, ignoreFunc, files, header, headerRegexp)
+			}), nil
+		},
+	)
+	v1FileLowerSnakeCaseCheckerBuilder = bufcheckinternal.NewCheckerBuilder(
 		"FILE_LOWER_SNAKE_CASE",
-		"filenames are lower_snake_case",
-		newAdapter(internal.CheckFileLowerSnakeCase),
+		func(configBuilder bufcheckinternal.ConfigBuilder) (string, error) {
+			if configBuilder.FileNameRegex != "" {
+				return fmt.Sprintf("filenames without the extension match %q (the pattern is configurable)", configBuilder.FileNameRegex), nil
+			}
+			return "filenames are lower_snake_case", nil
+		},
+		func(configBuilder bufcheckinternal.ConfigBuilder) (bufcheckinternal.CheckFunc, error) {
+			if configBuilder.FileNameRegex == "" {
+				return newAdapter(internal.CheckFileLowerSnakeCase), nil
+			}
+			fileNameRegexp, err := regexp.Compile(configBuilder.FileNameRegex)
+			if err != nil {
+				return nil, fmt.Errorf("invalid file_name_regex %q: %v", configBuilder.FileNameRegex, err)
+			}
+			return bufcheckinternal.CheckFunc(func(id string, ignoreFunc bufcheckinternal.IgnoreFunc, _ []protosource.File, files []protosource.File) ([]bufanalysis.FileAnnotation, error) {
+				return internal.CheckFileNameRegex(id, ignoreFunc, files, fileNameRegexp)
+			}), nil
+		},
+	)
+	v1FileNoCRLFCheckerBuilder = bufcheckinternal.NewNopCheckerBuilder(
+		"FILE_NO_CRLF",
+		"files use LF line endings instead of CRLF line endings (only available when linting sources)",
+		newAdapter(internal.CheckFileNoCRLF),
+	)
+	v1ImportNoForbiddenPathCheckerBuilder = bufcheckinternal.NewCheckerBuilder(
+		"IMPORT_NO_FORBIDDEN_PATH",
+		func(configBuilder bufcheckinternal.ConfigBuilder) (string, error) {
+			return "imports do not reference paths that are forbidden for the importing file (forbidden paths are configurable)", nil
+		},
+		func(configBuilder bufcheckinternal.ConfigBuilder) (bufcheckinternal.CheckFunc, error) {
+			fromGlobToForbiddenGlobs := make(map[string][]string, len(configBuilder.ImportForbiddenPaths))
+			for fromGlob, forbiddenGlobs := range configBuilder.ImportForbiddenPaths {
+				fromGlob = normalpath.Normalize(fromGlob)
+				if _, err := normalpath.MatchGlob(fromGlob, ""); err != nil {
+					return nil, fmt.Errorf("invalid import_forbidden_paths glob %q: %v", fromGlob, err)
+				}
+				for _, forbiddenGlob := range forbiddenGlobs {
+					forbiddenGlob = normalpath.Normalize(forbiddenGlob)
+					if _, err := normalpath.MatchGlob(forbiddenGlob, ""); err != nil {
+						return nil, fmt.Errorf("invalid import_forbidden_paths glob %q: %v", forbiddenGlob, err)
+					}
+					fromGlobToForbiddenGlobs[fromGlob] = append(fromGlobToForbiddenGlobs[fromGlob], forbiddenGlob)
+				}
+			}
+			return bufcheckinternal.CheckFunc(func(id string, ignoreFunc bufcheckinternal.IgnoreFunc, _ []protosource.File, files []protosource.File) ([]bufanalysis.FileAnnotation, error) {
+				return internal.CheckImportNoForbiddenPath(id, ignoreFunc, files, fromGlobToForbiddenGlobs)
+			}), nil
+		},
 	)
 	v1ImportNoPublicCheckerBuilder = bufcheckinternal.NewNopCheckerBuilder(
 		"IMPORT_NO_PUBLIC",
@@ -398,11 +1151,156 @@ var (
 		"imports are not weak",
 		newAdapter(internal.CheckImportNoWeak),
 	)
+	v1ImportSortedCheckerBuilder = bufcheckinternal.NewCheckerBuilder(
+		"IMPORT_SORTED",
+		func(configBuilder bufcheckinternal.ConfigBuilder) (string, error) {
+			if len(configBuilder.ImportSortedGroups) == 0 {
+				return "imports are sorted (groups of imports are configurable)", nil
+			}
+			return fmt.Sprintf("imports are sorted within the groups of import path prefixes %q in order (groups of imports are configurable)", configBuilder.ImportSortedGroups), nil
+		},
+		func(configBuilder bufcheckinternal.ConfigBuilder) (bufcheckinternal.CheckFunc, error) {
+			seenGroups := make(map[string]struct{}, len(configBuilder.ImportSortedGroups))
+			for _, group := range configBuilder.ImportSortedGroups {
+				if _, ok := seenGroups[group]; ok {
+					return nil, fmt.Errorf("import_sorted_groups contains %q multiple times", group)
+				}
+				seenGroups[group] = struct{}{}
+			}
+			groups := configBuilder.ImportSortedGroups
+			return bufcheckinternal.CheckFunc(func(id string, ignoreFunc bufcheckinternal.IgnoreFunc, _ []protosource.File, files []protosource.File) ([]bufanalysis.FileAnnotation, error) {
+				return internal.CheckImportSorted(id, ignoreFunc, files, groups)
+			}), nil
+		},
+	)
+	v1MapKeyNoForbiddenTypeCheckerBuilder = bufcheckinternal.NewCheckerBuilder(
+		"MAP_KEY_NO_FORBIDDEN_TYPE",
+		func(configBuilder bufcheckinternal.ConfigBuilder) (string, error) {
+			if len(configBuilder.MapKeyForbiddenTypes) == 0 {
+				return "map fields do not have forbidden key types (no key types are currently forbidden, forbidden key types are configurable)", nil
+			}
+			return fmt.Sprintf("map fields do not have key types %s (forbidden key types are configurable)", strings.Join(configBuilder.MapKeyForbiddenTypes, ", ")), nil
+		},
+		func(configBuilder bufcheckinternal.ConfigBuilder) (bufcheckinternal.CheckFunc, error) {
+			forbiddenTypes := make(map[string]struct{}, len(configBuilder.MapKeyForbiddenTypes))
+			for _, forbiddenType := range configBuilder.MapKeyForbiddenTypes {
+				if _, ok := v1MapKeyTypes[forbiddenType]; !ok {
+					return nil, fmt.Errorf("map_key_forbidden_types contains %q which is not a scalar type", forbiddenType)
+				}
+				forbiddenTypes[forbiddenType] = struct{}{}
+			}
+			return bufcheckinternal.CheckFunc(func(id string, ignoreFunc bufcheckinternal.IgnoreFunc, _ []protosource.File, files []protosource.File) ([]bufanalysis.FileAnnotation, error) {
+				return internal.CheckMapKeyNoForbiddenType(id, ignoreFunc, files, forbiddenTypes)
+			}), nil
+		},
+	)
+	v1MapValueNoMapWrapperCheckerBuilder = bufcheckinternal.NewCheckerBuilder(
+		"MAP_VALUE_NO_MAP_WRAPPER",
+		func(configBuilder bufcheckinternal.ConfigBuilder) (string, error) {
+			return "map fields do not have value types that only wrap a single map field, which are likely nested maps (allowed value types are configurable)", nil
+		},
+		func(configBuilder bufcheckinternal.ConfigBuilder) (bufcheckinternal.CheckFunc, error) {
+			allowTypes := make(map[string]struct{}, len(configBuilder.AllowMapWrapperTypes))
+			for _, allowType := range configBuilder.AllowMapWrapperTypes {
+				allowTypes[strings.TrimPrefix(allowType, ".")] = struct{}{}
+			}
+			return bufcheckinternal.CheckFunc(func(id string, ignoreFunc bufcheckinternal.IgnoreFunc, _ []protosource.File, files []protosource.File) ([]bufanalysis.FileAnnotation, error) {
+				return internal.CheckMapValueNoMapWrapper(id, ignoreFunc, files, allowTypes)
+			}), nil
+		},
+	)
+	v1MessageFieldNumbersDenseCheckerBuilder = bufcheckinternal.NewCheckerBuilder(
+		"MESSAGE_FIELD_NUMBERS_DENSE",
+		func(configBuilder bufcheckinternal.ConfigBuilder) (string, error) {
+			if configBuilder.MessageFieldNumbersDenseFactor <= 0 {
+				return "", errors.New("message_field_numbers_dense_factor must be positive")
+			}
+			return fmt.Sprintf("the highest field number of messages is at most %d times the number of fields (the factor is configurable)", configBuilder.MessageFieldNumbersDenseFactor), nil
+		},
+		func(configBuilder bufcheckinternal.ConfigBuilder) (bufcheckinternal.CheckFunc, error) {
+			if configBuilder.MessageFieldNumbersDenseFactor <= 0 {
+				return nil, errors.New("message_field_numbers_dense_factor must be positive")
+			}
+			return bufcheckinternal.CheckFunc(func(id string, ignoreFunc bufcheckinternal.IgnoreFunc, _ []protosource.File, files []protosource.File) ([]bufanalysis.FileAnnotation, error) {
+				return internal.CheckMessageFieldNumbersDense(id, ignoreFunc, files, configBuilder.MessageFieldNumbersDenseFactor)
+			}), nil
+		},
+	)
 	v1MessagePascalCaseCheckerBuilder = bufcheckinternal.NewNopCheckerBuilder(
 		"MESSAGE_PASCAL_CASE",
 		"messages are PascalCase",
 		newAdapter(internal.CheckMessagePascalCase),
 	)
+	v1NameMaxLengthCheckerBuilder = bufcheckinternal.NewCheckerBuilder(
+		"NAME_MAX_LENGTH",
+		func(configBuilder bufcheckinternal.ConfigBuilder) (string, error) {
+			if configBuilder.NameMaxLength <= 0 {
+				return "", errors.New("name_max_length must be positive")
+			}
+			return fmt.Sprintf("message, field, enum, enum value, service, and RPC names have at most %d characters (configurable)", configBuilder.NameMaxLength), nil
+		},
+		func(configBuilder bufcheckinternal.ConfigBuilder) (bufcheckinternal.CheckFunc, error) {
+			if configBuilder.NameMaxLength <= 0 {
+				return nil, errors.New("name_max_length must be positive")
+			}
+			return bufcheckinternal.CheckFunc(func(id string, ignoreFunc bufcheckinternal.IgnoreFunc, _ []protosource.File, files []protosource.File) ([]bufanalysis.FileAnnotation, error) {
+				return internal.CheckNameMaxLength(id, ignoreFunc, files, configBuilder.NameMaxLength)
+			}), nil
+		},
+	)
+	v1NameNoLeadingUnderscoreCheckerBuilder = bufcheckinternal.NewNopCheckerBuilder(
+		"NAME_NO_LEADING_UNDERSCORE",
+		"message, field, enum, service, and RPC names do not start with an underscore",
+		newAdapter(internal.CheckNameNoLeadingUnderscore),
+	)
+	v1NameNoReservedWordCheckerBuilder = bufcheckinternal.NewCheckerBuilder(
+		"NAME_NO_RESERVED_WORD",
+		func(configBuilder bufcheckinternal.ConfigBuilder) (string, error) {
+			languages := configBuilder.ReservedWordLanguages
+			if len(languages) == 0 {
+				languages = internal.ReservedWordLanguages()
+			}
+			return fmt.Sprintf("message, field, and enum names are not reserved words in %s (languages are configurable)", strings.Join(languages, ", ")), nil
+		},
+		func(configBuilder bufcheckinternal.ConfigBuilder) (bufcheckinternal.CheckFunc, error) {
+			supportedLanguages := internal.ReservedWordLanguages()
+			supportedLanguageMap := stringutil.SliceToMap(supportedLanguages)
+			for _, language := range configBuilder.ReservedWordLanguages {
+				if _, ok := supportedLanguageMap[language]; !ok {
+					return nil, fmt.Errorf("reserved_word_languages contains %q which is not one of %s", language, strings.Join(supportedLanguages, ", "))
+				}
+			}
+			languages := configBuilder.ReservedWordLanguages
+			if len(languages) == 0 {
+				languages = supportedLanguages
+			}
+			return bufcheckinternal.CheckFunc(func(id string, ignoreFunc bufcheckinternal.IgnoreFunc, _ []protosource.File, files []protosource.File) ([]bufanalysis.FileAnnotation, error) {
+				return internal.CheckNameNoReservedWord(id, ignoreFunc, files, languages)
+			}), nil
+		},
+	)
+	v1NestedTypeReferencedCheckerBuilder = bufcheckinternal.NewCheckerBuilder(
+		"NESTED_TYPE_REFERENCED",
+		func(configBuilder bufcheckinternal.ConfigBuilder) (string, error) {
+			imageScope, err := getV1NestedTypeReferencedImageScope(configBuilder)
+			if err != nil {
+				return "", err
+			}
+			if imageScope {
+				return "nested messages and enums are referenced by a field or RPC in any file (the scope is configurable)", nil
+			}
+			return "nested messages and enums are referenced by a field or RPC in the same file (the scope is configurable)", nil
+		},
+		func(configBuilder bufcheckinternal.ConfigBuilder) (bufcheckinternal.CheckFunc, error) {
+			imageScope, err := getV1NestedTypeReferencedImageScope(configBuilder)
+			if err != nil {
+				return nil, err
+			}
+			return bufcheckinternal.CheckFunc(func(id string, ignoreFunc bufcheckinternal.IgnoreFunc, _ []protosource.File, files []protosource.File) ([]bufanalysis.FileAnnotation, error) {
+				return internal.CheckNestedTypeReferenced(id, ignoreFunc, files, imageScope)
+			}), nil
+		},
+	)
 	v1OneofLowerSnakeCaseCheckerBuilder = bufcheckinternal.NewNopCheckerBuilder(
 		"ONEOF_LOWER_SNAKE_CASE",
 		"oneof names are lower_snake_case",
@@ -410,7 +1308,7 @@ var (
 	)
 	v1PackageDefinedCheckerBuilder = bufcheckinternal.NewNopCheckerBuilder(
 		"PACKAGE_DEFINED",
-		"all files with have a package defined",
+		"all files have a package defined",
 		newAdapter(internal.CheckPackageDefined),
 	)
 	v1PackageDirectoryMatchCheckerBuilder = bufcheckinternal.NewNopCheckerBuilder(
@@ -418,6 +1316,27 @@ var (
 		"all files with are in a directory that matches their package name",
 		newAdapter(internal.CheckPackageDirectoryMatch),
 	)
+	v1PackageFileMaxCountCheckerBuilder = bufcheckinternal.NewCheckerBuilder(
+		"PACKAGE_FILE_MAX_COUNT",
+		func(configBuilder bufcheckinternal.ConfigBuilder) (string, error) {
+			if configBuilder.PackageFileMaxCount <= 0 {
+				return "", errors.New("package_file_max_count must be positive")
+			}
+			annotated := "each file"
+			if configBuilder.PackageFileMaxCountFirstFileOnly {
+				annotated = "the first file"
+			}
+			return fmt.Sprintf("packages have at most %d files, annotating %s of a package with more (maximum and annotation are configurable)", configBuilder.PackageFileMaxCount, annotated), nil
+		},
+		func(configBuilder bufcheckinternal.ConfigBuilder) (bufcheckinternal.CheckFunc, error) {
+			if configBuilder.PackageFileMaxCount <= 0 {
+				return nil, errors.New("package_file_max_count must be positive")
+			}
+			return bufcheckinternal.CheckFunc(func(id string, ignoreFunc bufcheckinternal.IgnoreFunc, _ []protosource.File, files []protosource.File) ([]bufanalysis.FileAnnotation, error) {
+				return internal.CheckPackageFileMaxCount(id, ignoreFunc, files, configBuilder.PackageFileMaxCount, configBuilder.PackageFileMaxCountFirstFileOnly)
+			}), nil
+		},
+	)
 	v1PackageLowerSnakeCaseCheckerBuilder = bufcheckinternal.NewNopCheckerBuilder(
 		"PACKAGE_LOWER_SNAKE_CASE",
 		"packages are lower_snake.case",
@@ -468,6 +1387,22 @@ var (
 		`the last component of all packages is a version of the form v\d+, v\d+test.*, v\d+(alpha|beta)\d+, or v\d+p\d+(alpha|beta)\d+, where numbers are >=1`,
 		newAdapter(internal.CheckPackageVersionSuffix),
 	)
+	v1RPCIdempotencyLevelSpecifiedCheckerBuilder = bufcheckinternal.NewCheckerBuilder(
+		"RPC_IDEMPOTENCY_LEVEL_SPECIFIED",
+		func(configBuilder bufcheckinternal.ConfigBuilder) (string, error) {
+			return "RPCs have the idempotency_level option set to a value other than IDEMPOTENCY_UNKNOWN (services are configurable)", nil
+		},
+		func(configBuilder bufcheckinternal.ConfigBuilder) (bufcheckinternal.CheckFunc, error) {
+			serviceNames := make(map[string]struct{}, len(configBuilder.RPCIdempotencyLevelServices))
+			for _, serviceName := range configBuilder.RPCIdempotencyLevelServices {
+				serviceNames[strings.TrimPrefix(serviceName, ".")] = struct{}{}
+			}
+			packages := configBuilder.RPCIdempotencyLevelPackages
+			return bufcheckinternal.CheckFunc(func(id string, ignoreFunc bufcheckinternal.IgnoreFunc, _ []protosource.File, files []protosource.File) ([]bufanalysis.FileAnnotation, error) {
+				return internal.CheckRPCIdempotencyLevelSpecified(id, ignoreFunc, files, serviceNames, packages)
+			}), nil
+		},
+	)
 	v1RPCNoClientStreamingCheckerBuilder = bufcheckinternal.NewNopCheckerBuilder(
 		"RPC_NO_CLIENT_STREAMING",
 		"RPCs are not client streaming",
@@ -478,6 +1413,21 @@ var (
 		"RPCs are not server streaming",
 		newAdapter(internal.CheckRPCNoServerStreaming),
 	)
+	v1RPCPaginationFieldsCheckerBuilder = bufcheckinternal.NewCheckerBuilder(
+		"RPC_PAGINATION_FIELDS",
+		func(configBuilder bufcheckinternal.ConfigBuilder) (string, error) {
+			return fmt.Sprintf("RPCs with names matching %q have page_size and page_token request fields and a next_page_token response field (the pattern is configurable)", configBuilder.RPCPaginationMethodRegex), nil
+		},
+		func(configBuilder bufcheckinternal.ConfigBuilder) (bufcheckinternal.CheckFunc, error) {
+			methodRegexp, err := regexp.Compile(configBuilder.RPCPaginationMethodRegex)
+			if err != nil {
+				return nil, fmt.Errorf("invalid rpc_pagination_method_regex %q: %v", configBuilder.RPCPaginationMethodRegex, err)
+			}
+			return bufcheckinternal.CheckFunc(func(id string, ignoreFunc bufcheckinternal.IgnoreFunc, _ []protosource.File, files []protosource.File) ([]bufanalysis.FileAnnotation, error) {
+				return internal.CheckRPCPaginationFields(id, ignoreFunc, files, methodRegexp)
+			}), nil
+		},
+	)
 	v1RPCPascalCaseCheckerBuilder = bufcheckinternal.NewNopCheckerBuilder(
 		"RPC_PASCAL_CASE",
 		"RPCs are PascalCase",
@@ -517,6 +1467,47 @@ var (
 			}), nil
 		},
 	)
+	v1RPCRequiredOptionCheckerBuilder = bufcheckinternal.NewCheckerBuilder(
+		"RPC_REQUIRED_OPTION",
+		func(configBuilder bufcheckinternal.ConfigBuilder) (string, error) {
+			if configBuilder.RPCRequiredOption == "" {
+				return "", errors.New("rpc_required_option is empty")
+			}
+			return "RPCs have the option (" + configBuilder.RPCRequiredOption + ") set (option and services are configurable)", nil
+		},
+		func(configBuilder bufcheckinternal.ConfigBuilder) (bufcheckinternal.CheckFunc, error) {
+			if configBuilder.RPCRequiredOption == "" {
+				return nil, errors.New("rpc_required_option is empty")
+			}
+			optionNumber := configBuilder.RPCRequiredOptionNumber
+			if optionNumber == 0 {
+				knownOptionNumber, ok := v1KnownMethodOptionNameToNumber[configBuilder.RPCRequiredOption]
+				if !ok {
+					return nil, fmt.Errorf("rpc_required_option_number must be set for unknown option %q", configBuilder.RPCRequiredOption)
+				}
+				optionNumber = knownOptionNumber
+			}
+			if optionNumber < 0 {
+				return nil, fmt.Errorf("rpc_required_option_number must be positive but was %d", optionNumber)
+			}
+			serviceNames := make(map[string]struct{}, len(configBuilder.RPCRequiredOptionServices))
+			for _, serviceName := range configBuilder.RPCRequiredOptionServices {
+				serviceNames[strings.TrimPrefix(serviceName, ".")] = struct{}{}
+			}
+			packages := configBuilder.RPCRequiredOptionPackages
+			return bufcheckinternal.CheckFunc(func(id string, ignoreFunc bufcheckinternal.IgnoreFunc, _ []protosource.File, files []protosource.File) ([]bufanalysis.FileAnnotation, error) {
+				return internal.CheckRPCRequiredOption(
+					id,
+					ignoreFunc,
+					files,
+					configBuilder.RPCRequiredOption,
+					optionNumber,
+					serviceNames,
+					packages,
+				)
+			}), nil
+		},
+	)
 	v1RPCResponseStandardNameCheckerBuilder = bufcheckinternal.NewCheckerBuilder(
 		"RPC_RESPONSE_STANDARD_NAME",
 		func(configBuilder bufcheckinternal.ConfigBuilder) (string, error) {
@@ -533,25 +1524,117 @@ var (
 			}), nil
 		},
 	)
+	v1ServiceNoEmptyCheckerBuilder = bufcheckinternal.NewCheckerBuilder(
+		"SERVICE_NO_EMPTY",
+		func(configBuilder bufcheckinternal.ConfigBuilder) (string, error) {
+			return "services have at least one RPC (allowed services are configurable)", nil
+		},
+		func(configBuilder bufcheckinternal.ConfigBuilder) (bufcheckinternal.CheckFunc, error) {
+			allowServices := make(map[string]struct{}, len(configBuilder.AllowEmptyServices))
+			for _, allowService := range configBuilder.AllowEmptyServices {
+				allowServices[strings.TrimPrefix(allowService, ".")] = struct{}{}
+			}
+			return bufcheckinternal.CheckFunc(func(id string, ignoreFunc bufcheckinternal.IgnoreFunc, _ []protosource.File, files []protosource.File) ([]bufanalysis.FileAnnotation, error) {
+				return internal.CheckServiceNoEmpty(id, ignoreFunc, files, allowServices)
+			}), nil
+		},
+	)
 	v1ServicePascalCaseCheckerBuilder = bufcheckinternal.NewNopCheckerBuilder(
 		"SERVICE_PASCAL_CASE",
 		"services are PascalCase",
 		newAdapter(internal.CheckServicePascalCase),
 	)
+	v1ServiceStreamingConsistentCheckerBuilder = bufcheckinternal.NewCheckerBuilder(
+		"SERVICE_STREAMING_CONSISTENT",
+		func(configBuilder bufcheckinternal.ConfigBuilder) (string, error) {
+			if configBuilder.ServiceStreamingConsistentDirection {
+				return "all RPCs of a service have the same streaming direction (whether the direction must match is configurable)", nil
+			}
+			return "RPCs of a service are either all streaming or all unary (whether the direction must match is configurable)", nil
+		},
+		func(configBuilder bufcheckinternal.ConfigBuilder) (bufcheckinternal.CheckFunc, error) {
+			return bufcheckinternal.CheckFunc(func(id string, ignoreFunc bufcheckinternal.IgnoreFunc, _ []protosource.File, files []protosource.File) ([]bufanalysis.FileAnnotation, error) {
+				return internal.CheckServiceStreamingConsistent(id, ignoreFunc, files, configBuilder.ServiceStreamingConsistentDirection)
+			}), nil
+		},
+	)
 	v1ServiceSuffixCheckerBuilder = bufcheckinternal.NewCheckerBuilder(
 		"SERVICE_SUFFIX",
 		func(configBuilder bufcheckinternal.ConfigBuilder) (string, error) {
-			if configBuilder.ServiceSuffix == "" {
-				return "", errors.New("service_suffix is empty")
+			if configBuilder.ServiceSuffix == nil {
+				return "", errors.New("service_suffix is nil")
+			}
+			if *configBuilder.ServiceSuffix == "" {
+				return "services are suffixed with a configurable suffix (disabled as the suffix is empty)", nil
+			}
+			return "services are suffixed with " + *configBuilder.ServiceSuffix + " (suffix is configurable)", nil
+		},
+		func(configBuilder bufcheckinternal.ConfigBuilder) (bufcheckinternal.CheckFunc, error) {
+			if configBuilder.ServiceSuffix == nil {
+				return nil, errors.New("service_suffix is nil")
+			}
+			serviceSuffix := *configBuilder.ServiceSuffix
+			return bufcheckinternal.CheckFunc(func(id string, ignoreFunc bufcheckinternal.IgnoreFunc, _ []protosource.File, files []protosource.File) ([]bufanalysis.FileAnnotation, error) {
+				// an explicitly empty suffix disables the check
+				if serviceSuffix == "" {
+					return nil, nil
+				}
+				return internal.CheckServiceSuffix(id, ignoreFunc, files, serviceSuffix)
+			}), nil
+		},
+	)
+	v1SyntaxSpecifiedCheckerBuilder = bufcheckinternal.NewNopCheckerBuilder(
+		"SYNTAX_SPECIFIED",
+		"all files have a syntax specified",
+		newAdapter(internal.CheckSyntaxSpecified),
+	)
+	v1TypeNamePackageMaxCountCheckerBuilder = bufcheckinternal.NewCheckerBuilder(
+		"TYPE_NAME_PACKAGE_MAX_COUNT",
+		func(configBuilder bufcheckinternal.ConfigBuilder) (string, error) {
+			if configBuilder.TypeNamePackageMaxCount <= 0 {
+				return "", errors.New("type_name_package_max_count must be positive")
 			}
-			return "services are suffixed with " + configBuilder.ServiceSuffix + " (suffix is configurable)", nil
+			return fmt.Sprintf("top-level message and enum names are defined in at most %d packages (the maximum is configurable)", configBuilder.TypeNamePackageMaxCount), nil
 		},
 		func(configBuilder bufcheckinternal.ConfigBuilder) (bufcheckinternal.CheckFunc, error) {
-			if configBuilder.ServiceSuffix == "" {
-				return nil, errors.New("service_suffix is empty")
+			if configBuilder.TypeNamePackageMaxCount <= 0 {
+				return nil, errors.New("type_name_package_max_count must be positive")
 			}
 			return bufcheckinternal.CheckFunc(func(id string, ignoreFunc bufcheckinternal.IgnoreFunc, _ []protosource.File, files []protosource.File) ([]bufanalysis.FileAnnotation, error) {
-				return internal.CheckServiceSuffix(id, ignoreFunc, files, configBuilder.ServiceSuffix)
+				return internal.CheckTypeNamePackageMaxCount(id, ignoreFunc, files, configBuilder.TypeNamePackageMaxCount)
+			}), nil
+		},
+	)
+	v1TypeNoEmptyCheckerBuilder = bufcheckinternal.NewCheckerBuilder(
+		"TYPE_NO_EMPTY",
+		func(configBuilder bufcheckinternal.ConfigBuilder) (string, error) {
+			return "messages and enums are not empty, except for google.protobuf.Empty (allowed types are configurable)", nil
+		},
+		func(configBuilder bufcheckinternal.ConfigBuilder) (bufcheckinternal.CheckFunc, error) {
+			// google.protobuf.Empty is always intentionally empty
+			allowEmptyTypes := map[string]struct{}{
+				"google.protobuf.Empty": {},
+			}
+			for _, allowEmptyType := range configBuilder.AllowEmptyTypes {
+				allowEmptyTypes[strings.TrimPrefix(allowEmptyType, ".")] = struct{}{}
+			}
+			return bufcheckinternal.CheckFunc(func(id string, ignoreFunc bufcheckinternal.IgnoreFunc, _ []protosource.File, files []protosource.File) ([]bufanalysis.FileAnnotation, error) {
+				return internal.CheckTypeNoEmpty(id, ignoreFunc, files, allowEmptyTypes)
+			}), nil
+		},
+	)
+	v1TypeNoWKTNameCheckerBuilder = bufcheckinternal.NewCheckerBuilder(
+		"TYPE_NO_WKT_NAME",
+		func(configBuilder bufcheckinternal.ConfigBuilder) (string, error) {
+			return "message and enum names are not the same as the names of well-known types such as Timestamp (allowed types are configurable)", nil
+		},
+		func(configBuilder bufcheckinternal.ConfigBuilder) (bufcheckinternal.CheckFunc, error) {
+			allowTypes := make(map[string]struct{}, len(configBuilder.AllowWKTNameTypes))
+			for _, allowType := range configBuilder.AllowWKTNameTypes {
+				allowTypes[strings.TrimPrefix(allowType, ".")] = struct{}{}
+			}
+			return bufcheckinternal.CheckFunc(func(id string, ignoreFunc bufcheckinternal.IgnoreFunc, _ []protosource.File, files []protosource.File) ([]bufanalysis.FileAnnotation, error) {
+				return internal.CheckTypeNoWKTName(id, ignoreFunc, files, allowTypes)
 			}), nil
 		},
 	)
@@ -564,3 +1647,114 @@ func newAdapter(
 		return f(id, ignoreFunc, files)
 	}
 }
+
+func newV1FieldProto3OptionalInvalidError(value string) error {
+	return fmt.Errorf("field_proto3_optional must be one of %q or %q but was %q", v1FieldProto3OptionalAlways, v1FieldProto3OptionalNever, value)
+}
+
+// getV1NestedTypeReferencedImageScope returns true if NESTED_TYPE_REFERENCED
+// counts references from all files.
+func getV1NestedTypeReferencedImageScope(configBuilder bufcheckinternal.ConfigBuilder) (bool, error) {
+	switch configBuilder.NestedTypeReferencedScope {
+	case "", v1NestedTypeReferencedScopeFile:
+		return false, nil
+	case v1NestedTypeReferencedScopeImage:
+		return true, nil
+	default:
+		return false, fmt.Errorf("nested_type_referenced_scope must be one of %q or %q but was %q", v1NestedTypeReferencedScopeFile, v1NestedTypeReferencedScopeImage, configBuilder.NestedTypeReferencedScope)
+	}
+}
+
+// getV1FieldDeprecatedReserveSeverity gets the severity for FIELD_DEPRECATED_RESERVE.
+//
+// This defaults to info, as the annotations are only a reminder.
+func getV1FieldDeprecatedReserveSeverity(configBuilder bufcheckinternal.ConfigBuilder) (bufanalysis.Severity, error) {
+	switch configBuilder.FieldDeprecatedReserveSeverity {
+	case "", "info":
+		return bufanalysis.SeverityInfo, nil
+	case "warning":
+		return bufanalysis.SeverityWarning, nil
+	case "error":
+		return bufanalysis.SeverityError, nil
+	default:
+		return 0, fmt.Errorf(`field_deprecated_reserve_severity must be one of "info", "warning" or "error" but was %q`, configBuilder.FieldDeprecatedReserveSeverity)
+	}
+}
+
+// foldV1FieldDeprecatedReserveSeverity adds FIELD_DEPRECATED_RESERVE to the
+// ErrorIDs or InfoIDs of the Config if field_deprecated_reserve_severity is set.
+//
+// Otherwise, the severity set by the Checker would be replaced with warning if
+// any error IDs are set, or with info if the ID is in the info IDs.
+func foldV1FieldDeprecatedReserveSeverity(config *Config, fieldDeprecatedReserveSeverity string) error {
+	if fieldDeprecatedReserveSeverity == "" {
+		return nil
+	}
+	severity, err := getV1FieldDeprecatedReserveSeverity(
+		bufcheckinternal.ConfigBuilder{
+			FieldDeprecatedReserveSeverity: fieldDeprecatedReserveSeverity,
+		},
+	)
+	if err != nil {
+		return err
+	}
+	id := v1FieldDeprecatedReserveCheckerBuilder.ID()
+	errorIDs := copyIDMap(config.ErrorIDs)
+	infoIDs := copyIDMap(config.InfoIDs)
+	delete(infoIDs, id)
+	switch severity {
+	case bufanalysis.SeverityError:
+		// all FileAnnotations have error severity if there are no error IDs
+		if len(errorIDs) > 0 {
+			errorIDs[id] = struct{}{}
+		}
+	case bufanalysis.SeverityInfo:
+		infoIDs[id] = struct{}{}
+	}
+	config.ErrorIDs = errorIDs
+	config.InfoIDs = infoIDs
+	return nil
+}
+
+func copyIDMap(idMap map[string]struct{}) map[string]struct{} {
+	c := make(map[string]struct{}, len(idMap))
+	for id := range idMap {
+		c[id] = struct{}{}
+	}
+	return c
+}
+
+// getV1FileHeader gets the normalized header or the header regexp for FILE_HEADER.
+//
+// Both are empty if no header is configured.
+func getV1FileHeader(configBuilder bufcheckinternal.ConfigBuilder) (string, *regexp.Regexp, error) {
+	numSet := 0
+	for _, value := range []string{
+		configBuilder.FileHeader,
+		configBuilder.FileHeaderRegex,
+		configBuilder.FileHeaderPath,
+	} {
+		if value != "" {
+			numSet++
+		}
+	}
+	if numSet > 1 {
+		return "", nil, errors.New("only one of file_header, file_header_regex, and file_header_path can be set")
+	}
+	switch {
+	case configBuilder.FileHeaderRegex != "":
+		headerRegexp, err := regexp.Compile(configBuilder.FileHeaderRegex)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid file_header_regex %q: %v", configBuilder.FileHeaderRegex, err)
+		}
+		return "", headerRegexp, nil
+	case configBuilder.FileHeaderPath != "":
+		header := internal.NormalizeFileHeader(configBuilder.FileHeaderPathContent)
+		if header == "" {
+			return "", nil, fmt.Errorf("file_header_path %q is empty", configBuilder.FileHeaderPath)
+		}
+		return header, nil, nil
+	default:
+		return internal.NormalizeFileHeader(configBuilder.FileHeader), nil, nil
+	}
+}
@@ -26,6 +26,24 @@ import (
 const (
 	defaultEnumZeroValueSuffix = "_UNSPECIFIED"
 	defaultServiceSuffix       = "Service"
+	defaultRPCRequiredOption   = "google.api.http"
+	defaultEnumValueMaxCount   = 1000
+	defaultNameMaxLength       = 64
+	// the highest field number may be up to 10 times the number of fields
+	defaultMessageFieldNumbersDenseFactor = 10
+	// a single typo
+	defaultFieldNamesDistinctMaxDistance = 1
+	// any reuse of a top-level type name across packages
+	defaultTypeNamePackageMaxCount = 1
+	// the standard List RPCs
+	defaultRPCPaginationMethodRegex = "^List"
+	// the protoc-gen-validate field rules
+	defaultFieldOptionConsistentOption = "validate.rules"
+	// the conventional deprecation comment prefixes
+	defaultDeprecationCommentRegex = `Deprecated:|Use .+ instead`
+	// the largest valid field number
+	defaultFieldMaxNumber      = 536870911
+	defaultPackageFileMaxCount = 100
 )
 
 // Config is the check config.
@@ -40,6 +58,16 @@ type Config struct {
 	IgnoreIDToRootPaths map[string]map[string]struct{}
 
 	AllowCommentIgnores bool
+
+	// ErrorIDs are the IDs of the Checkers that produce FileAnnotations with error severity.
+	//
+	// If empty, all FileAnnotations have error severity. Otherwise, FileAnnotations
+	// produced by Checkers not in ErrorIDs have warning severity.
+	ErrorIDs map[string]struct{}
+	// InfoIDs are the IDs of the Checkers that produce FileAnnotations with info severity.
+	//
+	// This takes precedence over ErrorIDs, and an ID cannot be in both.
+	InfoIDs map[string]struct{}
 }
 
 // ConfigBuilder is a config builder.
@@ -52,11 +80,67 @@ type ConfigBuilder struct {
 
 	AllowCommentIgnores bool
 
+	ErrorIDsOrCategories []string
+	InfoIDsOrCategories  []string
+
+	CommentEnumValuePackages             []string
+	CommentEnumValueAllowZero            bool
+	CommentMessagePackages               []string
+	CommentMessageTopLevelOnly           bool
+	DeprecationCommentRegex              string
 	EnumZeroValueSuffix                  string
+	EnumValueMaxCount                    int
+	EnumValueMaxCountDistinctNumbers     bool
+	FieldMaxNumber                       int
+	FieldMaxNumberMessageRegex           string
+	FieldNumbersAscendingIgnoreOneofs    bool
+	FieldDeprecatedReserveSeverity       string
+	FieldNoAnyPackages                   []string
+	FieldOptionConsistentOption          string
+	FieldOptionConsistentOptionNumber    int
+	FieldOptionConsistentTypes           []string
+	FieldPresenceCommentPackages         []string
+	FieldProto3Optional                  string
+	FieldProto3OptionalPackages          []string
+	FieldNoWrapperTypePackages           []string
+	FileHeader                           string
+	FileHeaderRegex                      string
+	FileHeaderPath                       string
+	FileHeaderPathContent                string
+	FileNameRegex                        string
+	PackageFileMaxCount                  int
+	PackageFileMaxCountFirstFileOnly     bool
 	RPCAllowSameRequestResponse          bool
 	RPCAllowGoogleProtobufEmptyRequests  bool
 	RPCAllowGoogleProtobufEmptyResponses bool
-	ServiceSuffix                        string
+	ServiceSuffix                        *string
+	AllowEmptyTypes                      []string
+	AllowEmptyServices                   []string
+	ServiceStreamingConsistentDirection  bool
+	RPCRequiredOption                    string
+	RPCRequiredOptionNumber              int
+	RPCRequiredOptionServices            []string
+	RPCRequiredOptionPackages            []string
+	RPCIdempotencyLevelServices          []string
+	RPCIdempotencyLevelPackages          []string
+	RPCPaginationMethodRegex             string
+	ImportForbiddenPaths                 map[string][]string
+	ImportSortedGroups                   []string
+	AllowWKTNameTypes                    []string
+	AllowStutterFields                   []string
+	AllowAnyFields                       []string
+	AllowNestedEnums                     []string
+	AllowInconsistentFieldLabels         []string
+	MapKeyForbiddenTypes                 []string
+	NameMaxLength                        int
+	MessageFieldNumbersDenseFactor       int
+	FieldNamesDistinctMaxDistance        int
+	TypeNamePackageMaxCount              int
+	NestedTypeReferencedScope            string
+	AllowMapWrapperTypes                 []string
+	Acronyms                             []string
+	ReservedWordLanguages                []string
+	FieldBoolNegativePrefixes            []string
 }
 
 // NewConfig returns a new Config.
@@ -88,8 +172,43 @@ func newConfig(
 	if configBuilder.EnumZeroValueSuffix == "" {
 		configBuilder.EnumZeroValueSuffix = defaultEnumZeroValueSuffix
 	}
-	if configBuilder.ServiceSuffix == "" {
-		configBuilder.ServiceSuffix = defaultServiceSuffix
+	if configBuilder.EnumValueMaxCount == 0 {
+		configBuilder.EnumValueMaxCount = defaultEnumValueMaxCount
+	}
+	if configBuilder.FieldMaxNumber == 0 {
+		configBuilder.FieldMaxNumber = defaultFieldMaxNumber
+	}
+	if configBuilder.NameMaxLength == 0 {
+		configBuilder.NameMaxLength = defaultNameMaxLength
+	}
+	if configBuilder.MessageFieldNumbersDenseFactor == 0 {
+		configBuilder.MessageFieldNumbersDenseFactor = defaultMessageFieldNumbersDenseFactor
+	}
+	if configBuilder.FieldNamesDistinctMaxDistance == 0 {
+		configBuilder.FieldNamesDistinctMaxDistance = defaultFieldNamesDistinctMaxDistance
+	}
+	if configBuilder.TypeNamePackageMaxCount == 0 {
+		configBuilder.TypeNamePackageMaxCount = defaultTypeNamePackageMaxCount
+	}
+	if configBuilder.PackageFileMaxCount == 0 {
+		configBuilder.PackageFileMaxCount = defaultPackageFileMaxCount
+	}
+	// an explicitly empty suffix is kept to disable SERVICE_SUFFIX
+	if configBuilder.ServiceSuffix == nil {
+		serviceSuffix := defaultServiceSuffix
+		configBuilder.ServiceSuffix = &serviceSuffix
+	}
+	if configBuilder.RPCRequiredOption == "" {
+		configBuilder.RPCRequiredOption = defaultRPCRequiredOption
+	}
+	if configBuilder.FieldOptionConsistentOption == "" {
+		configBuilder.FieldOptionConsistentOption = defaultFieldOptionConsistentOption
+	}
+	if configBuilder.DeprecationCommentRegex == "" {
+		configBuilder.DeprecationCommentRegex = defaultDeprecationCommentRegex
+	}
+	if configBuilder.RPCPaginationMethodRegex == "" {
+		configBuilder.RPCPaginationMethodRegex = defaultRPCPaginationMethodRegex
 	}
 	return newConfigForCheckerBuilders(
 		configBuilder,
@@ -119,6 +238,19 @@ func newConfigForCheckerBuilders(
 	if err != nil {
 		return nil, err
 	}
+	errorIDMap, err := transformToIDMap(configBuilder.ErrorIDsOrCategories, idToCategories, categoryToIDs)
+	if err != nil {
+		return nil, err
+	}
+	infoIDMap, err := transformToIDMap(configBuilder.InfoIDsOrCategories, idToCategories, categoryToIDs)
+	if err != nil {
+		return nil, err
+	}
+	for infoID := range infoIDMap {
+		if _, ok := errorIDMap[infoID]; ok {
+			return nil, fmt.Errorf("%q cannot be both an error and an info ID", infoID)
+		}
+	}
 
 	// this removes duplicates
 	// we already know that a given checker with the same ID is equivalent
@@ -202,6 +334,8 @@ func newConfigForCheckerBuilders(
 		IgnoreIDToRootPaths: ignoreIDToRootPaths,
 		IgnoreRootPaths:     ignoreRootPaths,
 		AllowCommentIgnores: configBuilder.AllowCommentIgnores,
+		ErrorIDs:            errorIDMap,
+		InfoIDs:             infoIDMap,
 	}, nil
 }
 
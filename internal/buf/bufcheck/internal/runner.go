@@ -57,7 +57,9 @@ func (r *Runner) Check(ctx context.Context, config *Config, previousFiles []prot
 	for _, checker := range checkers {
 		checker := checker
 		go func() {
+			timer := instrument.Start(r.logger, "checker", zap.String("id", checker.ID()))
 			iFileAnnotations, iErr := checker.check(ignoreFunc, previousFiles, files)
+			timer.End()
 			resultC <- newResult(iFileAnnotations, iErr)
 		}()
 	}
@@ -74,11 +76,131 @@ func (r *Runner) Check(ctx context.Context, config *Config, previousFiles []prot
 	if err != nil {
 		return nil, err
 	}
+	if len(config.ErrorIDs) > 0 {
+		for i, fileAnnotation := range fileAnnotations {
+			if _, ok := config.ErrorIDs[fileAnnotation.Type()]; !ok {
+				fileAnnotations[i] = bufanalysis.FileAnnotationWithSeverity(fileAnnotation, bufanalysis.SeverityWarning)
+			}
+		}
+	}
+	if len(config.InfoIDs) > 0 {
+		for i, fileAnnotation := range fileAnnotations {
+			if _, ok := config.InfoIDs[fileAnnotation.Type()]; ok {
+				fileAnnotations[i] = bufanalysis.FileAnnotationWithSeverity(fileAnnotation, bufanalysis.SeverityInfo)
+			}
+		}
+	}
 	bufanalysis.SortFileAnnotations(fileAnnotations)
 	return fileAnnotations, nil
 
 }
 
+// CheckFiles runs the Checkers, calling fileAnnotationsFunc with the sorted FileAnnotations
+// of each file as soon as they are complete.
+//
+// Files with a path in pathToConfig are checked with that Config, every other file
+// is checked with config.
+//
+// The Checkers with IDs in multiFileIDs check multiple files together, and are run first
+// over all files for each Config, keeping only the FileAnnotations of the files checked
+// with that Config. FileAnnotations without a file are kept for config only. Every other
+// Checker is run for each file individually, in the order of the sorted FileAnnotations,
+// so that concatenating the FileAnnotations of every call to fileAnnotationsFunc results
+// in the returned FileAnnotations. fileAnnotationsFunc is not called for files without
+// FileAnnotations. If fileAnnotationsFunc returns an error, CheckFiles stops and returns
+// the error.
+func (r *Runner) CheckFiles(
+	ctx context.Context,
+	config *Config,
+	pathToConfig map[string]*Config,
+	files []protosource.File,
+	multiFileIDs map[string]struct{},
+	fileAnnotationsFunc func([]bufanalysis.FileAnnotation) error,
+) ([]bufanalysis.FileAnnotation, error) {
+	paths := make([]string, 0, len(files))
+	pathToFile := make(map[string]protosource.File, len(files))
+	externalPathToConfig := make(map[string]*Config, len(files))
+	// config is always first so that it gets the FileAnnotations without a file
+	configs := []*Config{config}
+	for _, file := range files {
+		fileConfig, ok := pathToConfig[file.Path()]
+		if !ok {
+			fileConfig = config
+		}
+		if !containsConfig(configs, fileConfig) {
+			configs = append(configs, fileConfig)
+		}
+		paths = append(paths, file.ExternalPath())
+		pathToFile[file.ExternalPath()] = file
+		externalPathToConfig[file.ExternalPath()] = fileConfig
+	}
+	configToFileConfig := make(map[*Config]*Config, len(configs))
+	// FileAnnotations without a FileInfo have an empty path, which sorts first
+	pathToMultiFileFileAnnotations := make(map[string][]bufanalysis.FileAnnotation)
+	for _, checkConfig := range configs {
+		multiFileConfig := *checkConfig
+		multiFileConfig.Checkers = nil
+		fileConfig := *checkConfig
+		fileConfig.Checkers = nil
+		for _, checker := range checkConfig.Checkers {
+			if _, ok := multiFileIDs[checker.ID()]; ok {
+				multiFileConfig.Checkers = append(multiFileConfig.Checkers, checker)
+			} else {
+				fileConfig.Checkers = append(fileConfig.Checkers, checker)
+			}
+		}
+		configToFileConfig[checkConfig] = &fileConfig
+		multiFileFileAnnotations, err := r.Check(ctx, &multiFileConfig, nil, files)
+		if err != nil {
+			return nil, err
+		}
+		for _, fileAnnotation := range multiFileFileAnnotations {
+			path := getFileAnnotationExternalPath(fileAnnotation)
+			pathConfig, ok := externalPathToConfig[path]
+			if !ok {
+				pathConfig = config
+			}
+			if pathConfig != checkConfig {
+				continue
+			}
+			paths = append(paths, path)
+			pathToMultiFileFileAnnotations[path] = append(pathToMultiFileFileAnnotations[path], fileAnnotation)
+		}
+	}
+	paths = stringutil.SliceToUniqueSortedSlice(paths)
+	var fileAnnotations []bufanalysis.FileAnnotation
+	for _, path := range paths {
+		pathFileAnnotations := pathToMultiFileFileAnnotations[path]
+		if file, ok := pathToFile[path]; ok {
+			fileFileAnnotations, err := r.Check(ctx, configToFileConfig[externalPathToConfig[path]], nil, []protosource.File{file})
+			if err != nil {
+				return nil, err
+			}
+			pathFileAnnotations = append(pathFileAnnotations, fileFileAnnotations...)
+		}
+		if len(pathFileAnnotations) == 0 {
+			continue
+		}
+		bufanalysis.SortFileAnnotations(pathFileAnnotations)
+		if fileAnnotationsFunc != nil {
+			if err := fileAnnotationsFunc(pathFileAnnotations); err != nil {
+				return nil, err
+			}
+		}
+		fileAnnotations = append(fileAnnotations, pathFileAnnotations...)
+	}
+	return fileAnnotations, nil
+}
+
+func containsConfig(configs []*Config, config *Config) bool {
+	for _, c := range configs {
+		if c == config {
+			return true
+		}
+	}
+	return false
+}
+
 func (r *Runner) newIgnoreFunc(config *Config) IgnoreFunc {
 	if r.ignorePrefix == "" || !config.AllowCommentIgnores {
 		return func(id string, descriptor protosource.Descriptor, location protosource.Location) bool {
@@ -128,6 +250,13 @@ func locationIsIgnored(id string, ignorePrefix string, location protosource.Loca
 	return false
 }
 
+func getFileAnnotationExternalPath(fileAnnotation bufanalysis.FileAnnotation) string {
+	if fileInfo := fileAnnotation.FileInfo(); fileInfo != nil {
+		return fileInfo.ExternalPath()
+	}
+	return ""
+}
+
 type result struct {
 	FileAnnotations []bufanalysis.FileAnnotation
 	Err             error
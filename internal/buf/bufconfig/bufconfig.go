@@ -39,14 +39,30 @@ type Config struct {
 
 // Provider is a provider.
 type Provider interface {
-	// GetConfig gets the Config for the given JSON or YAML data.
+	// GetConfig gets the Config for the YAML data at ConfigFilePath within the given bucket.
 	//
-	// If the data is of length 0, returns the default config.
-	GetConfig(ctx context.Context, readBucket storage.ReadBucket) (*Config, error)
+	// If there is no file at ConfigFilePath, returns the default config.
+	// A relative lint extends is an error unless GetConfigWithLocalDir is given.
+	GetConfig(ctx context.Context, readBucket storage.ReadBucket, options ...GetConfigOption) (*Config, error)
 	// GetConfig gets the Config for the given JSON or YAML data.
 	//
 	// If the data is of length 0, returns the default config.
-	GetConfigForData(data []byte) (*Config, error)
+	// A relative lint extends is relative to the current directory.
+	GetConfigForData(ctx context.Context, data []byte) (*Config, error)
+}
+
+// GetConfigOption is an option for GetConfig.
+type GetConfigOption func(*getConfigOptions)
+
+// GetConfigWithLocalDir returns a new GetConfigOption that says that the bucket
+// is a local directory, that is the external paths of the bucket are local file paths.
+//
+// A relative lint extends is relative to the config file, which can only be
+// resolved for local directories.
+func GetConfigWithLocalDir() GetConfigOption {
+	return func(getConfigOptions *getConfigOptions) {
+		getConfigOptions.localDir = true
+	}
 }
 
 // ProviderOption is an option for a new Provider.
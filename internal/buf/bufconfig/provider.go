@@ -16,26 +16,40 @@ package bufconfig
 
 import (
 	"context"
+	"fmt"
 	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/bufbuild/buf/internal/buf/bufcheck/bufbreaking"
 	"github.com/bufbuild/buf/internal/buf/bufcheck/buflint"
 	"github.com/bufbuild/buf/internal/buf/bufmod"
 	"github.com/bufbuild/buf/internal/pkg/encoding"
 	"github.com/bufbuild/buf/internal/pkg/instrument"
+	"github.com/bufbuild/buf/internal/pkg/normalpath"
 	"github.com/bufbuild/buf/internal/pkg/storage"
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
 )
 
+// extendsHTTPTimeout is the timeout for reading a lint extends URL.
+const extendsHTTPTimeout = 30 * time.Second
+
 type provider struct {
 	logger                 *zap.Logger
 	externalConfigModifier func(*ExternalConfig) error
+	httpClient             *http.Client
 }
 
 func newProvider(logger *zap.Logger, options ...ProviderOption) *provider {
 	provider := &provider{
-		logger: logger,
+		logger:     logger,
+		httpClient: &http.Client{
+			Timeout: extendsHTTPTimeout,
+		},
 	}
 	for _, option := range options {
 		option(provider)
@@ -43,14 +57,23 @@ func newProvider(logger *zap.Logger, options ...ProviderOption) *provider {
 	return provider
 }
 
-func (p *provider) GetConfig(ctx context.Context, readBucket storage.ReadBucket) (_ *Config, retErr error) {
+func (p *provider) GetConfig(
+	ctx context.Context,
+	readBucket storage.ReadBucket,
+	options ...GetConfigOption,
+) (_ *Config, retErr error) {
 	defer instrument.Start(p.logger, "get_config").End()
 
+	getConfigOptions := newGetConfigOptions()
+	for _, option := range options {
+		option(getConfigOptions)
+	}
+
 	externalConfig := &ExternalConfig{}
 	readObject, err := readBucket.Get(ctx, ConfigFilePath)
 	if err != nil {
 		if storage.IsNotExist(err) {
-			return p.newConfig(externalConfig)
+			return p.newConfig(ctx, externalConfig, readBucket)
 		}
 		return nil, err
 	}
@@ -64,25 +87,53 @@ func (p *provider) GetConfig(ctx context.Context, readBucket storage.ReadBucket)
 	if err := encoding.UnmarshalYAMLStrict(data, externalConfig); err != nil {
 		return nil, err
 	}
-	return p.newConfig(externalConfig)
+	location := readObject.ExternalPath()
+	if !getConfigOptions.localDir {
+		if extends := externalConfig.Lint.Extends; extends != "" && !isURL(extends) && !filepath.IsAbs(extends) {
+			return nil, fmt.Errorf("lint extends %q in %s is a relative path, which is only supported for local directories, use an absolute path or an https URL instead", extends, location)
+		}
+		// the external path is not a local file path
+		location = ""
+	}
+	if err := p.resolveLintExtends(ctx, externalConfig, location); err != nil {
+		return nil, err
+	}
+	return p.newConfig(ctx, externalConfig, readBucket)
 }
 
-func (p *provider) GetConfigForData(data []byte) (*Config, error) {
+func (p *provider) GetConfigForData(ctx context.Context, data []byte) (*Config, error) {
 	defer instrument.Start(p.logger, "get_config_for_data").End()
 
 	externalConfig := &ExternalConfig{}
 	if err := encoding.UnmarshalJSONOrYAMLStrict(data, externalConfig); err != nil {
 		return nil, err
 	}
-	return p.newConfig(externalConfig)
+	// relative paths are relative to the current directory
+	if err := p.resolveLintExtends(ctx, externalConfig, ""); err != nil {
+		return nil, err
+	}
+	return p.newConfig(ctx, externalConfig, nil)
 }
 
-func (p *provider) newConfig(externalConfig *ExternalConfig) (*Config, error) {
+// newConfig returns a new Config for the ExternalConfig.
+//
+// The file_header_path of the lint config is relative to the root of the read bucket,
+// or to the current directory if the read bucket is nil.
+func (p *provider) newConfig(ctx context.Context, externalConfig *ExternalConfig, readBucket storage.ReadBucket) (*Config, error) {
 	if p.externalConfigModifier != nil {
 		if err := p.externalConfigModifier(externalConfig); err != nil {
 			return nil, err
 		}
 	}
+	if err := resolveLintFileHeaderPath(ctx, &externalConfig.Lint, readBucket); err != nil {
+		return nil, err
+	}
+	for dirPath, overrideExternalConfig := range externalConfig.Lint.Overrides {
+		if err := resolveLintFileHeaderPath(ctx, &overrideExternalConfig, readBucket); err != nil {
+			return nil, fmt.Errorf("overrides for directory %q: %v", dirPath, err)
+		}
+		externalConfig.Lint.Overrides[dirPath] = overrideExternalConfig
+	}
 	buildConfig, err := bufmod.NewConfig(externalConfig.Build)
 	if err != nil {
 		return nil, err
@@ -101,3 +152,156 @@ func (p *provider) newConfig(externalConfig *ExternalConfig) (*Config, error) {
 		Lint:     lintConfig,
 	}, nil
 }
+
+// resolveLintFileHeaderPath sets the FileHeaderPathContent of the lint config
+// to the content of the file at its FileHeaderPath.
+func resolveLintFileHeaderPath(ctx context.Context, lintExternalConfig *buflint.ExternalConfig, readBucket storage.ReadBucket) error {
+	if lintExternalConfig.FileHeaderPath == "" {
+		return nil
+	}
+	data, err := readLintFileHeaderPath(ctx, lintExternalConfig.FileHeaderPath, readBucket)
+	if err != nil {
+		return fmt.Errorf("could not read file_header_path %q: %v", lintExternalConfig.FileHeaderPath, err)
+	}
+	if len(data) == 0 {
+		return fmt.Errorf("file_header_path %q is empty", lintExternalConfig.FileHeaderPath)
+	}
+	lintExternalConfig.FileHeaderPathContent = string(data)
+	return nil
+}
+
+func readLintFileHeaderPath(ctx context.Context, fileHeaderPath string, readBucket storage.ReadBucket) ([]byte, error) {
+	if readBucket == nil {
+		return ioutil.ReadFile(fileHeaderPath)
+	}
+	path, err := normalpath.NormalizeAndValidate(fileHeaderPath)
+	if err != nil {
+		return nil, err
+	}
+	return storage.ReadPath(ctx, readBucket, path)
+}
+
+// resolveLintExtends replaces the lint config of the ExternalConfig with the lint
+// config extended from the chain of lint configs given by extends.
+//
+// The location is the path or URL of the config, relative paths given by extends
+// are relative to the location. If the location is empty, relative paths are
+// relative to the current directory.
+func (p *provider) resolveLintExtends(ctx context.Context, externalConfig *ExternalConfig, location string) error {
+	if externalConfig.Lint.Extends == "" {
+		return nil
+	}
+	if location != "" && !isURL(location) {
+		absLocation, err := filepath.Abs(location)
+		if err != nil {
+			return err
+		}
+		location = absLocation
+	}
+	lintExternalConfig, err := p.getExtendedLintExternalConfig(ctx, externalConfig.Lint, location, nil)
+	if err != nil {
+		return err
+	}
+	externalConfig.Lint = lintExternalConfig
+	return nil
+}
+
+func (p *provider) getExtendedLintExternalConfig(
+	ctx context.Context,
+	lintExternalConfig buflint.ExternalConfig,
+	location string,
+	locations []string,
+) (buflint.ExternalConfig, error) {
+	if lintExternalConfig.Extends == "" {
+		return lintExternalConfig, nil
+	}
+	baseLocation, err := getExtendsLocation(location, lintExternalConfig.Extends)
+	if err != nil {
+		return buflint.ExternalConfig{}, err
+	}
+	// the config is what is linted against, so it must not be modifiable in transit
+	if strings.HasPrefix(baseLocation, "http://") {
+		return buflint.ExternalConfig{}, fmt.Errorf("lint extends %q must be an https URL", lintExternalConfig.Extends)
+	}
+	if location != "" && len(locations) == 0 {
+		locations = []string{location}
+	}
+	for _, otherLocation := range locations {
+		if otherLocation == baseLocation {
+			return buflint.ExternalConfig{}, fmt.Errorf("lint extends cycle: %s", strings.Join(append(locations, baseLocation), " -> "))
+		}
+	}
+	data, err := p.readExtendsLocation(ctx, baseLocation)
+	if err != nil {
+		return buflint.ExternalConfig{}, fmt.Errorf("could not read lint extends %q: %v", lintExternalConfig.Extends, err)
+	}
+	baseExternalConfig := &ExternalConfig{}
+	if err := encoding.UnmarshalJSONOrYAMLStrict(data, baseExternalConfig); err != nil {
+		return buflint.ExternalConfig{}, fmt.Errorf("could not parse lint extends %q: %v", lintExternalConfig.Extends, err)
+	}
+	baseLintExternalConfig, err := p.getExtendedLintExternalConfig(
+		ctx,
+		baseExternalConfig.Lint,
+		baseLocation,
+		append(locations, baseLocation),
+	)
+	if err != nil {
+		return buflint.ExternalConfig{}, err
+	}
+	return buflint.ExtendExternalConfig(baseLintExternalConfig, lintExternalConfig)
+}
+
+func (p *provider) readExtendsLocation(ctx context.Context, location string) (_ []byte, retErr error) {
+	if !isURL(location) {
+		return ioutil.ReadFile(location)
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+	if err != nil {
+		return nil, err
+	}
+	response, err := p.httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		retErr = multierr.Append(retErr, response.Body.Close())
+	}()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("got HTTP status code %d", response.StatusCode)
+	}
+	return ioutil.ReadAll(response.Body)
+}
+
+// getExtendsLocation returns the location of extends for the config at the location.
+func getExtendsLocation(location string, extends string) (string, error) {
+	if isURL(extends) {
+		return extends, nil
+	}
+	if isURL(location) {
+		locationURL, err := url.Parse(location)
+		if err != nil {
+			return "", err
+		}
+		extendsURL, err := url.Parse(extends)
+		if err != nil {
+			return "", err
+		}
+		return locationURL.ResolveReference(extendsURL).String(), nil
+	}
+	if filepath.IsAbs(extends) {
+		return filepath.Clean(extends), nil
+	}
+	return filepath.Abs(filepath.Join(filepath.Dir(location), extends))
+}
+
+func isURL(location string) bool {
+	return strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://")
+}
+
+type getConfigOptions struct {
+	localDir bool
+}
+
+func newGetConfigOptions() *getConfigOptions {
+	return &getConfigOptions{}
+}
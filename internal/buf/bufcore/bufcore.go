@@ -143,6 +143,18 @@ func NewMultiImage(images ...Image) (Image, error) {
 	}
 }
 
+// MergeImages returns a new Image that is the union of the given Images.
+//
+// Files with the same path across the Images are de-duplicated. If a file has
+// conflicting definitions across the Images, this returns an error. Source code
+// info is not considered part of the definition, and the first file is used.
+// A file is only an import if it is an import in all Images that contain it.
+//
+// Reorders the ImageFiles to be in DAG order.
+func MergeImages(images ...Image) (Image, error) {
+	return mergeImages(images)
+}
+
 // NewImageForProto returns a new Image for the given proto Image.
 //
 // The input Files are expected to be in correct DAG order!
@@ -275,6 +287,20 @@ func ImageWithoutImports(image Image) Image {
 	return newImageNoValidate(newImageFiles)
 }
 
+// ImageWithImportDepth returns a copy of the Image that only includes the imports
+// that are within the given depth of the non-import Files.
+//
+// A depth of 0 results in no imports, a depth of 1 results in only the direct
+// imports of the non-import Files, and so on. A negative depth results in all imports.
+//
+// The backing Files are not copied.
+func ImageWithImportDepth(image Image, depth int) Image {
+	if depth < 0 {
+		return image
+	}
+	return imageWithImportDepth(image, depth)
+}
+
 // ImageWithOnlyPaths returns a copy of the Image that only includes the Files
 // with the given root relative file paths.
 //
@@ -39,11 +39,50 @@ func FreeMessageRangeStrings(
 	module bufcore.Module,
 	image bufcore.Image,
 ) ([]string, error) {
-	fileInfos, err := module.TargetFileInfos(ctx)
+	files, err := getTargetFiles(ctx, module, image)
 	if err != nil {
 		return nil, err
 	}
 	var s []string
+	for _, file := range files {
+		for _, message := range file.Messages() {
+			s = freeMessageRangeStringsRec(s, message)
+		}
+	}
+	return s, nil
+}
+
+// FreeMessageRanges gets the free MessageRanges for the target files.
+//
+// Recursive. The MessageRanges are in the same order as FreeMessageRangeStrings.
+func FreeMessageRanges(
+	ctx context.Context,
+	module bufcore.Module,
+	image bufcore.Image,
+) ([]protosource.MessageRange, error) {
+	files, err := getTargetFiles(ctx, module, image)
+	if err != nil {
+		return nil, err
+	}
+	var messageRanges []protosource.MessageRange
+	for _, file := range files {
+		for _, message := range file.Messages() {
+			messageRanges = freeMessageRangesRec(messageRanges, message)
+		}
+	}
+	return messageRanges, nil
+}
+
+func getTargetFiles(
+	ctx context.Context,
+	module bufcore.Module,
+	image bufcore.Image,
+) ([]protosource.File, error) {
+	fileInfos, err := module.TargetFileInfos(ctx)
+	if err != nil {
+		return nil, err
+	}
+	files := make([]protosource.File, 0, len(fileInfos))
 	for _, fileInfo := range fileInfos {
 		imageFile := image.GetFile(fileInfo.Path())
 		if imageFile == nil {
@@ -53,11 +92,9 @@ func FreeMessageRangeStrings(
 		if err != nil {
 			return nil, err
 		}
-		for _, message := range file.Messages() {
-			s = freeMessageRangeStringsRec(s, message)
-		}
+		files = append(files, file)
 	}
-	return s, nil
+	return files, nil
 }
 
 func freeMessageRangeStringsRec(
@@ -72,3 +109,13 @@ func freeMessageRangeStringsRec(
 	}
 	return s
 }
+
+func freeMessageRangesRec(
+	messageRanges []protosource.MessageRange,
+	message protosource.Message,
+) []protosource.MessageRange {
+	for _, nestedMessage := range message.Messages() {
+		messageRanges = freeMessageRangesRec(messageRanges, nestedMessage)
+	}
+	return append(messageRanges, protosource.FreeMessageRanges(message)...)
+}
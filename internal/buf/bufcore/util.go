@@ -19,6 +19,8 @@ import (
 	"fmt"
 
 	imagev1 "github.com/bufbuild/buf/internal/gen/proto/go/v1/bufbuild/buf/image/v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
 )
 
 func getImportFileIndexes(protoImage *imagev1.Image) (map[int]struct{}, error) {
@@ -69,6 +71,43 @@ func imageWithOnlyPaths(
 	return NewImage(imageFiles)
 }
 
+func imageWithImportDepth(image Image, depth int) Image {
+	includedPaths := make(map[string]struct{})
+	var currentPaths []string
+	for _, imageFile := range image.Files() {
+		if !imageFile.IsImport() {
+			includedPaths[imageFile.Path()] = struct{}{}
+			currentPaths = append(currentPaths, imageFile.Path())
+		}
+	}
+	for i := 0; i < depth && len(currentPaths) > 0; i++ {
+		var nextPaths []string
+		for _, currentPath := range currentPaths {
+			imageFile := image.GetFile(currentPath)
+			if imageFile == nil {
+				continue
+			}
+			for _, importPath := range imageFile.ImportPaths() {
+				if _, ok := includedPaths[importPath]; ok {
+					continue
+				}
+				includedPaths[importPath] = struct{}{}
+				nextPaths = append(nextPaths, importPath)
+			}
+		}
+		currentPaths = nextPaths
+	}
+	// the image is already in DAG order, and a subset of a DAG order is still a DAG order
+	imageFiles := image.Files()
+	newImageFiles := make([]ImageFile, 0, len(includedPaths))
+	for _, imageFile := range imageFiles {
+		if _, ok := includedPaths[imageFile.Path()]; ok {
+			newImageFiles = append(newImageFiles, imageFile)
+		}
+	}
+	return newImageNoValidate(newImageFiles)
+}
+
 // returns accumulated files in correct order
 func addFileWithImports(
 	accumulator []ImageFile,
@@ -110,3 +149,43 @@ func addFileWithImports(
 	)
 	return accumulator
 }
+
+func mergeImages(images []Image) (Image, error) {
+	var imageFiles []ImageFile
+	pathToIndex := make(map[string]int)
+	for _, image := range images {
+		for _, imageFile := range image.Files() {
+			path := imageFile.Path()
+			index, ok := pathToIndex[path]
+			if !ok {
+				pathToIndex[path] = len(imageFiles)
+				imageFiles = append(imageFiles, imageFile)
+				continue
+			}
+			existingImageFile := imageFiles[index]
+			if !fileDescriptorProtoDefinitionsEqual(existingImageFile.Proto(), imageFile.Proto()) {
+				return nil, fmt.Errorf("conflicting definitions for file %s across images", path)
+			}
+			if existingImageFile.IsImport() && !imageFile.IsImport() {
+				imageFiles[index] = newImageFileNoValidate(
+					existingImageFile.Proto(),
+					existingImageFile.ExternalPath(),
+					false,
+				)
+			}
+		}
+	}
+	return newImage(imageFiles, true)
+}
+
+// fileDescriptorProtoDefinitionsEqual returns true if the FileDescriptorProtos
+// are equal, not including source code info.
+func fileDescriptorProtoDefinitionsEqual(one *descriptorpb.FileDescriptorProto, two *descriptorpb.FileDescriptorProto) bool {
+	if one.GetSourceCodeInfo() != nil || two.GetSourceCodeInfo() != nil {
+		one = proto.Clone(one).(*descriptorpb.FileDescriptorProto)
+		one.SourceCodeInfo = nil
+		two = proto.Clone(two).(*descriptorpb.FileDescriptorProto)
+		two.SourceCodeInfo = nil
+	}
+	return proto.Equal(one, two)
+}
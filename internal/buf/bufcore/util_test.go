@@ -0,0 +1,107 @@
+// Copyright 2020 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufcore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestImageWithImportDepth(t *testing.T) {
+	t.Parallel()
+	// a.proto is the only non-import, and has a diamond import of d.proto through
+	// b.proto and c.proto, which publicly imports e.proto
+	// f.proto is an import that is not imported by any file
+	image := testNewImage(
+		t,
+		testNewImageFile(t, "f.proto", true),
+		testNewImageFile(t, "e.proto", true),
+		testNewImageFile(t, "d.proto", true, "e.proto"),
+		testNewImageFile(t, "c.proto", true, "d.proto"),
+		testNewImageFile(t, "b.proto", true, "d.proto"),
+		testNewImageFile(t, "a.proto", false, "b.proto", "c.proto"),
+	)
+	image.Files()[2].Proto().PublicDependency = []int32{0}
+	testCases := []struct {
+		depth         int
+		expectedPaths []string
+	}{
+		{
+			depth:         -1,
+			expectedPaths: []string{"f.proto", "e.proto", "d.proto", "c.proto", "b.proto", "a.proto"},
+		},
+		{
+			depth:         0,
+			expectedPaths: []string{"a.proto"},
+		},
+		{
+			depth:         1,
+			expectedPaths: []string{"c.proto", "b.proto", "a.proto"},
+		},
+		{
+			depth:         2,
+			expectedPaths: []string{"d.proto", "c.proto", "b.proto", "a.proto"},
+		},
+		{
+			// public imports count as a level of depth like any other import
+			depth:         3,
+			expectedPaths: []string{"e.proto", "d.proto", "c.proto", "b.proto", "a.proto"},
+		},
+		{
+			depth:         10,
+			expectedPaths: []string{"e.proto", "d.proto", "c.proto", "b.proto", "a.proto"},
+		},
+	}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(
+			"",
+			func(t *testing.T) {
+				t.Parallel()
+				depthImage := ImageWithImportDepth(image, testCase.depth)
+				paths := make([]string, 0, len(depthImage.Files()))
+				for _, imageFile := range depthImage.Files() {
+					paths = append(paths, imageFile.Path())
+					assert.Equal(t, imageFile, depthImage.GetFile(imageFile.Path()))
+				}
+				assert.Equal(t, testCase.expectedPaths, paths, "depth %d", testCase.depth)
+			},
+		)
+	}
+}
+
+func testNewImage(t *testing.T, imageFiles ...ImageFile) Image {
+	image, err := NewImage(imageFiles)
+	require.NoError(t, err)
+	return image
+}
+
+func testNewImageFile(t *testing.T, path string, isImport bool, importPaths ...string) ImageFile {
+	imageFile, err := NewImageFile(
+		&descriptorpb.FileDescriptorProto{
+			Name:       proto.String(path),
+			Dependency: importPaths,
+			Syntax:     proto.String("proto3"),
+		},
+		"",
+		isImport,
+	)
+	require.NoError(t, err)
+	return imageFile
+}
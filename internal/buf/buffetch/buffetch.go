@@ -18,6 +18,7 @@ import (
 	"context"
 	"io"
 	"net/http"
+	"os"
 
 	"github.com/bufbuild/buf/internal/pkg/app"
 	"github.com/bufbuild/buf/internal/pkg/fetch"
@@ -33,6 +34,11 @@ const (
 	ImageEncodingBin ImageEncoding = iota + 1
 	// ImageEncodingJSON is the JSON image encoding.
 	ImageEncodingJSON
+	// ImageEncodingBundle is the bundle image encoding.
+	//
+	// A bundle is a tar archive of the binary image and a JSON manifest
+	// of the root and import files of the image.
+	ImageEncodingBundle
 )
 
 var (
@@ -40,6 +46,10 @@ var (
 	//
 	// This does not include deprecated formats.
 	ImageFormatsString = stringutil.SliceToString(imageFormatsNotDeprecated)
+	// ImageFormats are all image formats.
+	//
+	// This does not include deprecated formats.
+	ImageFormats = append([]string{}, imageFormatsNotDeprecated...)
 	// SourceFormatsString is the string representation of all source formats.
 	//
 	// This does not include deprecated formats.
@@ -90,6 +100,8 @@ type ImageRef interface {
 // SourceRef is a source bucket reference.
 type SourceRef interface {
 	Ref
+	// IsDir returns true if the source bucket is a local directory.
+	IsDir() bool
 	fetchBucketRef() fetch.BucketRef
 }
 
@@ -168,9 +180,22 @@ type Writer interface {
 		ctx context.Context,
 		container app.EnvStdoutContainer,
 		imageRef ImageRef,
+		options ...PutImageFileOption,
 	) (io.WriteCloser, error)
 }
 
+// PutImageFileOption is an option for PutImageFile.
+type PutImageFileOption func(*putImageFileOptions)
+
+// WithPutImageFileMode says to put local image files with the given permission bits.
+//
+// The default is to use the same permissions as os.Create.
+func WithPutImageFileMode(fileMode os.FileMode) PutImageFileOption {
+	return func(putImageFileOptions *putImageFileOptions) {
+		putImageFileOptions.fileMode = &fileMode
+	}
+}
+
 // NewWriter returns a new Writer.
 func NewWriter(
 	logger *zap.Logger,
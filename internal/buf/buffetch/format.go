@@ -19,6 +19,8 @@ const (
 	formatBin = "bin"
 	// formatBingz is the binary gzipped format.
 	formatBingz = "bingz"
+	// formatBundle is the bundle format.
+	formatBundle = "bundle"
 	// formatDir is the directory format.
 	formatDir = "dir"
 	// formatGit is the git format.
@@ -40,11 +42,13 @@ var (
 	imageFormats = []string{
 		formatBin,
 		formatBingz,
+		formatBundle,
 		formatJSON,
 		formatJSONGZ,
 	}
 	imageFormatsNotDeprecated = []string{
 		formatBin,
+		formatBundle,
 		formatJSON,
 	}
 	// sorted
@@ -66,6 +70,7 @@ var (
 	allFormats = []string{
 		formatBin,
 		formatBingz,
+		formatBundle,
 		formatDir,
 		formatGit,
 		formatJSON,
@@ -77,6 +82,7 @@ var (
 	// sorted
 	allFormatsNotDeprecated = []string{
 		formatBin,
+		formatBundle,
 		formatDir,
 		formatGit,
 		formatJSON,
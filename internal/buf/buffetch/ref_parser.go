@@ -50,6 +50,7 @@ func newRefParserInternal(
 			fetch.WithRawRefProcessor(rawRefProcessor),
 			fetch.WithSingleFormat(formatBin),
 			fetch.WithSingleFormat(formatJSON),
+			fetch.WithSingleFormat(formatBundle),
 			fetch.WithSingleFormat(
 				formatBingz,
 				fetch.WithSingleDefaultCompressionType(
@@ -277,6 +278,8 @@ func parseImageEncoding(format string) (ImageEncoding, error) {
 		return ImageEncodingBin, nil
 	case formatJSON, formatJSONGZ:
 		return ImageEncodingJSON, nil
+	case formatBundle:
+		return ImageEncodingBundle, nil
 	default:
 		return 0, fmt.Errorf("invalid format for image: %q", format)
 	}
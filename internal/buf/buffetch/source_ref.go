@@ -59,6 +59,11 @@ func (r *sourceRef) PathForExternalPath(externalPath string) (string, error) {
 	return normalpath.NormalizeAndValidate(path)
 }
 
+func (r *sourceRef) IsDir() bool {
+	_, ok := r.bucketRef.(fetch.DirRef)
+	return ok
+}
+
 func (r *sourceRef) fetchRef() fetch.Ref {
 	return r.bucketRef
 }
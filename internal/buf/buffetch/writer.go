@@ -17,6 +17,7 @@ package buffetch
 import (
 	"context"
 	"io"
+	"os"
 
 	"github.com/bufbuild/buf/internal/pkg/app"
 	"github.com/bufbuild/buf/internal/pkg/fetch"
@@ -43,6 +44,24 @@ func (w *writer) PutImageFile(
 	ctx context.Context,
 	container app.EnvStdoutContainer,
 	imageRef ImageRef,
+	options ...PutImageFileOption,
 ) (io.WriteCloser, error) {
-	return w.fetchWriter.PutFile(ctx, container, imageRef.fetchFileRef())
+	putImageFileOptions := newPutImageFileOptions()
+	for _, option := range options {
+		option(putImageFileOptions)
+	}
+	var putFileOptions []fetch.PutFileOption
+	if putImageFileOptions.fileMode != nil {
+		putFileOptions = append(putFileOptions, fetch.WithPutFileMode(*putImageFileOptions.fileMode))
+	}
+	return w.fetchWriter.PutFile(ctx, container, imageRef.fetchFileRef(), putFileOptions...)
+}
+
+type putImageFileOptions struct {
+	// nil to use the default
+	fileMode *os.FileMode
+}
+
+func newPutImageFileOptions() *putImageFileOptions {
+	return &putImageFileOptions{}
 }
@@ -62,6 +62,12 @@ func (b *includeBuilder) buildForIncludes(
 	if len(includeDirPaths) == 0 {
 		includeDirPaths = []string{"."}
 	}
+	// a symlink and its target would otherwise result in the same files
+	// being found in multiple include directories
+	includeDirPaths, err := dedupeIncludeDirPathsByRealPath(includeDirPaths)
+	if err != nil {
+		return nil, err
+	}
 	absIncludeDirPaths, err := normalizeAndCheckPaths(
 		includeDirPaths,
 		"include directory",
@@ -82,7 +88,10 @@ func (b *includeBuilder) buildForIncludes(
 	}
 	var rootBuckets []storage.ReadBucket
 	for _, includeDirPath := range includeDirPaths {
-		rootBucket, err := storageos.NewReadWriteBucket(includeDirPath)
+		rootBucket, err := storageos.NewReadWriteBucket(
+			includeDirPath,
+			storageos.ReadWriteBucketWithFollowSymlinks(),
+		)
 		if err != nil {
 			return nil, err
 		}
@@ -17,12 +17,15 @@ package bufmod
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
+	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/bufbuild/buf/internal/buf/bufcore"
 	"github.com/bufbuild/buf/internal/buf/bufcore/bufcoretesting"
 	"github.com/bufbuild/buf/internal/pkg/normalpath"
+	"github.com/bufbuild/buf/internal/pkg/tmp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
@@ -198,3 +201,82 @@ func testIncludeDirPaths(
 	}
 	return includeDirPaths
 }
+
+func TestIncludeSymlinkDeduplicated(t *testing.T) {
+	t.Parallel()
+	tmpDir, err := tmp.NewDir("")
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, tmpDir.Close()) }()
+	dirPath := filepath.Join(tmpDir.AbsPath(), "proto")
+	require.NoError(t, os.MkdirAll(dirPath, 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dirPath, "1.proto"), []byte(`syntax = "proto3";`), 0600))
+	symlinkDirPath := filepath.Join(tmpDir.AbsPath(), "vendor")
+	require.NoError(t, os.Symlink(dirPath, symlinkDirPath))
+	module, err := NewIncludeBuilder(zap.NewNop()).BuildForIncludes(
+		context.Background(),
+		[]string{
+			dirPath,
+			symlinkDirPath,
+		},
+	)
+	require.NoError(t, err)
+	fileInfos, err := module.TargetFileInfos(context.Background())
+	require.NoError(t, err)
+	bufcoretesting.AssertFileInfosEqual(
+		t,
+		[]bufcore.FileInfo{
+			bufcoretesting.NewFileInfo(t, "1.proto", filepath.Join(dirPath, "1.proto"), false),
+		},
+		fileInfos,
+	)
+}
+
+func TestIncludeSymlinkDir(t *testing.T) {
+	t.Parallel()
+	tmpDir, err := tmp.NewDir("")
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, tmpDir.Close()) }()
+	vendorDirPath := filepath.Join(tmpDir.AbsPath(), "vendor", "a")
+	require.NoError(t, os.MkdirAll(vendorDirPath, 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(vendorDirPath, "1.proto"), []byte(`syntax = "proto3";`), 0600))
+	dirPath := filepath.Join(tmpDir.AbsPath(), "proto")
+	require.NoError(t, os.MkdirAll(dirPath, 0755))
+	require.NoError(t, os.Symlink(vendorDirPath, filepath.Join(dirPath, "a")))
+	module, err := NewIncludeBuilder(zap.NewNop()).BuildForIncludes(
+		context.Background(),
+		[]string{
+			dirPath,
+		},
+	)
+	require.NoError(t, err)
+	fileInfos, err := module.TargetFileInfos(context.Background())
+	require.NoError(t, err)
+	bufcoretesting.AssertFileInfosEqual(
+		t,
+		[]bufcore.FileInfo{
+			bufcoretesting.NewFileInfo(t, "a/1.proto", filepath.Join(dirPath, "a", "1.proto"), false),
+		},
+		fileInfos,
+	)
+}
+
+func TestIncludeSymlinkCycleError(t *testing.T) {
+	t.Parallel()
+	tmpDir, err := tmp.NewDir("")
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, tmpDir.Close()) }()
+	dirPath := filepath.Join(tmpDir.AbsPath(), "proto")
+	require.NoError(t, os.MkdirAll(filepath.Join(dirPath, "a"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dirPath, "a", "1.proto"), []byte(`syntax = "proto3";`), 0600))
+	require.NoError(t, os.Symlink(dirPath, filepath.Join(dirPath, "a", "loop")))
+	module, err := NewIncludeBuilder(zap.NewNop()).BuildForIncludes(
+		context.Background(),
+		[]string{
+			dirPath,
+		},
+	)
+	require.NoError(t, err)
+	_, err = module.TargetFileInfos(context.Background())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "symlink cycle")
+}
@@ -17,6 +17,8 @@ package bufmod
 import (
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"sort"
 	"strings"
 
@@ -126,6 +128,37 @@ func normalizeAndCheckPaths(
 	return outputs, nil
 }
 
+// dedupeIncludeDirPathsByRealPath removes include directories that resolve
+// to the same real path as a previous include directory, for example a
+// symlink and its target. The first include directory is kept.
+//
+// Include directories that do not exist are kept as-is so that the error
+// is reported when the bucket for the include directory is created.
+func dedupeIncludeDirPathsByRealPath(includeDirPaths []string) ([]string, error) {
+	outputs := make([]string, 0, len(includeDirPaths))
+	realPaths := make(map[string]struct{}, len(includeDirPaths))
+	for _, includeDirPath := range includeDirPaths {
+		realPath, err := filepath.EvalSymlinks(includeDirPath)
+		if err != nil {
+			if os.IsNotExist(err) {
+				outputs = append(outputs, includeDirPath)
+				continue
+			}
+			// user error
+			return nil, fmt.Errorf("could not resolve include directory %s: %v", includeDirPath, err)
+		}
+		if realPath, err = filepath.Abs(realPath); err != nil {
+			return nil, err
+		}
+		if _, ok := realPaths[realPath]; ok {
+			continue
+		}
+		realPaths[realPath] = struct{}{}
+		outputs = append(outputs, includeDirPath)
+	}
+	return outputs, nil
+}
+
 // TODO: refactor this
 func sortAndCheckDuplicatePaths(outputs []string, name string, pathType normalpath.PathType) ([]string, error) {
 	sort.Strings(outputs)
@@ -19,6 +19,7 @@ package bufwire
 
 import (
 	"context"
+	"os"
 
 	"github.com/bufbuild/buf/internal/buf/bufanalysis"
 	"github.com/bufbuild/buf/internal/buf/bufbuild"
@@ -34,6 +35,13 @@ import (
 type Env interface {
 	Image() bufcore.Image
 	Config() *bufconfig.Config
+	// FileContent returns the raw content of the non-import file with the
+	// given root relative path.
+	//
+	// This is only available if the Env was built from sources with
+	// GetEnvWithFileContent and the Config required the content, otherwise
+	// nil is returned.
+	FileContent(path string) []byte
 }
 
 // EnvReader is an environment reader.
@@ -49,6 +57,7 @@ type EnvReader interface {
 		externalFilePaths []string,
 		externalFileFilePathsAllowNotExist bool,
 		excludeSourceCodeInfo bool,
+		options ...GetEnvOption,
 	) (Env, []bufanalysis.FileAnnotation, error)
 	// GetImageEnv is the same as GetEnv but only allows image values and never builds.
 	GetImageEnv(
@@ -69,6 +78,7 @@ type EnvReader interface {
 		externalFilePaths []string,
 		externalFileFilePathsAllowNotExist bool,
 		excludeSourceCodeInfo bool,
+		options ...GetEnvOption,
 	) (Env, []bufanalysis.FileAnnotation, error)
 	// ListFiles lists the files.
 	ListFiles(
@@ -84,6 +94,20 @@ type EnvReader interface {
 	) (*bufconfig.Config, error)
 }
 
+// GetEnvOption is an option for GetEnv and GetSourceEnv.
+type GetEnvOption func(*getEnvOptions)
+
+// GetEnvWithFileContent returns a GetEnvOption that keeps the raw content of
+// the non-import files when building from sources, for use with Env.FileContent.
+//
+// The content is only read if requiresFileContent returns true for the resolved
+// Config, so that the files are not read again when the content is not used.
+func GetEnvWithFileContent(requiresFileContent func(*bufconfig.Config) bool) GetEnvOption {
+	return func(getEnvOptions *getEnvOptions) {
+		getEnvOptions.requiresFileContent = requiresFileContent
+	}
+}
+
 // NewEnvReader returns a new EnvReader.
 func NewEnvReader(
 	logger *zap.Logger,
@@ -148,9 +172,22 @@ type ImageWriter interface {
 		image bufcore.Image,
 		asFileDescriptorSet bool,
 		excludeImports bool,
+		options ...PutImageOption,
 	) error
 }
 
+// PutImageOption is an option for PutImage.
+type PutImageOption func(*putImageOptions)
+
+// PutImageWithFileMode says to write local image files with the given permission bits.
+//
+// The default is to use the same permissions as os.Create.
+func PutImageWithFileMode(fileMode os.FileMode) PutImageOption {
+	return func(putImageOptions *putImageOptions) {
+		putImageOptions.fileMode = &fileMode
+	}
+}
+
 // NewImageWriter returns a new ImageWriter.
 func NewImageWriter(
 	logger *zap.Logger,
@@ -163,3 +200,7 @@ func NewImageWriter(
 		fetchWriter,
 	)
 }
+
+type getEnvOptions struct {
+	requiresFileContent func(*bufconfig.Config) bool
+}
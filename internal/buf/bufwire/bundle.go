@@ -0,0 +1,133 @@
+// Copyright 2020 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bufwire
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/bufbuild/buf/internal/buf/bufcore"
+	"github.com/bufbuild/buf/internal/pkg/storage"
+	"github.com/bufbuild/buf/internal/pkg/storage/storagearchive"
+	"github.com/bufbuild/buf/internal/pkg/storage/storagemem"
+)
+
+const (
+	// bundleImagePath is the path of the binary image within a bundle.
+	bundleImagePath = "image.bin"
+	// bundleManifestPath is the path of the manifest within a bundle.
+	bundleManifestPath = "manifest.json"
+)
+
+// bundleManifest is the manifest of a bundle.
+//
+// The paths are in the order of the files in the image.
+type bundleManifest struct {
+	// Roots are the paths of the files that were targeted.
+	Roots []string `json:"roots"`
+	// Imports are the paths of the files that are only included as
+	// transitive imports of the roots.
+	Imports []string `json:"imports"`
+}
+
+func newBundleManifest(image bufcore.Image) *bundleManifest {
+	manifest := &bundleManifest{
+		Roots:   []string{},
+		Imports: []string{},
+	}
+	for _, imageFile := range image.Files() {
+		if imageFile.IsImport() {
+			manifest.Imports = append(manifest.Imports, imageFile.Path())
+		} else {
+			manifest.Roots = append(manifest.Roots, imageFile.Path())
+		}
+	}
+	return manifest
+}
+
+// marshalBundle returns the tar archive of the image data and the manifest of the image.
+func marshalBundle(ctx context.Context, imageData []byte, image bufcore.Image) ([]byte, error) {
+	manifestData, err := json.MarshalIndent(newBundleManifest(image), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	readBucket, err := storagemem.NewReadBucket(
+		map[string][]byte{
+			bundleImagePath:    imageData,
+			bundleManifestPath: append(manifestData, '\n'),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	buffer := bytes.NewBuffer(nil)
+	if err := storagearchive.Tar(ctx, readBucket, buffer); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// unmarshalBundle returns the image data and the manifest within the bundle.
+func unmarshalBundle(ctx context.Context, data []byte) ([]byte, *bundleManifest, error) {
+	readBucketBuilder := storagemem.NewReadBucketBuilder()
+	if err := storagearchive.Untar(ctx, bytes.NewReader(data), readBucketBuilder, nil, 0); err != nil {
+		return nil, nil, fmt.Errorf("could not untar bundle: %v", err)
+	}
+	readBucket, err := readBucketBuilder.ToReadBucket()
+	if err != nil {
+		return nil, nil, err
+	}
+	imageData, err := storage.ReadPath(ctx, readBucket, bundleImagePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not read %s from bundle: %v", bundleImagePath, err)
+	}
+	manifestData, err := storage.ReadPath(ctx, readBucket, bundleManifestPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not read %s from bundle: %v", bundleManifestPath, err)
+	}
+	manifest := &bundleManifest{}
+	if err := json.Unmarshal(manifestData, manifest); err != nil {
+		return nil, nil, fmt.Errorf("could not unmarshal bundle manifest: %v", err)
+	}
+	return imageData, manifest, nil
+}
+
+// imageWithBundleManifest returns the image with the files marked as imports
+// as listed in the manifest.
+//
+// This is needed as the image within the bundle may be a FileDescriptorSet,
+// which does not record which files are imports.
+func imageWithBundleManifest(image bufcore.Image, manifest *bundleManifest) (bufcore.Image, error) {
+	importPaths := make(map[string]struct{}, len(manifest.Imports))
+	for _, importPath := range manifest.Imports {
+		importPaths[importPath] = struct{}{}
+	}
+	imageFiles := make([]bufcore.ImageFile, len(image.Files()))
+	for i, imageFile := range image.Files() {
+		_, isImport := importPaths[imageFile.Path()]
+		if isImport == imageFile.IsImport() {
+			imageFiles[i] = imageFile
+			continue
+		}
+		var err error
+		imageFiles[i], err = bufcore.NewImageFile(imageFile.Proto(), imageFile.ExternalPath(), isImport)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return bufcore.NewImage(imageFiles)
+}
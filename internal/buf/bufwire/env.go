@@ -22,12 +22,15 @@ import (
 type env struct {
 	image  bufcore.Image
 	config *bufconfig.Config
+	// pathToFileContent is nil unless the file content was requested
+	pathToFileContent map[string][]byte
 }
 
-func newEnv(image bufcore.Image, config *bufconfig.Config) *env {
+func newEnv(image bufcore.Image, config *bufconfig.Config, pathToFileContent map[string][]byte) *env {
 	return &env{
-		image:  image,
-		config: config,
+		image:             image,
+		config:            config,
+		pathToFileContent: pathToFileContent,
 	}
 }
 
@@ -38,3 +41,7 @@ func (e *env) Image() bufcore.Image {
 func (e *env) Config() *bufconfig.Config {
 	return e.config
 }
+
+func (e *env) FileContent(path string) []byte {
+	return e.pathToFileContent[path]
+}
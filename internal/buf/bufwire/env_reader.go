@@ -84,6 +84,7 @@ func (e *envReader) GetEnv(
 	externalFilePaths []string,
 	externalFilePathsAllowNotExist bool,
 	excludeSourceCodeInfo bool,
+	options ...GetEnvOption,
 ) (_ Env, _ []bufanalysis.FileAnnotation, retErr error) {
 	defer instrument.Start(e.logger, "get_env").End()
 	defer func() {
@@ -117,6 +118,7 @@ func (e *envReader) GetEnv(
 			externalFilePathsAllowNotExist,
 			excludeSourceCodeInfo,
 			t,
+			options...,
 		)
 	default:
 		return nil, nil, fmt.Errorf("invalid ref: %T", ref)
@@ -162,6 +164,7 @@ func (e *envReader) GetSourceEnv(
 	externalFilePaths []string,
 	externalFilePathsAllowNotExist bool,
 	excludeSourceCodeInfo bool,
+	options ...GetEnvOption,
 ) (_ Env, _ []bufanalysis.FileAnnotation, retErr error) {
 	defer instrument.Start(e.logger, "get_source_env").End()
 	defer func() {
@@ -182,6 +185,7 @@ func (e *envReader) GetSourceEnv(
 		externalFilePathsAllowNotExist,
 		excludeSourceCodeInfo,
 		sourceRef,
+		options...,
 	)
 }
 
@@ -247,7 +251,7 @@ func (e *envReader) GetConfig(
 	configOverride string,
 ) (*bufconfig.Config, error) {
 	if configOverride != "" {
-		return e.parseConfigOverride(configOverride)
+		return e.parseConfigOverride(ctx, configOverride)
 	}
 	// if there is no config override, we read the config from the current directory
 	data, err := ioutil.ReadFile(bufconfig.ConfigFilePath)
@@ -259,7 +263,7 @@ func (e *envReader) GetConfig(
 		data = nil
 	}
 	// if there was no file, this just returns default config
-	return e.configProvider.GetConfigForData(data)
+	return e.configProvider.GetConfigForData(ctx, data)
 }
 
 func (e *envReader) getEnvFromImage(
@@ -286,7 +290,7 @@ func (e *envReader) getEnvFromImage(
 	if err != nil {
 		return nil, err
 	}
-	return newEnv(image, config), nil
+	return newEnv(image, config, nil), nil
 }
 
 func (e *envReader) getEnvFromSource(
@@ -297,7 +301,12 @@ func (e *envReader) getEnvFromSource(
 	externalFilePathsAllowNotExist bool,
 	excludeSourceCodeInfo bool,
 	sourceRef buffetch.SourceRef,
+	options ...GetEnvOption,
 ) (_ Env, _ []bufanalysis.FileAnnotation, retErr error) {
+	getEnvOptions := &getEnvOptions{}
+	for _, option := range options {
+		option(getEnvOptions)
+	}
 	readBucketCloser, config, err := e.getSourceBucketAndConfig(ctx, container, sourceRef, configOverride)
 	if err != nil {
 		return nil, nil, err
@@ -336,14 +345,14 @@ func (e *envReader) getEnvFromSource(
 	if err != nil {
 		return nil, nil, err
 	}
-	var options []bufbuild.BuildOption
+	var bufbuildOptions []bufbuild.BuildOption
 	if excludeSourceCodeInfo {
-		options = append(options, bufbuild.WithExcludeSourceCodeInfo())
+		bufbuildOptions = append(bufbuildOptions, bufbuild.WithExcludeSourceCodeInfo())
 	}
 	image, fileAnnotations, err := e.buildBuilder.Build(
 		ctx,
 		module,
-		options...,
+		bufbuildOptions...,
 	)
 	if err != nil {
 		return nil, nil, err
@@ -351,7 +360,41 @@ func (e *envReader) getEnvFromSource(
 	if len(fileAnnotations) > 0 {
 		return nil, fileAnnotations, nil
 	}
-	return newEnv(image, config), nil, nil
+	var pathToFileContent map[string][]byte
+	if getEnvOptions.requiresFileContent != nil && getEnvOptions.requiresFileContent(config) {
+		// the content is read before the bucket is closed
+		pathToFileContent, err = getPathToFileContent(ctx, module, image)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return newEnv(image, config, pathToFileContent), nil, nil
+}
+
+func getPathToFileContent(ctx context.Context, module bufcore.Module, image bufcore.Image) (map[string][]byte, error) {
+	pathToFileContent := make(map[string][]byte)
+	for _, imageFile := range image.Files() {
+		if imageFile.IsImport() {
+			continue
+		}
+		content, err := readModuleFile(ctx, module, imageFile.Path())
+		if err != nil {
+			return nil, err
+		}
+		pathToFileContent[imageFile.Path()] = content
+	}
+	return pathToFileContent, nil
+}
+
+func readModuleFile(ctx context.Context, module bufcore.Module, path string) (_ []byte, retErr error) {
+	moduleFile, err := module.GetFile(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		retErr = multierr.Append(retErr, moduleFile.Close())
+	}()
+	return ioutil.ReadAll(moduleFile)
 }
 
 func (e *envReader) getSourceBucketAndConfig(
@@ -371,11 +414,15 @@ func (e *envReader) getSourceBucketAndConfig(
 	}()
 	var config *bufconfig.Config
 	if configOverride != "" {
-		config, err = e.parseConfigOverride(configOverride)
+		config, err = e.parseConfigOverride(ctx, configOverride)
 	} else {
 		// if there is no config override, we read the config from the bucket
 		// if there was no file, this just returns default config
-		config, err = e.configProvider.GetConfig(ctx, readBucketCloser)
+		var getConfigOptions []bufconfig.GetConfigOption
+		if sourceRef.IsDir() {
+			getConfigOptions = append(getConfigOptions, bufconfig.GetConfigWithLocalDir())
+		}
+		config, err = e.configProvider.GetConfig(ctx, readBucketCloser, getConfigOptions...)
 	}
 	if err != nil {
 		return nil, nil, err
@@ -383,7 +430,7 @@ func (e *envReader) getSourceBucketAndConfig(
 	return readBucketCloser, config, nil
 }
 
-func (e *envReader) parseConfigOverride(value string) (*bufconfig.Config, error) {
+func (e *envReader) parseConfigOverride(ctx context.Context, value string) (*bufconfig.Config, error) {
 	value = strings.TrimSpace(value)
 	if value == "" {
 		return nil, errors.New("config override value is empty")
@@ -399,7 +446,7 @@ func (e *envReader) parseConfigOverride(value string) (*bufconfig.Config, error)
 	default:
 		data = []byte(value)
 	}
-	config, err := e.configProvider.GetConfigForData(data)
+	config, err := e.configProvider.GetConfigForData(ctx, data)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %v", e.configOverrideFlagName, err)
 	}
@@ -97,8 +97,17 @@ func (i *imageReader) getImageForImageRef(
 	if err != nil {
 		return nil, err
 	}
+	imageEncoding := imageRef.ImageEncoding()
+	var manifest *bundleManifest
+	if imageEncoding == buffetch.ImageEncodingBundle {
+		data, manifest, err = unmarshalBundle(ctx, data)
+		if err != nil {
+			return nil, err
+		}
+		imageEncoding = buffetch.ImageEncodingBin
+	}
 	protoImage := &imagev1.Image{}
-	switch imageEncoding := imageRef.ImageEncoding(); imageEncoding {
+	switch imageEncoding {
 	// we have to double parse due to custom options
 	// See https://github.com/golang/protobuf/issues/1123
 	// TODO: revisit
@@ -158,6 +167,12 @@ func (i *imageReader) getImageForImageRef(
 	if err != nil {
 		return nil, err
 	}
+	if manifest != nil {
+		image, err = imageWithBundleManifest(image, manifest)
+		if err != nil {
+			return nil, err
+		}
+	}
 	if len(externalFilePaths) == 0 {
 		return image, nil
 	}
@@ -17,6 +17,7 @@ package bufwire
 import (
 	"context"
 	"fmt"
+	"os"
 
 	"github.com/bufbuild/buf/internal/buf/bufcore"
 	"github.com/bufbuild/buf/internal/buf/buffetch"
@@ -53,9 +54,15 @@ func (i *imageWriter) PutImage(
 	image bufcore.Image,
 	asFileDescriptorSet bool,
 	excludeImports bool,
+	options ...PutImageOption,
 ) (retErr error) {
 	defer instrument.Start(i.logger, "put_image").End()
 
+	putImageOptions := newPutImageOptions()
+	for _, option := range options {
+		option(putImageOptions)
+	}
+
 	imageRef, err := i.fetchImageRefParser.GetImageRef(ctx, value)
 	if err != nil {
 		return err
@@ -65,7 +72,8 @@ func (i *imageWriter) PutImage(
 		return nil
 	}
 	writeImage := image
-	if excludeImports {
+	// bundles are self-contained, so they always include imports
+	if excludeImports && imageRef.ImageEncoding() != buffetch.ImageEncodingBundle {
 		writeImage = bufcore.ImageWithoutImports(image)
 	}
 	var message proto.Message
@@ -74,11 +82,15 @@ func (i *imageWriter) PutImage(
 	} else {
 		message = bufcore.ImageToProtoImage(writeImage)
 	}
-	data, err := i.imageMarshal(message, image, imageRef.ImageEncoding())
+	data, err := i.imageMarshal(ctx, message, image, imageRef.ImageEncoding())
 	if err != nil {
 		return err
 	}
-	writeCloser, err := i.fetchWriter.PutImageFile(ctx, container, imageRef)
+	var putImageFileOptions []buffetch.PutImageFileOption
+	if putImageOptions.fileMode != nil {
+		putImageFileOptions = append(putImageFileOptions, buffetch.WithPutImageFileMode(*putImageOptions.fileMode))
+	}
+	writeCloser, err := i.fetchWriter.PutImageFile(ctx, container, imageRef, putImageFileOptions...)
 	if err != nil {
 		return err
 	}
@@ -90,6 +102,7 @@ func (i *imageWriter) PutImage(
 }
 
 func (i *imageWriter) imageMarshal(
+	ctx context.Context,
 	message proto.Message,
 	image bufcore.Image,
 	imageEncoding buffetch.ImageEncoding,
@@ -109,7 +122,22 @@ func (i *imageWriter) imageMarshal(
 			return nil, err
 		}
 		return protoencoding.NewJSONMarshaler(resolver).Marshal(message)
+	case buffetch.ImageEncodingBundle:
+		data, err := protoencoding.NewWireMarshaler().Marshal(message)
+		if err != nil {
+			return nil, err
+		}
+		return marshalBundle(ctx, data, image)
 	default:
 		return nil, fmt.Errorf("unknown image encoding: %v", imageEncoding)
 	}
 }
+
+type putImageOptions struct {
+	// nil to use the default
+	fileMode *os.FileMode
+}
+
+func newPutImageOptions() *putImageOptions {
+	return &putImageOptions{}
+}
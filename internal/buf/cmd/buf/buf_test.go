@@ -15,19 +15,26 @@
 package buf
 
 import (
+	"archive/tar"
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/bufbuild/buf/internal/buf/cmd/buf/internal/protoc"
 	"github.com/bufbuild/buf/internal/pkg/app"
 	"github.com/bufbuild/buf/internal/pkg/app/appcmd"
 	"github.com/bufbuild/buf/internal/pkg/app/appcmd/appcmdtesting"
+	"github.com/bufbuild/buf/internal/pkg/app/appflag"
+	"github.com/bufbuild/buf/internal/pkg/protoencoding"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/descriptorpb"
 )
 
 func TestSuccess1(t *testing.T) {
@@ -194,6 +201,167 @@ func TestFail9(t *testing.T) {
 	)
 }
 
+func TestFailGroupByRule(t *testing.T) {
+	t.Parallel()
+	stdout := bytes.NewBuffer(nil)
+	testRun(
+		t,
+		1,
+		nil,
+		stdout,
+		"check",
+		"lint",
+		"--input",
+		filepath.Join("testdata", "fail"),
+		"--group-by",
+		"rule",
+	)
+	assert.Equal(
+		t,
+		`FIELD_LOWER_SNAKE_CASE
+  testdata/fail/buf/buf.proto:6:9:Field name "oneTwo" should be lower_snake_case, such as "one_two".
+PACKAGE_DIRECTORY_MATCH
+  testdata/fail/buf/buf.proto:3:1:Files with package "other" must be within a directory "other" relative to root but were in directory "buf".
+`,
+		stdout.String(),
+	)
+}
+
+func TestFailGroupByRuleJSON(t *testing.T) {
+	t.Parallel()
+	testRunStdout(
+		t,
+		1,
+		`{"path":"testdata/fail/buf/buf.proto","start_line":3,"start_column":1,"end_line":3,"end_column":15,"type":"PACKAGE_DIRECTORY_MATCH","message":"Files with package \"other\" must be within a directory \"other\" relative to root but were in directory \"buf\"."}
+		{"path":"testdata/fail/buf/buf.proto","start_line":6,"start_column":9,"end_line":6,"end_column":15,"type":"FIELD_LOWER_SNAKE_CASE","message":"Field name \"oneTwo\" should be lower_snake_case, such as \"one_two\"."}`,
+		"check",
+		"lint",
+		"--input",
+		filepath.Join("testdata", "fail"),
+		"--group-by",
+		"rule",
+		"--error-format",
+		"json",
+	)
+}
+
+func TestFailGroupByUnknown(t *testing.T) {
+	t.Parallel()
+	testRunStdout(
+		t,
+		1,
+		``,
+		"check",
+		"lint",
+		"--input",
+		filepath.Join("testdata", "fail"),
+		"--group-by",
+		"type",
+	)
+}
+
+func TestFailSeverity1(t *testing.T) {
+	t.Parallel()
+	testRunStdout(
+		t,
+		1,
+		`testdata/fail/buf/buf.proto:3:1:warning: Files with package "other" must be within a directory "other" relative to root but were in directory "buf".
+        testdata/fail/buf/buf.proto:6:9:Field name "oneTwo" should be lower_snake_case, such as "one_two".`,
+		"check",
+		"lint",
+		"--input",
+		filepath.Join("testdata", "fail"),
+		"--input-config",
+		`{"lint":{"use":["BASIC"],"error":["FIELD_LOWER_SNAKE_CASE"]}}`,
+	)
+}
+
+func TestFailSeverity2(t *testing.T) {
+	t.Parallel()
+	testRunStdout(
+		t,
+		0,
+		`testdata/fail/buf/buf.proto:3:1:warning: Files with package "other" must be within a directory "other" relative to root but were in directory "buf".
+        testdata/fail/buf/buf.proto:6:9:warning: Field name "oneTwo" should be lower_snake_case, such as "one_two".`,
+		"check",
+		"lint",
+		"--input",
+		filepath.Join("testdata", "fail"),
+		"--input-config",
+		`{"lint":{"use":["BASIC"],"error":["ENUM_PASCAL_CASE"]}}`,
+	)
+}
+
+func TestFailSeverity3(t *testing.T) {
+	t.Parallel()
+	testRunStdout(
+		t,
+		1,
+		`{"path":"testdata/fail/buf/buf.proto","start_line":3,"start_column":1,"end_line":3,"end_column":15,"type":"PACKAGE_DIRECTORY_MATCH","message":"Files with package \"other\" must be within a directory \"other\" relative to root but were in directory \"buf\".","severity":"warning"}
+        {"path":"testdata/fail/buf/buf.proto","start_line":6,"start_column":9,"end_line":6,"end_column":15,"type":"FIELD_LOWER_SNAKE_CASE","message":"Field name \"oneTwo\" should be lower_snake_case, such as \"one_two\"."}`,
+		"check",
+		"lint",
+		"--input",
+		filepath.Join("testdata", "fail"),
+		"--input-config",
+		`{"lint":{"use":["BASIC"],"error":["FIELD_LOWER_SNAKE_CASE"]}}`,
+		"--error-format",
+		"json",
+	)
+}
+
+func TestFailSeverityInfo1(t *testing.T) {
+	t.Parallel()
+	testRunStdout(
+		t,
+		0,
+		`testdata/fail/buf/buf.proto:3:1:info: Files with package "other" must be within a directory "other" relative to root but were in directory "buf".
+        testdata/fail/buf/buf.proto:6:9:info: Field name "oneTwo" should be lower_snake_case, such as "one_two".`,
+		"check",
+		"lint",
+		"--input",
+		filepath.Join("testdata", "fail"),
+		"--input-config",
+		`{"lint":{"use":["BASIC"],"info":["FIELD_LOWER_SNAKE_CASE","PACKAGE_DIRECTORY_MATCH"]}}`,
+	)
+}
+
+func TestFailSeverityInfo2(t *testing.T) {
+	t.Parallel()
+	testRunStdout(
+		t,
+		1,
+		`{"path":"testdata/fail/buf/buf.proto","start_line":3,"start_column":1,"end_line":3,"end_column":15,"type":"PACKAGE_DIRECTORY_MATCH","message":"Files with package \"other\" must be within a directory \"other\" relative to root but were in directory \"buf\"."}
+        {"path":"testdata/fail/buf/buf.proto","start_line":6,"start_column":9,"end_line":6,"end_column":15,"type":"FIELD_LOWER_SNAKE_CASE","message":"Field name \"oneTwo\" should be lower_snake_case, such as \"one_two\".","severity":"info"}`,
+		"check",
+		"lint",
+		"--input",
+		filepath.Join("testdata", "fail"),
+		"--input-config",
+		`{"lint":{"use":["BASIC"],"info":["FIELD_LOWER_SNAKE_CASE"]}}`,
+		"--error-format",
+		"json",
+	)
+}
+
+func TestFailSeverityInfo3(t *testing.T) {
+	t.Parallel()
+	testRunStdout(
+		t,
+		0,
+		`{"path":"testdata/fail/buf/buf.proto","start_line":3,"start_column":1,"end_line":3,"end_column":15,"type":"PACKAGE_DIRECTORY_MATCH","message":"Files with package \"other\" must be within a directory \"other\" relative to root but were in directory \"buf\".","severity":"warning"}
+        {"path":"testdata/fail/buf/buf.proto","start_line":6,"start_column":9,"end_line":6,"end_column":15,"type":"FIELD_LOWER_SNAKE_CASE","message":"Field name \"oneTwo\" should be lower_snake_case, such as \"one_two\".","severity":"info"}`,
+		"check",
+		"lint",
+		"--input",
+		filepath.Join("testdata", "fail"),
+		"--input-config",
+		`{"lint":{"use":["BASIC"],"error":["ENUM_PASCAL_CASE"],"info":["FIELD_LOWER_SNAKE_CASE"]}}`,
+		"--error-format",
+		"json",
+	)
+}
+
 func TestFail10(t *testing.T) {
 	t.Parallel()
 	testRunStdout(
@@ -244,6 +412,305 @@ func TestFail12(t *testing.T) {
 	)
 }
 
+func TestFailStrict(t *testing.T) {
+	t.Parallel()
+	testRunStdout(
+		t,
+		1,
+		`testdata/success/buf/buf.proto:3:1:Package name "buf" should be suffixed with a correctly formed version, such as "buf.v1".
+testdata/success/buf/buf.proto:7:1:Message "Foo" should have a non-empty comment for documentation.
+testdata/success/buf/buf.proto:8:3:Field "one" should have a non-empty comment for documentation.
+testdata/success/buf/buf.proto:8:3:Field "one" should either use the optional label or have a non-empty comment documenting the meaning of its zero value.
+testdata/success/buf/buf.proto:9:3:Field "two" should have a non-empty comment for documentation.`,
+		"check",
+		"lint",
+		"--input",
+		filepath.Join("testdata", "success"),
+		"--strict",
+	)
+}
+
+func TestFailMaxAnnotations(t *testing.T) {
+	t.Parallel()
+	testRunStdout(
+		t,
+		1,
+		`testdata/success/buf/buf.proto:3:1:Package name "buf" should be suffixed with a correctly formed version, such as "buf.v1".
+testdata/success/buf/buf.proto:7:1:Message "Foo" should have a non-empty comment for documentation.
+... and 3 more`,
+		"check",
+		"lint",
+		"--input",
+		filepath.Join("testdata", "success"),
+		"--strict",
+		"--max-annotations",
+		"2",
+	)
+}
+
+func TestErrorFormatSink(t *testing.T) {
+	t.Parallel()
+	tempDirPath, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, os.RemoveAll(tempDirPath)) }()
+	jsonFilePath := filepath.Join(tempDirPath, "lint.json")
+	textFilePath := filepath.Join(tempDirPath, "lint.txt")
+	testRunStdout(
+		t,
+		1,
+		`testdata/success/buf/buf.proto:3:1:Package name "buf" should be suffixed with a correctly formed version, such as "buf.v1".
+... and 4 more`,
+		"check",
+		"lint",
+		"--input",
+		filepath.Join("testdata", "success"),
+		"--strict",
+		"--max-annotations",
+		"1",
+		"--error-format-sink",
+		"json:"+jsonFilePath,
+		"--error-format-sink",
+		"text:"+textFilePath,
+	)
+	data, err := ioutil.ReadFile(jsonFilePath)
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		`{"path":"testdata/success/buf/buf.proto","start_line":3,"start_column":1,"end_line":3,"end_column":13,"type":"PACKAGE_VERSION_SUFFIX","message":"Package name \"buf\" should be suffixed with a correctly formed version, such as \"buf.v1\"."}
+{"path":"testdata/success/buf/buf.proto","start_line":7,"start_column":1,"end_line":10,"end_column":2,"type":"COMMENT_MESSAGE","message":"Message \"Foo\" should have a non-empty comment for documentation."}
+{"path":"testdata/success/buf/buf.proto","start_line":8,"start_column":3,"end_line":8,"end_column":17,"type":"COMMENT_FIELD","message":"Field \"one\" should have a non-empty comment for documentation."}
+{"path":"testdata/success/buf/buf.proto","start_line":8,"start_column":3,"end_line":8,"end_column":17,"type":"FIELD_PRESENCE_COMMENT","message":"Field \"one\" should either use the optional label or have a non-empty comment documenting the meaning of its zero value."}
+{"path":"testdata/success/buf/buf.proto","start_line":9,"start_column":3,"end_line":9,"end_column":43,"type":"COMMENT_FIELD","message":"Field \"two\" should have a non-empty comment for documentation."}
+`,
+		string(data),
+	)
+	data, err = ioutil.ReadFile(textFilePath)
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		`testdata/success/buf/buf.proto:3:1:Package name "buf" should be suffixed with a correctly formed version, such as "buf.v1".
+testdata/success/buf/buf.proto:7:1:Message "Foo" should have a non-empty comment for documentation.
+testdata/success/buf/buf.proto:8:3:Field "one" should have a non-empty comment for documentation.
+testdata/success/buf/buf.proto:8:3:Field "one" should either use the optional label or have a non-empty comment documenting the meaning of its zero value.
+testdata/success/buf/buf.proto:9:3:Field "two" should have a non-empty comment for documentation.
+`,
+		string(data),
+	)
+
+	// the file is written even if there are no violations
+	testRunStdout(
+		t,
+		0,
+		``,
+		"check",
+		"lint",
+		"--input",
+		filepath.Join("testdata", "success"),
+		"--error-format-sink",
+		"json:"+jsonFilePath,
+	)
+	data, err = ioutil.ReadFile(jsonFilePath)
+	require.NoError(t, err)
+	assert.Empty(t, data)
+
+	testRunStdout(
+		t,
+		1,
+		``,
+		"check",
+		"lint",
+		"--input",
+		filepath.Join("testdata", "success"),
+		"--error-format-sink",
+		"foo:"+jsonFilePath,
+	)
+	testRunStdout(
+		t,
+		1,
+		``,
+		"check",
+		"lint",
+		"--input",
+		filepath.Join("testdata", "success"),
+		"--error-format-sink",
+		jsonFilePath,
+	)
+}
+
+func TestCheckLintWatchTimeout(t *testing.T) {
+	t.Parallel()
+	// the exit code is that of the last run once timed out
+	testRunStdout(
+		t,
+		1,
+		`testdata/fail/buf/buf.proto:3:1:Files with package "other" must be within a directory "other" relative to root but were in directory "buf".
+        testdata/fail/buf/buf.proto:6:9:Field name "oneTwo" should be lower_snake_case, such as "one_two".`,
+		"check",
+		"lint",
+		"--input",
+		filepath.Join("testdata", "fail"),
+		"--watch",
+		"--timeout",
+		"500ms",
+	)
+	// the context error is returned if the last run succeeded
+	testRunStdout(
+		t,
+		1,
+		``,
+		"check",
+		"lint",
+		"--input",
+		filepath.Join("testdata", "success"),
+		"--watch",
+		"--timeout",
+		"500ms",
+	)
+}
+
+func TestCheckLintWatchRequiresDirectory(t *testing.T) {
+	t.Parallel()
+	testRunStdout(
+		t,
+		1,
+		``,
+		"check",
+		"lint",
+		"--input",
+		filepath.Join("testdata", "success", "buf", "buf.proto"),
+		"--watch",
+	)
+	testRunStdout(
+		t,
+		1,
+		``,
+		"check",
+		"lint",
+		"--input",
+		filepath.Join("testdata", "success"),
+		"--diff-only",
+		"-",
+		"--watch",
+	)
+}
+
+func TestLogFile(t *testing.T) {
+	t.Parallel()
+	tempDirPath, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, os.RemoveAll(tempDirPath)) }()
+	logFilePath := filepath.Join(tempDirPath, "buf.log")
+	stderr := bytes.NewBuffer(nil)
+	err = appcmd.Run(
+		context.Background(),
+		app.NewContainer(
+			nil,
+			nil,
+			bytes.NewBuffer(nil),
+			stderr,
+			"test",
+			"image",
+			"build",
+			"-o",
+			app.DevNullFilePath,
+			"--source",
+			filepath.Join("testdata", "success"),
+			"--log-level",
+			"debug",
+			"--log-format",
+			"json",
+			"--log-file",
+			logFilePath,
+		),
+		newRootCommand("test"),
+	)
+	require.NoError(t, err)
+	assert.Empty(t, stderr.String())
+	data, err := ioutil.ReadFile(logFilePath)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"message":"start"`)
+	assert.Contains(t, string(data), `"message":"end"`)
+}
+
+func TestLogFileDefaultFormat(t *testing.T) {
+	t.Parallel()
+	tempDirPath, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, os.RemoveAll(tempDirPath)) }()
+	logFilePath := filepath.Join(tempDirPath, "buf.log")
+	err = appcmd.Run(
+		context.Background(),
+		app.NewContainer(
+			nil,
+			nil,
+			bytes.NewBuffer(nil),
+			bytes.NewBuffer(nil),
+			"test",
+			"image",
+			"build",
+			"-o",
+			app.DevNullFilePath,
+			"--source",
+			filepath.Join("testdata", "success"),
+			"--log-level",
+			"debug",
+			"--log-file",
+			logFilePath,
+		),
+		newRootCommand("test"),
+	)
+	require.NoError(t, err)
+	data, err := ioutil.ReadFile(logFilePath)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "start")
+	// the log file defaults to the text format, which has no color escape sequences
+	assert.NotContains(t, string(data), "\x1b[")
+}
+
+func TestFailDiffOnly1(t *testing.T) {
+	t.Parallel()
+	stdout := bytes.NewBuffer(nil)
+	testRun(
+		t,
+		1,
+		bytes.NewBufferString(filepath.Join("testdata", "fail", "buf", "buf.proto")+"\n"),
+		stdout,
+		"check",
+		"lint",
+		"--input",
+		filepath.Join("testdata", "fail"),
+		"--diff-only",
+		"-",
+	)
+	assert.Equal(
+		t,
+		`testdata/fail/buf/buf.proto:3:1:Files with package "other" must be within a directory "other" relative to root but were in directory "buf".
+testdata/fail/buf/buf.proto:6:9:Field name "oneTwo" should be lower_snake_case, such as "one_two".
+`,
+		stdout.String(),
+	)
+}
+
+func TestFailDiffOnly2(t *testing.T) {
+	t.Parallel()
+	tempDirPath, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, os.RemoveAll(tempDirPath)) }()
+	diffOnlyFilePath := filepath.Join(tempDirPath, "changed.txt")
+	require.NoError(t, ioutil.WriteFile(diffOnlyFilePath, []byte("buf/other.proto\n"), 0600))
+	testRunStdout(
+		t,
+		0,
+		``,
+		"check",
+		"lint",
+		"--input",
+		filepath.Join("testdata", "fail"),
+		"--diff-only",
+		diffOnlyFilePath,
+	)
+}
+
 func TestFailCheckBreaking1(t *testing.T) {
 	t.Parallel()
 	testRunStdout(
@@ -476,6 +943,288 @@ func TestImageConvertRoundtripJSONBinaryJSON(t *testing.T) {
 	require.Equal(t, json1, stdout.Bytes())
 }
 
+func TestImageBuildBundle(t *testing.T) {
+	t.Parallel()
+
+	stdout := bytes.NewBuffer(nil)
+	testRun(
+		t,
+		0,
+		nil,
+		stdout,
+		"image",
+		"build",
+		"-o",
+		"-#format=bundle",
+		"--as-file-descriptor-set",
+		// bundles always include imports
+		"--exclude-imports",
+		"--source",
+		filepath.Join("testdata", "success"),
+	)
+	bundle := stdout.Bytes()
+	pathToData := testUntar(t, bundle)
+	require.Len(t, pathToData, 2)
+	assert.JSONEq(
+		t,
+		`{"roots":["buf/buf.proto"],"imports":["google/protobuf/descriptor.proto"]}`,
+		string(pathToData["manifest.json"]),
+	)
+	assert.Equal(
+		t,
+		[]string{"google/protobuf/descriptor.proto", "buf/buf.proto"},
+		testFileDescriptorSetFileNames(t, pathToData["image.bin"]),
+	)
+
+	// the manifest marks the imports of the FileDescriptorSet when the bundle is read
+	stdout = bytes.NewBuffer(nil)
+	testRun(
+		t,
+		0,
+		bytes.NewReader(bundle),
+		stdout,
+		"experimental",
+		"image",
+		"convert",
+		"-i",
+		"-#format=bundle",
+		"-o",
+		"-",
+		"--exclude-imports",
+	)
+	assert.Equal(t, []string{"buf/buf.proto"}, testFileDescriptorSetFileNames(t, stdout.Bytes()))
+}
+
+func TestCheckLintInputFormatBin(t *testing.T) {
+	t.Parallel()
+	testCheckLintInputFormat(t, "bin")
+}
+
+func TestCheckLintInputFormatJSON(t *testing.T) {
+	t.Parallel()
+	testCheckLintInputFormat(t, "json")
+}
+
+func TestCheckLintInputFormatUnknown(t *testing.T) {
+	t.Parallel()
+	testRun(
+		t,
+		1,
+		nil,
+		nil,
+		"check",
+		"lint",
+		"--input",
+		"-",
+		"--input-format",
+		"yaml",
+	)
+}
+
+func TestCheckLintFileHeaderPath(t *testing.T) {
+	t.Parallel()
+	tempDirPath, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, os.RemoveAll(tempDirPath)) }()
+	// the file_header_path is relative to the root of the input, not the current directory
+	files := []struct {
+		name string
+		data []byte
+	}{
+		{name: "buf.yaml", data: []byte("lint:\n  use:\n    - FILE_HEADER\n  file_header_path: header.txt\n")},
+		{name: "header.txt", data: []byte("Copyright Acme, Inc.\n")},
+		{name: "a.proto", data: []byte("// Copyright Acme, Inc.\n\nsyntax = \"proto3\";\n\npackage a;\n")},
+		{name: "b.proto", data: []byte("syntax = \"proto3\";\n\npackage a;\n")},
+	}
+	tarBuffer := bytes.NewBuffer(nil)
+	tarWriter := tar.NewWriter(tarBuffer)
+	for _, file := range files {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(tempDirPath, file.name), file.data, 0600))
+		require.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: file.name, Mode: 0600, Size: int64(len(file.data))}))
+		_, err := tarWriter.Write(file.data)
+		require.NoError(t, err)
+	}
+	require.NoError(t, tarWriter.Close())
+
+	testRunStdout(
+		t,
+		1,
+		filepath.Join(tempDirPath, "b.proto")+`:1:1:Files must begin with a header comment.`,
+		"check",
+		"lint",
+		"--input",
+		tempDirPath,
+	)
+	stdout := bytes.NewBuffer(nil)
+	testRun(
+		t,
+		1,
+		tarBuffer,
+		stdout,
+		"check",
+		"lint",
+		"--input",
+		"-#format=tar",
+	)
+	assert.Equal(
+		t,
+		`b.proto:1:1:Files must begin with a header comment.`,
+		strings.TrimSpace(stdout.String()),
+	)
+}
+
+func TestCheckLintFileNoCRLF(t *testing.T) {
+	t.Parallel()
+	tempDirPath, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, os.RemoveAll(tempDirPath)) }()
+	configData := []byte("lint:\n  use:\n    - FILE_NO_CRLF\n")
+	protoData := []byte("syntax = \"proto3\";\r\n\r\npackage a;\r\n")
+	require.NoError(t, ioutil.WriteFile(filepath.Join(tempDirPath, "buf.yaml"), configData, 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(tempDirPath, "a.proto"), protoData, 0600))
+
+	testRunStdout(
+		t,
+		1,
+		filepath.Join(tempDirPath, "a.proto")+`:1:1:Files should use LF line endings but "a.proto" contains CRLF line endings.`,
+		"check",
+		"lint",
+		"--input",
+		tempDirPath,
+	)
+
+	// the content is also available for sources that are not directories
+	tarBuffer := bytes.NewBuffer(nil)
+	tarWriter := tar.NewWriter(tarBuffer)
+	for _, file := range []struct {
+		name string
+		data []byte
+	}{
+		{name: "buf.yaml", data: configData},
+		{name: "a.proto", data: protoData},
+	} {
+		require.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: file.name, Mode: 0600, Size: int64(len(file.data))}))
+		_, err := tarWriter.Write(file.data)
+		require.NoError(t, err)
+	}
+	require.NoError(t, tarWriter.Close())
+	stdout := bytes.NewBuffer(nil)
+	testRun(
+		t,
+		1,
+		tarBuffer,
+		stdout,
+		"check",
+		"lint",
+		"--input",
+		"-#format=tar",
+	)
+	assert.Equal(
+		t,
+		`a.proto:1:1:Files should use LF line endings but "a.proto" contains CRLF line endings.`,
+		strings.TrimSpace(stdout.String()),
+	)
+
+	// images do not have the raw content, which is an error instead of
+	// silently not checking the files
+	imagePath := testBuildImage(t, tempDirPath, tempDirPath, "image.bin")
+	stdout = bytes.NewBuffer(nil)
+	testRun(
+		t,
+		1,
+		nil,
+		stdout,
+		"check",
+		"lint",
+		"--input",
+		imagePath,
+		"--input-config",
+		`{"lint":{"use":["FILE_NO_CRLF"]}}`,
+	)
+	assert.Empty(t, stdout.String())
+}
+
+func TestImageMerge(t *testing.T) {
+	t.Parallel()
+	tmpDirPath, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, os.RemoveAll(tmpDirPath)) }()
+	aImagePath := testBuildImage(t, tmpDirPath, filepath.Join("testdata", "merge", "a"), "a.bin")
+	bImagePath := testBuildImage(t, tmpDirPath, filepath.Join("testdata", "merge", "b"), "b.bin")
+	mergedImagePath := filepath.Join(tmpDirPath, "merged.bin")
+	testRun(
+		t,
+		0,
+		nil,
+		nil,
+		"experimental",
+		"image",
+		"merge",
+		"-i",
+		aImagePath,
+		"-i",
+		bImagePath,
+		// duplicate files with the same definition are de-duplicated
+		"-i",
+		aImagePath,
+		"-o",
+		mergedImagePath,
+	)
+	testRunStdout(
+		t,
+		0,
+		`
+		a.proto
+		b.proto
+		`,
+		"ls-files",
+		"--input",
+		mergedImagePath,
+	)
+}
+
+func TestImageMergeConflict(t *testing.T) {
+	t.Parallel()
+	tmpDirPath, err := ioutil.TempDir("", "")
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, os.RemoveAll(tmpDirPath)) }()
+	aImagePath := testBuildImage(t, tmpDirPath, filepath.Join("testdata", "merge", "a"), "a.bin")
+	conflictImagePath := testBuildImage(t, tmpDirPath, filepath.Join("testdata", "merge", "conflict"), "conflict.bin")
+	testRun(
+		t,
+		1,
+		nil,
+		nil,
+		"experimental",
+		"image",
+		"merge",
+		"-i",
+		aImagePath,
+		"-i",
+		conflictImagePath,
+		"-o",
+		filepath.Join(tmpDirPath, "merged.bin"),
+	)
+}
+
+// testBuildImage builds an image for the source and returns the path to the image.
+func testBuildImage(t *testing.T, outputDirPath string, source string, name string) string {
+	imagePath := filepath.Join(outputDirPath, name)
+	testRun(
+		t,
+		0,
+		nil,
+		nil,
+		"image",
+		"build",
+		"-o",
+		imagePath,
+		"--source",
+		source,
+	)
+	return imagePath
+}
+
 func testRunStdout(t *testing.T, expectedExitCode int, expectedStdout string, args ...string) {
 	testRunStdoutInternal(
 		t,
@@ -515,6 +1264,109 @@ func testRunStdoutInternal(t *testing.T, expectedExitCode int, expectedStdout st
 	)
 }
 
+func testCheckLintInputFormat(t *testing.T, inputFormat string) {
+	stdout := bytes.NewBuffer(nil)
+	testRun(
+		t,
+		0,
+		nil,
+		stdout,
+		"image",
+		"build",
+		"-o",
+		"-#format="+inputFormat,
+		"--source",
+		filepath.Join("testdata", "fail"),
+	)
+	require.NotEmpty(t, stdout.Bytes())
+
+	stdin := stdout
+	stdout = bytes.NewBuffer(nil)
+	testRun(
+		t,
+		1,
+		stdin,
+		stdout,
+		"check",
+		"lint",
+		"--input",
+		"-",
+		"--input-format",
+		inputFormat,
+		"--input-config",
+		`{"lint":{"use":["FIELD_LOWER_SNAKE_CASE"]}}`,
+	)
+	assert.Equal(
+		t,
+		`buf/buf.proto:6:9:Field name "oneTwo" should be lower_snake_case, such as "one_two".`,
+		strings.TrimSpace(stdout.String()),
+	)
+}
+
+func testUntar(t *testing.T, data []byte) map[string][]byte {
+	pathToData := make(map[string][]byte)
+	tarReader := tar.NewReader(bytes.NewReader(data))
+	for {
+		tarHeader, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		fileData, err := ioutil.ReadAll(tarReader)
+		require.NoError(t, err)
+		pathToData[tarHeader.Name] = fileData
+	}
+	return pathToData
+}
+
+func testFileDescriptorSetFileNames(t *testing.T, data []byte) []string {
+	fileDescriptorSet := &descriptorpb.FileDescriptorSet{}
+	require.NoError(t, protoencoding.NewWireUnmarshaler(nil).Unmarshal(data, fileDescriptorSet))
+	fileNames := make([]string, 0, len(fileDescriptorSet.File))
+	for _, fileDescriptorProto := range fileDescriptorSet.File {
+		fileNames = append(fileNames, fileDescriptorProto.GetName())
+	}
+	return fileNames
+}
+
+func TestProtocCaseInsensitiveFlags(t *testing.T) {
+	t.Parallel()
+	args := []string{
+		"-I",
+		filepath.Join("testdata", "success"),
+		"--Descriptor_Set_Out",
+		app.DevNullFilePath,
+		filepath.Join("testdata", "success", "buf", "buf.proto"),
+	}
+	for _, testCase := range []struct {
+		value            string
+		expectedExitCode int
+	}{
+		{value: "", expectedExitCode: 1},
+		{value: "false", expectedExitCode: 1},
+		{value: "true", expectedExitCode: 0},
+	} {
+		testCase := testCase
+		getenv := func(key string) string {
+			if key == protoc.CaseInsensitiveFlagsEnvKey {
+				return testCase.value
+			}
+			return ""
+		}
+		appcmdtesting.RunCommandExitCode(
+			t,
+			func(use string) *appcmd.Command {
+				return protoc.NewCommand(use, appflag.NewBuilder(), newProtocCommandOptions(getenv)...)
+			},
+			testCase.expectedExitCode,
+			nil,
+			nil,
+			nil,
+			args...,
+		)
+	}
+}
+
 func testRun(
 	t *testing.T,
 	expectedExitCode int,
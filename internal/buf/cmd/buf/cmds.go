@@ -15,8 +15,11 @@
 package buf
 
 import (
+	"os"
+	"strconv"
 	"time"
 
+	"github.com/bufbuild/buf/internal/buf/cmd/buf/internal/graph"
 	"github.com/bufbuild/buf/internal/buf/cmd/buf/internal/lsfiles"
 	"github.com/bufbuild/buf/internal/buf/cmd/buf/internal/protoc"
 	"github.com/bufbuild/buf/internal/pkg/app/appcmd"
@@ -32,7 +35,7 @@ func newRootCommand(use string, options ...RootCommandOption) *appcmd.Command {
 			newImageCmd(builder),
 			newCheckCmd(builder),
 			lsfiles.NewCommand("ls-files", builder),
-			protoc.NewCommand("protoc", builder),
+			protoc.NewCommand("protoc", builder, newProtocCommandOptions(os.Getenv)...),
 			newExperimentalCmd(builder),
 		},
 		BindPersistentFlags: builder.BindRoot,
@@ -44,12 +47,27 @@ func newRootCommand(use string, options ...RootCommandOption) *appcmd.Command {
 	return rootCommand
 }
 
+// newProtocCommandOptions returns the options for the protoc command from the environment.
+//
+// Flag names are normalized while the flags are parsed, before the container of the
+// command is available, so the environment is read when the command is created.
+func newProtocCommandOptions(getenv func(string) string) []protoc.CommandOption {
+	options := []protoc.CommandOption{
+		protoc.CommandWithBufVersion(Version),
+	}
+	if caseInsensitiveFlags, _ := strconv.ParseBool(getenv(protoc.CaseInsensitiveFlagsEnvKey)); caseInsensitiveFlags {
+		options = append(options, protoc.CommandWithCaseInsensitiveFlags())
+	}
+	return options
+}
+
 func newExperimentalCmd(builder appflag.Builder) *appcmd.Command {
 	return &appcmd.Command{
 		Use:   "experimental",
 		Short: "Experimental commands. Unstable and will likely change.",
 		SubCommands: []*appcmd.Command{
 			newExperimentalImageCmd(builder),
+			graph.NewCommand("graph", builder),
 		},
 	}
 }
@@ -70,6 +88,7 @@ func newExperimentalImageCmd(builder appflag.Builder) *appcmd.Command {
 		Short: "Work with Images and FileDescriptorSets.",
 		SubCommands: []*appcmd.Command{
 			newImageConvertCmd(builder),
+			newImageMergeCmd(builder),
 		},
 	}
 }
@@ -113,6 +132,23 @@ func newImageConvertCmd(builder appflag.Builder) *appcmd.Command {
 	}
 }
 
+func newImageMergeCmd(builder appflag.Builder) *appcmd.Command {
+	flags := newFlags()
+	return &appcmd.Command{
+		Use:   "merge",
+		Short: "Merge the input Images into a single output Image, de-duplicating files by path.",
+		Args:  cobra.NoArgs,
+		Run:   newRunFunc(builder, flags, imageMerge),
+		BindFlags: appcmd.BindMultiple(
+			flags.bindImageMergeInputs,
+			flags.bindImageMergeOutput,
+			flags.bindImageMergeAsFileDescriptorSet,
+			flags.bindImageMergeExcludeImports,
+			flags.bindImageMergeExcludeSourceInfo,
+		),
+	}
+}
+
 func newCheckCmd(builder appflag.Builder) *appcmd.Command {
 	return &appcmd.Command{
 		Use:   "check",
@@ -138,6 +174,13 @@ func newCheckLintCmd(builder appflag.Builder) *appcmd.Command {
 			flags.bindCheckLintConfig,
 			flags.bindCheckFiles,
 			flags.bindCheckLintErrorFormat,
+			flags.bindCheckLintDiffOnly,
+			flags.bindCheckLintStrict,
+			flags.bindCheckLintMaxAnnotations,
+			flags.bindCheckLintErrorFormatSink,
+			flags.bindCheckLintWatch,
+			flags.bindCheckLintInputFormat,
+			flags.bindCheckLintGroupBy,
 			flags.bindExperimentalGitClone,
 		),
 	}
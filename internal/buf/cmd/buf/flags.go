@@ -36,8 +36,17 @@ const (
 	imageBuildOutputFlagName           = "output"
 	imageConvertInputFlagName          = "image"
 	imageConvertOutputFlagName         = "output"
+	imageMergeInputFlagName            = "image"
+	imageMergeOutputFlagName           = "output"
 	checkLintInputFlagName             = "input"
 	checkLintConfigFlagName            = "input-config"
+	checkLintDiffOnlyFlagName          = "diff-only"
+	checkLintStrictFlagName            = "strict"
+	checkLintMaxAnnotationsFlagName    = "max-annotations"
+	checkLintErrorFormatSinkFlagName   = "error-format-sink"
+	checkLintWatchFlagName             = "watch"
+	checkLintInputFormatFlagName       = "input-format"
+	checkLintGroupByFlagName           = "group-by"
 	checkBreakingInputFlagName         = "input"
 	checkBreakingConfigFlagName        = "input-config"
 	checkBreakingAgainstInputFlagName  = "against-input"
@@ -50,6 +59,14 @@ const (
 	experimentalGitCloneFlagName       = "experimental-git-clone"
 )
 
+var (
+	// checkLintGroupBys are the valid values of --group-by.
+	checkLintGroupBys = []string{
+		"file",
+		"rule",
+	}
+)
+
 // flags are the flags.
 type flags struct {
 	Config               string
@@ -57,6 +74,7 @@ type flags struct {
 	Input                string
 	AgainstInput         string
 	ConvertInput         string
+	MergeInputs          []string
 	Output               string
 	AsFileDescriptorSet  bool
 	ExcludeImports       bool
@@ -68,6 +86,13 @@ type flags struct {
 	ErrorFormat          string
 	Format               string
 	ExperimentalGitClone bool
+	DiffOnly             string
+	Strict               bool
+	MaxAnnotations       int
+	ErrorFormatSinks     []string
+	Watch                bool
+	InputFormat          string
+	GroupBy              string
 }
 
 func newFlags() *flags {
@@ -158,6 +183,31 @@ func (f *flags) bindImageConvertExcludeSourceInfo(flagSet *pflag.FlagSet) {
 	flagSet.BoolVar(&f.ExcludeSourceInfo, "exclude-source-info", false, "Exclude source info.")
 }
 
+func (f *flags) bindImageMergeInputs(flagSet *pflag.FlagSet) {
+	flagSet.StringSliceVarP(&f.MergeInputs, imageMergeInputFlagName, "i", nil, fmt.Sprintf(`Required. The images to merge. Can be specified multiple times. Must be one of format %s.
+
+Files with the same path must have the same definition across all images.`, buffetch.ImageFormatsString))
+}
+
+func (f *flags) bindImageMergeOutput(flagSet *pflag.FlagSet) {
+	flagSet.StringVarP(&f.Output, imageMergeOutputFlagName, "o", "", fmt.Sprintf(`Required. The location to write the merged image to. Must be one of format %s.`, buffetch.ImageFormatsString))
+}
+
+func (f *flags) bindImageMergeAsFileDescriptorSet(flagSet *pflag.FlagSet) {
+	flagSet.BoolVar(&f.AsFileDescriptorSet, "as-file-descriptor-set", false, `Output as a google.protobuf.FileDescriptorSet instead of an image.
+
+Note that images are wire-compatible with FileDescriptorSets, however this flag will strip
+the additional metadata added for Buf usage.`)
+}
+
+func (f *flags) bindImageMergeExcludeImports(flagSet *pflag.FlagSet) {
+	flagSet.BoolVar(&f.ExcludeImports, "exclude-imports", false, "Exclude imports.")
+}
+
+func (f *flags) bindImageMergeExcludeSourceInfo(flagSet *pflag.FlagSet) {
+	flagSet.BoolVar(&f.ExcludeSourceInfo, "exclude-source-info", false, "Exclude source info.")
+}
+
 func (f *flags) bindCheckLintInput(flagSet *pflag.FlagSet) {
 	flagSet.StringVar(&f.Input, checkLintInputFlagName, ".", fmt.Sprintf(`The source or image to lint. Must be one of format %s.`, buffetch.AllFormatsString))
 }
@@ -166,6 +216,56 @@ func (f *flags) bindCheckLintConfig(flagSet *pflag.FlagSet) {
 	flagSet.StringVar(&f.Config, checkLintConfigFlagName, "", `The config file or data to use.`)
 }
 
+func (f *flags) bindCheckLintDiffOnly(flagSet *pflag.FlagSet) {
+	flagSet.StringVar(&f.DiffOnly, checkLintDiffOnlyFlagName, "", `Only print lint violations for the changed files listed in this file, one path per line.
+Use "-" to read the paths from stdin. Paths are matched against both the root relative
+and the external paths of the files.`)
+}
+
+func (f *flags) bindCheckLintStrict(flagSet *pflag.FlagSet) {
+	flagSet.BoolVar(&f.Strict, checkLintStrictFlagName, false, `Enable every lint checker, overriding use and except in the config.
+Comment ignores and the other config values are still respected.`)
+}
+
+func (f *flags) bindCheckLintMaxAnnotations(flagSet *pflag.FlagSet) {
+	flagSet.IntVar(&f.MaxAnnotations, checkLintMaxAnnotationsFlagName, 0, `The maximum number of lint violations to print, after sorting. 0 means no limit.
+If any violations are not printed, the number of remaining violations is printed last,
+except for the json error format. The exit code still reflects all violations.`)
+}
+
+func (f *flags) bindCheckLintErrorFormatSink(flagSet *pflag.FlagSet) {
+	flagSet.StringSliceVar(
+		&f.ErrorFormatSinks,
+		checkLintErrorFormatSinkFlagName,
+		nil,
+		fmt.Sprintf(
+			`Additionally write the build errors or check violations to a file, in the form format:path.
+The format must be one of %s. The file is overwritten, and is written even if there
+are no violations. All violations are written regardless of --%s. May be specified multiple times.`,
+			stringutil.SliceToString(buflint.AllFormatStrings),
+			checkLintMaxAnnotationsFlagName,
+		),
+	)
+}
+
+func (f *flags) bindCheckLintWatch(flagSet *pflag.FlagSet) {
+	flagSet.BoolVar(&f.Watch, checkLintWatchFlagName, false, fmt.Sprintf(`Re-run the lint checks each time a .proto or .yaml file in the input changes, until interrupted.
+The input must be a directory. If stdout is a terminal, it is cleared before each run.
+The --timeout still applies, use --timeout=0 to watch until interrupted. Once timed out, the exit code is that of the last run, or non-zero if the last run succeeded.
+Cannot be used with --%s "-".`, checkLintDiffOnlyFlagName))
+}
+
+func (f *flags) bindCheckLintInputFormat(flagSet *pflag.FlagSet) {
+	flagSet.StringVar(&f.InputFormat, checkLintInputFormatFlagName, "", fmt.Sprintf(`The format of the image to lint. Must be one of %s.
+This overrides the format inferred from the input, and is needed to lint a JSON image read from stdin with --%s -.`, buffetch.ImageFormatsString, checkLintInputFlagName))
+}
+
+func (f *flags) bindCheckLintGroupBy(flagSet *pflag.FlagSet) {
+	flagSet.StringVar(&f.GroupBy, checkLintGroupByFlagName, "file", fmt.Sprintf(`How to group the lint violations that are printed. Must be one of %s.
+With rule, the violations of each rule are printed together under a line with the rule ID, sorted by file and line.
+Violations are not grouped for --%s json or config-ignore-yaml.`, stringutil.SliceToString(checkLintGroupBys), errorFormatFlagName))
+}
+
 func (f *flags) bindCheckBreakingInput(flagSet *pflag.FlagSet) {
 	flagSet.StringVar(&f.Input, checkBreakingInputFlagName, ".", fmt.Sprintf(`The source or image to check for breaking changes. Must be one of format %s.`, buffetch.AllFormatsString))
 }
@@ -0,0 +1,115 @@
+// Copyright 2020 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graph
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/bufbuild/buf/internal/buf/bufcore"
+)
+
+// printDOT prints the import graph of the Image in DOT format.
+//
+// If byPackage is true, files are collapsed into one node per package,
+// and imports within the same package are not printed.
+func printDOT(writer io.Writer, image bufcore.Image, byPackage bool) error {
+	bufferedWriter := bufio.NewWriter(writer)
+	nodes, edges := getNodesAndEdges(image, byPackage)
+	if _, err := fmt.Fprintln(bufferedWriter, "digraph {"); err != nil {
+		return err
+	}
+	for _, node := range nodes {
+		if _, err := fmt.Fprintf(bufferedWriter, "  %s;\n", strconv.Quote(node)); err != nil {
+			return err
+		}
+	}
+	for _, edge := range edges {
+		attributes := ""
+		if edge.public {
+			attributes = " [style=dashed]"
+		}
+		if _, err := fmt.Fprintf(
+			bufferedWriter,
+			"  %s -> %s%s;\n",
+			strconv.Quote(edge.from),
+			strconv.Quote(edge.to),
+			attributes,
+		); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintln(bufferedWriter, "}"); err != nil {
+		return err
+	}
+	return bufferedWriter.Flush()
+}
+
+type edge struct {
+	from   string
+	to     string
+	public bool
+}
+
+// getNodesAndEdges returns the nodes and edges in the order of the Image files.
+func getNodesAndEdges(image bufcore.Image, byPackage bool) ([]string, []*edge) {
+	var nodes []string
+	var edges []*edge
+	seenNodes := make(map[string]struct{})
+	keyToEdge := make(map[string]*edge)
+	getNode := func(imageFile bufcore.ImageFile) string {
+		if byPackage {
+			return imageFile.Proto().GetPackage()
+		}
+		return imageFile.Path()
+	}
+	for _, imageFile := range image.Files() {
+		from := getNode(imageFile)
+		if _, ok := seenNodes[from]; !ok {
+			seenNodes[from] = struct{}{}
+			nodes = append(nodes, from)
+		}
+		publicDependencyIndexes := make(map[int32]struct{})
+		for _, index := range imageFile.Proto().GetPublicDependency() {
+			publicDependencyIndexes[index] = struct{}{}
+		}
+		for i, importPath := range imageFile.ImportPaths() {
+			_, public := publicDependencyIndexes[int32(i)]
+			to := importPath
+			if importImageFile := image.GetFile(importPath); importImageFile != nil {
+				to = getNode(importImageFile)
+			}
+			if byPackage && from == to {
+				continue
+			}
+			key := from + "\x00" + to
+			if existingEdge, ok := keyToEdge[key]; ok {
+				// with byPackage, the edge is public if any of the imports are public
+				existingEdge.public = existingEdge.public || public
+				continue
+			}
+			edge := &edge{
+				from:   from,
+				to:     to,
+				public: public,
+			}
+			keyToEdge[key] = edge
+			edges = append(edges, edge)
+		}
+	}
+	return nodes, edges
+}
@@ -0,0 +1,97 @@
+// Copyright 2020 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graph
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/bufbuild/buf/internal/buf/bufcore"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestPrintDOT(t *testing.T) {
+	t.Parallel()
+	testPrintDOT(
+		t,
+		false,
+		`digraph {
+  "a/a.proto";
+  "a/b.proto";
+  "b/b.proto";
+  "c/c.proto";
+  "a/b.proto" -> "a/a.proto";
+  "b/b.proto" -> "a/b.proto" [style=dashed];
+  "c/c.proto" -> "a/a.proto";
+  "c/c.proto" -> "b/b.proto";
+}
+`,
+	)
+}
+
+func TestPrintDOTByPackage(t *testing.T) {
+	t.Parallel()
+	testPrintDOT(
+		t,
+		true,
+		`digraph {
+  "a";
+  "b";
+  "c";
+  "b" -> "a" [style=dashed];
+  "c" -> "a";
+  "c" -> "b";
+}
+`,
+	)
+}
+
+func testPrintDOT(t *testing.T, byPackage bool, expected string) {
+	image, err := bufcore.NewImage(
+		[]bufcore.ImageFile{
+			testNewImageFile(t, "a/a.proto", "a", nil, nil),
+			testNewImageFile(t, "a/b.proto", "a", []string{"a/a.proto"}, nil),
+			testNewImageFile(t, "b/b.proto", "b", []string{"a/b.proto"}, []int32{0}),
+			testNewImageFile(t, "c/c.proto", "c", []string{"a/a.proto", "b/b.proto"}, nil),
+		},
+	)
+	require.NoError(t, err)
+	buffer := bytes.NewBuffer(nil)
+	require.NoError(t, printDOT(buffer, image, byPackage))
+	require.Equal(t, expected, buffer.String())
+}
+
+func testNewImageFile(
+	t *testing.T,
+	path string,
+	pkg string,
+	dependencies []string,
+	publicDependencies []int32,
+) bufcore.ImageFile {
+	imageFile, err := bufcore.NewImageFile(
+		&descriptorpb.FileDescriptorProto{
+			Name:             proto.String(path),
+			Package:          proto.String(pkg),
+			Dependency:       dependencies,
+			PublicDependency: publicDependencies,
+		},
+		"",
+		false,
+	)
+	require.NoError(t, err)
+	return imageFile
+}
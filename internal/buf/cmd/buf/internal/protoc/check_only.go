@@ -0,0 +1,76 @@
+// Copyright 2020 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protoc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/bufbuild/buf/internal/buf/bufanalysis"
+	"github.com/bufbuild/buf/internal/buf/bufconfig"
+	"github.com/bufbuild/buf/internal/buf/bufcore"
+	"github.com/bufbuild/buf/internal/buf/cmd/internal"
+	"github.com/bufbuild/buf/internal/pkg/app/applog"
+	"github.com/bufbuild/buf/internal/pkg/storage/storageos"
+)
+
+// checkOnly lints the image with the lint config of the buf.yaml in the working
+// directory, or the default lint config if there is no buf.yaml.
+//
+// The working directory is the current directory if workingDir is empty.
+//
+// Any FileAnnotations are printed to stderr with the error format, and an error is
+// only returned if any of them have error severity. Nothing is written.
+func checkOnly(
+	ctx context.Context,
+	container applog.Container,
+	image bufcore.Image,
+	workingDir string,
+	errorFormat string,
+) error {
+	if workingDir == "" {
+		workingDir = "."
+	}
+	readBucket, err := storageos.NewReadWriteBucket(workingDir)
+	if err != nil {
+		return err
+	}
+	config, err := bufconfig.NewProvider(container.Logger()).GetConfig(ctx, readBucket, bufconfig.GetConfigWithLocalDir())
+	if err != nil {
+		return err
+	}
+	fileAnnotations, err := internal.NewBuflintHandler(container.Logger()).Check(
+		ctx,
+		config.Lint,
+		bufcore.ImageWithoutImports(image),
+	)
+	if err != nil {
+		return err
+	}
+	if len(fileAnnotations) > 0 {
+		if err := bufanalysis.PrintFileAnnotations(
+			container.Stderr(),
+			fileAnnotations,
+			errorFormat,
+		); err != nil {
+			return err
+		}
+		// warnings are printed but do not result in a non-zero exit code
+		if bufanalysis.ContainsSeverity(fileAnnotations, bufanalysis.SeverityError) {
+			return errors.New("")
+		}
+	}
+	return nil
+}
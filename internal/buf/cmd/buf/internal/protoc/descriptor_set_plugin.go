@@ -0,0 +1,103 @@
+// Copyright 2020 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protoc
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/bufbuild/buf/internal/buf/bufcore"
+	"github.com/bufbuild/buf/internal/pkg/app"
+	"github.com/bufbuild/buf/internal/pkg/instrument"
+	"github.com/bufbuild/buf/internal/pkg/protoencoding"
+	"github.com/bufbuild/buf/internal/pkg/tmp"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+)
+
+const (
+	descriptorSetPluginDescriptorSetPlaceholder = "{descriptor_set}"
+	descriptorSetPluginOutPlaceholder           = "{out}"
+)
+
+// executeDescriptorSetPlugin writes the image as a FileDescriptorSet to a temporary
+// file and runs the plugin with the path of the file substituted into its arguments.
+//
+// The plugin arguments are the whitespace-separated values of the plugin options. If no
+// argument contains the descriptor set placeholder, the path is appended as the last
+// argument. The temporary file is removed once the plugin exits.
+func executeDescriptorSetPlugin(
+	ctx context.Context,
+	logger *zap.Logger,
+	container app.EnvStderrContainer,
+	image bufcore.Image,
+	pluginName string,
+	pluginInfo *pluginInfo,
+) (retErr error) {
+	defer instrument.Start(logger, "plugin", zap.String("plugin", pluginName)).End()
+	pluginPath := pluginInfo.Path
+	if pluginPath == "" {
+		pluginPath = "protoc-gen-" + pluginName
+	}
+	pluginPath, err := exec.LookPath(pluginPath)
+	if err != nil {
+		return fmt.Errorf("--%s_out: %v", pluginName, err)
+	}
+	data, err := protoencoding.NewWireMarshaler().Marshal(bufcore.ImageToFileDescriptorSet(image))
+	if err != nil {
+		return err
+	}
+	file, err := tmp.NewFileWithData(data)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		retErr = multierr.Append(retErr, file.Close())
+	}()
+	args := getDescriptorSetPluginArgs(pluginInfo.Opt, file.AbsPath(), pluginInfo.Out)
+	logger.Debug(
+		"descriptor_set_plugin",
+		zap.String("plugin", pluginName),
+		zap.String("path", pluginPath),
+		zap.Strings("args", args),
+	)
+	cmd := exec.CommandContext(ctx, pluginPath, args...)
+	cmd.Env = app.Environ(container)
+	cmd.Stdout = container.Stderr()
+	cmd.Stderr = container.Stderr()
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("--%s_out: %v", pluginName, err)
+	}
+	return nil
+}
+
+func getDescriptorSetPluginArgs(opt string, descriptorSetPath string, out string) []string {
+	split := strings.Fields(opt)
+	args := make([]string, 0, len(split)+1)
+	substituted := false
+	for _, arg := range split {
+		if strings.Contains(arg, descriptorSetPluginDescriptorSetPlaceholder) {
+			substituted = true
+			arg = strings.Replace(arg, descriptorSetPluginDescriptorSetPlaceholder, descriptorSetPath, -1)
+		}
+		args = append(args, strings.Replace(arg, descriptorSetPluginOutPlaceholder, out, -1))
+	}
+	if !substituted {
+		args = append(args, descriptorSetPath)
+	}
+	return args
+}
@@ -17,6 +17,9 @@ package protoc
 import (
 	"errors"
 	"fmt"
+
+	"github.com/bufbuild/buf/internal/buf/buffetch"
+	"github.com/bufbuild/buf/internal/pkg/stringutil"
 )
 
 var (
@@ -32,6 +35,18 @@ func newCannotSpecifyPathWithoutOutError(pluginName string) error {
 	return fmt.Errorf("cannot specify --%s=protoc-gen-%s without --%s_out", pluginPathValuesFlagName, pluginName, pluginName)
 }
 
+func newPluginDescriptorSetWithoutOutError(pluginName string) error {
+	return fmt.Errorf("--%s had plugin %q but --%s_out was not set", pluginDescriptorSetFlagName, pluginName, pluginName)
+}
+
+func newPluginEnvInvalidError(value string) error {
+	return fmt.Errorf("--%s value invalid: %q, must be in the form NAME=VALUE or plugin:NAME=VALUE", pluginEnvFlagName, value)
+}
+
+func newPluginEnvWithoutOutError(pluginName string) error {
+	return fmt.Errorf("--%s had plugin %q but --%s_out was not set", pluginEnvFlagName, pluginName, pluginName)
+}
+
 func newRecursiveReferenceError(flagFilePath string) error {
 	return fmt.Errorf("%s recursively referenced", flagFilePath)
 }
@@ -64,6 +79,46 @@ func newDuplicatePluginPathError(pluginName string) error {
 	return fmt.Errorf("duplicate --%s for protoc-gen-%s", pluginPathValuesFlagName, pluginName)
 }
 
+func newPrintFreeFieldNumbersFormatInvalidError(format string) error {
+	return fmt.Errorf("--%s had invalid value %q, must be one of %s", printFreeFieldNumbersFormatFlagName, format, stringutil.SliceToString(printFreeFieldNumbersFormats))
+}
+
+func newPrintFreeFieldNumbersBaselineWithoutReservedError() error {
+	return fmt.Errorf("--%s can only be used with --%s and --%s=reserved", printFreeFieldNumbersBaselineFlagName, printFreeFieldNumbersFlagName, printFreeFieldNumbersFormatFlagName)
+}
+
+func newImportDepthInvalidError(importDepth int) error {
+	return fmt.Errorf("--%s had invalid value %d, must be greater than or equal to 0", importDepthFlagName, importDepth)
+}
+
+func newOutputModeInvalidError(outputMode string) error {
+	return fmt.Errorf("--%s had invalid value %q, must be octal permission bits such as 0640", outputModeFlagName, outputMode)
+}
+
+func newShuffleFilesInvalidError(shuffleFiles string) error {
+	return fmt.Errorf("--%s had invalid value %q, must be an integer seed", shuffleFilesFlagName, shuffleFiles)
+}
+
+func newOutputFormatInvalidError(outputFormat string) error {
+	return fmt.Errorf("--%s had invalid value %q, must be one of %s", outputFormatFlagName, outputFormat, buffetch.ImageFormatsString)
+}
+
+func newPluginStrategyInvalidError(pluginStrategy string) error {
+	return fmt.Errorf("--%s had invalid value %q, must be one of %s", pluginStrategyFlagName, pluginStrategy, stringutil.SliceToString(pluginStrategies))
+}
+
+func newImportsRegexInvalidError(flagName string, value string, err error) error {
+	return fmt.Errorf("--%s had invalid regular expression %q: %v", flagName, value, err)
+}
+
+func newPublicAndPrivateImportsError(path string) error {
+	return fmt.Errorf("%s matches both --%s and --%s", path, publicImportsFlagName, privateImportsFlagName)
+}
+
+func newOutputFormatWithOptionsError() error {
+	return fmt.Errorf("cannot set --%s when --%s has options after #", outputFormatFlagName, outputFlagName)
+}
+
 func newEncodeNotSupportedError() error {
 	//lint:ignore ST1005 CLI error message
 	return fmt.Errorf(
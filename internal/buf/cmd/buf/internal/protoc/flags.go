@@ -17,7 +17,9 @@ package protoc
 import (
 	"fmt"
 	"io/ioutil"
+	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/bufbuild/buf/internal/buf/bufanalysis"
@@ -29,11 +31,62 @@ import (
 const (
 	includeDirPathsFlagName       = "proto_path"
 	includeImportsFlagName        = "include_imports"
+	importDepthFlagName           = "import_depth"
 	includeSourceInfoFlagName     = "include_source_info"
 	printFreeFieldNumbersFlagName = "print_free_field_numbers"
-	outputFlagName                = "descriptor_set_out"
-	pluginPathValuesFlagName      = "plugin"
-	errorFormatFlagName           = "error_format"
+	// printFreeFieldNumbersFormatFlagName is a buf-specific flag.
+	printFreeFieldNumbersFormatFlagName = "print_free_field_numbers_format"
+	outputFlagName                      = "descriptor_set_out"
+	pluginPathValuesFlagName            = "plugin"
+	errorFormatFlagName                 = "error_format"
+	// progressFlagName is a buf-specific flag.
+	progressFlagName = "progress"
+	// outputModeFlagName is a buf-specific flag.
+	outputModeFlagName = "output_mode"
+	// outputFormatFlagName is a buf-specific flag.
+	outputFormatFlagName = "descriptor_set_out_format"
+	// pluginCacheDirFlagName is a buf-specific flag.
+	pluginCacheDirFlagName = "plugin_cache_dir"
+	// pluginCacheDisableFlagName is a buf-specific flag.
+	pluginCacheDisableFlagName = "plugin_cache_disable"
+	// noBuiltinWKTFlagName is a buf-specific flag.
+	noBuiltinWKTFlagName = "no_builtin_wkt"
+	// pluginDescriptorSetFlagName is a buf-specific flag.
+	pluginDescriptorSetFlagName = "plugin_descriptor_set"
+	// errorOnBOMFlagName is a buf-specific flag.
+	errorOnBOMFlagName = "error_on_bom"
+	// pluginManifestFlagName is a buf-specific flag.
+	pluginManifestFlagName = "plugin_manifest"
+	// relativeImportsFlagName is a buf-specific flag.
+	relativeImportsFlagName = "relative_imports"
+	// verifyFlagName is a buf-specific flag.
+	verifyFlagName = "verify"
+	// printFreeFieldNumbersBaselineFlagName is a buf-specific flag.
+	printFreeFieldNumbersBaselineFlagName = "print_free_field_numbers_baseline"
+	// printPluginsFlagName is a buf-specific flag.
+	printPluginsFlagName = "print_plugins"
+	// checkOnlyFlagName is a buf-specific flag.
+	checkOnlyFlagName = "check_only"
+	// pluginEnvFlagName is a buf-specific flag.
+	pluginEnvFlagName = "plugin_env"
+	// pluginEnvCleanFlagName is a buf-specific flag.
+	pluginEnvCleanFlagName = "plugin_env_clean"
+	// workingDirFlagName is a buf-specific flag.
+	workingDirFlagName = "working_dir"
+	// pluginStrategyFlagName is a buf-specific flag.
+	pluginStrategyFlagName = "plugin_strategy"
+	// publicImportsFlagName is a buf-specific flag.
+	publicImportsFlagName = "public_imports"
+	// privateImportsFlagName is a buf-specific flag.
+	privateImportsFlagName = "private_imports"
+	// stripSourceInfoFlagName is a buf-specific flag.
+	stripSourceInfoFlagName = "strip_source_info"
+	// pluginPathDirFlagName is a buf-specific flag.
+	pluginPathDirFlagName = "plugin_path_dir"
+	// imageCacheDirFlagName is a buf-specific flag.
+	imageCacheDirFlagName = "image_cache_dir"
+	// shuffleFilesFlagName is a buf-specific flag.
+	shuffleFilesFlagName = "shuffle_files"
 
 	pluginFakeFlagName = "protoc_plugin_fake"
 
@@ -46,15 +99,98 @@ const (
 var (
 	defaultIncludeDirPaths = []string{"."}
 	defaultErrorFormat     = "gcc"
+	// the empty string defaults to text
+	printFreeFieldNumbersFormats = []string{
+		"text",
+		"json",
+		"csv",
+		"reserved",
+	}
+	// unlimited
+	defaultImportDepth = -1
+	// the empty string defaults to aggregate
+	pluginStrategies = []string{
+		pluginStrategyAggregate,
+		pluginStrategyDirectory,
+	}
 )
 
 type flags struct {
 	IncludeDirPaths       []string
 	IncludeImports        bool
+	ImportDepth           int
 	IncludeSourceInfo     bool
 	PrintFreeFieldNumbers bool
-	Output                string
-	ErrorFormat           string
+	// PrintFreeFieldNumbersFormat is empty for the default text format.
+	PrintFreeFieldNumbersFormat string
+	// PrintFreeFieldNumbersBaseline is a buf-specific flag.
+	PrintFreeFieldNumbersBaseline string
+	Output                        string
+	ErrorFormat                   string
+	// Progress is a buf-specific flag.
+	Progress bool
+	// OutputMode is a buf-specific flag.
+	//
+	// Parsed into OutputFileMode on env.
+	OutputMode string
+	// OutputFormat is a buf-specific flag.
+	//
+	// Empty if the format should be inferred from Output.
+	OutputFormat string
+	// PluginCacheDir is a buf-specific flag.
+	PluginCacheDir string
+	// PluginCacheDisable is a buf-specific flag.
+	PluginCacheDisable []string
+	// NoBuiltinWKT is a buf-specific flag.
+	NoBuiltinWKT bool
+	// PluginDescriptorSet is a buf-specific flag.
+	PluginDescriptorSet []string
+	// ErrorOnBOM is a buf-specific flag.
+	ErrorOnBOM bool
+	// PluginManifest is a buf-specific flag.
+	PluginManifest string
+	// RelativeImports is a buf-specific flag.
+	RelativeImports bool
+	// Verify is a buf-specific flag.
+	Verify bool
+	// PrintPlugins is a buf-specific flag.
+	PrintPlugins bool
+	// CheckOnly is a buf-specific flag.
+	CheckOnly bool
+	// PluginEnv is a buf-specific flag.
+	//
+	// Parsed into the Env of each pluginInfo on env.
+	PluginEnv []string
+	// PluginEnvClean is a buf-specific flag.
+	PluginEnvClean bool
+	// PluginStrategy is a buf-specific flag.
+	//
+	// Empty for the default aggregate strategy.
+	PluginStrategy string
+	// PublicImports is a buf-specific flag.
+	//
+	// A regular expression of file paths.
+	PublicImports string
+	// PrivateImports is a buf-specific flag.
+	//
+	// A regular expression of file paths.
+	PrivateImports string
+	// StripSourceInfo is a buf-specific flag.
+	StripSourceInfo bool
+	// PluginPathDirs is a buf-specific flag.
+	//
+	// Searched for plugins without a path before $PATH.
+	PluginPathDirs []string
+	// ImageCacheDir is a buf-specific flag.
+	ImageCacheDir string
+	// ShuffleFiles is a buf-specific flag.
+	//
+	// Parsed into ShuffleFilesSeed on env.
+	ShuffleFiles string
+	// WorkingDir is a buf-specific flag.
+	//
+	// Applied to the paths on env in Build.
+	WorkingDir string
 }
 
 type env struct {
@@ -62,6 +198,10 @@ type env struct {
 
 	PluginNameToPluginInfo map[string]*pluginInfo
 	FilePaths              []string
+	// OutputFileMode is nil if OutputMode was not set.
+	OutputFileMode *os.FileMode
+	// ShuffleFilesSeed is nil if ShuffleFiles was not set.
+	ShuffleFilesSeed *int64
 }
 
 type flagsBuilder struct {
@@ -76,15 +216,36 @@ type flagsBuilder struct {
 
 	pluginFake        []string
 	pluginNameToValue map[string]*pluginValue
+
+	caseInsensitive bool
+	// flagSet is the FlagSet given to Bind, used in Build to find the
+	// position of the "--" terminator, if any
+	flagSet *pflag.FlagSet
 }
 
-func newFlagsBuilder() *flagsBuilder {
-	return &flagsBuilder{
+func newFlagsBuilder(options ...flagsBuilderOption) *flagsBuilder {
+	flagsBuilder := &flagsBuilder{
 		pluginNameToValue: make(map[string]*pluginValue),
 	}
+	for _, option := range options {
+		option(flagsBuilder)
+	}
+	return flagsBuilder
+}
+
+type flagsBuilderOption func(*flagsBuilder)
+
+// flagsBuilderWithCaseInsensitive lowercases flag names during normalization.
+//
+// This includes the _out and _opt plugin suffixes, but not the plugin names.
+func flagsBuilderWithCaseInsensitive() flagsBuilderOption {
+	return func(flagsBuilder *flagsBuilder) {
+		flagsBuilder.caseInsensitive = true
+	}
 }
 
 func (f *flagsBuilder) Bind(flagSet *pflag.FlagSet) {
+	f.flagSet = flagSet
 	flagSet.StringSliceVarP(
 		&f.IncludeDirPaths,
 		includeDirPathsFlagName,
@@ -101,6 +262,17 @@ func (f *flagsBuilder) Bind(flagSet *pflag.FlagSet) {
 		false,
 		`Include imports in the resulting FileDescriptorSet.`,
 	)
+	flagSet.IntVar(
+		&f.ImportDepth,
+		importDepthFlagName,
+		// we use a negative default so that we can differentiate between
+		// the default and an explicit 0 when merging recursive flag files
+		defaultImportDepth,
+		fmt.Sprintf(
+			`The maximum depth of imports from the input files to include with --%s. 0 includes no imports. The default is unlimited.`,
+			includeImportsFlagName,
+		),
+	)
 	flagSet.BoolVar(
 		&f.IncludeSourceInfo,
 		includeSourceInfoFlagName,
@@ -113,6 +285,28 @@ func (f *flagsBuilder) Bind(flagSet *pflag.FlagSet) {
 		false,
 		`Print the free field numbers of all messages.`,
 	)
+	flagSet.StringVar(
+		&f.PrintFreeFieldNumbersFormat,
+		printFreeFieldNumbersFormatFlagName,
+		"",
+		fmt.Sprintf(
+			`The format to print the free field numbers with when --%s is set. Must be one of format %s. Defaults to text.
+The reserved format prints reserved statements for the numbers between the used numbers of each message, or with --%s, for the fields deleted since the baseline.`,
+			printFreeFieldNumbersFlagName,
+			stringutil.SliceToString(printFreeFieldNumbersFormats),
+			printFreeFieldNumbersBaselineFlagName,
+		),
+	)
+	flagSet.StringVar(
+		&f.PrintFreeFieldNumbersBaseline,
+		printFreeFieldNumbersBaselineFlagName,
+		"",
+		fmt.Sprintf(
+			`The image to compare against with --%s=reserved, such as a previous --%s. The fields of messages in the baseline that are neither used nor reserved are printed as reserved statements.`,
+			printFreeFieldNumbersFormatFlagName,
+			outputFlagName,
+		),
+	)
 	flagSet.StringVarP(
 		&f.Output,
 		outputFlagName,
@@ -123,6 +317,25 @@ func (f *flagsBuilder) Bind(flagSet *pflag.FlagSet) {
 			buffetch.ImageFormatsString,
 		),
 	)
+	flagSet.StringVar(
+		&f.OutputMode,
+		outputModeFlagName,
+		"",
+		fmt.Sprintf(
+			`The octal permission bits to write the file given to --%s with, such as 0640. Does not apply to plugin outputs. Defaults to 0644, subject to the umask.`,
+			outputFlagName,
+		),
+	)
+	flagSet.StringVar(
+		&f.OutputFormat,
+		outputFormatFlagName,
+		"",
+		fmt.Sprintf(
+			`The format to write the file given to --%s with, overriding the format inferred from the path. Must be one of %s. This is needed to write JSON to stdout.`,
+			outputFlagName,
+			buffetch.ImageFormatsString,
+		),
+	)
 	flagSet.StringVar(
 		&f.ErrorFormat,
 		errorFormatFlagName,
@@ -135,6 +348,212 @@ func (f *flagsBuilder) Bind(flagSet *pflag.FlagSet) {
 			stringutil.SliceToString(bufanalysis.AllFormatStringsWithAliases),
 		),
 	)
+	flagSet.BoolVar(
+		&f.Progress,
+		progressFlagName,
+		false,
+		`Print the number of files parsed so far to stderr during compilation.`,
+	)
+	flagSet.StringVar(
+		&f.PluginCacheDir,
+		pluginCacheDirFlagName,
+		"",
+		`The directory to cache plugin outputs in. If set, a plugin is not run if its output for an identical CodeGeneratorRequest is already in the cache.
+The cache is keyed on the plugin name, the plugin path, and the CodeGeneratorRequest, so the cache should be cleared when a plugin is upgraded in place.`,
+	)
+	flagSet.StringSliceVar(
+		&f.PluginCacheDisable,
+		pluginCacheDisableFlagName,
+		nil,
+		fmt.Sprintf(
+			`The names of the plugins to never cache the outputs of when --%s is set, such as "go". Plugins that do not produce the same output for the same CodeGeneratorRequest must be listed here.`,
+			pluginCacheDirFlagName,
+		),
+	)
+	flagSet.BoolVar(
+		&f.NoBuiltinWKT,
+		noBuiltinWKTFlagName,
+		false,
+		fmt.Sprintf(
+			`Do not resolve imports of the well-known types from the copies built into buf. By default, google/protobuf/*.proto imports that are not found in any --%s are resolved from the well-known types of the vendored protobuf version.`,
+			includeDirPathsFlagName,
+		),
+	)
+	flagSet.BoolVar(
+		&f.ErrorOnBOM,
+		errorOnBOMFlagName,
+		false,
+		`Error on files that start with a UTF-8 byte order mark. By default, a leading UTF-8 byte order mark is stripped.`,
+	)
+	flagSet.BoolVar(
+		&f.Verify,
+		verifyFlagName,
+		false,
+		fmt.Sprintf(
+			`Instead of writing --%s, verify that it would not change. Exits with a non-zero exit code and prints the changed files if --%s does not exist or differs.`,
+			outputFlagName,
+			outputFlagName,
+		),
+	)
+	flagSet.BoolVar(
+		&f.PrintPlugins,
+		printPluginsFlagName,
+		false,
+		`Print the plugins as JSON after all flags and argument files are resolved, one plugin per line sorted by name, and exit without building or running plugins.`,
+	)
+	flagSet.BoolVar(
+		&f.CheckOnly,
+		checkOnlyFlagName,
+		false,
+		fmt.Sprintf(
+			`Only build and lint, without writing --%s or running plugins. Files are linted with the lint config of the buf.yaml in the --%s directory, or the current directory if not set, or the default lint config if there is none. Prints the annotations with --%s, and exits with a non-zero exit code if the build fails or any annotation is an error.`,
+			outputFlagName,
+			workingDirFlagName,
+			errorFormatFlagName,
+		),
+	)
+	flagSet.BoolVar(
+		&f.RelativeImports,
+		relativeImportsFlagName,
+		false,
+		fmt.Sprintf(
+			`Resolve imports that are not found in any --%s relative to the directories of the importing files. It is an error if an import resolves to different files for different importing files.`,
+			includeDirPathsFlagName,
+		),
+	)
+	flagSet.StringArrayVar(
+		&f.PluginEnv,
+		pluginEnvFlagName,
+		nil,
+		`An environment variable to set for plugins, in the form NAME=VALUE for all plugins or plugin:NAME=VALUE for a single plugin, such as "go:GOFLAGS=-mod=mod" for --go_out. May be specified multiple times. The value for a single plugin takes precedence.
+The outputs of plugins are cached separately for different values.`,
+	)
+	flagSet.BoolVar(
+		&f.PluginEnvClean,
+		pluginEnvCleanFlagName,
+		false,
+		fmt.Sprintf(
+			`Run plugins with only the environment variables set with --%s, and the essential environment variables %s of this process. By default, plugins inherit the environment of this process.`,
+			pluginEnvFlagName,
+			strings.Join(pluginEnvEssentialKeys, ", "),
+		),
+	)
+	flagSet.StringVar(
+		&f.PluginStrategy,
+		pluginStrategyFlagName,
+		"",
+		fmt.Sprintf(
+			`How to partition the files to generate between invocations of each plugin. Must be one of %s. Defaults to %s.
+With %s, each plugin is invoked once with all files. With %s, each plugin is invoked once per directory with the files in that directory, and the files in other directories are only given as imports.
+Plugins that write a file shared by all files, such as a single descriptor or index file, write it once per directory with %s, and the last invocation wins. Does not apply to --%s.`,
+			stringutil.SliceToString(pluginStrategies),
+			pluginStrategyAggregate,
+			pluginStrategyAggregate,
+			pluginStrategyDirectory,
+			pluginStrategyDirectory,
+			pluginDescriptorSetFlagName,
+		),
+	)
+	flagSet.StringVar(
+		&f.PublicImports,
+		publicImportsFlagName,
+		"",
+		fmt.Sprintf(
+			`A regular expression of file paths, such as "^acme/". All imports of the matching files are made public in --%s, so that importers of these files can use the types of their imports. Weak imports are not changed.`,
+			outputFlagName,
+		),
+	)
+	flagSet.StringVar(
+		&f.PrivateImports,
+		privateImportsFlagName,
+		"",
+		fmt.Sprintf(
+			`A regular expression of file paths, such as "^acme/internal/". All public imports of the matching files are made regular imports in --%s. It is an error if a file matches both --%s and --%s.`,
+			outputFlagName,
+			publicImportsFlagName,
+			privateImportsFlagName,
+		),
+	)
+	flagSet.BoolVar(
+		&f.StripSourceInfo,
+		stripSourceInfoFlagName,
+		false,
+		fmt.Sprintf(
+			`Strip source info from the resulting FileDescriptorSet just before it is written, even with --%s. This avoids a second compilation when source info is only needed during the build.`,
+			includeSourceInfoFlagName,
+		),
+	)
+	flagSet.StringArrayVar(
+		&f.PluginPathDirs,
+		pluginPathDirFlagName,
+		nil,
+		fmt.Sprintf(
+			`A directory to search for protoc-gen-NAME plugin executables before $PATH. May be specified multiple times, in which case the directories are searched in order. Plugins with a path set with --%s are not searched for.`,
+			pluginPathValuesFlagName,
+		),
+	)
+	flagSet.StringVar(
+		&f.ImageCacheDir,
+		imageCacheDirFlagName,
+		"",
+		fmt.Sprintf(
+			`The directory to cache built images in, such as a volume shared between machines. If set, the input files are not compiled if an image for identical inputs is already in the cache.
+The cache is keyed on the version of buf, the --%s and input file paths as given, and the paths and contents of all .proto files in each --%s, so relative paths should be used to share the cache between machines.`,
+			includeDirPathsFlagName,
+			includeDirPathsFlagName,
+		),
+	)
+	flagSet.StringVar(
+		&f.ShuffleFiles,
+		shuffleFilesFlagName,
+		"",
+		fmt.Sprintf(
+			`Shuffle the files in --%s with the given integer seed. The same seed always results in the same order. This is only meant for testing that consumers of the FileDescriptorSet do not depend on the file order.`,
+			outputFlagName,
+		),
+	)
+	_ = flagSet.MarkHidden(shuffleFilesFlagName)
+	flagSet.StringVarP(
+		&f.WorkingDir,
+		workingDirFlagName,
+		"C",
+		"",
+		fmt.Sprintf(
+			`The directory to resolve relative --%s, --%s, --%s, --%s, --%s, --%s, --%s, plugin output, and input file paths against, and to read the buf.yaml for --%s from, similar to make -C. The current directory of this process is not changed, and argument files are still read relative to it.`,
+			includeDirPathsFlagName,
+			outputFlagName,
+			pluginPathDirFlagName,
+			pluginManifestFlagName,
+			pluginCacheDirFlagName,
+			imageCacheDirFlagName,
+			printFreeFieldNumbersBaselineFlagName,
+			checkOnlyFlagName,
+		),
+	)
+	flagSet.StringSliceVar(
+		&f.PluginDescriptorSet,
+		pluginDescriptorSetFlagName,
+		nil,
+		fmt.Sprintf(
+			`The names of the plugins to give the FileDescriptorSet as a file instead of a CodeGeneratorRequest on stdin, such as "foo" for --foo_out. This is for tools that do not implement the protoc plugin protocol.
+The FileDescriptorSet is written to a temporary file that is removed once the plugin exits. The plugin is run with the whitespace-separated values of --foo_opt as arguments, with %s replaced by the path of the file and %s replaced by the value of --foo_out. If no argument contains %s, the path is appended as the last argument.
+Imports are only included if --%s is set, subject to --%s.`,
+			descriptorSetPluginDescriptorSetPlaceholder,
+			descriptorSetPluginOutPlaceholder,
+			descriptorSetPluginDescriptorSetPlaceholder,
+			includeImportsFlagName,
+			importDepthFlagName,
+		),
+	)
+	flagSet.StringVar(
+		&f.PluginManifest,
+		pluginManifestFlagName,
+		"",
+		fmt.Sprintf(
+			`The location to write a JSON manifest of the files generated by each plugin to, as an object from plugin name to the sorted paths of the generated files. Plugins given to --%s are not included.`,
+			pluginDescriptorSetFlagName,
+		),
+	)
 	flagSet.StringSliceVar(
 		&f.PluginPathValues,
 		pluginPathValuesFlagName,
@@ -181,21 +600,34 @@ func (f *flagsBuilder) Bind(flagSet *pflag.FlagSet) {
 }
 
 func (f *flagsBuilder) Normalize(flagSet *pflag.FlagSet, name string) string {
-	if name != "descriptor_set_out" && strings.HasSuffix(name, "_out") {
-		f.pluginFakeParse(name, "_out", true)
+	flagName := name
+	if f.caseInsensitive {
+		flagName = strings.ToLower(flagName)
+	}
+	// the plugin suffixes are matched on the normalized name, so --GO_OUT is a
+	// plugin flag if case-insensitive, while the plugin name keeps its case
+	if flagName != outputFlagName && strings.HasSuffix(flagName, "_out") {
+		f.pluginFakeParse(name[:len(name)-len("_out")], true)
 		return pluginFakeFlagName
 	}
-	if strings.HasSuffix(name, "_opt") {
-		f.pluginFakeParse(name, "_opt", false)
+	if strings.HasSuffix(flagName, "_opt") {
+		f.pluginFakeParse(name[:len(name)-len("_opt")], false)
 		return pluginFakeFlagName
 	}
-	return strings.Replace(name, "-", "_", -1)
+	return strings.Replace(flagName, "-", "_", -1)
 }
 
 func (f *flagsBuilder) Build(args []string) (*env, error) {
 	pluginNameToPluginInfo := make(map[string]*pluginInfo)
 	seenFlagFilePaths := make(map[string]struct{})
-	filePaths, err := f.buildRec(args, pluginNameToPluginInfo, seenFlagFilePaths)
+	argsLenAtDash := -1
+	if f.flagSet != nil {
+		argsLenAtDash = f.flagSet.ArgsLenAtDash()
+	}
+	if err := f.checkImportDepth(); err != nil {
+		return nil, err
+	}
+	filePaths, err := f.buildRec(args, argsLenAtDash, pluginNameToPluginInfo, seenFlagFilePaths)
 	if err != nil {
 		return nil, err
 	}
@@ -210,6 +642,14 @@ func (f *flagsBuilder) Build(args []string) (*env, error) {
 			return nil, newCannotSpecifyPathWithoutOutError(pluginName)
 		}
 	}
+	for _, pluginName := range f.PluginDescriptorSet {
+		if pluginInfo, ok := pluginNameToPluginInfo[pluginName]; !ok || pluginInfo.Out == "" {
+			return nil, newPluginDescriptorSetWithoutOutError(pluginName)
+		}
+	}
+	if err := parsePluginEnv(f.PluginEnv, pluginNameToPluginInfo); err != nil {
+		return nil, err
+	}
 	if len(f.IncludeDirPaths) == 0 {
 		f.IncludeDirPaths = defaultIncludeDirPaths
 	}
@@ -219,15 +659,88 @@ func (f *flagsBuilder) Build(args []string) (*env, error) {
 	if len(filePaths) == 0 {
 		return nil, errNoInputFiles
 	}
+	if f.OutputFormat != "" {
+		if _, ok := stringutil.SliceToMap(buffetch.ImageFormats)[f.OutputFormat]; !ok {
+			return nil, newOutputFormatInvalidError(f.OutputFormat)
+		}
+	}
+	if f.PluginStrategy != "" {
+		if _, ok := stringutil.SliceToMap(pluginStrategies)[f.PluginStrategy]; !ok {
+			return nil, newPluginStrategyInvalidError(f.PluginStrategy)
+		}
+	}
+	var outputFileMode *os.FileMode
+	if f.OutputMode != "" {
+		fileMode, err := parseOutputMode(f.OutputMode)
+		if err != nil {
+			return nil, err
+		}
+		outputFileMode = &fileMode
+	}
+	var shuffleFilesSeed *int64
+	if f.ShuffleFiles != "" {
+		seed, err := strconv.ParseInt(f.ShuffleFiles, 10, 64)
+		if err != nil {
+			return nil, newShuffleFilesInvalidError(f.ShuffleFiles)
+		}
+		shuffleFilesSeed = &seed
+	}
+	if f.WorkingDir != "" {
+		f.IncludeDirPaths = joinWorkingDir(f.WorkingDir, f.IncludeDirPaths)
+		filePaths = joinWorkingDir(f.WorkingDir, filePaths)
+		if len(f.PluginPathDirs) > 0 {
+			f.PluginPathDirs = joinWorkingDir(f.WorkingDir, f.PluginPathDirs)
+		}
+		f.Output = joinWorkingDirRef(f.WorkingDir, f.Output)
+		f.PrintFreeFieldNumbersBaseline = joinWorkingDirRef(f.WorkingDir, f.PrintFreeFieldNumbersBaseline)
+		for _, path := range []*string{
+			&f.PluginManifest,
+			&f.PluginCacheDir,
+			&f.ImageCacheDir,
+		} {
+			if *path != "" {
+				*path = joinWorkingDir(f.WorkingDir, []string{*path})[0]
+			}
+		}
+		for _, pluginInfo := range pluginNameToPluginInfo {
+			pluginInfo.Out = joinWorkingDir(f.WorkingDir, []string{pluginInfo.Out})[0]
+		}
+	}
 	return &env{
 		flags:                  f.flags,
 		PluginNameToPluginInfo: pluginNameToPluginInfo,
 		FilePaths:              filePaths,
+		OutputFileMode:         outputFileMode,
+		ShuffleFilesSeed:       shuffleFilesSeed,
 	}, nil
 }
 
-func (f *flagsBuilder) pluginFakeParse(name string, suffix string, isOut bool) {
-	pluginName := strings.TrimSuffix(name, suffix)
+// joinWorkingDir returns the paths with the relative paths joined to workingDir.
+//
+// A new slice is returned, as paths may be defaultIncludeDirPaths.
+func joinWorkingDir(workingDir string, paths []string) []string {
+	joinedPaths := make([]string, len(paths))
+	for i, path := range paths {
+		if filepath.IsAbs(path) {
+			joinedPaths[i] = path
+		} else {
+			joinedPaths[i] = filepath.Join(workingDir, path)
+		}
+	}
+	return joinedPaths
+}
+
+// joinWorkingDirRef returns the image reference joined to workingDir if it is a relative path.
+//
+// Empty references, stdin or stdout, and remote references are returned as is.
+func joinWorkingDirRef(workingDir string, ref string) string {
+	if ref == "" || ref == "-" || strings.HasPrefix(ref, "-#") || strings.Contains(ref, "://") {
+		return ref
+	}
+	return joinWorkingDir(workingDir, []string{ref})[0]
+}
+
+func (f *flagsBuilder) pluginFakeParse(pluginName string, isOut bool) {
 	pluginValue, ok := f.pluginNameToValue[pluginName]
 	if !ok {
 		pluginValue = newPluginValue()
@@ -241,8 +754,11 @@ func (f *flagsBuilder) pluginFakeParse(name string, suffix string, isOut bool) {
 	}
 }
 
+// argsLenAtDash is the number of args before the "--" terminator, or -1 if
+// there was no terminator. All args after the terminator are file paths.
 func (f *flagsBuilder) buildRec(
 	args []string,
+	argsLenAtDash int,
 	pluginNameToPluginInfo map[string]*pluginInfo,
 	seenFlagFilePaths map[string]struct{},
 ) ([]string, error) {
@@ -250,53 +766,102 @@ func (f *flagsBuilder) buildRec(
 		return nil, err
 	}
 	filePaths := make([]string, 0, len(args))
-	for _, arg := range args {
+	for i, arg := range args {
 		if len(arg) == 0 {
 			return nil, errArgEmpty
 		}
-		if arg[0] != '@' {
+		if arg[0] != '@' || (argsLenAtDash >= 0 && i >= argsLenAtDash) {
 			filePaths = append(filePaths, arg)
 		} else {
-			flagFilePath := arg[1:]
-			if _, ok := seenFlagFilePaths[flagFilePath]; ok {
-				return nil, newRecursiveReferenceError(flagFilePath)
-			}
-			seenFlagFilePaths[flagFilePath] = struct{}{}
-			data, err := ioutil.ReadFile(flagFilePath)
-			if err != nil {
-				return nil, err
-			}
-			var flagFilePathArgs []string
-			for _, flagFilePathArg := range strings.Split(string(data), "\n") {
-				flagFilePathArg = strings.TrimSpace(flagFilePathArg)
-				if flagFilePathArg != "" {
-					flagFilePathArgs = append(flagFilePathArgs, flagFilePathArg)
+			for _, flagFilePath := range getFlagFilePaths(arg) {
+				subFilePaths, err := f.buildFlagFile(flagFilePath, pluginNameToPluginInfo, seenFlagFilePaths)
+				if err != nil {
+					return nil, err
 				}
+				filePaths = append(filePaths, subFilePaths...)
 			}
-			subFlagsBuilder := newFlagsBuilder()
-			flagSet := pflag.NewFlagSet(flagFilePath, pflag.ContinueOnError)
-			subFlagsBuilder.Bind(flagSet)
-			flagSet.SetNormalizeFunc(normalizeFunc(subFlagsBuilder.Normalize))
-			if err := flagSet.Parse(flagFilePathArgs); err != nil {
-				return nil, err
-			}
-			subFilePaths, err := subFlagsBuilder.buildRec(
-				flagSet.Args(),
-				pluginNameToPluginInfo,
-				seenFlagFilePaths,
-			)
-			if err != nil {
-				return nil, err
-			}
-			if err := f.merge(subFlagsBuilder); err != nil {
-				return nil, err
-			}
-			filePaths = append(filePaths, subFilePaths...)
 		}
 	}
 	return filePaths, nil
 }
 
+func (f *flagsBuilder) buildFlagFile(
+	flagFilePath string,
+	pluginNameToPluginInfo map[string]*pluginInfo,
+	seenFlagFilePaths map[string]struct{},
+) ([]string, error) {
+	if _, ok := seenFlagFilePaths[flagFilePath]; ok {
+		return nil, newRecursiveReferenceError(flagFilePath)
+	}
+	seenFlagFilePaths[flagFilePath] = struct{}{}
+	data, err := ioutil.ReadFile(flagFilePath)
+	if err != nil {
+		return nil, err
+	}
+	var flagFilePathArgs []string
+	for _, flagFilePathArg := range strings.Split(string(data), "\n") {
+		flagFilePathArg = strings.TrimSpace(flagFilePathArg)
+		if flagFilePathArg != "" {
+			flagFilePathArgs = append(flagFilePathArgs, flagFilePathArg)
+		}
+	}
+	subFlagsBuilder := newFlagsBuilder(f.subFlagsBuilderOptions()...)
+	flagSet := pflag.NewFlagSet(flagFilePath, pflag.ContinueOnError)
+	subFlagsBuilder.Bind(flagSet)
+	flagSet.SetNormalizeFunc(normalizeFunc(subFlagsBuilder.Normalize))
+	if err := flagSet.Parse(flagFilePathArgs); err != nil {
+		return nil, err
+	}
+	subFilePaths, err := subFlagsBuilder.buildRec(
+		flagSet.Args(),
+		flagSet.ArgsLenAtDash(),
+		pluginNameToPluginInfo,
+		seenFlagFilePaths,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.merge(subFlagsBuilder); err != nil {
+		return nil, err
+	}
+	return subFilePaths, nil
+}
+
+// getFlagFilePaths gets the flag file paths from an arg starting with '@'.
+//
+// The arg may be a comma-separated list such as "@a.txt,@b.txt", in which case
+// the paths are returned in order. The arg is only split if every element
+// starts with '@', so that existing flag file paths containing commas still work.
+func getFlagFilePaths(arg string) []string {
+	split := strings.Split(arg, ",")
+	flagFilePaths := make([]string, 0, len(split))
+	for _, element := range split {
+		if len(element) < 2 || element[0] != '@' {
+			return []string{arg[1:]}
+		}
+		flagFilePaths = append(flagFilePaths, element[1:])
+	}
+	return flagFilePaths
+}
+
+// checkImportDepth returns an error if --import_depth was explicitly set to a negative value.
+//
+// Negative values are only used internally to denote that the flag was not set.
+func (f *flagsBuilder) checkImportDepth() error {
+	if f.flagSet != nil && f.flagSet.Changed(importDepthFlagName) && f.ImportDepth < 0 {
+		return newImportDepthInvalidError(f.ImportDepth)
+	}
+	return nil
+}
+
+func (f *flagsBuilder) subFlagsBuilderOptions() []flagsBuilderOption {
+	var options []flagsBuilderOption
+	if f.caseInsensitive {
+		options = append(options, flagsBuilderWithCaseInsensitive())
+	}
+	return options
+}
+
 // we need to bind a separate flags as pflags overrides the values with defaults if you bind again
 // note that pflags does not error on duplicates so we do not either
 func (f *flagsBuilder) merge(subFlagsBuilder *flagsBuilder) error {
@@ -304,18 +869,91 @@ func (f *flagsBuilder) merge(subFlagsBuilder *flagsBuilder) error {
 	if subFlagsBuilder.IncludeImports {
 		f.IncludeImports = true
 	}
+	if err := subFlagsBuilder.checkImportDepth(); err != nil {
+		return err
+	}
+	if subFlagsBuilder.ImportDepth >= 0 {
+		f.ImportDepth = subFlagsBuilder.ImportDepth
+	}
 	if subFlagsBuilder.IncludeSourceInfo {
 		f.IncludeSourceInfo = true
 	}
 	if subFlagsBuilder.PrintFreeFieldNumbers {
 		f.PrintFreeFieldNumbers = true
 	}
+	if subFlagsBuilder.PrintFreeFieldNumbersFormat != "" {
+		f.PrintFreeFieldNumbersFormat = subFlagsBuilder.PrintFreeFieldNumbersFormat
+	}
+	if subFlagsBuilder.PrintFreeFieldNumbersBaseline != "" {
+		f.PrintFreeFieldNumbersBaseline = subFlagsBuilder.PrintFreeFieldNumbersBaseline
+	}
 	if subFlagsBuilder.Output != "" {
 		f.Output = subFlagsBuilder.Output
 	}
+	if subFlagsBuilder.OutputMode != "" {
+		f.OutputMode = subFlagsBuilder.OutputMode
+	}
+	if subFlagsBuilder.OutputFormat != "" {
+		f.OutputFormat = subFlagsBuilder.OutputFormat
+	}
 	if subFlagsBuilder.ErrorFormat != "" {
 		f.ErrorFormat = subFlagsBuilder.ErrorFormat
 	}
+	if subFlagsBuilder.Progress {
+		f.Progress = true
+	}
+	if subFlagsBuilder.PluginCacheDir != "" {
+		f.PluginCacheDir = subFlagsBuilder.PluginCacheDir
+	}
+	f.PluginCacheDisable = append(f.PluginCacheDisable, subFlagsBuilder.PluginCacheDisable...)
+	if subFlagsBuilder.NoBuiltinWKT {
+		f.NoBuiltinWKT = true
+	}
+	f.PluginDescriptorSet = append(f.PluginDescriptorSet, subFlagsBuilder.PluginDescriptorSet...)
+	if subFlagsBuilder.ErrorOnBOM {
+		f.ErrorOnBOM = true
+	}
+	if subFlagsBuilder.PluginManifest != "" {
+		f.PluginManifest = subFlagsBuilder.PluginManifest
+	}
+	if subFlagsBuilder.RelativeImports {
+		f.RelativeImports = true
+	}
+	if subFlagsBuilder.Verify {
+		f.Verify = true
+	}
+	if subFlagsBuilder.PrintPlugins {
+		f.PrintPlugins = true
+	}
+	if subFlagsBuilder.CheckOnly {
+		f.CheckOnly = true
+	}
+	f.PluginEnv = append(f.PluginEnv, subFlagsBuilder.PluginEnv...)
+	if subFlagsBuilder.PluginEnvClean {
+		f.PluginEnvClean = true
+	}
+	if subFlagsBuilder.PluginStrategy != "" {
+		f.PluginStrategy = subFlagsBuilder.PluginStrategy
+	}
+	if subFlagsBuilder.PublicImports != "" {
+		f.PublicImports = subFlagsBuilder.PublicImports
+	}
+	if subFlagsBuilder.PrivateImports != "" {
+		f.PrivateImports = subFlagsBuilder.PrivateImports
+	}
+	if subFlagsBuilder.StripSourceInfo {
+		f.StripSourceInfo = true
+	}
+	f.PluginPathDirs = append(f.PluginPathDirs, subFlagsBuilder.PluginPathDirs...)
+	if subFlagsBuilder.ImageCacheDir != "" {
+		f.ImageCacheDir = subFlagsBuilder.ImageCacheDir
+	}
+	if subFlagsBuilder.ShuffleFiles != "" {
+		f.ShuffleFiles = subFlagsBuilder.ShuffleFiles
+	}
+	if subFlagsBuilder.WorkingDir != "" {
+		f.WorkingDir = subFlagsBuilder.WorkingDir
+	}
 	f.PluginPathValues = append(f.PluginPathValues, subFlagsBuilder.PluginPathValues...)
 	if subFlagsBuilder.Encode != "" {
 		f.Encode = subFlagsBuilder.Encode
@@ -437,3 +1075,12 @@ func normalizeFunc(f func(*pflag.FlagSet, string) string) func(*pflag.FlagSet, s
 		return pflag.NormalizedName(f(flagSet, name))
 	}
 }
+
+// parseOutputMode parses the octal value of --output_mode.
+func parseOutputMode(outputMode string) (os.FileMode, error) {
+	value, err := strconv.ParseUint(outputMode, 8, 32)
+	if err != nil || value > uint64(os.ModePerm) {
+		return 0, newOutputModeInvalidError(outputMode)
+	}
+	return os.FileMode(value), nil
+}
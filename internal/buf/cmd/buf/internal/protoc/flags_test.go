@@ -16,6 +16,7 @@ package protoc
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
 	"testing"
 
@@ -26,9 +27,10 @@ import (
 
 func TestParseFlags(t *testing.T) {
 	testCases := []struct {
-		Args          []string
-		Expected      *env
-		ExpectedError error
+		Args            []string
+		CaseInsensitive bool
+		Expected        *env
+		ExpectedError   error
 	}{
 		{
 			ExpectedError: errNoInputFiles,
@@ -41,6 +43,7 @@ func TestParseFlags(t *testing.T) {
 				flags: flags{
 					IncludeDirPaths: defaultIncludeDirPaths,
 					ErrorFormat:     defaultErrorFormat,
+					ImportDepth:     defaultImportDepth,
 				},
 				FilePaths: []string{
 					"foo.proto",
@@ -61,6 +64,7 @@ func TestParseFlags(t *testing.T) {
 						"proto",
 					},
 					ErrorFormat: "text",
+					ImportDepth: defaultImportDepth,
 				},
 				FilePaths: []string{
 					"foo.proto",
@@ -83,6 +87,7 @@ func TestParseFlags(t *testing.T) {
 						"proto",
 					},
 					ErrorFormat: "text",
+					ImportDepth: defaultImportDepth,
 				},
 				PluginNameToPluginInfo: map[string]*pluginInfo{
 					"go": {
@@ -113,6 +118,7 @@ func TestParseFlags(t *testing.T) {
 						"proto",
 					},
 					ErrorFormat: "text",
+					ImportDepth: defaultImportDepth,
 				},
 				PluginNameToPluginInfo: map[string]*pluginInfo{
 					"go": {
@@ -145,6 +151,7 @@ func TestParseFlags(t *testing.T) {
 						"proto",
 					},
 					ErrorFormat: "text",
+					ImportDepth: defaultImportDepth,
 				},
 				PluginNameToPluginInfo: map[string]*pluginInfo{
 					"go": {
@@ -178,6 +185,7 @@ func TestParseFlags(t *testing.T) {
 						"proto",
 					},
 					ErrorFormat: "text",
+					ImportDepth: defaultImportDepth,
 				},
 				PluginNameToPluginInfo: map[string]*pluginInfo{
 					"go": {
@@ -191,6 +199,52 @@ func TestParseFlags(t *testing.T) {
 				},
 			},
 		},
+		{
+			Args: []string{
+				"--include_imports",
+				"--import_depth",
+				"1",
+				"foo.proto",
+			},
+			Expected: &env{
+				flags: flags{
+					IncludeDirPaths: defaultIncludeDirPaths,
+					IncludeImports:  true,
+					ImportDepth:     1,
+					ErrorFormat:     defaultErrorFormat,
+				},
+				FilePaths: []string{
+					"foo.proto",
+				},
+			},
+		},
+		{
+			Args: []string{
+				"--include_imports",
+				"--import_depth",
+				"0",
+				"foo.proto",
+			},
+			Expected: &env{
+				flags: flags{
+					IncludeDirPaths: defaultIncludeDirPaths,
+					IncludeImports:  true,
+					ImportDepth:     0,
+					ErrorFormat:     defaultErrorFormat,
+				},
+				FilePaths: []string{
+					"foo.proto",
+				},
+			},
+		},
+		{
+			Args: []string{
+				"--include_imports",
+				"--import_depth=-1",
+				"foo.proto",
+			},
+			ExpectedError: newImportDepthInvalidError(-1),
+		},
 		{
 			Args: []string{
 				"@" + filepath.Join("testdata", "1", "flags.txt"),
@@ -202,6 +256,7 @@ func TestParseFlags(t *testing.T) {
 						"proto",
 					},
 					ErrorFormat: "text",
+					ImportDepth: defaultImportDepth,
 				},
 				PluginNameToPluginInfo: map[string]*pluginInfo{
 					"go": {
@@ -226,6 +281,7 @@ func TestParseFlags(t *testing.T) {
 						"proto",
 					},
 					ErrorFormat: "text",
+					ImportDepth: defaultImportDepth,
 				},
 				PluginNameToPluginInfo: map[string]*pluginInfo{
 					"go": {
@@ -246,12 +302,662 @@ func TestParseFlags(t *testing.T) {
 			},
 			ExpectedError: newRecursiveReferenceError(filepath.Join("testdata", "3", "flags1.txt")),
 		},
+		{
+			Args: []string{
+				"@" + filepath.Join("testdata", "5", "flags1.txt") + ",@" + filepath.Join("testdata", "5", "flags2.txt"),
+				"foo.proto",
+			},
+			Expected: &env{
+				flags: flags{
+					IncludeDirPaths: []string{
+						"proto",
+					},
+					ErrorFormat: "text",
+					ImportDepth: defaultImportDepth,
+				},
+				PluginNameToPluginInfo: map[string]*pluginInfo{
+					"go": {
+						Out: "go_out",
+						Opt: "plugins=grpc",
+					},
+				},
+				FilePaths: []string{
+					"foo.proto",
+				},
+			},
+		},
+		{
+			// flags1.txt references flags2.txt, so flags2.txt is seen twice
+			Args: []string{
+				"@" + filepath.Join("testdata", "2", "flags1.txt") + ",@" + filepath.Join("testdata", "2", "flags2.txt"),
+				"foo.proto",
+			},
+			ExpectedError: newRecursiveReferenceError(filepath.Join("testdata", "2", "flags2.txt")),
+		},
+		{
+			Args: []string{
+				"-I",
+				"proto",
+				"--",
+				"-foo.proto",
+				"--include_imports",
+				"@bar.proto",
+			},
+			Expected: &env{
+				flags: flags{
+					IncludeDirPaths: []string{
+						"proto",
+					},
+					ErrorFormat: defaultErrorFormat,
+					ImportDepth: defaultImportDepth,
+				},
+				FilePaths: []string{
+					"-foo.proto",
+					"--include_imports",
+					"@bar.proto",
+				},
+			},
+		},
+		{
+			Args: []string{
+				"@" + filepath.Join("testdata", "4", "flags.txt"),
+				"foo.proto",
+			},
+			Expected: &env{
+				flags: flags{
+					IncludeDirPaths: []string{
+						"proto",
+					},
+					ErrorFormat: defaultErrorFormat,
+					ImportDepth: defaultImportDepth,
+				},
+				FilePaths: []string{
+					"-bar.proto",
+					"foo.proto",
+				},
+			},
+		},
+		{
+			Args: []string{
+				"--Include_Imports",
+				"--Error_Format",
+				"text",
+				"--Descriptor_Set_Out",
+				"foo.bin",
+				"foo.proto",
+			},
+			CaseInsensitive: true,
+			Expected: &env{
+				flags: flags{
+					IncludeDirPaths: defaultIncludeDirPaths,
+					IncludeImports:  true,
+					ErrorFormat:     "text",
+					ImportDepth:     defaultImportDepth,
+					Output:          "foo.bin",
+				},
+				FilePaths: []string{
+					"foo.proto",
+				},
+			},
+		},
+		{
+			Args: []string{
+				"--Foo_out",
+				"foo_out",
+				"--Foo_opt",
+				"bar",
+				"foo.proto",
+			},
+			CaseInsensitive: true,
+			Expected: &env{
+				flags: flags{
+					IncludeDirPaths: defaultIncludeDirPaths,
+					ErrorFormat:     defaultErrorFormat,
+					ImportDepth:     defaultImportDepth,
+				},
+				PluginNameToPluginInfo: map[string]*pluginInfo{
+					// plugin names are not lowercased
+					"Foo": {
+						Out: "foo_out",
+						Opt: "bar",
+					},
+				},
+				FilePaths: []string{
+					"foo.proto",
+				},
+			},
+		},
+		{
+			Args: []string{
+				"--GO_OUT",
+				"go_out",
+				"--GO_OPT",
+				"bar",
+				"foo.proto",
+			},
+			CaseInsensitive: true,
+			Expected: &env{
+				flags: flags{
+					IncludeDirPaths: defaultIncludeDirPaths,
+					ErrorFormat:     defaultErrorFormat,
+					ImportDepth:     defaultImportDepth,
+				},
+				PluginNameToPluginInfo: map[string]*pluginInfo{
+					"GO": {
+						Out: "go_out",
+						Opt: "bar",
+					},
+				},
+				FilePaths: []string{
+					"foo.proto",
+				},
+			},
+		},
+		{
+			Args: []string{
+				"--output_mode",
+				"0640",
+				"foo.proto",
+			},
+			Expected: &env{
+				flags: flags{
+					IncludeDirPaths: defaultIncludeDirPaths,
+					ErrorFormat:     defaultErrorFormat,
+					ImportDepth:     defaultImportDepth,
+					OutputMode:      "0640",
+				},
+				FilePaths: []string{
+					"foo.proto",
+				},
+				OutputFileMode: testFileModePtr(0640),
+			},
+		},
+		{
+			Args: []string{
+				"--output_mode",
+				"0648",
+				"foo.proto",
+			},
+			ExpectedError: newOutputModeInvalidError("0648"),
+		},
+		{
+			Args: []string{
+				"--output_mode",
+				"1777",
+				"foo.proto",
+			},
+			ExpectedError: newOutputModeInvalidError("1777"),
+		},
+		{
+			Args: []string{
+				"--descriptor_set_out_format",
+				"json",
+				"foo.proto",
+			},
+			Expected: &env{
+				flags: flags{
+					IncludeDirPaths: defaultIncludeDirPaths,
+					ErrorFormat:     defaultErrorFormat,
+					ImportDepth:     defaultImportDepth,
+					OutputFormat:    "json",
+				},
+				FilePaths: []string{
+					"foo.proto",
+				},
+			},
+		},
+		{
+			Args: []string{
+				"--descriptor_set_out_format",
+				"tar",
+				"foo.proto",
+			},
+			ExpectedError: newOutputFormatInvalidError("tar"),
+		},
+		{
+			Args: []string{
+				"--no_builtin_wkt",
+				"foo.proto",
+			},
+			Expected: &env{
+				flags: flags{
+					IncludeDirPaths: defaultIncludeDirPaths,
+					ErrorFormat:     defaultErrorFormat,
+					ImportDepth:     defaultImportDepth,
+					NoBuiltinWKT:    true,
+				},
+				FilePaths: []string{
+					"foo.proto",
+				},
+			},
+		},
+		{
+			Args: []string{
+				"--foo_out=bar",
+				"--plugin_descriptor_set=foo",
+				"foo.proto",
+			},
+			Expected: &env{
+				flags: flags{
+					IncludeDirPaths:     defaultIncludeDirPaths,
+					ErrorFormat:         defaultErrorFormat,
+					ImportDepth:         defaultImportDepth,
+					PluginDescriptorSet: []string{"foo"},
+				},
+				PluginNameToPluginInfo: map[string]*pluginInfo{
+					"foo": {
+						Out: "bar",
+					},
+				},
+				FilePaths: []string{
+					"foo.proto",
+				},
+			},
+		},
+		{
+			Args: []string{
+				"--plugin_descriptor_set=foo",
+				"foo.proto",
+			},
+			ExpectedError: newPluginDescriptorSetWithoutOutError("foo"),
+		},
+		{
+			Args: []string{
+				"--foo_out=bar",
+				"--baz_out=bat",
+				"--plugin_env=A=1,2",
+				"--plugin_env=B=3",
+				"--plugin_env=foo:B=4",
+				"--plugin_env=foo:C=",
+				"foo.proto",
+			},
+			Expected: &env{
+				flags: flags{
+					IncludeDirPaths: defaultIncludeDirPaths,
+					ErrorFormat:     defaultErrorFormat,
+					ImportDepth:     defaultImportDepth,
+					PluginEnv:       []string{"A=1,2", "B=3", "foo:B=4", "foo:C="},
+				},
+				PluginNameToPluginInfo: map[string]*pluginInfo{
+					"foo": {
+						Out: "bar",
+						Env: map[string]string{
+							"A": "1,2",
+							"B": "4",
+							"C": "",
+						},
+					},
+					"baz": {
+						Out: "bat",
+						Env: map[string]string{
+							"A": "1,2",
+							"B": "3",
+						},
+					},
+				},
+				FilePaths: []string{
+					"foo.proto",
+				},
+			},
+		},
+		{
+			Args: []string{
+				"--foo_out=bar",
+				"--plugin_env=A",
+				"foo.proto",
+			},
+			ExpectedError: newPluginEnvInvalidError("A"),
+		},
+		{
+			Args: []string{
+				"--foo_out=bar",
+				"--plugin_env=foo:=1",
+				"foo.proto",
+			},
+			ExpectedError: newPluginEnvInvalidError("foo:=1"),
+		},
+		{
+			Args: []string{
+				"--foo_out=bar",
+				"--plugin_env=baz:A=1",
+				"foo.proto",
+			},
+			ExpectedError: newPluginEnvWithoutOutError("baz"),
+		},
+		{
+			Args: []string{
+				"@" + filepath.Join("testdata", "6", "flags1.txt") + ",@" + filepath.Join("testdata", "6", "flags2.txt"),
+				"--print_plugins",
+				"foo.proto",
+			},
+			Expected: &env{
+				flags: flags{
+					IncludeDirPaths: []string{
+						"proto",
+					},
+					ErrorFormat:  defaultErrorFormat,
+					ImportDepth:  defaultImportDepth,
+					PrintPlugins: true,
+				},
+				PluginNameToPluginInfo: map[string]*pluginInfo{
+					"go": {
+						Out:  "go_out",
+						Opt:  "plugins=grpc",
+						Path: "/bin/protoc-gen-go",
+					},
+					"java": {
+						Out: "java_out",
+						Opt: "lite",
+					},
+				},
+				FilePaths: []string{
+					"foo.proto",
+				},
+			},
+		},
+		{
+			Args: []string{
+				"-C",
+				"bar",
+				"-I",
+				"proto",
+				"-I",
+				"/proto",
+				"-o",
+				"image.bin",
+				"--foo_out=foo_out",
+				"foo.proto",
+			},
+			Expected: &env{
+				flags: flags{
+					IncludeDirPaths: []string{
+						filepath.Join("bar", "proto"),
+						"/proto",
+					},
+					ErrorFormat: defaultErrorFormat,
+					ImportDepth: defaultImportDepth,
+					Output:      filepath.Join("bar", "image.bin"),
+					WorkingDir:  "bar",
+				},
+				PluginNameToPluginInfo: map[string]*pluginInfo{
+					"foo": {
+						Out: filepath.Join("bar", "foo_out"),
+					},
+				},
+				FilePaths: []string{
+					filepath.Join("bar", "foo.proto"),
+				},
+			},
+		},
+		{
+			Args: []string{
+				"--working_dir=bar",
+				"--foo_out=foo_out",
+				"--plugin_manifest=manifest.json",
+				"--plugin_cache_dir=plugin_cache",
+				"--image_cache_dir=/image_cache",
+				"--print_free_field_numbers",
+				"--print_free_field_numbers_format=reserved",
+				"--print_free_field_numbers_baseline=baseline.bin#format=bin",
+				"foo.proto",
+			},
+			Expected: &env{
+				flags: flags{
+					IncludeDirPaths: []string{
+						"bar",
+					},
+					ErrorFormat:                   defaultErrorFormat,
+					ImportDepth:                   defaultImportDepth,
+					PrintFreeFieldNumbers:         true,
+					PrintFreeFieldNumbersFormat:   "reserved",
+					PrintFreeFieldNumbersBaseline: filepath.Join("bar", "baseline.bin") + "#format=bin",
+					PluginManifest:                filepath.Join("bar", "manifest.json"),
+					PluginCacheDir:                filepath.Join("bar", "plugin_cache"),
+					ImageCacheDir:                 "/image_cache",
+					WorkingDir:                    "bar",
+				},
+				PluginNameToPluginInfo: map[string]*pluginInfo{
+					"foo": {
+						Out: filepath.Join("bar", "foo_out"),
+					},
+				},
+				FilePaths: []string{
+					filepath.Join("bar", "foo.proto"),
+				},
+			},
+		},
+		{
+			Args: []string{
+				"--working_dir=bar",
+				"-o",
+				"-",
+				"foo.proto",
+			},
+			Expected: &env{
+				flags: flags{
+					IncludeDirPaths: []string{
+						"bar",
+					},
+					ErrorFormat: defaultErrorFormat,
+					ImportDepth: defaultImportDepth,
+					Output:      "-",
+					WorkingDir:  "bar",
+				},
+				FilePaths: []string{
+					filepath.Join("bar", "foo.proto"),
+				},
+			},
+		},
+		{
+			Args: []string{
+				"--foo_out=bar",
+				"--plugin_strategy=directory",
+				"foo.proto",
+			},
+			Expected: &env{
+				flags: flags{
+					IncludeDirPaths: defaultIncludeDirPaths,
+					ErrorFormat:     defaultErrorFormat,
+					ImportDepth:     defaultImportDepth,
+					PluginStrategy:  pluginStrategyDirectory,
+				},
+				PluginNameToPluginInfo: map[string]*pluginInfo{
+					"foo": {
+						Out: "bar",
+					},
+				},
+				FilePaths: []string{
+					"foo.proto",
+				},
+			},
+		},
+		{
+			Args: []string{
+				"--foo_out=bar",
+				"--plugin_strategy=package",
+				"foo.proto",
+			},
+			ExpectedError: newPluginStrategyInvalidError("package"),
+		},
+		{
+			Args: []string{
+				"--public_imports=^acme/",
+				"--private_imports=^acme/internal/",
+				"foo.proto",
+			},
+			Expected: &env{
+				flags: flags{
+					IncludeDirPaths: defaultIncludeDirPaths,
+					ErrorFormat:     defaultErrorFormat,
+					ImportDepth:     defaultImportDepth,
+					PublicImports:   "^acme/",
+					PrivateImports:  "^acme/internal/",
+				},
+				FilePaths: []string{
+					"foo.proto",
+				},
+			},
+		},
+		{
+			Args: []string{
+				"--include_source_info",
+				"--strip_source_info",
+				"foo.proto",
+			},
+			Expected: &env{
+				flags: flags{
+					IncludeDirPaths:   defaultIncludeDirPaths,
+					ErrorFormat:       defaultErrorFormat,
+					ImportDepth:       defaultImportDepth,
+					IncludeSourceInfo: true,
+					StripSourceInfo:   true,
+				},
+				FilePaths: []string{
+					"foo.proto",
+				},
+			},
+		},
+		{
+			Args: []string{
+				"--plugin_path_dir=bin",
+				"--plugin_path_dir=/opt/plugins",
+				"foo.proto",
+			},
+			Expected: &env{
+				flags: flags{
+					IncludeDirPaths: defaultIncludeDirPaths,
+					ErrorFormat:     defaultErrorFormat,
+					ImportDepth:     defaultImportDepth,
+					PluginPathDirs: []string{
+						"bin",
+						"/opt/plugins",
+					},
+				},
+				FilePaths: []string{
+					"foo.proto",
+				},
+			},
+		},
+		{
+			Args: []string{
+				"--image_cache_dir=cache",
+				"foo.proto",
+			},
+			Expected: &env{
+				flags: flags{
+					IncludeDirPaths: defaultIncludeDirPaths,
+					ErrorFormat:     defaultErrorFormat,
+					ImportDepth:     defaultImportDepth,
+					ImageCacheDir:   "cache",
+				},
+				FilePaths: []string{
+					"foo.proto",
+				},
+			},
+		},
+		{
+			Args: []string{
+				"--shuffle_files=42",
+				"foo.proto",
+			},
+			Expected: &env{
+				flags: flags{
+					IncludeDirPaths: defaultIncludeDirPaths,
+					ErrorFormat:     defaultErrorFormat,
+					ImportDepth:     defaultImportDepth,
+					ShuffleFiles:    "42",
+				},
+				FilePaths: []string{
+					"foo.proto",
+				},
+				ShuffleFilesSeed: testInt64Ptr(42),
+			},
+		},
+		{
+			Args: []string{
+				"--shuffle_files=abc",
+				"foo.proto",
+			},
+			ExpectedError: newShuffleFilesInvalidError("abc"),
+		},
+		{
+			Args: []string{
+				"--check_only",
+				"foo.proto",
+			},
+			Expected: &env{
+				flags: flags{
+					IncludeDirPaths: defaultIncludeDirPaths,
+					ErrorFormat:     defaultErrorFormat,
+					ImportDepth:     defaultImportDepth,
+					CheckOnly:       true,
+				},
+				FilePaths: []string{
+					"foo.proto",
+				},
+			},
+		},
+		{
+			Args: []string{
+				"--error_on_bom",
+				"foo.proto",
+			},
+			Expected: &env{
+				flags: flags{
+					IncludeDirPaths: defaultIncludeDirPaths,
+					ErrorFormat:     defaultErrorFormat,
+					ImportDepth:     defaultImportDepth,
+					ErrorOnBOM:      true,
+				},
+				FilePaths: []string{
+					"foo.proto",
+				},
+			},
+		},
+		{
+			Args: []string{
+				"--verify",
+				"foo.proto",
+			},
+			Expected: &env{
+				flags: flags{
+					IncludeDirPaths: defaultIncludeDirPaths,
+					ErrorFormat:     defaultErrorFormat,
+					ImportDepth:     defaultImportDepth,
+					Verify:          true,
+				},
+				FilePaths: []string{
+					"foo.proto",
+				},
+			},
+		},
+		{
+			Args: []string{
+				"--relative_imports",
+				"foo.proto",
+			},
+			Expected: &env{
+				flags: flags{
+					IncludeDirPaths: defaultIncludeDirPaths,
+					ErrorFormat:     defaultErrorFormat,
+					ImportDepth:     defaultImportDepth,
+					RelativeImports: true,
+				},
+				FilePaths: []string{
+					"foo.proto",
+				},
+			},
+		},
 	}
 	for i, testCase := range testCases {
 		name := fmt.Sprintf("%d", i)
 		testCase := testCase
 		t.Run(name, func(t *testing.T) {
-			env, err := testParseFlags(name, testCase.Args)
+			var options []flagsBuilderOption
+			if testCase.CaseInsensitive {
+				options = append(options, flagsBuilderWithCaseInsensitive())
+			}
+			env, err := testParseFlags(name, testCase.Args, options...)
 			if testCase.ExpectedError != nil {
 				assert.Equal(t, testCase.ExpectedError, err)
 			} else {
@@ -276,8 +982,27 @@ func TestParseFlags(t *testing.T) {
 	}
 }
 
-func testParseFlags(name string, args []string) (*env, error) {
-	flagsBuilder := newFlagsBuilder()
+func TestParseFlagsCaseSensitiveError(t *testing.T) {
+	_, err := testParseFlags("test", []string{"--Include_Imports", "foo.proto"})
+	assert.Error(t, err)
+}
+
+func TestParseFlagsCaseSensitivePluginSuffixError(t *testing.T) {
+	// the plugin suffixes are case-sensitive by default, so --Foo_Out is the unknown flag Foo_Out
+	_, err := testParseFlags("test", []string{"--Foo_Out", "foo_out", "foo.proto"})
+	assert.Error(t, err)
+}
+
+func testFileModePtr(fileMode os.FileMode) *os.FileMode {
+	return &fileMode
+}
+
+func testInt64Ptr(value int64) *int64 {
+	return &value
+}
+
+func testParseFlags(name string, args []string, options ...flagsBuilderOption) (*env, error) {
+	flagsBuilder := newFlagsBuilder(options...)
 	flagSet := pflag.NewFlagSet(name, pflag.ContinueOnError)
 	flagsBuilder.Bind(flagSet)
 	flagSet.SetNormalizeFunc(normalizeFunc(flagsBuilder.Normalize))
@@ -0,0 +1,298 @@
+// Copyright 2020 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protoc
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bufbuild/buf/internal/buf/bufcore"
+	"github.com/bufbuild/buf/internal/buf/bufcore/bufcoreutil"
+	"github.com/bufbuild/buf/internal/pkg/protosource"
+)
+
+func printFreeFieldNumbers(
+	ctx context.Context,
+	writer io.Writer,
+	module bufcore.Module,
+	image bufcore.Image,
+	format string,
+	baselineImage bufcore.Image,
+) error {
+	switch format {
+	case "", "text":
+		s, err := bufcoreutil.FreeMessageRangeStrings(ctx, module, image)
+		if err != nil {
+			return err
+		}
+		_, err = writer.Write([]byte(strings.Join(s, "\n") + "\n"))
+		return err
+	case "json":
+		freeFieldNumberRanges, err := getFreeFieldNumberRanges(ctx, module, image)
+		if err != nil {
+			return err
+		}
+		for _, freeFieldNumberRange := range freeFieldNumberRanges {
+			data, err := json.Marshal(freeFieldNumberRange)
+			if err != nil {
+				return err
+			}
+			if _, err := writer.Write(append(data, '\n')); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "csv":
+		freeFieldNumberRanges, err := getFreeFieldNumberRanges(ctx, module, image)
+		if err != nil {
+			return err
+		}
+		csvWriter := csv.NewWriter(writer)
+		if err := csvWriter.Write([]string{"message", "free_start", "free_end"}); err != nil {
+			return err
+		}
+		for _, freeFieldNumberRange := range freeFieldNumberRanges {
+			if err := csvWriter.Write(
+				[]string{
+					freeFieldNumberRange.Message,
+					strconv.Itoa(freeFieldNumberRange.FreeStart),
+					strconv.Itoa(freeFieldNumberRange.FreeEnd),
+				},
+			); err != nil {
+				return err
+			}
+		}
+		csvWriter.Flush()
+		return csvWriter.Error()
+	case "reserved":
+		reservedSuggestions, err := getReservedSuggestions(ctx, module, image, baselineImage)
+		if err != nil {
+			return err
+		}
+		for _, reservedSuggestion := range reservedSuggestions {
+			if _, err := writer.Write([]byte(reservedSuggestion.String())); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return newPrintFreeFieldNumbersFormatInvalidError(format)
+	}
+}
+
+const (
+	// the field numbers reserved for the protobuf implementation
+	implementationReservedStart = 19000
+	implementationReservedEnd   = 19999
+)
+
+type freeFieldNumberRange struct {
+	Message   string `json:"message,omitempty" yaml:"message,omitempty"`
+	FreeStart int    `json:"free_start,omitempty" yaml:"free_start,omitempty"`
+	FreeEnd   int    `json:"free_end,omitempty" yaml:"free_end,omitempty"`
+}
+
+func getFreeFieldNumberRanges(
+	ctx context.Context,
+	module bufcore.Module,
+	image bufcore.Image,
+) ([]*freeFieldNumberRange, error) {
+	messageRanges, err := bufcoreutil.FreeMessageRanges(ctx, module, image)
+	if err != nil {
+		return nil, err
+	}
+	freeFieldNumberRanges := make([]*freeFieldNumberRange, len(messageRanges))
+	for i, messageRange := range messageRanges {
+		freeFieldNumberRanges[i] = &freeFieldNumberRange{
+			Message:   messageRange.Message().FullName(),
+			FreeStart: messageRange.Start(),
+			FreeEnd:   messageRange.End(),
+		}
+	}
+	return freeFieldNumberRanges, nil
+}
+
+// reservedSuggestion is a suggestion of reserved statements for a message.
+type reservedSuggestion struct {
+	Message string
+	// Each range is inclusive.
+	NumberRanges [][2]int
+	Names        []string
+}
+
+// String returns the reserved statements preceded by a comment with the message.
+func (r *reservedSuggestion) String() string {
+	var builder strings.Builder
+	_, _ = builder.WriteString("// " + r.Message + "\n")
+	if len(r.NumberRanges) > 0 {
+		numberRangeStrings := make([]string, len(r.NumberRanges))
+		for i, numberRange := range r.NumberRanges {
+			numberRangeStrings[i] = strconv.Itoa(numberRange[0])
+			if numberRange[1] != numberRange[0] {
+				numberRangeStrings[i] += " to " + strconv.Itoa(numberRange[1])
+			}
+		}
+		_, _ = builder.WriteString("reserved " + strings.Join(numberRangeStrings, ", ") + ";\n")
+	}
+	if len(r.Names) > 0 {
+		nameStrings := make([]string, len(r.Names))
+		for i, name := range r.Names {
+			nameStrings[i] = strconv.Quote(name)
+		}
+		_, _ = builder.WriteString("reserved " + strings.Join(nameStrings, ", ") + ";\n")
+	}
+	return builder.String()
+}
+
+// getReservedSuggestions gets the reserved suggestions for the messages of the target files.
+//
+// If baselineImage is nil, the numbers between the used numbers of each message are
+// suggested. Otherwise, the numbers and names of the fields of the same message in
+// baselineImage that are neither used nor reserved are suggested.
+func getReservedSuggestions(
+	ctx context.Context,
+	module bufcore.Module,
+	image bufcore.Image,
+	baselineImage bufcore.Image,
+) ([]*reservedSuggestion, error) {
+	messageRanges, err := bufcoreutil.FreeMessageRanges(ctx, module, image)
+	if err != nil {
+		return nil, err
+	}
+	var baselineFullNameToMessage map[string]protosource.Message
+	if baselineImage != nil {
+		baselineFullNameToMessage, err = getFullNameToMessage(baselineImage)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// the free ranges of a message are contiguous
+	var reservedSuggestions []*reservedSuggestion
+	for i := 0; i < len(messageRanges); {
+		message := messageRanges[i].Message()
+		var freeRanges []protosource.MessageRange
+		for ; i < len(messageRanges) && messageRanges[i].Message().FullName() == message.FullName(); i++ {
+			freeRanges = append(freeRanges, messageRanges[i])
+		}
+		reservedSuggestion := &reservedSuggestion{
+			Message: message.FullName(),
+		}
+		if baselineFullNameToMessage == nil {
+			for _, freeRange := range freeRanges {
+				if freeRange.Max() {
+					continue
+				}
+				reservedSuggestion.NumberRanges = append(
+					reservedSuggestion.NumberRanges,
+					withoutImplementationNumbers(freeRange.Start(), freeRange.End())...,
+				)
+			}
+		} else {
+			baselineMessage, ok := baselineFullNameToMessage[message.FullName()]
+			if !ok {
+				continue
+			}
+			names := make(map[string]struct{})
+			for _, field := range message.Fields() {
+				names[field.Name()] = struct{}{}
+			}
+			for _, reservedName := range message.ReservedNames() {
+				names[reservedName.Value()] = struct{}{}
+			}
+			baselineFields := append([]protosource.Field(nil), baselineMessage.Fields()...)
+			sort.Slice(baselineFields, func(i int, j int) bool {
+				return baselineFields[i].Number() < baselineFields[j].Number()
+			})
+			for _, baselineField := range baselineFields {
+				if numberInMessageRanges(baselineField.Number(), freeRanges) {
+					reservedSuggestion.NumberRanges = appendNumber(reservedSuggestion.NumberRanges, baselineField.Number())
+				}
+				if _, ok := names[baselineField.Name()]; !ok {
+					names[baselineField.Name()] = struct{}{}
+					reservedSuggestion.Names = append(reservedSuggestion.Names, baselineField.Name())
+				}
+			}
+		}
+		if len(reservedSuggestion.NumberRanges) > 0 || len(reservedSuggestion.Names) > 0 {
+			reservedSuggestions = append(reservedSuggestions, reservedSuggestion)
+		}
+	}
+	return reservedSuggestions, nil
+}
+
+func getFullNameToMessage(image bufcore.Image) (map[string]protosource.Message, error) {
+	fullNameToMessage := make(map[string]protosource.Message)
+	for _, imageFile := range image.Files() {
+		file, err := protosource.NewFile(imageFile)
+		if err != nil {
+			return nil, err
+		}
+		if err := protosource.ForEachMessage(
+			func(message protosource.Message) error {
+				fullNameToMessage[message.FullName()] = message
+				return nil
+			},
+			file,
+		); err != nil {
+			return nil, err
+		}
+	}
+	return fullNameToMessage, nil
+}
+
+// withoutImplementationNumbers returns the inclusive range without the numbers
+// reserved for the implementation, which cannot be used in reserved statements.
+func withoutImplementationNumbers(start int, end int) [][2]int {
+	var numberRanges [][2]int
+	if start < implementationReservedStart {
+		numberRangeEnd := end
+		if numberRangeEnd >= implementationReservedStart {
+			numberRangeEnd = implementationReservedStart - 1
+		}
+		numberRanges = append(numberRanges, [2]int{start, numberRangeEnd})
+	}
+	if end > implementationReservedEnd {
+		numberRangeStart := start
+		if numberRangeStart <= implementationReservedEnd {
+			numberRangeStart = implementationReservedEnd + 1
+		}
+		numberRanges = append(numberRanges, [2]int{numberRangeStart, end})
+	}
+	return numberRanges
+}
+
+func numberInMessageRanges(number int, messageRanges []protosource.MessageRange) bool {
+	for _, messageRange := range messageRanges {
+		if number >= messageRange.Start() && number <= messageRange.End() {
+			return true
+		}
+	}
+	return false
+}
+
+// appendNumber appends the number to the sorted inclusive ranges, extending the
+// last range if the number is adjacent to it.
+func appendNumber(numberRanges [][2]int, number int) [][2]int {
+	if last := len(numberRanges) - 1; last >= 0 && numberRanges[last][1]+1 == number {
+		numberRanges[last][1] = number
+		return numberRanges
+	}
+	return append(numberRanges, [2]int{number, number})
+}
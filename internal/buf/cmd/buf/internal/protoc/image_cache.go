@@ -0,0 +1,228 @@
+// Copyright 2020 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protoc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/bufbuild/buf/internal/buf/bufcore"
+	"github.com/bufbuild/buf/internal/gen/data/wkt"
+	imagev1 "github.com/bufbuild/buf/internal/gen/proto/go/v1/bufbuild/buf/image/v1"
+	"github.com/bufbuild/buf/internal/pkg/protoencoding"
+	"github.com/bufbuild/buf/internal/pkg/storage"
+	"github.com/bufbuild/buf/internal/pkg/storage/storageos"
+	"go.uber.org/multierr"
+)
+
+// imageCacheVersion is included in every key so that the cache can be
+// invalidated if the format of the cache ever changes.
+const imageCacheVersion = "v1"
+
+// imageCache is a content-addressed cache of built Images.
+//
+// The keys are computed with getImageCacheKey, so implementations only need
+// to store and retrieve Images by key. This allows the cache to be backed by
+// a shared volume, or by a remote store for distributed builds.
+type imageCache interface {
+	// Get gets the Image for the key.
+	//
+	// Returns false if there is no Image for the key.
+	Get(ctx context.Context, key string) (bufcore.Image, bool, error)
+	// Put puts the Image for the key.
+	Put(ctx context.Context, key string, image bufcore.Image) error
+}
+
+// fileImageCache is an imageCache backed by a directory.
+//
+// Each Image is stored in its own file named by the key, so concurrent
+// invocations sharing a cache directory only ever race to write identical data.
+type fileImageCache struct {
+	dirPath string
+}
+
+func newFileImageCache(dirPath string) *fileImageCache {
+	return &fileImageCache{
+		dirPath: dirPath,
+	}
+}
+
+func (c *fileImageCache) Get(ctx context.Context, key string) (bufcore.Image, bool, error) {
+	data, err := ioutil.ReadFile(c.path(key))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	// a corrupt entry is treated as a miss and will be overwritten
+	protoImage := &imagev1.Image{}
+	if err := protoencoding.NewWireUnmarshaler(nil).Unmarshal(data, protoImage); err != nil {
+		return nil, false, nil
+	}
+	image, err := bufcore.NewImageForProto(protoImage)
+	if err != nil {
+		return nil, false, nil
+	}
+	return image, true, nil
+}
+
+func (c *fileImageCache) Put(ctx context.Context, key string, image bufcore.Image) (retErr error) {
+	data, err := protoencoding.NewWireMarshaler().Marshal(bufcore.ImageToProtoImage(image))
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(c.dirPath, 0755); err != nil {
+		return err
+	}
+	// write to a temporary file first so that readers never see a partial entry
+	file, err := ioutil.TempFile(c.dirPath, "."+key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if retErr != nil {
+			retErr = multierr.Append(retErr, os.Remove(file.Name()))
+		}
+	}()
+	if _, err := file.Write(data); err != nil {
+		return multierr.Append(err, file.Close())
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), c.path(key))
+}
+
+func (c *fileImageCache) path(key string) string {
+	return filepath.Join(c.dirPath, key)
+}
+
+// getImageCacheKey returns the key for the build of the file paths with the include
+// directories.
+//
+// The key is a hash of the version of buf, the include directories and file paths
+// as given, the paths and contents of all .proto files within each include directory,
+// the paths and contents of the builtin well-known types if used, and the values
+// that affect the build, such as whether source code info is included. Relative
+// include directories and file paths result in the same key on different machines
+// for the same contents.
+func getImageCacheKey(
+	ctx context.Context,
+	bufVersion string,
+	includeDirPaths []string,
+	filePaths []string,
+	builtinWKT bool,
+	buildValues ...string,
+) (string, error) {
+	hash := sha256.New()
+	writeValue := func(value string) {
+		_, _ = hash.Write([]byte(value))
+		_, _ = hash.Write([]byte{0})
+	}
+	writeReadBucket := func(readBucket storage.ReadBucket) error {
+		var paths []string
+		if err := readBucket.Walk(
+			ctx,
+			"",
+			func(objectInfo storage.ObjectInfo) error {
+				paths = append(paths, objectInfo.Path())
+				return nil
+			},
+		); err != nil {
+			return err
+		}
+		sort.Strings(paths)
+		writeValue(strconv.Itoa(len(paths)))
+		for _, path := range paths {
+			data, err := storage.ReadPath(ctx, readBucket, path)
+			if err != nil {
+				return err
+			}
+			writeValue(path)
+			writeValue(strconv.Itoa(len(data)))
+			_, _ = hash.Write(data)
+		}
+		return nil
+	}
+	writeValue(imageCacheVersion)
+	writeValue(bufVersion)
+	for _, buildValue := range buildValues {
+		writeValue(buildValue)
+	}
+	writeValue(strconv.Itoa(len(filePaths)))
+	for _, filePath := range filePaths {
+		writeValue(filePath)
+	}
+	writeValue(strconv.Itoa(len(includeDirPaths)))
+	for _, includeDirPath := range includeDirPaths {
+		writeValue(includeDirPath)
+		readWriteBucket, err := storageos.NewReadWriteBucket(
+			includeDirPath,
+			storageos.ReadWriteBucketWithFollowSymlinks(),
+		)
+		if err != nil {
+			return "", err
+		}
+		if err := writeReadBucket(storage.Map(readWriteBucket, storage.MatchPathExt(".proto"))); err != nil {
+			return "", err
+		}
+	}
+	writeValue(strconv.FormatBool(builtinWKT))
+	if builtinWKT {
+		if err := writeReadBucket(wkt.ReadBucket); err != nil {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// getCachedImage gets the Image for the key from the imageCache, with the external
+// paths of the files in the Module.
+//
+// Returns nil if there is no Image for the key. The external paths are not part of
+// the cached Image, as the same Image may have been built on a different machine.
+func getCachedImage(ctx context.Context, imageCache imageCache, key string, module bufcore.Module) (bufcore.Image, error) {
+	image, ok, err := imageCache.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, nil
+	}
+	imageFiles := make([]bufcore.ImageFile, len(image.Files()))
+	for i, imageFile := range image.Files() {
+		fileInfo, err := module.GetFileInfo(ctx, imageFile.Path())
+		if err != nil {
+			if storage.IsNotExist(err) {
+				// builtin files such as the well-known types are not in the Module
+				imageFiles[i] = imageFile
+				continue
+			}
+			return nil, err
+		}
+		imageFiles[i], err = bufcore.NewImageFile(imageFile.Proto(), fileInfo.ExternalPath(), imageFile.IsImport())
+		if err != nil {
+			return nil, err
+		}
+	}
+	return bufcore.NewImage(imageFiles)
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/bufbuild/buf/internal/pkg/app"
 	"github.com/bufbuild/buf/internal/pkg/app/appproto"
 	"github.com/bufbuild/buf/internal/pkg/app/appproto/appprotoexec"
+	"github.com/bufbuild/buf/internal/pkg/instrument"
 	"github.com/bufbuild/buf/internal/pkg/storage/storageos"
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
@@ -36,12 +37,19 @@ type pluginInfo struct {
 	Opt string
 	// optional
 	Path string
+	// optional
+	//
+	// The environment variables set with --plugin_env.
+	Env map[string]string
 }
 
 func newPluginInfo() *pluginInfo {
 	return &pluginInfo{}
 }
 
+// executePlugin executes the plugin and writes the response files to the plugin output.
+//
+// The paths of the written files are returned, joined with the plugin output.
 func executePlugin(
 	ctx context.Context,
 	logger *zap.Logger,
@@ -49,23 +57,78 @@ func executePlugin(
 	image bufcore.Image,
 	pluginName string,
 	pluginInfo *pluginInfo,
-) error {
+	pluginEnvClean bool,
+	pluginCache *pluginCache,
+) ([]string, error) {
+	defer instrument.Start(logger, "plugin", zap.String("plugin", pluginName)).End()
 	handler, err := appprotoexec.NewHandler(logger, pluginName, "", pluginInfo.Path)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	request := bufcore.ImageToCodeGeneratorRequest(image, pluginInfo.Opt)
-	response, err := appproto.Execute(ctx, container, handler, request)
+	response, err := executePluginHandler(ctx, logger, container, handler, request, pluginName, pluginInfo, pluginEnvClean, pluginCache)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if errString := response.GetError(); errString != "" {
-		return fmt.Errorf("--%s_out: %s", pluginName, errString)
+		return nil, fmt.Errorf("--%s_out: %s", pluginName, errString)
 	}
 	if err := writeResponseFiles(ctx, response.File, pluginInfo.Out); err != nil {
-		return fmt.Errorf("--%s_out: %v", pluginName, err)
+		return nil, fmt.Errorf("--%s_out: %v", pluginName, err)
 	}
-	return nil
+	filePaths := make([]string, len(response.File))
+	for i, file := range response.File {
+		filePaths[i] = filepath.Join(pluginInfo.Out, filepath.FromSlash(file.GetName()))
+	}
+	return filePaths, nil
+}
+
+// executePluginHandler executes the handler, or replays the response from the
+// cache if the cache is non-nil and has a response for the request.
+//
+// The container is the container of the plugin, with the clean environment if pluginEnvClean is set.
+func executePluginHandler(
+	ctx context.Context,
+	logger *zap.Logger,
+	container app.EnvStderrContainer,
+	handler appproto.Handler,
+	request *pluginpb.CodeGeneratorRequest,
+	pluginName string,
+	pluginInfo *pluginInfo,
+	pluginEnvClean bool,
+	pluginCache *pluginCache,
+) (*pluginpb.CodeGeneratorResponse, error) {
+	if pluginCache == nil {
+		return appproto.Execute(ctx, container, handler, request)
+	}
+	pluginEnv := pluginInfo.Env
+	if pluginEnvClean {
+		// the clean environment is small and known, so all of it is part of the key
+		pluginEnv = app.EnvironMap(container)
+	}
+	key, err := pluginCache.Key(pluginName, pluginInfo.Path, pluginEnv, pluginEnvClean, request)
+	if err != nil {
+		return nil, err
+	}
+	response, ok, err := pluginCache.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		logger.Debug("plugin_cache_hit", zap.String("plugin", pluginName), zap.String("key", key))
+		return response, nil
+	}
+	response, err = appproto.Execute(ctx, container, handler, request)
+	if err != nil {
+		return nil, err
+	}
+	// errors are not cached so that they are always reported by the plugin itself
+	if response.GetError() == "" {
+		if err := pluginCache.Put(key, response); err != nil {
+			return nil, err
+		}
+	}
+	return response, nil
 }
 
 func writeResponseFiles(
@@ -0,0 +1,160 @@
+// Copyright 2020 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protoc
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+
+	"github.com/bufbuild/buf/internal/pkg/app"
+	"github.com/bufbuild/buf/internal/pkg/protoencoding"
+	"go.uber.org/multierr"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+// pluginCacheVersion is included in every key so that the cache can be
+// invalidated if the format of the cache ever changes.
+const pluginCacheVersion = "v1"
+
+// pluginCache is a content-addressed cache of CodeGeneratorResponses.
+//
+// Each response is stored in its own file named by the key, so concurrent
+// invocations sharing a cache directory only ever race to write identical data.
+type pluginCache struct {
+	dirPath string
+}
+
+func newPluginCache(dirPath string) *pluginCache {
+	return &pluginCache{
+		dirPath: dirPath,
+	}
+}
+
+// Key returns the key for the plugin and request.
+//
+// The request is marshaled deterministically, so identical requests have identical keys.
+// The content of the plugin binary is included, so replacing the binary is a miss.
+// If pluginEnvClean is set, pluginEnv is the complete environment of the plugin,
+// otherwise only the environment variables set for the plugin.
+func (c *pluginCache) Key(
+	pluginName string,
+	pluginPath string,
+	pluginEnv map[string]string,
+	pluginEnvClean bool,
+	request *pluginpb.CodeGeneratorRequest,
+) (string, error) {
+	requestData, err := protoencoding.NewWireMarshaler().Marshal(request)
+	if err != nil {
+		return "", err
+	}
+	pluginDigest, err := getPluginDigest(pluginName, pluginPath)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.New()
+	values := append(
+		[]string{pluginCacheVersion, pluginName, pluginPath, pluginDigest, strconv.FormatBool(pluginEnvClean)},
+		app.Environ(app.NewEnvContainer(pluginEnv))...,
+	)
+	for _, value := range values {
+		_, _ = hash.Write([]byte(value))
+		_, _ = hash.Write([]byte{0})
+	}
+	_, _ = hash.Write(requestData)
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Get gets the response for the key.
+//
+// Returns false if there is no response for the key.
+func (c *pluginCache) Get(key string) (*pluginpb.CodeGeneratorResponse, bool, error) {
+	data, err := ioutil.ReadFile(c.path(key))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	response := &pluginpb.CodeGeneratorResponse{}
+	if err := protoencoding.NewWireUnmarshaler(nil).Unmarshal(data, response); err != nil {
+		// a corrupt entry is treated as a miss and will be overwritten
+		return nil, false, nil
+	}
+	return response, true, nil
+}
+
+// Put puts the response for the key.
+func (c *pluginCache) Put(key string, response *pluginpb.CodeGeneratorResponse) (retErr error) {
+	data, err := protoencoding.NewWireMarshaler().Marshal(response)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(c.dirPath, 0755); err != nil {
+		return err
+	}
+	// write to a temporary file first so that readers never see a partial entry
+	file, err := ioutil.TempFile(c.dirPath, "."+key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if retErr != nil {
+			retErr = multierr.Append(retErr, os.Remove(file.Name()))
+		}
+	}()
+	if _, err := file.Write(data); err != nil {
+		return multierr.Append(err, file.Close())
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), c.path(key))
+}
+
+func (c *pluginCache) path(key string) string {
+	return filepath.Join(c.dirPath, key)
+}
+
+// getPluginDigest returns the hex-encoded SHA256 digest of the plugin binary
+// that appprotoexec.NewHandler resolves for the plugin name and path.
+//
+// Returns empty if there is no such binary, i.e. the plugin is proxied through protoc.
+func getPluginDigest(pluginName string, pluginPath string) (_ string, retErr error) {
+	if pluginPath == "" {
+		pluginPath = "protoc-gen-" + pluginName
+	}
+	binaryPath, err := exec.LookPath(pluginPath)
+	if err != nil {
+		return "", nil
+	}
+	file, err := os.Open(binaryPath)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		retErr = multierr.Append(retErr, file.Close())
+	}()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,113 @@
+// Copyright 2020 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protoc
+
+import (
+	"strings"
+
+	"github.com/bufbuild/buf/internal/pkg/app"
+)
+
+// pluginEnvEssentialKeys are the environment variables of this process that
+// are always passed to plugins with --plugin_env_clean.
+var pluginEnvEssentialKeys = []string{
+	"HOME",
+	"PATH",
+	"SYSTEMROOT",
+	"TEMP",
+	"TMP",
+	"TMPDIR",
+}
+
+// parsePluginEnv parses the --plugin_env values and sets the Env of each pluginInfo.
+//
+// Values in the form NAME=VALUE apply to all plugins, values in the form
+// plugin:NAME=VALUE apply to the named plugin only and take precedence.
+func parsePluginEnv(values []string, pluginNameToPluginInfo map[string]*pluginInfo) error {
+	globalEnv := make(map[string]string)
+	pluginNameToEnv := make(map[string]map[string]string)
+	for _, value := range values {
+		equalIndex := strings.Index(value, "=")
+		if equalIndex <= 0 {
+			return newPluginEnvInvalidError(value)
+		}
+		key := value[:equalIndex]
+		envValue := value[equalIndex+1:]
+		if colonIndex := strings.Index(key, ":"); colonIndex >= 0 {
+			pluginName := key[:colonIndex]
+			key = key[colonIndex+1:]
+			if pluginName == "" || key == "" {
+				return newPluginEnvInvalidError(value)
+			}
+			if pluginInfo, ok := pluginNameToPluginInfo[pluginName]; !ok || pluginInfo.Out == "" {
+				return newPluginEnvWithoutOutError(pluginName)
+			}
+			env, ok := pluginNameToEnv[pluginName]
+			if !ok {
+				env = make(map[string]string)
+				pluginNameToEnv[pluginName] = env
+			}
+			env[key] = envValue
+			continue
+		}
+		globalEnv[key] = envValue
+	}
+	for pluginName, pluginInfo := range pluginNameToPluginInfo {
+		env := make(map[string]string, len(globalEnv)+len(pluginNameToEnv[pluginName]))
+		for key, value := range globalEnv {
+			env[key] = value
+		}
+		for key, value := range pluginNameToEnv[pluginName] {
+			env[key] = value
+		}
+		if len(env) > 0 {
+			pluginInfo.Env = env
+		}
+	}
+	return nil
+}
+
+type pluginEnvStderrContainer struct {
+	app.EnvContainer
+	app.StderrContainer
+}
+
+// newPluginEnvStderrContainer returns a new app.EnvStderrContainer for a plugin.
+//
+// If clean is set, only the essential environment variables of the container
+// are passed, otherwise all are.
+func newPluginEnvStderrContainer(
+	container app.EnvStderrContainer,
+	env map[string]string,
+	clean bool,
+) app.EnvStderrContainer {
+	if len(env) == 0 && !clean {
+		return container
+	}
+	var envContainer app.EnvContainer = container
+	if clean {
+		essentialEnv := make(map[string]string, len(pluginEnvEssentialKeys))
+		for _, key := range pluginEnvEssentialKeys {
+			if value := container.Env(key); value != "" {
+				essentialEnv[key] = value
+			}
+		}
+		envContainer = app.NewEnvContainer(essentialEnv)
+	}
+	return &pluginEnvStderrContainer{
+		EnvContainer:    app.NewEnvContainerWithOverrides(envContainer, env),
+		StderrContainer: container,
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright 2020 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protoc
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"sort"
+)
+
+// writePluginManifest writes the JSON manifest of the files generated by each plugin.
+//
+// The manifest is an object from plugin name to the sorted paths of the files the
+// plugin generated, such as {"go":["gen/a.pb.go"]}.
+func writePluginManifest(manifestPath string, pluginNameToFilePaths map[string][]string) error {
+	for _, filePaths := range pluginNameToFilePaths {
+		sort.Strings(filePaths)
+	}
+	data, err := json.MarshalIndent(pluginNameToFilePaths, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(manifestPath, append(data, '\n'), 0644)
+}
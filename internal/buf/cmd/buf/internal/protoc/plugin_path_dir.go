@@ -0,0 +1,44 @@
+// Copyright 2020 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protoc
+
+import (
+	"os/exec"
+	"path/filepath"
+)
+
+// resolvePluginPathDirs sets the Path of each pluginInfo without a Path to the
+// first executable protoc-gen-NAME found in pluginPathDirs.
+//
+// Plugins that are not found in pluginPathDirs keep an empty Path, and are
+// resolved using $PATH when executed.
+func resolvePluginPathDirs(pluginNameToPluginInfo map[string]*pluginInfo, pluginPathDirs []string) {
+	if len(pluginPathDirs) == 0 {
+		return
+	}
+	for pluginName, pluginInfo := range pluginNameToPluginInfo {
+		if pluginInfo.Path != "" {
+			continue
+		}
+		for _, pluginPathDir := range pluginPathDirs {
+			// exec.LookPath does not consult $PATH for paths with a separator, and
+			// handles the executable extensions on Windows
+			if pluginPath, err := exec.LookPath(filepath.Join(pluginPathDir, "protoc-gen-"+pluginName)); err == nil {
+				pluginInfo.Path = pluginPath
+				break
+			}
+		}
+	}
+}
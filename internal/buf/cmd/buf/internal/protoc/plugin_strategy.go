@@ -0,0 +1,60 @@
+// Copyright 2020 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protoc
+
+import (
+	"sort"
+
+	"github.com/bufbuild/buf/internal/buf/bufcore"
+	"github.com/bufbuild/buf/internal/pkg/normalpath"
+)
+
+const (
+	pluginStrategyAggregate = "aggregate"
+	pluginStrategyDirectory = "directory"
+)
+
+// getPluginImages returns the Images to invoke a plugin with for the plugin strategy.
+//
+// For the default aggregate strategy, this is the Image itself. For the directory
+// strategy, this is an Image per directory that contains non-import files, sorted
+// by directory, where only the files in the directory are not imports.
+func getPluginImages(image bufcore.Image, pluginStrategy string) ([]bufcore.Image, error) {
+	if pluginStrategy != pluginStrategyDirectory {
+		return []bufcore.Image{image}, nil
+	}
+	dirToPaths := make(map[string][]string)
+	for _, imageFile := range image.Files() {
+		if imageFile.IsImport() {
+			continue
+		}
+		dir := normalpath.Dir(imageFile.Path())
+		dirToPaths[dir] = append(dirToPaths[dir], imageFile.Path())
+	}
+	dirs := make([]string, 0, len(dirToPaths))
+	for dir := range dirToPaths {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+	images := make([]bufcore.Image, len(dirs))
+	for i, dir := range dirs {
+		dirImage, err := bufcore.ImageWithOnlyPaths(image, dirToPaths[dir])
+		if err != nil {
+			return nil, err
+		}
+		images[i] = dirImage
+	}
+	return images, nil
+}
@@ -0,0 +1,57 @@
+// Copyright 2020 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protoc
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+)
+
+type printedPlugin struct {
+	Name string            `json:"name,omitempty" yaml:"name,omitempty"`
+	Out  string            `json:"out,omitempty" yaml:"out,omitempty"`
+	Opt  string            `json:"opt,omitempty" yaml:"opt,omitempty"`
+	Path string            `json:"path,omitempty" yaml:"path,omitempty"`
+	Env  map[string]string `json:"env,omitempty" yaml:"env,omitempty"`
+}
+
+// printPlugins prints the resolved plugins as JSON, one plugin per line, sorted by name.
+func printPlugins(writer io.Writer, pluginNameToPluginInfo map[string]*pluginInfo) error {
+	pluginNames := make([]string, 0, len(pluginNameToPluginInfo))
+	for pluginName := range pluginNameToPluginInfo {
+		pluginNames = append(pluginNames, pluginName)
+	}
+	sort.Strings(pluginNames)
+	for _, pluginName := range pluginNames {
+		pluginInfo := pluginNameToPluginInfo[pluginName]
+		data, err := json.Marshal(
+			&printedPlugin{
+				Name: pluginName,
+				Out:  pluginInfo.Out,
+				Opt:  pluginInfo.Opt,
+				Path: pluginInfo.Path,
+				Env:  pluginInfo.Env,
+			},
+		)
+		if err != nil {
+			return err
+		}
+		if _, err := writer.Write(append(data, '\n')); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+// Copyright 2020 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protoc
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/bufbuild/buf/internal/pkg/app"
+)
+
+// nonTerminalProgressSteps is the number of progress lines printed when
+// stderr is not a terminal, not counting the initial line.
+const nonTerminalProgressSteps = 10
+
+// newProgressFunc returns a function to give to bufbuild.WithProgressFunc
+// that prints progress to the writer.
+//
+// If the writer is a terminal, a single line is updated in place. Otherwise,
+// periodic counts are printed on separate lines so that log files are not
+// flooded. Write errors are ignored, as progress is best-effort.
+func newProgressFunc(writer io.Writer) func(int, int) {
+	if app.IsTerminal(writer) {
+		return func(parsed int, total int) {
+			_, _ = fmt.Fprintf(writer, "\rParsed %d/%d files", parsed, total)
+			if parsed == total {
+				_, _ = fmt.Fprintln(writer)
+			}
+		}
+	}
+	lastStep := -1
+	return func(parsed int, total int) {
+		step := parsed * nonTerminalProgressSteps / total
+		if step == lastStep && parsed != total {
+			return
+		}
+		lastStep = step
+		_, _ = fmt.Fprintf(writer, "Parsed %d/%d files\n", parsed, total)
+	}
+}
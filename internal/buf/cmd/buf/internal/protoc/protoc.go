@@ -18,24 +18,35 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/bufbuild/buf/internal/buf/bufanalysis"
 	"github.com/bufbuild/buf/internal/buf/bufbuild"
-	"github.com/bufbuild/buf/internal/buf/bufcore/bufcoreutil"
+	"github.com/bufbuild/buf/internal/buf/bufcore"
 	"github.com/bufbuild/buf/internal/buf/bufmod"
+	"github.com/bufbuild/buf/internal/buf/bufwire"
 	"github.com/bufbuild/buf/internal/buf/cmd/internal"
 	"github.com/bufbuild/buf/internal/pkg/app"
 	"github.com/bufbuild/buf/internal/pkg/app/appcmd"
 	"github.com/bufbuild/buf/internal/pkg/app/appflag"
 	"github.com/bufbuild/buf/internal/pkg/app/applog"
+	"github.com/bufbuild/buf/internal/pkg/stringutil"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
 // NewCommand returns a new Command.
-func NewCommand(use string, builder appflag.Builder) *appcmd.Command {
-	flagsBuilder := newFlagsBuilder()
+func NewCommand(use string, builder appflag.Builder, options ...CommandOption) *appcmd.Command {
+	commandOptions := newCommandOptions()
+	for _, option := range options {
+		option(commandOptions)
+	}
+	var flagsBuilderOptions []flagsBuilderOption
+	if commandOptions.caseInsensitiveFlags {
+		flagsBuilderOptions = append(flagsBuilderOptions, flagsBuilderWithCaseInsensitive())
+	}
+	flagsBuilder := newFlagsBuilder(flagsBuilderOptions...)
 	return &appcmd.Command{
 		Use:   use,
 		Short: "High-performance protoc replacement.",
@@ -49,14 +60,19 @@ Additional flags:
 
       --(.*)_out:                   Run the named plugin.
       --(.*)_opt:                   Options for the named plugin.
-      @filename:                    Parse arguments from the given filename.`,
+      @filename:                    Parse arguments from the given filename. Multiple filenames
+                                    can be given as @filename1,@filename2.
+      --:                           Treat all following arguments as file paths.
+
+Flag names are matched case-insensitively, for example --Include_Imports or --GO_OUT,
+if the environment variable ` + CaseInsensitiveFlagsEnvKey + ` is set to true.`,
 		Run: builder.NewRunFunc(
 			func(ctx context.Context, container applog.Container) error {
 				env, err := flagsBuilder.Build(app.Args(container))
 				if err != nil {
 					return err
 				}
-				return run(ctx, container, env)
+				return run(ctx, container, env, commandOptions.bufVersion)
 			},
 		),
 		BindFlags:     flagsBuilder.Bind,
@@ -65,7 +81,44 @@ Additional flags:
 	}
 }
 
-func run(ctx context.Context, container applog.Container, env *env) (retErr error) {
+// CaseInsensitiveFlagsEnvKey is the environment variable that enables
+// CommandWithCaseInsensitiveFlags for the protoc command of the buf binary.
+const CaseInsensitiveFlagsEnvKey = "BUF_PROTOC_CASE_INSENSITIVE_FLAGS"
+
+// CommandOption is an option for a new Command.
+type CommandOption func(*commandOptions)
+
+// CommandWithCaseInsensitiveFlags returns a new CommandOption that matches
+// flag names case-insensitively, for example --Include_Imports or --GO_OUT.
+//
+// The plugin names of the --(.*)_out and --(.*)_opt flags keep their case.
+// The default is to match flag names case-sensitively.
+func CommandWithCaseInsensitiveFlags() CommandOption {
+	return func(commandOptions *commandOptions) {
+		commandOptions.caseInsensitiveFlags = true
+	}
+}
+
+// CommandWithBufVersion returns a new CommandOption that sets the version of buf.
+//
+// The version is part of the keys of the image cache, so that images built by
+// another version of buf are not used.
+func CommandWithBufVersion(bufVersion string) CommandOption {
+	return func(commandOptions *commandOptions) {
+		commandOptions.bufVersion = bufVersion
+	}
+}
+
+type commandOptions struct {
+	caseInsensitiveFlags bool
+	bufVersion           string
+}
+
+func newCommandOptions() *commandOptions {
+	return &commandOptions{}
+}
+
+func run(ctx context.Context, container applog.Container, env *env, bufVersion string) (retErr error) {
 	if env.PrintFreeFieldNumbers && len(env.PluginNameToPluginInfo) > 0 {
 		return fmt.Errorf("cannot call --%s and plugins at the same time", printFreeFieldNumbersFlagName)
 	}
@@ -75,6 +128,27 @@ func run(ctx context.Context, container applog.Container, env *env) (retErr erro
 	if len(env.PluginNameToPluginInfo) > 0 && env.Output != "" {
 		return fmt.Errorf("cannot call --%s and plugins at the same time", outputFlagName)
 	}
+	if len(env.PluginNameToPluginInfo) > 0 && env.Verify {
+		return fmt.Errorf("cannot call --%s and plugins at the same time", verifyFlagName)
+	}
+	if len(env.PluginNameToPluginInfo) == 0 && env.PluginManifest != "" {
+		return fmt.Errorf("cannot call --%s without plugins", pluginManifestFlagName)
+	}
+	if env.PrintFreeFieldNumbersBaseline != "" && (!env.PrintFreeFieldNumbers || env.PrintFreeFieldNumbersFormat != "reserved") {
+		return newPrintFreeFieldNumbersBaselineWithoutReservedError()
+	}
+	if env.CheckOnly && env.PrintFreeFieldNumbers {
+		return fmt.Errorf("cannot call --%s and --%s at the same time", checkOnlyFlagName, printFreeFieldNumbersFlagName)
+	}
+	if env.CheckOnly && env.Verify {
+		return fmt.Errorf("cannot call --%s and --%s at the same time", checkOnlyFlagName, verifyFlagName)
+	}
+	if env.Output == "" && (env.PublicImports != "" || env.PrivateImports != "") {
+		return fmt.Errorf("cannot call --%s or --%s without --%s", publicImportsFlagName, privateImportsFlagName, outputFlagName)
+	}
+	if len(env.PluginNameToPluginInfo) == 0 && (len(env.PluginEnv) > 0 || env.PluginEnvClean) {
+		return fmt.Errorf("cannot call --%s or --%s without plugins", pluginEnvFlagName, pluginEnvCleanFlagName)
+	}
 
 	if checkedEntry := container.Logger().Check(zapcore.DebugLevel, "env"); checkedEntry != nil {
 		checkedEntry.Write(
@@ -82,6 +156,9 @@ func run(ctx context.Context, container applog.Container, env *env) (retErr erro
 			zap.Any("plugins", env.PluginNameToPluginInfo),
 		)
 	}
+	if env.PrintPlugins {
+		return printPlugins(container.Stdout(), env.PluginNameToPluginInfo)
+	}
 
 	module, err := bufmod.NewIncludeBuilder(container.Logger()).BuildForIncludes(
 		ctx,
@@ -92,63 +169,217 @@ func run(ctx context.Context, container applog.Container, env *env) (retErr erro
 		return err
 	}
 	var buildOptions []bufbuild.BuildOption
-	// we always need source code info if we are doing generation
-	if len(env.PluginNameToPluginInfo) == 0 && !env.IncludeSourceInfo {
+	// we always need source code info if we are doing generation or linting
+	excludeSourceCodeInfo := len(env.PluginNameToPluginInfo) == 0 && !env.CheckOnly && !env.IncludeSourceInfo
+	if excludeSourceCodeInfo {
 		buildOptions = append(buildOptions, bufbuild.WithExcludeSourceCodeInfo())
 	}
-	image, fileAnnotations, err := bufbuild.NewBuilder(container.Logger()).Build(
-		ctx,
-		module,
-		buildOptions...,
-	)
-	if err != nil {
-		return err
+	if env.Progress {
+		buildOptions = append(buildOptions, bufbuild.WithProgressFunc(newProgressFunc(container.Stderr())))
+	}
+	if env.NoBuiltinWKT {
+		buildOptions = append(buildOptions, bufbuild.WithoutBuiltinWKT())
 	}
-	if len(fileAnnotations) > 0 {
-		if err := bufanalysis.PrintFileAnnotations(
-			container.Stderr(),
-			fileAnnotations,
-			env.ErrorFormat,
-		); err != nil {
+	if env.ErrorOnBOM {
+		buildOptions = append(buildOptions, bufbuild.WithErrorOnBOM())
+	}
+	if env.RelativeImports {
+		buildOptions = append(buildOptions, bufbuild.WithRelativeImports())
+	}
+	var buildImageCache imageCache
+	var imageCacheKey string
+	var image bufcore.Image
+	if env.ImageCacheDir != "" {
+		buildImageCache = newFileImageCache(env.ImageCacheDir)
+		imageCacheKey, err = getImageCacheKey(
+			ctx,
+			bufVersion,
+			env.IncludeDirPaths,
+			env.FilePaths,
+			!env.NoBuiltinWKT,
+			strconv.FormatBool(excludeSourceCodeInfo),
+			strconv.FormatBool(env.ErrorOnBOM),
+			strconv.FormatBool(env.RelativeImports),
+		)
+		if err != nil {
 			return err
 		}
-		return errors.New("")
-	}
-
-	if env.PrintFreeFieldNumbers {
-		s, err := bufcoreutil.FreeMessageRangeStrings(ctx, module, image)
+		image, err = getCachedImage(ctx, buildImageCache, imageCacheKey, module)
 		if err != nil {
 			return err
 		}
-		if _, err := container.Stdout().Write([]byte(strings.Join(s, "\n") + "\n")); err != nil {
+		if image != nil {
+			container.Logger().Debug("image_cache_hit", zap.String("key", imageCacheKey))
+		}
+	}
+	if image == nil {
+		var fileAnnotations []bufanalysis.FileAnnotation
+		image, fileAnnotations, err = bufbuild.NewBuilder(container.Logger()).Build(
+			ctx,
+			module,
+			buildOptions...,
+		)
+		if err != nil {
 			return err
 		}
-		return nil
+		if len(fileAnnotations) > 0 {
+			if err := bufanalysis.PrintFileAnnotations(
+				container.Stderr(),
+				fileAnnotations,
+				env.ErrorFormat,
+			); err != nil {
+				return err
+			}
+			return errors.New("")
+		}
+		// only successful builds are cached so that errors are always reported
+		if buildImageCache != nil {
+			if err := buildImageCache.Put(ctx, imageCacheKey, image); err != nil {
+				return err
+			}
+		}
+	}
+
+	if env.CheckOnly {
+		return checkOnly(ctx, container, image, env.WorkingDir, env.ErrorFormat)
+	}
+	if env.PrintFreeFieldNumbers {
+		var baselineImage bufcore.Image
+		if env.PrintFreeFieldNumbersBaseline != "" {
+			baselineImage, err = internal.NewBufwireImageReader(
+				container.Logger(),
+				printFreeFieldNumbersBaselineFlagName,
+			).GetImage(
+				ctx,
+				container,
+				env.PrintFreeFieldNumbersBaseline,
+				nil,
+				false,
+				true,
+			)
+			if err != nil {
+				return err
+			}
+		}
+		return printFreeFieldNumbers(
+			ctx,
+			container.Stdout(),
+			module,
+			image,
+			env.PrintFreeFieldNumbersFormat,
+			baselineImage,
+		)
 	}
 	if len(env.PluginNameToPluginInfo) > 0 {
+		resolvePluginPathDirs(env.PluginNameToPluginInfo, env.PluginPathDirs)
+		var cache *pluginCache
+		if env.PluginCacheDir != "" {
+			cache = newPluginCache(env.PluginCacheDir)
+		}
+		pluginCacheDisable := stringutil.SliceToMap(env.PluginCacheDisable)
+		pluginDescriptorSet := stringutil.SliceToMap(env.PluginDescriptorSet)
+		pluginNameToFilePaths := make(map[string][]string)
 		// TODO: parallel
 		for pluginName, pluginInfo := range env.PluginNameToPluginInfo {
-			if err := executePlugin(
-				ctx,
-				container.Logger(),
-				container,
-				image,
-				pluginName,
-				pluginInfo,
-			); err != nil {
+			pluginContainer := newPluginEnvStderrContainer(container, pluginInfo.Env, env.PluginEnvClean)
+			if _, ok := pluginDescriptorSet[pluginName]; ok {
+				descriptorSetImage := bufcore.ImageWithoutImports(image)
+				if env.IncludeImports {
+					descriptorSetImage = bufcore.ImageWithImportDepth(image, env.ImportDepth)
+				}
+				if err := executeDescriptorSetPlugin(
+					ctx,
+					container.Logger(),
+					pluginContainer,
+					descriptorSetImage,
+					pluginName,
+					pluginInfo,
+				); err != nil {
+					return err
+				}
+				continue
+			}
+			pluginNameCache := cache
+			if _, ok := pluginCacheDisable[pluginName]; ok {
+				pluginNameCache = nil
+			}
+			pluginImages, err := getPluginImages(image, env.PluginStrategy)
+			if err != nil {
 				return err
 			}
+			for _, pluginImage := range pluginImages {
+				filePaths, err := executePlugin(
+					ctx,
+					container.Logger(),
+					pluginContainer,
+					pluginImage,
+					pluginName,
+					pluginInfo,
+					env.PluginEnvClean,
+					pluginNameCache,
+				)
+				if err != nil {
+					return err
+				}
+				pluginNameToFilePaths[pluginName] = append(pluginNameToFilePaths[pluginName], filePaths...)
+			}
+		}
+		if env.PluginManifest != "" {
+			return writePluginManifest(env.PluginManifest, pluginNameToFilePaths)
 		}
 		return nil
 	}
 	if env.Output == "" {
 		return fmt.Errorf("--%s is required", outputFlagName)
 	}
+	if env.IncludeImports {
+		image = bufcore.ImageWithImportDepth(image, env.ImportDepth)
+	}
+	if env.PublicImports != "" || env.PrivateImports != "" {
+		image, err = rewriteImports(image, env.PublicImports, env.PrivateImports)
+		if err != nil {
+			return err
+		}
+	}
+	if env.StripSourceInfo {
+		image, err = stripSourceInfo(image)
+		if err != nil {
+			return err
+		}
+	}
+	if env.ShuffleFilesSeed != nil {
+		image, err = shuffleFiles(image, *env.ShuffleFilesSeed)
+		if err != nil {
+			return err
+		}
+	}
+	output := env.Output
+	if env.OutputFormat != "" {
+		if strings.Contains(output, "#") {
+			return newOutputFormatWithOptionsError()
+		}
+		output = output + "#format=" + env.OutputFormat
+	}
+	var putImageOptions []bufwire.PutImageOption
+	if env.OutputFileMode != nil {
+		putImageOptions = append(putImageOptions, bufwire.PutImageWithFileMode(*env.OutputFileMode))
+	}
+	if env.Verify {
+		return verifyOutput(
+			ctx,
+			container,
+			output,
+			image,
+			!env.IncludeImports,
+			putImageOptions...,
+		)
+	}
 	return internal.NewBufwireImageWriter(container.Logger()).PutImage(ctx,
 		container,
-		env.Output,
+		output,
 		image,
 		true,
 		!env.IncludeImports,
+		putImageOptions...,
 	)
 }
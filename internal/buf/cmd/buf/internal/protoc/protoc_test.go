@@ -17,12 +17,17 @@ package protoc
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/bufbuild/buf/internal/buf/bufcore"
 	"github.com/bufbuild/buf/internal/buf/internal/buftesting"
+	"github.com/bufbuild/buf/internal/pkg/app"
 	"github.com/bufbuild/buf/internal/pkg/app/appcmd"
 	"github.com/bufbuild/buf/internal/pkg/app/appcmd/appcmdtesting"
 	"github.com/bufbuild/buf/internal/pkg/app/appflag"
@@ -33,6 +38,7 @@ import (
 	"github.com/bufbuild/buf/internal/pkg/tmp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/descriptorpb"
 )
 
@@ -82,6 +88,980 @@ func TestComparePrintFreeFieldNumbersGoogleapis(t *testing.T) {
 	)
 }
 
+func TestPrintFreeFieldNumbersText(t *testing.T) {
+	t.Parallel()
+	testPrintFreeFieldNumbers(
+		t,
+		"",
+		`a.Foo.Bar                           free: 2 6-INF
+a.Foo                               free: 1 3-INF
+`,
+	)
+}
+
+func TestPrintFreeFieldNumbersJSON(t *testing.T) {
+	t.Parallel()
+	testPrintFreeFieldNumbers(
+		t,
+		"json",
+		`{"message":"a.Foo.Bar","free_start":2,"free_end":2}
+{"message":"a.Foo.Bar","free_start":6,"free_end":536870911}
+{"message":"a.Foo","free_start":1,"free_end":1}
+{"message":"a.Foo","free_start":3,"free_end":536870911}
+`,
+	)
+}
+
+func TestPrintFreeFieldNumbersCSV(t *testing.T) {
+	t.Parallel()
+	testPrintFreeFieldNumbers(
+		t,
+		"csv",
+		`message,free_start,free_end
+a.Foo.Bar,2,2
+a.Foo.Bar,6,536870911
+a.Foo,1,1
+a.Foo,3,536870911
+`,
+	)
+}
+
+func TestPrintFreeFieldNumbersReserved(t *testing.T) {
+	t.Parallel()
+	expectedStdout := `// a.Foo.Bar
+reserved 2;
+// a.Foo
+reserved 1;
+`
+	testPrintFreeFieldNumbers(t, "reserved", expectedStdout)
+	testReservedStatementsCompile(t, expectedStdout)
+}
+
+func TestPrintFreeFieldNumbersReservedBaseline(t *testing.T) {
+	t.Parallel()
+	tmpDir, err := tmp.NewDir("")
+	require.NoError(t, err)
+	baselineFilePath := filepath.Join(tmpDir.AbsPath(), "baseline.bin")
+	appcmdtesting.RunCommandSuccess(
+		t,
+		func(use string) *appcmd.Command {
+			return NewCommand(
+				use,
+				appflag.NewBuilder(),
+			)
+		},
+		nil,
+		nil,
+		nil,
+		"-I",
+		filepath.Join("testdata", "freefieldnumbersbaseline"),
+		"-o",
+		baselineFilePath,
+		filepath.Join("testdata", "freefieldnumbersbaseline", "a.proto"),
+	)
+	// a.Deleted is not in the current image so nothing is suggested for it
+	expectedStdout := `// a.Foo.Bar
+reserved 2;
+reserved "two_old";
+// a.Foo
+reserved 1, 5 to 6;
+reserved "one_old", "five", "six";
+`
+	testPrintFreeFieldNumbers(
+		t,
+		"reserved",
+		expectedStdout,
+		fmt.Sprintf("--%s=%s", printFreeFieldNumbersBaselineFlagName, baselineFilePath),
+	)
+	testReservedStatementsCompile(t, expectedStdout)
+	require.NoError(t, tmpDir.Close())
+}
+
+func TestPrintFreeFieldNumbersBaselineWithoutReserved(t *testing.T) {
+	t.Parallel()
+	stderr := testRunVerify(
+		t,
+		1,
+		"-I",
+		filepath.Join("testdata", "freefieldnumbers"),
+		fmt.Sprintf("--%s", printFreeFieldNumbersFlagName),
+		fmt.Sprintf("--%s=baseline.bin", printFreeFieldNumbersBaselineFlagName),
+		filepath.Join("testdata", "freefieldnumbers", "a.proto"),
+	)
+	assert.Contains(t, stderr, newPrintFreeFieldNumbersBaselineWithoutReservedError().Error())
+}
+
+func TestPrintPlugins(t *testing.T) {
+	t.Parallel()
+	// the plugins are defined across both argument files, and nothing is built
+	appcmdtesting.RunCommandSuccessStdout(
+		t,
+		func(use string) *appcmd.Command {
+			return NewCommand(
+				use,
+				appflag.NewBuilder(),
+			)
+		},
+		`{"name":"go","out":"go_out","opt":"plugins=grpc","path":"/bin/protoc-gen-go","env":{"FOO":"bar"}}
+{"name":"java","out":"java_out","opt":"lite"}
+`,
+		nil,
+		nil,
+		"@"+filepath.Join("testdata", "6", "flags1.txt")+",@"+filepath.Join("testdata", "6", "flags2.txt"),
+		fmt.Sprintf("--%s", printPluginsFlagName),
+		fmt.Sprintf("--%s=go:FOO=bar", pluginEnvFlagName),
+		"foo.proto",
+	)
+}
+
+func TestProgressFuncNonTerminal(t *testing.T) {
+	t.Parallel()
+	buffer := bytes.NewBuffer(nil)
+	progressFunc := newProgressFunc(buffer)
+	for i := 1; i <= 25; i++ {
+		progressFunc(i, 25)
+	}
+	assert.Equal(
+		t,
+		`Parsed 1/25 files
+Parsed 3/25 files
+Parsed 5/25 files
+Parsed 8/25 files
+Parsed 10/25 files
+Parsed 13/25 files
+Parsed 15/25 files
+Parsed 18/25 files
+Parsed 20/25 files
+Parsed 23/25 files
+Parsed 25/25 files
+`,
+		buffer.String(),
+	)
+}
+
+func TestOutputMode(t *testing.T) {
+	t.Parallel()
+	tmpDir, err := tmp.NewDir("")
+	require.NoError(t, err)
+	outputFilePath := filepath.Join(tmpDir.AbsPath(), "image.bin")
+	appcmdtesting.RunCommandSuccess(
+		t,
+		func(use string) *appcmd.Command {
+			return NewCommand(
+				use,
+				appflag.NewBuilder(),
+			)
+		},
+		nil,
+		nil,
+		nil,
+		"-I",
+		filepath.Join("testdata", "freefieldnumbers"),
+		"-o",
+		outputFilePath,
+		fmt.Sprintf("--%s=0640", outputModeFlagName),
+		filepath.Join("testdata", "freefieldnumbers", "a.proto"),
+	)
+	fileInfo, err := os.Stat(outputFilePath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0640), fileInfo.Mode().Perm())
+	require.NoError(t, tmpDir.Close())
+}
+
+func TestOutputFormatBin(t *testing.T) {
+	t.Parallel()
+	stdout := testRunOutputFormat(t, "bin")
+	fileDescriptorSet := &descriptorpb.FileDescriptorSet{}
+	require.NoError(t, protoencoding.NewWireUnmarshaler(nil).Unmarshal(stdout, fileDescriptorSet))
+	require.Len(t, fileDescriptorSet.File, 1)
+	assert.Equal(t, "a.proto", fileDescriptorSet.File[0].GetName())
+}
+
+func TestOutputFormatJSON(t *testing.T) {
+	t.Parallel()
+	stdout := testRunOutputFormat(t, "json")
+	fileDescriptorSet := &descriptorpb.FileDescriptorSet{}
+	require.NoError(t, protoencoding.NewJSONUnmarshaler(nil).Unmarshal(stdout, fileDescriptorSet))
+	require.Len(t, fileDescriptorSet.File, 1)
+	assert.Equal(t, "a.proto", fileDescriptorSet.File[0].GetName())
+}
+
+func TestOutputFileOrderDeterministic(t *testing.T) {
+	t.Parallel()
+	aFilePath := filepath.Join("testdata", "checkonly", "a", "v1", "a.proto")
+	bFilePath := filepath.Join("testdata", "checkonly", "b", "v1", "b.proto")
+	data := testRunOutputFileOrder(t, aFilePath, bFilePath)
+	// building the same input twice must produce the same bytes
+	assert.Equal(t, data, testRunOutputFileOrder(t, aFilePath, bFilePath))
+	// the order of the file paths on the command line does not affect the output
+	assert.Equal(t, data, testRunOutputFileOrder(t, bFilePath, aFilePath))
+	fileDescriptorSet := &descriptorpb.FileDescriptorSet{}
+	require.NoError(t, protoencoding.NewWireUnmarshaler(nil).Unmarshal(data, fileDescriptorSet))
+	fileNames := make([]string, 0, len(fileDescriptorSet.File))
+	for _, fileDescriptorProto := range fileDescriptorSet.File {
+		fileNames = append(fileNames, fileDescriptorProto.GetName())
+	}
+	assert.Equal(t, []string{"a/v1/a.proto", "b/v1/b.proto"}, fileNames)
+}
+
+func TestShuffleFiles(t *testing.T) {
+	t.Parallel()
+	imageFiles := make([]bufcore.ImageFile, 0, 10)
+	fileNames := make([]string, 0, 10)
+	for i := 0; i < 10; i++ {
+		fileName := fmt.Sprintf("%d.proto", i)
+		imageFile, err := bufcore.NewImageFile(
+			&descriptorpb.FileDescriptorProto{
+				Name:   proto.String(fileName),
+				Syntax: proto.String("proto3"),
+			},
+			"",
+			false,
+		)
+		require.NoError(t, err)
+		imageFiles = append(imageFiles, imageFile)
+		fileNames = append(fileNames, fileName)
+	}
+	image, err := bufcore.NewImage(imageFiles)
+	require.NoError(t, err)
+	shuffledImage, err := shuffleFiles(image, 1)
+	require.NoError(t, err)
+	shuffledFileNames := testImageFileNames(shuffledImage)
+	// the same seed yields the same permutation
+	for i := 0; i < 3; i++ {
+		otherShuffledImage, err := shuffleFiles(image, 1)
+		require.NoError(t, err)
+		assert.Equal(t, shuffledFileNames, testImageFileNames(otherShuffledImage))
+	}
+	assert.NotEqual(t, fileNames, shuffledFileNames)
+	assert.ElementsMatch(t, fileNames, shuffledFileNames)
+	otherShuffledImage, err := shuffleFiles(image, 2)
+	require.NoError(t, err)
+	assert.NotEqual(t, shuffledFileNames, testImageFileNames(otherShuffledImage))
+	// the input Image is not modified
+	assert.Equal(t, fileNames, testImageFileNames(image))
+}
+
+func TestShuffleFilesOutput(t *testing.T) {
+	t.Parallel()
+	aFilePath := filepath.Join("testdata", "checkonly", "a", "v1", "a.proto")
+	bFilePath := filepath.Join("testdata", "checkonly", "b", "v1", "b.proto")
+	shuffleFilesFlag := fmt.Sprintf("--%s=1", shuffleFilesFlagName)
+	data := testRunOutputFileOrder(t, shuffleFilesFlag, aFilePath, bFilePath)
+	assert.Equal(t, data, testRunOutputFileOrder(t, shuffleFilesFlag, aFilePath, bFilePath))
+	fileDescriptorSet := &descriptorpb.FileDescriptorSet{}
+	require.NoError(t, protoencoding.NewWireUnmarshaler(nil).Unmarshal(data, fileDescriptorSet))
+	fileNames := make([]string, 0, len(fileDescriptorSet.File))
+	for _, fileDescriptorProto := range fileDescriptorSet.File {
+		fileNames = append(fileNames, fileDescriptorProto.GetName())
+	}
+	assert.ElementsMatch(t, []string{"a/v1/a.proto", "b/v1/b.proto"}, fileNames)
+}
+
+func TestAbsoluteFilePath(t *testing.T) {
+	t.Parallel()
+	absIncludeDirPath, err := filepath.Abs(filepath.Join("testdata", "importpath"))
+	require.NoError(t, err)
+	// the file names are relative to the include directory regardless of whether
+	// the include directory and file path are absolute, as with protoc
+	for _, includeDirPath := range []string{
+		absIncludeDirPath,
+		filepath.Join("testdata", "importpath"),
+	} {
+		stdout := bytes.NewBuffer(nil)
+		appcmdtesting.RunCommandSuccess(
+			t,
+			func(use string) *appcmd.Command {
+				return NewCommand(
+					use,
+					appflag.NewBuilder(),
+				)
+			},
+			nil,
+			nil,
+			stdout,
+			"-I",
+			includeDirPath,
+			"-o",
+			"-",
+			filepath.Join(absIncludeDirPath, "foo", "a.proto"),
+		)
+		fileDescriptorSet := &descriptorpb.FileDescriptorSet{}
+		require.NoError(t, protoencoding.NewWireUnmarshaler(nil).Unmarshal(stdout.Bytes(), fileDescriptorSet))
+		require.Len(t, fileDescriptorSet.File, 1)
+		assert.Equal(t, "foo/a.proto", fileDescriptorSet.File[0].GetName())
+	}
+}
+
+func TestWorkingDir(t *testing.T) {
+	t.Parallel()
+	stdout := bytes.NewBuffer(nil)
+	appcmdtesting.RunCommandSuccess(
+		t,
+		func(use string) *appcmd.Command {
+			return NewCommand(
+				use,
+				appflag.NewBuilder(),
+			)
+		},
+		nil,
+		nil,
+		stdout,
+		"-C",
+		filepath.Join("testdata", "importpath"),
+		"-o",
+		"-",
+		filepath.Join("foo", "a.proto"),
+	)
+	fileDescriptorSet := &descriptorpb.FileDescriptorSet{}
+	require.NoError(t, protoencoding.NewWireUnmarshaler(nil).Unmarshal(stdout.Bytes(), fileDescriptorSet))
+	require.Len(t, fileDescriptorSet.File, 1)
+	assert.Equal(t, "foo/a.proto", fileDescriptorSet.File[0].GetName())
+}
+
+func TestWorkingDirOutput(t *testing.T) {
+	t.Parallel()
+	tmpDir, err := tmp.NewDir("")
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(tmpDir.AbsPath(), "a.proto"), []byte(`syntax = "proto3";`), 0600))
+	appcmdtesting.RunCommandSuccess(
+		t,
+		func(use string) *appcmd.Command {
+			return NewCommand(
+				use,
+				appflag.NewBuilder(),
+			)
+		},
+		nil,
+		nil,
+		nil,
+		fmt.Sprintf("--%s=%s", workingDirFlagName, tmpDir.AbsPath()),
+		"-o",
+		"image.bin",
+		"a.proto",
+	)
+	data, err := ioutil.ReadFile(filepath.Join(tmpDir.AbsPath(), "image.bin"))
+	require.NoError(t, err)
+	fileDescriptorSet := &descriptorpb.FileDescriptorSet{}
+	require.NoError(t, protoencoding.NewWireUnmarshaler(nil).Unmarshal(data, fileDescriptorSet))
+	require.Len(t, fileDescriptorSet.File, 1)
+	assert.Equal(t, "a.proto", fileDescriptorSet.File[0].GetName())
+	require.NoError(t, tmpDir.Close())
+}
+
+func TestRewriteImports(t *testing.T) {
+	t.Parallel()
+	assert.Equal(
+		t,
+		map[string][]int32{
+			"acme/a.proto":          {1},
+			"acme/internal/b.proto": {0},
+			"other/c.proto":         nil,
+		},
+		testRewriteImports(t),
+	)
+}
+
+func TestRewriteImportsPublic(t *testing.T) {
+	t.Parallel()
+	assert.Equal(
+		t,
+		map[string][]int32{
+			"acme/a.proto":          {0, 1},
+			"acme/internal/b.proto": {0},
+			"other/c.proto":         nil,
+		},
+		testRewriteImports(t, fmt.Sprintf("--%s=^acme/", publicImportsFlagName)),
+	)
+}
+
+func TestRewriteImportsPrivate(t *testing.T) {
+	t.Parallel()
+	assert.Equal(
+		t,
+		map[string][]int32{
+			"acme/a.proto":          {1},
+			"acme/internal/b.proto": nil,
+			"other/c.proto":         nil,
+		},
+		testRewriteImports(t, fmt.Sprintf("--%s=^acme/internal/", privateImportsFlagName)),
+	)
+	assert.Equal(
+		t,
+		map[string][]int32{
+			"acme/a.proto":          {0, 1},
+			"acme/internal/b.proto": nil,
+			"other/c.proto":         nil,
+		},
+		testRewriteImports(
+			t,
+			fmt.Sprintf("--%s=^acme/[^/]+$", publicImportsFlagName),
+			fmt.Sprintf("--%s=^acme/internal/", privateImportsFlagName),
+		),
+	)
+}
+
+func TestRewriteImportsPublicAndPrivate(t *testing.T) {
+	t.Parallel()
+	appcmdtesting.RunCommandExitCode(
+		t,
+		func(use string) *appcmd.Command {
+			return NewCommand(
+				use,
+				appflag.NewBuilder(),
+			)
+		},
+		1,
+		nil,
+		nil,
+		nil,
+		"-I",
+		filepath.Join("testdata", "rewriteimports"),
+		"-o",
+		"-",
+		fmt.Sprintf("--%s=^acme/", publicImportsFlagName),
+		fmt.Sprintf("--%s=^acme/internal/", privateImportsFlagName),
+		filepath.Join("testdata", "rewriteimports", "acme", "a.proto"),
+	)
+}
+
+func TestStripSourceInfo(t *testing.T) {
+	t.Parallel()
+	fileDescriptorSet := testRunStripSourceInfo(t, fmt.Sprintf("--%s", includeSourceInfoFlagName))
+	require.Len(t, fileDescriptorSet.File, 1)
+	require.NotNil(t, fileDescriptorSet.File[0].GetSourceCodeInfo())
+	imageFile, err := bufcore.NewImageFile(fileDescriptorSet.File[0], "", false)
+	require.NoError(t, err)
+	image, err := bufcore.NewImage([]bufcore.ImageFile{imageFile})
+	require.NoError(t, err)
+	strippedImage, err := stripSourceInfo(image)
+	require.NoError(t, err)
+	require.Len(t, strippedImage.Files(), 1)
+	assert.Nil(t, strippedImage.Files()[0].Proto().GetSourceCodeInfo())
+	// the in-memory image is not modified
+	assert.NotNil(t, image.Files()[0].Proto().GetSourceCodeInfo())
+
+	fileDescriptorSet = testRunStripSourceInfo(
+		t,
+		fmt.Sprintf("--%s", includeSourceInfoFlagName),
+		fmt.Sprintf("--%s", stripSourceInfoFlagName),
+	)
+	require.Len(t, fileDescriptorSet.File, 1)
+	assert.Equal(t, "a.proto", fileDescriptorSet.File[0].GetName())
+	assert.Nil(t, fileDescriptorSet.File[0].GetSourceCodeInfo())
+}
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+	tmpDir, err := tmp.NewDir("")
+	require.NoError(t, err)
+	outputFilePath := filepath.Join(tmpDir.AbsPath(), "image.bin")
+	freeFieldNumbersArgs := []string{
+		"-I",
+		filepath.Join("testdata", "freefieldnumbers"),
+		"-o",
+		outputFilePath,
+		filepath.Join("testdata", "freefieldnumbers", "a.proto"),
+	}
+	// the output does not exist yet
+	testRunVerify(t, 1, append(freeFieldNumbersArgs, "--verify")...)
+	_, err = os.Stat(outputFilePath)
+	assert.True(t, os.IsNotExist(err))
+	testRunVerify(t, 0, freeFieldNumbersArgs...)
+	data, err := ioutil.ReadFile(outputFilePath)
+	require.NoError(t, err)
+	testRunVerify(t, 0, append(freeFieldNumbersArgs, "--verify")...)
+	stderr := testRunVerify(
+		t,
+		1,
+		"-I",
+		filepath.Join("testdata", "importpath"),
+		"-o",
+		outputFilePath,
+		"--verify",
+		filepath.Join("testdata", "importpath", "foo", "a.proto"),
+	)
+	assert.Contains(t, stderr, "added: foo/a.proto\nremoved: a.proto\n")
+	assert.Contains(t, stderr, "would change")
+	// the output is never written
+	newData, err := ioutil.ReadFile(outputFilePath)
+	require.NoError(t, err)
+	assert.Equal(t, data, newData)
+	require.NoError(t, tmpDir.Close())
+}
+
+func TestCheckOnly(t *testing.T) {
+	t.Parallel()
+	tmpDir, err := tmp.NewDir("")
+	require.NoError(t, err)
+	outputFilePath := filepath.Join(tmpDir.AbsPath(), "image.bin")
+	markerFilePath := filepath.Join(tmpDir.AbsPath(), "marker")
+	pluginPath := filepath.Join(tmpDir.AbsPath(), "protoc-gen-fake")
+	// the plugin records each invocation and returns an empty response
+	require.NoError(
+		t,
+		ioutil.WriteFile(
+			pluginPath,
+			[]byte(fmt.Sprintf(`#!/bin/sh
+cat > /dev/null
+echo run >> %s
+`, markerFilePath)),
+			0755,
+		),
+	)
+	testRunCheckOnly(
+		t,
+		0,
+		"-I",
+		filepath.Join("testdata", "checkonly"),
+		"-o",
+		outputFilePath,
+		fmt.Sprintf("--%s", checkOnlyFlagName),
+		filepath.Join("testdata", "checkonly", "a", "v1", "a.proto"),
+	)
+	_, err = os.Stat(outputFilePath)
+	assert.True(t, os.IsNotExist(err))
+	testRunCheckOnly(
+		t,
+		0,
+		"-I",
+		filepath.Join("testdata", "checkonly"),
+		fmt.Sprintf("--%s=protoc-gen-fake=%s", pluginPathValuesFlagName, pluginPath),
+		fmt.Sprintf("--fake_out=%s", tmpDir.AbsPath()),
+		fmt.Sprintf("--%s", checkOnlyFlagName),
+		filepath.Join("testdata", "checkonly", "a", "v1", "a.proto"),
+	)
+	_, err = os.Stat(markerFilePath)
+	assert.True(t, os.IsNotExist(err))
+	require.NoError(t, tmpDir.Close())
+}
+
+func TestCheckOnlyLintError(t *testing.T) {
+	t.Parallel()
+	tmpDir, err := tmp.NewDir("")
+	require.NoError(t, err)
+	outputFilePath := filepath.Join(tmpDir.AbsPath(), "image.bin")
+	stderr := testRunCheckOnly(
+		t,
+		1,
+		"-I",
+		filepath.Join("testdata", "checkonly"),
+		"-o",
+		outputFilePath,
+		fmt.Sprintf("--%s", checkOnlyFlagName),
+		fmt.Sprintf("--%s=json", errorFormatFlagName),
+		filepath.Join("testdata", "checkonly", "b", "v1", "b.proto"),
+	)
+	assert.Contains(t, stderr, `"path":"testdata/checkonly/b/v1/b.proto"`)
+	assert.Contains(t, stderr, `"type":"MESSAGE_PASCAL_CASE"`)
+	_, err = os.Stat(outputFilePath)
+	assert.True(t, os.IsNotExist(err))
+	require.NoError(t, tmpDir.Close())
+}
+
+func TestCheckOnlyWorkingDir(t *testing.T) {
+	t.Parallel()
+	// the buf.yaml in the working directory excludes MESSAGE_PASCAL_CASE
+	testRunCheckOnly(
+		t,
+		0,
+		fmt.Sprintf("--%s=%s", workingDirFlagName, filepath.Join("testdata", "checkonlyworkingdir")),
+		fmt.Sprintf("--%s", checkOnlyFlagName),
+		filepath.Join("a", "v1", "a.proto"),
+	)
+	testRunCheckOnly(
+		t,
+		1,
+		"-I",
+		filepath.Join("testdata", "checkonlyworkingdir"),
+		fmt.Sprintf("--%s", checkOnlyFlagName),
+		filepath.Join("testdata", "checkonlyworkingdir", "a", "v1", "a.proto"),
+	)
+}
+
+func TestCheckOnlyLintWarning(t *testing.T) {
+	t.Parallel()
+	// the buf.yaml in the working directory only makes FIELD_LOWER_SNAKE_CASE an error
+	stderr := testRunCheckOnly(
+		t,
+		0,
+		fmt.Sprintf("--%s=%s", workingDirFlagName, filepath.Join("testdata", "checkonlywarning")),
+		fmt.Sprintf("--%s", checkOnlyFlagName),
+		fmt.Sprintf("--%s=json", errorFormatFlagName),
+		filepath.Join("a", "v1", "a.proto"),
+	)
+	assert.Contains(t, stderr, `"type":"MESSAGE_PASCAL_CASE"`)
+}
+
+func TestImageCache(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	tmpDir, err := tmp.NewDir("")
+	require.NoError(t, err)
+	includeDirPath := filepath.Join(tmpDir.AbsPath(), "proto")
+	require.NoError(t, os.Mkdir(includeDirPath, 0755))
+	filePath := filepath.Join(includeDirPath, "a.proto")
+	require.NoError(t, ioutil.WriteFile(filePath, []byte("syntax = \"proto3\";\n\npackage a;\n"), 0644))
+	key, err := getImageCacheKey(ctx, "0.1.0", []string{includeDirPath}, []string{filePath}, true, "true")
+	require.NoError(t, err)
+	otherKey, err := getImageCacheKey(ctx, "0.1.0", []string{includeDirPath}, []string{filePath}, true, "false")
+	require.NoError(t, err)
+	assert.NotEqual(t, key, otherKey)
+	// images built by another version of buf or without the builtin well-known types are not used
+	otherKey, err = getImageCacheKey(ctx, "0.2.0", []string{includeDirPath}, []string{filePath}, true, "true")
+	require.NoError(t, err)
+	assert.NotEqual(t, key, otherKey)
+	otherKey, err = getImageCacheKey(ctx, "0.1.0", []string{includeDirPath}, []string{filePath}, false, "true")
+	require.NoError(t, err)
+	assert.NotEqual(t, key, otherKey)
+
+	cache := newFileImageCache(filepath.Join(tmpDir.AbsPath(), "cache"))
+	_, ok, err := cache.Get(ctx, key)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	imageFile, err := bufcore.NewImageFile(
+		&descriptorpb.FileDescriptorProto{
+			Name:    proto.String("a.proto"),
+			Package: proto.String("a"),
+			Syntax:  proto.String("proto3"),
+		},
+		"",
+		false,
+	)
+	require.NoError(t, err)
+	image, err := bufcore.NewImage([]bufcore.ImageFile{imageFile})
+	require.NoError(t, err)
+	require.NoError(t, cache.Put(ctx, key, image))
+	cachedImage, ok, err := cache.Get(ctx, key)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Len(t, cachedImage.Files(), 1)
+	assert.Equal(t, "a.proto", cachedImage.Files()[0].Path())
+	assert.Equal(t, "a", cachedImage.Files()[0].Proto().GetPackage())
+
+	// the key changes with the contents of the files in the include directories
+	require.NoError(t, ioutil.WriteFile(filepath.Join(includeDirPath, "b.proto"), []byte("syntax = \"proto3\";\n"), 0644))
+	newKey, err := getImageCacheKey(ctx, "0.1.0", []string{includeDirPath}, []string{filePath}, true, "true")
+	require.NoError(t, err)
+	assert.NotEqual(t, key, newKey)
+	_, ok, err = cache.Get(ctx, newKey)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	require.NoError(t, tmpDir.Close())
+}
+
+func TestImageCacheDir(t *testing.T) {
+	t.Parallel()
+	tmpDir, err := tmp.NewDir("")
+	require.NoError(t, err)
+	includeDirPath := filepath.Join(tmpDir.AbsPath(), "proto")
+	require.NoError(t, os.Mkdir(includeDirPath, 0755))
+	cacheDirPath := filepath.Join(tmpDir.AbsPath(), "cache")
+	filePath := filepath.Join(includeDirPath, "a.proto")
+	require.NoError(t, ioutil.WriteFile(filePath, []byte("syntax = \"proto3\";\n\npackage a;\n"), 0644))
+	fileDescriptorSet := testRunImageCacheDir(t, "0.1.0", includeDirPath, cacheDirPath, filePath)
+	require.Len(t, fileDescriptorSet.File, 1)
+	assert.Equal(t, "a", fileDescriptorSet.File[0].GetPackage())
+
+	// replace the cached image to verify that the second build is a hit
+	fileInfos, err := ioutil.ReadDir(cacheDirPath)
+	require.NoError(t, err)
+	require.Len(t, fileInfos, 1)
+	data, err := protoencoding.NewWireMarshaler().Marshal(
+		&descriptorpb.FileDescriptorSet{
+			File: []*descriptorpb.FileDescriptorProto{
+				{
+					Name:    proto.String("a.proto"),
+					Package: proto.String("cached"),
+					Syntax:  proto.String("proto3"),
+				},
+			},
+		},
+	)
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(cacheDirPath, fileInfos[0].Name()), data, 0644))
+	fileDescriptorSet = testRunImageCacheDir(t, "0.1.0", includeDirPath, cacheDirPath, filePath)
+	require.Len(t, fileDescriptorSet.File, 1)
+	assert.Equal(t, "cached", fileDescriptorSet.File[0].GetPackage())
+
+	// another version of buf is a miss
+	fileDescriptorSet = testRunImageCacheDir(t, "0.2.0", includeDirPath, cacheDirPath, filePath)
+	require.Len(t, fileDescriptorSet.File, 1)
+	assert.Equal(t, "a", fileDescriptorSet.File[0].GetPackage())
+
+	// changing an input file is a miss
+	require.NoError(t, ioutil.WriteFile(filePath, []byte("syntax = \"proto3\";\n\npackage b;\n"), 0644))
+	fileDescriptorSet = testRunImageCacheDir(t, "0.1.0", includeDirPath, cacheDirPath, filePath)
+	require.Len(t, fileDescriptorSet.File, 1)
+	assert.Equal(t, "b", fileDescriptorSet.File[0].GetPackage())
+	require.NoError(t, tmpDir.Close())
+}
+
+func TestPluginCache(t *testing.T) {
+	t.Parallel()
+	// the second invocation is replayed from the cache
+	testPluginCache(t, nil, nil, false, "run\n")
+}
+
+func TestPluginCachePluginEnvClean(t *testing.T) {
+	t.Parallel()
+	// the second invocation has a different environment
+	testPluginCache(t, nil, []string{fmt.Sprintf("--%s", pluginEnvCleanFlagName)}, false, "run\nrun\n")
+}
+
+func TestPluginCacheDisable(t *testing.T) {
+	t.Parallel()
+	testPluginCache(t, []string{fmt.Sprintf("--%s=fake", pluginCacheDisableFlagName)}, nil, false, "run\nrun\n")
+}
+
+func TestPluginCacheReplacePlugin(t *testing.T) {
+	t.Parallel()
+	// replacing the plugin binary at the same path is a miss
+	testPluginCache(t, nil, nil, true, "run\nrun\n")
+}
+
+func TestPluginDescriptorSet(t *testing.T) {
+	t.Parallel()
+	testPluginDescriptorSet(t, nil, []string{"a.proto"})
+}
+
+func TestPluginDescriptorSetIncludeImports(t *testing.T) {
+	t.Parallel()
+	testPluginDescriptorSet(
+		t,
+		[]string{fmt.Sprintf("--%s", includeImportsFlagName)},
+		[]string{"google/protobuf/timestamp.proto", "a.proto"},
+	)
+}
+
+func TestPluginEnv(t *testing.T) {
+	t.Parallel()
+	testPluginEnv(t, nil, "foobar")
+	testPluginEnv(t, []string{fmt.Sprintf("--%s=FOO=baz", pluginEnvFlagName)}, "bazbar")
+	testPluginEnv(
+		t,
+		[]string{
+			fmt.Sprintf("--%s=fake:FOO=bat", pluginEnvFlagName),
+			fmt.Sprintf("--%s=FOO=baz", pluginEnvFlagName),
+		},
+		"batbar",
+	)
+}
+
+func TestPluginEnvClean(t *testing.T) {
+	t.Parallel()
+	testPluginEnv(t, []string{fmt.Sprintf("--%s", pluginEnvCleanFlagName)}, "")
+	testPluginEnv(
+		t,
+		[]string{
+			fmt.Sprintf("--%s", pluginEnvCleanFlagName),
+			fmt.Sprintf("--%s=FOO=baz", pluginEnvFlagName),
+		},
+		"baz",
+	)
+}
+
+func TestPluginStrategyAggregate(t *testing.T) {
+	t.Parallel()
+	testPluginStrategy(t, nil, 1)
+	testPluginStrategy(t, []string{fmt.Sprintf("--%s=%s", pluginStrategyFlagName, pluginStrategyAggregate)}, 1)
+}
+
+func TestPluginStrategyDirectory(t *testing.T) {
+	t.Parallel()
+	testPluginStrategy(t, []string{fmt.Sprintf("--%s=%s", pluginStrategyFlagName, pluginStrategyDirectory)}, 2)
+}
+
+func TestPluginPathDir(t *testing.T) {
+	// not parallel as $PATH is modified, which is used by exec.LookPath
+	tmpDir, err := tmp.NewDir("")
+	require.NoError(t, err)
+	pathDirPath := filepath.Join(tmpDir.AbsPath(), "path")
+	pluginPathDirPath := filepath.Join(tmpDir.AbsPath(), "plugins")
+	emptyDirPath := filepath.Join(tmpDir.AbsPath(), "empty")
+	for _, dirPath := range []string{pathDirPath, pluginPathDirPath, emptyDirPath} {
+		require.NoError(t, os.Mkdir(dirPath, 0755))
+	}
+	for dirPath, marker := range map[string]string{
+		pathDirPath:       "path",
+		pluginPathDirPath: "plugin_path_dir",
+	} {
+		// the plugin records which directory it was found in and returns an empty response
+		require.NoError(
+			t,
+			ioutil.WriteFile(
+				filepath.Join(dirPath, "protoc-gen-fake"),
+				[]byte(`#!/bin/sh
+cat > /dev/null
+echo `+marker+` > "${MARKER_FILE}"
+`),
+				0755,
+			),
+		)
+	}
+	oldPath := os.Getenv("PATH")
+	require.NoError(t, os.Setenv("PATH", pathDirPath+string(os.PathListSeparator)+oldPath))
+	defer func() {
+		require.NoError(t, os.Setenv("PATH", oldPath))
+	}()
+	testPluginPathDir(t, tmpDir.AbsPath(), nil, "path")
+	testPluginPathDir(t, tmpDir.AbsPath(), []string{fmt.Sprintf("--%s=%s", pluginPathDirFlagName, emptyDirPath)}, "path")
+	testPluginPathDir(t, tmpDir.AbsPath(), []string{fmt.Sprintf("--%s=%s", pluginPathDirFlagName, pluginPathDirPath)}, "plugin_path_dir")
+	testPluginPathDir(
+		t,
+		tmpDir.AbsPath(),
+		[]string{
+			fmt.Sprintf("--%s=%s", pluginPathDirFlagName, emptyDirPath),
+			fmt.Sprintf("--%s=%s", pluginPathDirFlagName, pluginPathDirPath),
+		},
+		"plugin_path_dir",
+	)
+	require.NoError(t, tmpDir.Close())
+}
+
+func TestPluginManifest(t *testing.T) {
+	t.Parallel()
+	tmpDir, err := tmp.NewDir("")
+	require.NoError(t, err)
+	manifestFilePath := filepath.Join(tmpDir.AbsPath(), "manifest.json")
+	args := []string{
+		"-I",
+		filepath.Join("testdata", "freefieldnumbers"),
+		fmt.Sprintf("--%s=%s", pluginManifestFlagName, manifestFilePath),
+	}
+	// each plugin returns a response with the single file given with the content "hi"
+	for pluginName, fileName := range map[string]string{"one": "a.txt", "two": "b.txt"} {
+		pluginPath := filepath.Join(tmpDir.AbsPath(), "protoc-gen-"+pluginName)
+		require.NoError(
+			t,
+			ioutil.WriteFile(
+				pluginPath,
+				[]byte(fmt.Sprintf(`#!/bin/sh
+cat > /dev/null
+printf '\172\013\012\005%s\172\002hi'
+`, fileName)),
+				0755,
+			),
+		)
+		outDirPath := filepath.Join(tmpDir.AbsPath(), pluginName)
+		require.NoError(t, os.Mkdir(outDirPath, 0755))
+		args = append(
+			args,
+			fmt.Sprintf("--%s=protoc-gen-%s=%s", pluginPathValuesFlagName, pluginName, pluginPath),
+			fmt.Sprintf("--%s_out=%s", pluginName, outDirPath),
+		)
+	}
+	appcmdtesting.RunCommandSuccess(
+		t,
+		func(use string) *appcmd.Command {
+			return NewCommand(
+				use,
+				appflag.NewBuilder(),
+			)
+		},
+		nil,
+		nil,
+		nil,
+		append(args, filepath.Join("testdata", "freefieldnumbers", "a.proto"))...,
+	)
+	data, err := ioutil.ReadFile(manifestFilePath)
+	require.NoError(t, err)
+	pluginNameToFilePaths := make(map[string][]string)
+	require.NoError(t, json.Unmarshal(data, &pluginNameToFilePaths))
+	assert.Equal(
+		t,
+		map[string][]string{
+			"one": {filepath.Join(tmpDir.AbsPath(), "one", "a.txt")},
+			"two": {filepath.Join(tmpDir.AbsPath(), "two", "b.txt")},
+		},
+		pluginNameToFilePaths,
+	)
+	require.NoError(t, tmpDir.Close())
+}
+
+func TestPluginManifestWorkingDir(t *testing.T) {
+	t.Parallel()
+	tmpDir, err := tmp.NewDir("")
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(tmpDir.AbsPath(), "a.proto"), []byte(`syntax = "proto3";`), 0600))
+	require.NoError(t, os.Mkdir(filepath.Join(tmpDir.AbsPath(), "one"), 0755))
+	pluginPath := filepath.Join(tmpDir.AbsPath(), "protoc-gen-one")
+	// the plugin returns a response with the single file a.txt with the content "hi"
+	require.NoError(
+		t,
+		ioutil.WriteFile(
+			pluginPath,
+			[]byte(`#!/bin/sh
+cat > /dev/null
+printf '\172\013\012\005a.txt\172\002hi'
+`),
+			0755,
+		),
+	)
+	appcmdtesting.RunCommandSuccess(
+		t,
+		func(use string) *appcmd.Command {
+			return NewCommand(
+				use,
+				appflag.NewBuilder(),
+			)
+		},
+		nil,
+		nil,
+		nil,
+		fmt.Sprintf("--%s=%s", workingDirFlagName, tmpDir.AbsPath()),
+		fmt.Sprintf("--%s=manifest.json", pluginManifestFlagName),
+		fmt.Sprintf("--%s=protoc-gen-one=%s", pluginPathValuesFlagName, pluginPath),
+		"--one_out=one",
+		"a.proto",
+	)
+	// the relative manifest path is relative to the working directory
+	data, err := ioutil.ReadFile(filepath.Join(tmpDir.AbsPath(), "manifest.json"))
+	require.NoError(t, err)
+	pluginNameToFilePaths := make(map[string][]string)
+	require.NoError(t, json.Unmarshal(data, &pluginNameToFilePaths))
+	assert.Equal(
+		t,
+		map[string][]string{
+			"one": {filepath.Join(tmpDir.AbsPath(), "one", "a.txt")},
+		},
+		pluginNameToFilePaths,
+	)
+	require.NoError(t, tmpDir.Close())
+}
+
+func TestGetDescriptorSetPluginArgs(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, []string{"/tmp/a.bin"}, getDescriptorSetPluginArgs("", "/tmp/a.bin", "out"))
+	assert.Equal(t, []string{"--foo", "/tmp/a.bin"}, getDescriptorSetPluginArgs("--foo", "/tmp/a.bin", "out"))
+	assert.Equal(
+		t,
+		[]string{"--input=/tmp/a.bin", "--output=out/gen"},
+		getDescriptorSetPluginArgs("--input={descriptor_set} --output={out}/gen", "/tmp/a.bin", "out"),
+	)
+}
+
+func TestBuiltinWKT(t *testing.T) {
+	t.Parallel()
+	testRunBuiltinWKT(t, 0)
+}
+
+func TestNoBuiltinWKT(t *testing.T) {
+	t.Parallel()
+	testRunBuiltinWKT(t, 1, fmt.Sprintf("--%s", noBuiltinWKTFlagName))
+}
+
+func TestNoBuiltinWKTIncludeDir(t *testing.T) {
+	t.Parallel()
+	testRunBuiltinWKT(
+		t,
+		0,
+		fmt.Sprintf("--%s", noBuiltinWKTFlagName),
+		"-I",
+		filepath.Join("testdata", "wktinclude"),
+	)
+}
+
 func TestCompareOutputGoogleapis(t *testing.T) {
 	t.Parallel()
 	googleapisDirPath := buftesting.GetGoogleapisDirPath(t, buftestingDirPath)
@@ -93,64 +1073,382 @@ func TestCompareOutputGoogleapis(t *testing.T) {
 		googleapisDirPath,
 		filePaths,
 	)
-	bufProtocFileDescriptorSet := testGetBufProtocFileDescriptorSet(t, googleapisDirPath)
-	prototesting.AssertFileDescriptorSetsEqual(t, bufProtocFileDescriptorSet, actualProtocFileDescriptorSet)
+	bufProtocFileDescriptorSet := testGetBufProtocFileDescriptorSet(t, googleapisDirPath)
+	prototesting.AssertFileDescriptorSetsEqual(t, bufProtocFileDescriptorSet, actualProtocFileDescriptorSet)
+}
+
+func TestCompareGeneratedStubsGoogleapisGo(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in short mode")
+	}
+	t.Parallel()
+	googleapisDirPath := buftesting.GetGoogleapisDirPath(t, buftestingDirPath)
+	testCompareGeneratedStubs(t, googleapisDirPath, "go", "Mgoogle/api/auth.proto=foo")
+}
+
+func TestCompareGeneratedStubsGoogleapisRuby(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in short mode")
+	}
+	t.Parallel()
+	googleapisDirPath := buftesting.GetGoogleapisDirPath(t, buftestingDirPath)
+	testCompareGeneratedStubs(t, googleapisDirPath, "ruby", "")
+}
+
+func testCompareGeneratedStubs(
+	t *testing.T,
+	dirPath string,
+	pluginName string,
+	pluginOpt string,
+) {
+	filePaths := buftesting.GetProtocFilePaths(t, dirPath, 1000)
+	var baseFlags []string
+	if pluginOpt != "" {
+		baseFlags = append(baseFlags, fmt.Sprintf("--%s_opt=%s", pluginName, pluginOpt))
+	}
+	actualProtocDir, err := tmp.NewDir("")
+	require.NoError(t, err)
+	defer func() {
+		_ = actualProtocDir.Close()
+	}()
+	bufProtocDir, err := tmp.NewDir("")
+	require.NoError(t, err)
+	defer func() {
+		_ = bufProtocDir.Close()
+	}()
+	buftesting.RunActualProtoc(
+		t,
+		false,
+		false,
+		dirPath,
+		filePaths,
+		map[string]string{
+			"PATH": os.Getenv("PATH"),
+		},
+		nil,
+		append(
+			baseFlags,
+			fmt.Sprintf("--%s_out=%s", pluginName, actualProtocDir.AbsPath()),
+		)...,
+	)
+	appcmdtesting.RunCommandSuccess(
+		t,
+		func(use string) *appcmd.Command {
+			return NewCommand(
+				use,
+				appflag.NewBuilder(),
+			)
+		},
+		map[string]string{
+			"PATH": os.Getenv("PATH"),
+		},
+		nil,
+		nil,
+		append(
+			append(
+				baseFlags,
+				"-I",
+				dirPath,
+				fmt.Sprintf("--%s_out=%s", pluginName, bufProtocDir.AbsPath()),
+			),
+			filePaths...,
+		)...,
+	)
+	actualReadWriteBucket, err := storageos.NewReadWriteBucket(actualProtocDir.AbsPath())
+	require.NoError(t, err)
+	bufReadWriteBucket, err := storageos.NewReadWriteBucket(bufProtocDir.AbsPath())
+	require.NoError(t, err)
+	diff, err := storage.Diff(
+		context.Background(),
+		actualReadWriteBucket,
+		bufReadWriteBucket,
+		"protoc",
+		"buf-protoc",
+	)
+	require.NoError(t, err)
+	assert.Empty(t, string(diff))
+}
+
+func testGetBufProtocFileDescriptorSet(t *testing.T, dirPath string) *descriptorpb.FileDescriptorSet {
+	data := testGetBufProtocFileDescriptorSetBytes(t, dirPath)
+	fileDescriptorSet := &descriptorpb.FileDescriptorSet{}
+	// TODO: change to image read logic
+	require.NoError(
+		t,
+		protoencoding.NewWireUnmarshaler(nil).Unmarshal(
+			data,
+			fileDescriptorSet,
+		),
+	)
+	return fileDescriptorSet
+}
+
+func testGetBufProtocFileDescriptorSetBytes(t *testing.T, dirPath string) []byte {
+	stdout := bytes.NewBuffer(nil)
+	appcmdtesting.RunCommandSuccess(
+		t,
+		func(use string) *appcmd.Command {
+			return NewCommand(
+				use,
+				appflag.NewBuilder(),
+			)
+		},
+		nil,
+		nil,
+		stdout,
+		append(
+			[]string{
+				"-I",
+				dirPath,
+				"-o",
+				"-",
+			},
+			buftesting.GetProtocFilePaths(t, dirPath, 1000)...,
+		)...,
+	)
+	return stdout.Bytes()
+}
+
+func testPrintFreeFieldNumbers(t *testing.T, format string, expectedStdout string, extraArgs ...string) {
+	args := []string{
+		"-I",
+		filepath.Join("testdata", "freefieldnumbers"),
+		fmt.Sprintf("--%s", printFreeFieldNumbersFlagName),
+	}
+	args = append(args, extraArgs...)
+	if format != "" {
+		args = append(args, fmt.Sprintf("--%s=%s", printFreeFieldNumbersFormatFlagName, format))
+	}
+	stdout := bytes.NewBuffer(nil)
+	appcmdtesting.RunCommandExitCode(
+		t,
+		func(use string) *appcmd.Command {
+			return NewCommand(
+				use,
+				appflag.NewBuilder(),
+			)
+		},
+		0,
+		nil,
+		nil,
+		stdout,
+		append(args, filepath.Join("testdata", "freefieldnumbers", "a.proto"))...,
+	)
+	assert.Equal(t, expectedStdout, stdout.String())
 }
 
-func TestCompareGeneratedStubsGoogleapisGo(t *testing.T) {
-	if testing.Short() {
-		t.Skip("skipping test in short mode")
+// testReservedStatementsCompile compiles a file with a message containing the
+// reserved statements for each message in the output of --print_free_field_numbers_format=reserved.
+func testReservedStatementsCompile(t *testing.T, reservedStatements string) {
+	tmpDir, err := tmp.NewDir("")
+	require.NoError(t, err)
+	var builder strings.Builder
+	_, _ = builder.WriteString("syntax = \"proto3\";\n\npackage a;\n")
+	var numMessages int
+	for _, line := range strings.Split(strings.TrimSpace(reservedStatements), "\n") {
+		if strings.HasPrefix(line, "// ") {
+			if numMessages > 0 {
+				_, _ = builder.WriteString("}\n")
+			}
+			numMessages++
+			_, _ = builder.WriteString(fmt.Sprintf("\nmessage Message%d {\n", numMessages))
+			continue
+		}
+		_, _ = builder.WriteString("  " + line + "\n")
 	}
-	t.Parallel()
-	googleapisDirPath := buftesting.GetGoogleapisDirPath(t, buftestingDirPath)
-	testCompareGeneratedStubs(t, googleapisDirPath, "go", "Mgoogle/api/auth.proto=foo")
+	require.NotZero(t, numMessages)
+	_, _ = builder.WriteString("}\n")
+	require.NoError(t, ioutil.WriteFile(filepath.Join(tmpDir.AbsPath(), "a.proto"), []byte(builder.String()), 0644))
+	appcmdtesting.RunCommandSuccess(
+		t,
+		func(use string) *appcmd.Command {
+			return NewCommand(
+				use,
+				appflag.NewBuilder(),
+			)
+		},
+		nil,
+		nil,
+		nil,
+		"-I",
+		tmpDir.AbsPath(),
+		"-o",
+		filepath.Join(tmpDir.AbsPath(), "image.bin"),
+		filepath.Join(tmpDir.AbsPath(), "a.proto"),
+	)
+	require.NoError(t, tmpDir.Close())
 }
 
-func TestCompareGeneratedStubsGoogleapisRuby(t *testing.T) {
-	if testing.Short() {
-		t.Skip("skipping test in short mode")
+// secondExtraArgs are only added to the second invocation.
+func testPluginCache(t *testing.T, extraArgs []string, secondExtraArgs []string, replacePlugin bool, expectedMarker string) {
+	tmpDir, err := tmp.NewDir("")
+	require.NoError(t, err)
+	markerFilePath := filepath.Join(tmpDir.AbsPath(), "marker")
+	pluginPath := filepath.Join(tmpDir.AbsPath(), "protoc-gen-fake")
+	cacheDirPath := filepath.Join(tmpDir.AbsPath(), "cache")
+	for i := 0; i < 2; i++ {
+		if i == 0 || replacePlugin {
+			// the plugin records each invocation and returns a response with the
+			// single file a.txt with the content "hi", the version comment only
+			// changes the content of the binary
+			require.NoError(
+				t,
+				ioutil.WriteFile(
+					pluginPath,
+					[]byte(fmt.Sprintf(`#!/bin/sh
+# version %d
+cat > /dev/null
+echo run >> %s
+printf '\172\013\012\005a.txt\172\002hi'
+`, i, markerFilePath)),
+					0755,
+				),
+			)
+		}
+		outDirPath := filepath.Join(tmpDir.AbsPath(), fmt.Sprintf("out%d", i))
+		require.NoError(t, os.Mkdir(outDirPath, 0755))
+		args := append(
+			[]string{
+				"-I",
+				filepath.Join("testdata", "freefieldnumbers"),
+				fmt.Sprintf("--%s=protoc-gen-fake=%s", pluginPathValuesFlagName, pluginPath),
+				fmt.Sprintf("--fake_out=%s", outDirPath),
+				fmt.Sprintf("--%s=%s", pluginCacheDirFlagName, cacheDirPath),
+			},
+			extraArgs...,
+		)
+		if i == 1 {
+			args = append(args, secondExtraArgs...)
+		}
+		appcmdtesting.RunCommandSuccess(
+			t,
+			func(use string) *appcmd.Command {
+				return NewCommand(
+					use,
+					appflag.NewBuilder(),
+				)
+			},
+			nil,
+			nil,
+			nil,
+			append(args, filepath.Join("testdata", "freefieldnumbers", "a.proto"))...,
+		)
+		data, err := ioutil.ReadFile(filepath.Join(outDirPath, "a.txt"))
+		require.NoError(t, err)
+		assert.Equal(t, "hi", string(data))
 	}
-	t.Parallel()
-	googleapisDirPath := buftesting.GetGoogleapisDirPath(t, buftestingDirPath)
-	testCompareGeneratedStubs(t, googleapisDirPath, "ruby", "")
+	data, err := ioutil.ReadFile(markerFilePath)
+	require.NoError(t, err)
+	assert.Equal(t, expectedMarker, string(data))
+	require.NoError(t, tmpDir.Close())
 }
 
-func testCompareGeneratedStubs(
-	t *testing.T,
-	dirPath string,
-	pluginName string,
-	pluginOpt string,
-) {
-	filePaths := buftesting.GetProtocFilePaths(t, dirPath, 1000)
-	var baseFlags []string
-	if pluginOpt != "" {
-		baseFlags = append(baseFlags, fmt.Sprintf("--%s_opt=%s", pluginName, pluginOpt))
-	}
-	actualProtocDir, err := tmp.NewDir("")
+func testPluginEnv(t *testing.T, extraArgs []string, expectedContent string) {
+	tmpDir, err := tmp.NewDir("")
 	require.NoError(t, err)
-	defer func() {
-		_ = actualProtocDir.Close()
-	}()
-	bufProtocDir, err := tmp.NewDir("")
+	outDirPath := filepath.Join(tmpDir.AbsPath(), "out")
+	require.NoError(t, os.Mkdir(outDirPath, 0755))
+	pluginPath := filepath.Join(tmpDir.AbsPath(), "protoc-gen-fake")
+	// the plugin returns a response with the single file a.txt with the
+	// values of FOO and BAR as the content
+	require.NoError(
+		t,
+		ioutil.WriteFile(
+			pluginPath,
+			[]byte(`#!/bin/sh
+cat > /dev/null
+content="${FOO}${BAR}"
+printf "\172\\$(printf '%03o' $((${#content} + 9)))\012\005a.txt\172\\$(printf '%03o' ${#content})${content}"
+`),
+			0755,
+		),
+	)
+	args := append(
+		[]string{
+			"-I",
+			filepath.Join("testdata", "freefieldnumbers"),
+			fmt.Sprintf("--%s=protoc-gen-fake=%s", pluginPathValuesFlagName, pluginPath),
+			fmt.Sprintf("--fake_out=%s", outDirPath),
+		},
+		extraArgs...,
+	)
+	appcmdtesting.RunCommandSuccess(
+		t,
+		func(use string) *appcmd.Command {
+			return NewCommand(
+				use,
+				appflag.NewBuilder(),
+			)
+		},
+		map[string]string{
+			"FOO": "foo",
+			"BAR": "bar",
+		},
+		nil,
+		nil,
+		append(args, filepath.Join("testdata", "freefieldnumbers", "a.proto"))...,
+	)
+	data, err := ioutil.ReadFile(filepath.Join(outDirPath, "a.txt"))
 	require.NoError(t, err)
-	defer func() {
-		_ = bufProtocDir.Close()
-	}()
-	buftesting.RunActualProtoc(
+	assert.Equal(t, expectedContent, string(data))
+	require.NoError(t, tmpDir.Close())
+}
+
+func testPluginStrategy(t *testing.T, extraArgs []string, expectedInvocations int) {
+	tmpDir, err := tmp.NewDir("")
+	require.NoError(t, err)
+	outDirPath := filepath.Join(tmpDir.AbsPath(), "out")
+	require.NoError(t, os.Mkdir(outDirPath, 0755))
+	invocationsFilePath := filepath.Join(tmpDir.AbsPath(), "invocations.txt")
+	pluginPath := filepath.Join(tmpDir.AbsPath(), "protoc-gen-fake")
+	// the plugin records each invocation and returns an empty response
+	require.NoError(
 		t,
-		false,
-		false,
-		dirPath,
-		filePaths,
+		ioutil.WriteFile(
+			pluginPath,
+			[]byte(`#!/bin/sh
+cat > /dev/null
+echo invoked >> "${INVOCATIONS_FILE}"
+`),
+			0755,
+		),
+	)
+	args := append(
+		[]string{
+			"-I",
+			filepath.Join("testdata", "checkonly"),
+			fmt.Sprintf("--%s=protoc-gen-fake=%s", pluginPathValuesFlagName, pluginPath),
+			fmt.Sprintf("--fake_out=%s", outDirPath),
+		},
+		extraArgs...,
+	)
+	appcmdtesting.RunCommandSuccess(
+		t,
+		func(use string) *appcmd.Command {
+			return NewCommand(
+				use,
+				appflag.NewBuilder(),
+			)
+		},
 		map[string]string{
-			"PATH": os.Getenv("PATH"),
+			"INVOCATIONS_FILE": invocationsFilePath,
 		},
 		nil,
+		nil,
 		append(
-			baseFlags,
-			fmt.Sprintf("--%s_out=%s", pluginName, actualProtocDir.AbsPath()),
+			args,
+			filepath.Join("testdata", "checkonly", "a", "v1", "a.proto"),
+			filepath.Join("testdata", "checkonly", "b", "v1", "b.proto"),
 		)...,
 	)
+	data, err := ioutil.ReadFile(invocationsFilePath)
+	require.NoError(t, err)
+	assert.Len(t, strings.Split(strings.TrimSpace(string(data)), "\n"), expectedInvocations)
+	require.NoError(t, tmpDir.Close())
+}
+
+func testPluginPathDir(t *testing.T, tmpDirPath string, extraArgs []string, expectedMarker string) {
+	markerFilePath := filepath.Join(tmpDirPath, "marker.txt")
 	appcmdtesting.RunCommandSuccess(
 		t,
 		func(use string) *appcmd.Command {
@@ -160,50 +1458,206 @@ func testCompareGeneratedStubs(
 			)
 		},
 		map[string]string{
-			"PATH": os.Getenv("PATH"),
+			"MARKER_FILE": markerFilePath,
 		},
 		nil,
 		nil,
 		append(
 			append(
-				baseFlags,
-				"-I",
-				dirPath,
-				fmt.Sprintf("--%s_out=%s", pluginName, bufProtocDir.AbsPath()),
+				[]string{
+					"-I",
+					filepath.Join("testdata", "checkonly"),
+					fmt.Sprintf("--fake_out=%s", tmpDirPath),
+				},
+				extraArgs...,
 			),
-			filePaths...,
+			filepath.Join("testdata", "checkonly", "a", "v1", "a.proto"),
 		)...,
 	)
-	actualReadWriteBucket, err := storageos.NewReadWriteBucket(actualProtocDir.AbsPath())
+	data, err := ioutil.ReadFile(markerFilePath)
 	require.NoError(t, err)
-	bufReadWriteBucket, err := storageos.NewReadWriteBucket(bufProtocDir.AbsPath())
+	assert.Equal(t, expectedMarker, strings.TrimSpace(string(data)))
+}
+
+func testRunImageCacheDir(t *testing.T, bufVersion string, includeDirPath string, cacheDirPath string, filePath string) *descriptorpb.FileDescriptorSet {
+	stdout := bytes.NewBuffer(nil)
+	appcmdtesting.RunCommandSuccess(
+		t,
+		func(use string) *appcmd.Command {
+			return NewCommand(
+				use,
+				appflag.NewBuilder(),
+				CommandWithBufVersion(bufVersion),
+			)
+		},
+		nil,
+		nil,
+		stdout,
+		"-I",
+		includeDirPath,
+		"-o",
+		"-",
+		fmt.Sprintf("--%s=%s", imageCacheDirFlagName, cacheDirPath),
+		filePath,
+	)
+	fileDescriptorSet := &descriptorpb.FileDescriptorSet{}
+	require.NoError(t, protoencoding.NewWireUnmarshaler(nil).Unmarshal(stdout.Bytes(), fileDescriptorSet))
+	return fileDescriptorSet
+}
+
+func testPluginDescriptorSet(t *testing.T, extraArgs []string, expectedFileNames []string) {
+	tmpDir, err := tmp.NewDir("")
 	require.NoError(t, err)
-	diff, err := storage.Diff(
-		context.Background(),
-		actualReadWriteBucket,
-		bufReadWriteBucket,
-		"protoc",
-		"buf-protoc",
+	outDirPath := filepath.Join(tmpDir.AbsPath(), "out")
+	require.NoError(t, os.Mkdir(outDirPath, 0755))
+	pluginPath := filepath.Join(tmpDir.AbsPath(), "protoc-gen-fake")
+	// the consumer copies the FileDescriptorSet it is given to the output
+	// directory and records its path so we can check it was cleaned up
+	require.NoError(
+		t,
+		ioutil.WriteFile(
+			pluginPath,
+			[]byte(`#!/bin/sh
+set -e
+test "${1}" = "--input"
+cp "${2}" "${3}/image.bin"
+echo "${2}" > "${3}/path.txt"
+`),
+			0755,
+		),
+	)
+	args := append(
+		[]string{
+			"-I",
+			filepath.Join("testdata", "wkt"),
+			fmt.Sprintf("--%s=protoc-gen-fake=%s", pluginPathValuesFlagName, pluginPath),
+			fmt.Sprintf("--fake_out=%s", outDirPath),
+			"--fake_opt=--input {descriptor_set} {out}",
+			fmt.Sprintf("--%s=fake", pluginDescriptorSetFlagName),
+		},
+		extraArgs...,
 	)
+	appcmdtesting.RunCommandSuccess(
+		t,
+		func(use string) *appcmd.Command {
+			return NewCommand(
+				use,
+				appflag.NewBuilder(),
+			)
+		},
+		nil,
+		nil,
+		nil,
+		append(args, filepath.Join("testdata", "wkt", "a.proto"))...,
+	)
+	data, err := ioutil.ReadFile(filepath.Join(outDirPath, "image.bin"))
 	require.NoError(t, err)
-	assert.Empty(t, string(diff))
+	fileDescriptorSet := &descriptorpb.FileDescriptorSet{}
+	require.NoError(t, protoencoding.NewWireUnmarshaler(nil).Unmarshal(data, fileDescriptorSet))
+	fileNames := make([]string, 0, len(fileDescriptorSet.File))
+	for _, fileDescriptorProto := range fileDescriptorSet.File {
+		fileNames = append(fileNames, fileDescriptorProto.GetName())
+	}
+	assert.Equal(t, expectedFileNames, fileNames)
+	data, err = ioutil.ReadFile(filepath.Join(outDirPath, "path.txt"))
+	require.NoError(t, err)
+	_, err = os.Stat(strings.TrimSpace(string(data)))
+	assert.True(t, os.IsNotExist(err))
+	require.NoError(t, tmpDir.Close())
 }
 
-func testGetBufProtocFileDescriptorSet(t *testing.T, dirPath string) *descriptorpb.FileDescriptorSet {
-	data := testGetBufProtocFileDescriptorSetBytes(t, dirPath)
+// testRewriteImports returns the public dependencies of each file in the output.
+func testRewriteImports(t *testing.T, extraArgs ...string) map[string][]int32 {
+	stdout := bytes.NewBuffer(nil)
+	appcmdtesting.RunCommandSuccess(
+		t,
+		func(use string) *appcmd.Command {
+			return NewCommand(
+				use,
+				appflag.NewBuilder(),
+			)
+		},
+		nil,
+		nil,
+		stdout,
+		append(
+			append(
+				[]string{
+					"-I",
+					filepath.Join("testdata", "rewriteimports"),
+					"-o",
+					"-",
+					fmt.Sprintf("--%s", includeImportsFlagName),
+				},
+				extraArgs...,
+			),
+			filepath.Join("testdata", "rewriteimports", "acme", "a.proto"),
+		)...,
+	)
 	fileDescriptorSet := &descriptorpb.FileDescriptorSet{}
-	// TODO: change to image read logic
-	require.NoError(
+	require.NoError(t, protoencoding.NewWireUnmarshaler(nil).Unmarshal(stdout.Bytes(), fileDescriptorSet))
+	fileNameToPublicDependency := make(map[string][]int32, len(fileDescriptorSet.File))
+	for _, fileDescriptorProto := range fileDescriptorSet.File {
+		fileNameToPublicDependency[fileDescriptorProto.GetName()] = fileDescriptorProto.PublicDependency
+	}
+	return fileNameToPublicDependency
+}
+
+func testRunStripSourceInfo(t *testing.T, extraArgs ...string) *descriptorpb.FileDescriptorSet {
+	stdout := bytes.NewBuffer(nil)
+	appcmdtesting.RunCommandSuccess(
 		t,
-		protoencoding.NewWireUnmarshaler(nil).Unmarshal(
-			data,
-			fileDescriptorSet,
-		),
+		func(use string) *appcmd.Command {
+			return NewCommand(
+				use,
+				appflag.NewBuilder(),
+			)
+		},
+		nil,
+		nil,
+		stdout,
+		append(
+			append(
+				[]string{
+					"-I",
+					filepath.Join("testdata", "freefieldnumbers"),
+					"-o",
+					"-",
+				},
+				extraArgs...,
+			),
+			filepath.Join("testdata", "freefieldnumbers", "a.proto"),
+		)...,
 	)
+	fileDescriptorSet := &descriptorpb.FileDescriptorSet{}
+	require.NoError(t, protoencoding.NewWireUnmarshaler(nil).Unmarshal(stdout.Bytes(), fileDescriptorSet))
 	return fileDescriptorSet
 }
 
-func testGetBufProtocFileDescriptorSetBytes(t *testing.T, dirPath string) []byte {
+func testRunOutputFormat(t *testing.T, format string) []byte {
+	stdout := bytes.NewBuffer(nil)
+	appcmdtesting.RunCommandSuccess(
+		t,
+		func(use string) *appcmd.Command {
+			return NewCommand(
+				use,
+				appflag.NewBuilder(),
+			)
+		},
+		nil,
+		nil,
+		stdout,
+		"-I",
+		filepath.Join("testdata", "freefieldnumbers"),
+		"-o",
+		"-",
+		fmt.Sprintf("--%s=%s", outputFormatFlagName, format),
+		filepath.Join("testdata", "freefieldnumbers", "a.proto"),
+	)
+	return stdout.Bytes()
+}
+
+func testRunOutputFileOrder(t *testing.T, filePaths ...string) []byte {
 	stdout := bytes.NewBuffer(nil)
 	appcmdtesting.RunCommandSuccess(
 		t,
@@ -219,12 +1673,84 @@ func testGetBufProtocFileDescriptorSetBytes(t *testing.T, dirPath string) []byte
 		append(
 			[]string{
 				"-I",
-				dirPath,
+				filepath.Join("testdata", "checkonly"),
 				"-o",
 				"-",
 			},
-			buftesting.GetProtocFilePaths(t, dirPath, 1000)...,
+			filePaths...,
 		)...,
 	)
 	return stdout.Bytes()
 }
+
+func testImageFileNames(image bufcore.Image) []string {
+	fileNames := make([]string, 0, len(image.Files()))
+	for _, imageFile := range image.Files() {
+		fileNames = append(fileNames, imageFile.Path())
+	}
+	return fileNames
+}
+
+func testRunVerify(t *testing.T, expectedExitCode int, args ...string) string {
+	stderr := bytes.NewBuffer(nil)
+	exitCode := app.GetExitCode(
+		appcmd.Run(
+			context.Background(),
+			app.NewContainer(
+				nil,
+				nil,
+				bytes.NewBuffer(nil),
+				stderr,
+				append([]string{"test"}, args...)...,
+			),
+			NewCommand("test", appflag.NewBuilder()),
+		),
+	)
+	require.Equal(t, expectedExitCode, exitCode, stderr.String())
+	return stderr.String()
+}
+
+func testRunCheckOnly(t *testing.T, expectedExitCode int, args ...string) string {
+	stderr := bytes.NewBuffer(nil)
+	exitCode := app.GetExitCode(
+		appcmd.Run(
+			context.Background(),
+			app.NewContainer(
+				nil,
+				nil,
+				bytes.NewBuffer(nil),
+				stderr,
+				append([]string{"test"}, args...)...,
+			),
+			NewCommand("test", appflag.NewBuilder()),
+		),
+	)
+	require.Equal(t, expectedExitCode, exitCode, stderr.String())
+	return stderr.String()
+}
+
+func testRunBuiltinWKT(t *testing.T, expectedExitCode int, extraArgs ...string) {
+	appcmdtesting.RunCommandExitCode(
+		t,
+		func(use string) *appcmd.Command {
+			return NewCommand(
+				use,
+				appflag.NewBuilder(),
+			)
+		},
+		expectedExitCode,
+		nil,
+		nil,
+		nil,
+		append(
+			[]string{
+				"-I",
+				filepath.Join("testdata", "wkt"),
+				"-o",
+				app.DevNullFilePath,
+				filepath.Join("testdata", "wkt", "a.proto"),
+			},
+			extraArgs...,
+		)...,
+	)
+}
@@ -0,0 +1,87 @@
+// Copyright 2020 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protoc
+
+import (
+	"regexp"
+
+	"github.com/bufbuild/buf/internal/buf/bufcore"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// rewriteImports returns a copy of the Image where all imports of the files matching
+// publicImports are public, and no imports of the files matching privateImports are public.
+//
+// Either regular expression may be empty, in which case no files match it.
+func rewriteImports(image bufcore.Image, publicImports string, privateImports string) (bufcore.Image, error) {
+	publicImportsRegexp, err := compileImportsRegexp(publicImportsFlagName, publicImports)
+	if err != nil {
+		return nil, err
+	}
+	privateImportsRegexp, err := compileImportsRegexp(privateImportsFlagName, privateImports)
+	if err != nil {
+		return nil, err
+	}
+	imageFiles := make([]bufcore.ImageFile, len(image.Files()))
+	for i, imageFile := range image.Files() {
+		public := publicImportsRegexp != nil && publicImportsRegexp.MatchString(imageFile.Path())
+		private := privateImportsRegexp != nil && privateImportsRegexp.MatchString(imageFile.Path())
+		if public && private {
+			return nil, newPublicAndPrivateImportsError(imageFile.Path())
+		}
+		if !public && !private {
+			imageFiles[i] = imageFile
+			continue
+		}
+		// the FileDescriptorProto is cloned as it may be shared with other images
+		fileDescriptorProto := proto.Clone(imageFile.Proto()).(*descriptorpb.FileDescriptorProto)
+		fileDescriptorProto.PublicDependency = nil
+		if public {
+			fileDescriptorProto.PublicDependency = getNonWeakDependencyIndexes(fileDescriptorProto)
+		}
+		imageFiles[i], err = bufcore.NewImageFile(fileDescriptorProto, imageFile.ExternalPath(), imageFile.IsImport())
+		if err != nil {
+			return nil, err
+		}
+	}
+	return bufcore.NewImage(imageFiles)
+}
+
+func compileImportsRegexp(flagName string, value string) (*regexp.Regexp, error) {
+	if value == "" {
+		return nil, nil
+	}
+	compiled, err := regexp.Compile(value)
+	if err != nil {
+		return nil, newImportsRegexInvalidError(flagName, value, err)
+	}
+	return compiled, nil
+}
+
+// getNonWeakDependencyIndexes returns the indexes of the dependencies that are not weak.
+func getNonWeakDependencyIndexes(fileDescriptorProto *descriptorpb.FileDescriptorProto) []int32 {
+	weakIndexes := make(map[int32]struct{}, len(fileDescriptorProto.WeakDependency))
+	for _, weakIndex := range fileDescriptorProto.WeakDependency {
+		weakIndexes[weakIndex] = struct{}{}
+	}
+	var indexes []int32
+	for i := range fileDescriptorProto.Dependency {
+		if _, ok := weakIndexes[int32(i)]; !ok {
+			indexes = append(indexes, int32(i))
+		}
+	}
+	return indexes
+}
@@ -0,0 +1,38 @@
+// Copyright 2020 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protoc
+
+import (
+	"math/rand"
+
+	"github.com/bufbuild/buf/internal/buf/bufcore"
+)
+
+// shuffleFiles returns a copy of the Image with the files deterministically
+// permuted by seed.
+//
+// The result is no longer in DAG order, which is the point: this is used to
+// test that consumers of a FileDescriptorSet do not depend on its file order.
+func shuffleFiles(image bufcore.Image, seed int64) (bufcore.Image, error) {
+	imageFiles := make([]bufcore.ImageFile, len(image.Files()))
+	copy(imageFiles, image.Files())
+	rand.New(rand.NewSource(seed)).Shuffle(
+		len(imageFiles),
+		func(i int, j int) {
+			imageFiles[i], imageFiles[j] = imageFiles[j], imageFiles[i]
+		},
+	)
+	return bufcore.NewImage(imageFiles)
+}
@@ -0,0 +1,43 @@
+// Copyright 2020 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protoc
+
+import (
+	"github.com/bufbuild/buf/internal/buf/bufcore"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// stripSourceInfo returns a copy of the Image without source code info.
+//
+// The given Image is not modified.
+func stripSourceInfo(image bufcore.Image) (bufcore.Image, error) {
+	imageFiles := make([]bufcore.ImageFile, len(image.Files()))
+	for i, imageFile := range image.Files() {
+		if imageFile.Proto().GetSourceCodeInfo() == nil {
+			imageFiles[i] = imageFile
+			continue
+		}
+		// the FileDescriptorProto is cloned as it may be shared with other images
+		fileDescriptorProto := proto.Clone(imageFile.Proto()).(*descriptorpb.FileDescriptorProto)
+		fileDescriptorProto.SourceCodeInfo = nil
+		var err error
+		imageFiles[i], err = bufcore.NewImageFile(fileDescriptorProto, imageFile.ExternalPath(), imageFile.IsImport())
+		if err != nil {
+			return nil, err
+		}
+	}
+	return bufcore.NewImage(imageFiles)
+}
@@ -0,0 +1,138 @@
+// Copyright 2020 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protoc
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bufbuild/buf/internal/buf/bufcore"
+	"github.com/bufbuild/buf/internal/buf/bufwire"
+	"github.com/bufbuild/buf/internal/buf/cmd/internal"
+	"github.com/bufbuild/buf/internal/pkg/app"
+	"github.com/bufbuild/buf/internal/pkg/app/applog"
+	"github.com/bufbuild/buf/internal/pkg/tmp"
+	"go.uber.org/multierr"
+	"google.golang.org/protobuf/proto"
+)
+
+// verifyOutput writes the image to a temporary file in the same format as the output
+// and returns an error if the output does not exist or differs from the temporary file.
+//
+// If the output differs, the files that would be added, removed, or changed are
+// printed to stderr. The output is never written.
+func verifyOutput(
+	ctx context.Context,
+	container applog.Container,
+	output string,
+	image bufcore.Image,
+	excludeImports bool,
+	putImageOptions ...bufwire.PutImageOption,
+) (retErr error) {
+	outputPath, outputOptions := output, ""
+	if index := strings.Index(output, "#"); index >= 0 {
+		outputPath, outputOptions = output[:index], output[index:]
+	}
+	if outputPath == "-" || outputPath == app.DevNullFilePath {
+		return fmt.Errorf("cannot call --%s when --%s is %q", verifyFlagName, outputFlagName, outputPath)
+	}
+	tmpDir, err := tmp.NewDir("")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		retErr = multierr.Append(retErr, tmpDir.Close())
+	}()
+	// the base name is kept so that the format is derived from the extension as for the output
+	tmpOutputPath := filepath.Join(tmpDir.AbsPath(), filepath.Base(outputPath))
+	if err := internal.NewBufwireImageWriter(container.Logger()).PutImage(
+		ctx,
+		container,
+		tmpOutputPath+outputOptions,
+		image,
+		true,
+		excludeImports,
+		putImageOptions...,
+	); err != nil {
+		return err
+	}
+	data, err := ioutil.ReadFile(tmpOutputPath)
+	if err != nil {
+		return err
+	}
+	existingData, err := ioutil.ReadFile(outputPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("--%s: %s does not exist and would be created", verifyFlagName, outputPath)
+		}
+		return err
+	}
+	if bytes.Equal(data, existingData) {
+		return nil
+	}
+	imageReader := internal.NewBufwireImageReader(container.Logger(), outputFlagName)
+	newImage, err := imageReader.GetImage(ctx, container, tmpOutputPath+outputOptions, nil, false, false)
+	if err != nil {
+		return err
+	}
+	existingImage, err := imageReader.GetImage(ctx, container, output, nil, false, false)
+	if err != nil {
+		// the existing output may not be an image at all
+		existingImage = nil
+	}
+	if err := printVerifyDiff(container.Stderr(), existingImage, newImage); err != nil {
+		return err
+	}
+	return fmt.Errorf("--%s: %s would change", verifyFlagName, outputPath)
+}
+
+// printVerifyDiff prints the files that differ between the existing and new images.
+//
+// existingImage is nil if the existing output could not be read as an image.
+func printVerifyDiff(writer io.Writer, existingImage bufcore.Image, newImage bufcore.Image) error {
+	if existingImage == nil {
+		_, err := fmt.Fprintln(writer, "existing output is not a valid image")
+		return err
+	}
+	var lines []string
+	for _, newFile := range newImage.Files() {
+		existingFile := existingImage.GetFile(newFile.Path())
+		switch {
+		case existingFile == nil:
+			lines = append(lines, "added: "+newFile.Path())
+		case !proto.Equal(existingFile.Proto(), newFile.Proto()):
+			lines = append(lines, "changed: "+newFile.Path())
+		}
+	}
+	for _, existingFile := range existingImage.Files() {
+		if newImage.GetFile(existingFile.Path()) == nil {
+			lines = append(lines, "removed: "+existingFile.Path())
+		}
+	}
+	if len(lines) == 0 {
+		// every file is equal, so only the order or encoding differs
+		lines = append(lines, "the order or encoding of the files changed")
+	}
+	sort.Strings(lines)
+	_, err := fmt.Fprintln(writer, strings.Join(lines, "\n"))
+	return err
+}
@@ -18,14 +18,25 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
 
 	"github.com/bufbuild/buf/internal/buf/bufanalysis"
 	"github.com/bufbuild/buf/internal/buf/bufcheck"
 	"github.com/bufbuild/buf/internal/buf/bufcheck/bufbreaking"
 	"github.com/bufbuild/buf/internal/buf/bufcheck/buflint"
+	"github.com/bufbuild/buf/internal/buf/bufconfig"
 	"github.com/bufbuild/buf/internal/buf/bufcore"
+	"github.com/bufbuild/buf/internal/buf/buffetch"
+	"github.com/bufbuild/buf/internal/buf/bufwire"
 	"github.com/bufbuild/buf/internal/buf/cmd/internal"
+	"github.com/bufbuild/buf/internal/pkg/app"
 	"github.com/bufbuild/buf/internal/pkg/app/applog"
+	"github.com/bufbuild/buf/internal/pkg/filewatch"
+	"github.com/bufbuild/buf/internal/pkg/stringutil"
+	"go.uber.org/multierr"
 )
 
 func imageBuild(ctx context.Context, container applog.Container, flags *flags) (retErr error) {
@@ -109,19 +120,138 @@ func imageConvert(ctx context.Context, container applog.Container, flags *flags)
 	)
 }
 
-func checkLint(ctx context.Context, container applog.Container, flags *flags) (retErr error) {
+func imageMerge(ctx context.Context, container applog.Container, flags *flags) (retErr error) {
+	internal.WarnExperimental(container)
+	if len(flags.MergeInputs) == 0 {
+		return fmt.Errorf("--%s is required", imageMergeInputFlagName)
+	}
+	if flags.Output == "" {
+		return fmt.Errorf("--%s is required", imageMergeOutputFlagName)
+	}
+	imageReader := internal.NewBufwireImageReader(
+		container.Logger(),
+		imageMergeInputFlagName,
+	)
+	images := make([]bufcore.Image, 0, len(flags.MergeInputs))
+	for _, mergeInput := range flags.MergeInputs {
+		image, err := imageReader.GetImage(
+			ctx,
+			container,
+			mergeInput,
+			nil,
+			false,
+			flags.ExcludeSourceInfo,
+		)
+		if err != nil {
+			return err
+		}
+		images = append(images, image)
+	}
+	image, err := bufcore.MergeImages(images...)
+	if err != nil {
+		return err
+	}
+	return internal.NewBufwireImageWriter(
+		container.Logger(),
+	).PutImage(
+		ctx,
+		container,
+		flags.Output,
+		image,
+		flags.AsFileDescriptorSet,
+		flags.ExcludeImports,
+	)
+}
+
+func checkLint(ctx context.Context, container applog.Container, flags *flags) error {
+	if flags.Watch {
+		return checkLintWatch(ctx, container, flags)
+	}
+	return checkLintOnce(ctx, container, flags)
+}
+
+// checkLintWatch runs checkLintOnce each time the files in the input directory change.
+//
+// Errors from checkLintOnce are printed and do not stop the watch, as they are
+// usually the result of a file that is being edited.
+//
+// Once the context is done, for example on --timeout, the error of the last run is
+// returned so that the exit code reflects the last result, or if the last run
+// succeeded, the error of the context.
+func checkLintWatch(ctx context.Context, container applog.Container, flags *flags) error {
+	if flags.DiffOnly == "-" {
+		return fmt.Errorf("cannot call --%s with --%s \"-\"", checkLintWatchFlagName, checkLintDiffOnlyFlagName)
+	}
+	if fileInfo, err := os.Stat(flags.Input); err != nil || !fileInfo.IsDir() {
+		return fmt.Errorf("--%s requires --%s to be a directory", checkLintWatchFlagName, checkLintInputFlagName)
+	}
+	clear := app.IsTerminal(container.Stdout())
+	var lastErr error
+	err := filewatch.Watch(
+		ctx,
+		[]string{flags.Input},
+		func(ctx context.Context) error {
+			if clear {
+				// move the cursor to the top left and clear the screen
+				_, _ = fmt.Fprint(container.Stdout(), "\033[H\033[2J")
+			}
+			lastErr = checkLintOnce(ctx, container, flags)
+			if lastErr != nil && lastErr.Error() != "" && ctx.Err() == nil {
+				_, _ = fmt.Fprintln(container.Stderr(), lastErr)
+			}
+			return nil
+		},
+		filewatch.WatchWithExts(".proto", ".yaml"),
+	)
+	if err != nil && err == ctx.Err() && lastErr != nil {
+		return lastErr
+	}
+	return err
+}
+
+func checkLintOnce(ctx context.Context, container applog.Container, flags *flags) (retErr error) {
+	errorFormatSinks, err := parseErrorFormatSinks(flags.ErrorFormatSinks)
+	if err != nil {
+		return err
+	}
+	input, err := getCheckLintInput(flags.Input, flags.InputFormat)
+	if err != nil {
+		return err
+	}
+	if _, ok := stringutil.SliceToMap(checkLintGroupBys)[flags.GroupBy]; !ok {
+		return fmt.Errorf("--%s: unknown value %q, must be one of %s", checkLintGroupByFlagName, flags.GroupBy, stringutil.SliceToString(checkLintGroupBys))
+	}
+	var configProviderOptions []bufconfig.ProviderOption
+	if flags.Strict {
+		configProviderOptions = append(
+			configProviderOptions,
+			bufconfig.ProviderWithExternalConfigModifier(
+				func(externalConfig *bufconfig.ExternalConfig) error {
+					strict := true
+					externalConfig.Lint.Strict = &strict
+					return nil
+				},
+			),
+		)
+	}
 	env, fileAnnotations, err := internal.NewBufwireEnvReader(
 		container.Logger(),
 		checkLintInputFlagName,
 		checkLintConfigFlagName,
+		configProviderOptions...,
 	).GetEnv(
 		ctx,
 		container,
-		flags.Input,
+		input,
 		flags.Config,
 		flags.Files, // we filter checks for files
 		false,       // input files must exist
 		false,       // we must include source info for linting
+		bufwire.GetEnvWithFileContent(
+			func(config *bufconfig.Config) bool {
+				return config.Lint.RequiresFileContent()
+			},
+		),
 	)
 	if err != nil {
 		return err
@@ -134,9 +264,37 @@ func checkLint(ctx context.Context, container applog.Container, flags *flags) (r
 		if err := bufanalysis.PrintFileAnnotations(container.Stdout(), fileAnnotations, formatString); err != nil {
 			return err
 		}
+		if err := writeErrorFormatSinks(errorFormatSinks, fileAnnotations, true); err != nil {
+			return err
+		}
 		return errors.New("")
 	}
-	fileAnnotations, err = internal.NewBuflintHandler(container.Logger()).Check(
+	handlerOptions := []buflint.HandlerOption{
+		// the content is only available for sources, so rules that operate
+		// on raw file content result in an error for images
+		buflint.HandlerWithFileContentFunc(
+			func(_ context.Context, imageFile bufcore.ImageFile) ([]byte, error) {
+				return env.FileContent(imageFile.Path()), nil
+			},
+		),
+	}
+	// the FileAnnotations of each file are printed as soon as they are complete,
+	// unless printing them requires all FileAnnotations
+	stream := flags.DiffOnly == "" &&
+		flags.MaxAnnotations <= 0 &&
+		flags.GroupBy != "rule" &&
+		strings.ToLower(strings.TrimSpace(flags.ErrorFormat)) != "config-ignore-yaml"
+	if stream {
+		handlerOptions = append(
+			handlerOptions,
+			buflint.HandlerWithFileAnnotationsFunc(
+				func(fileAnnotations []bufanalysis.FileAnnotation) error {
+					return bufanalysis.PrintFileAnnotations(container.Stdout(), fileAnnotations, flags.ErrorFormat)
+				},
+			),
+		)
+	}
+	fileAnnotations, err = internal.NewBuflintHandler(container.Logger(), handlerOptions...).Check(
 		ctx,
 		env.Config().Lint,
 		bufcore.ImageWithoutImports(env.Image()),
@@ -144,19 +302,141 @@ func checkLint(ctx context.Context, container applog.Container, flags *flags) (r
 	if err != nil {
 		return err
 	}
+	if flags.DiffOnly != "" {
+		diffOnlyPaths, err := readDiffOnlyPaths(container, flags.DiffOnly)
+		if err != nil {
+			return err
+		}
+		fileAnnotations = bufanalysis.FilterFileAnnotationsForPaths(fileAnnotations, diffOnlyPaths)
+	}
+	if err := writeErrorFormatSinks(errorFormatSinks, fileAnnotations, false); err != nil {
+		return err
+	}
 	if len(fileAnnotations) > 0 {
-		if err := buflint.PrintFileAnnotations(
-			container.Stdout(),
-			fileAnnotations,
-			flags.ErrorFormat,
-		); err != nil {
+		if !stream {
+			if err := printCheckLintFileAnnotations(
+				container.Stdout(),
+				fileAnnotations,
+				flags.ErrorFormat,
+				flags.MaxAnnotations,
+				flags.GroupBy == "rule",
+			); err != nil {
+				return err
+			}
+		}
+		// warnings are printed but do not result in a non-zero exit code
+		if bufanalysis.ContainsSeverity(fileAnnotations, bufanalysis.SeverityError) {
+			return errors.New("")
+		}
+	}
+	return nil
+}
+
+// printCheckLintFileAnnotations prints at most maxAnnotations of the FileAnnotations
+// if maxAnnotations is set.
+//
+// All FileAnnotations are always printed for config-ignore-yaml, as the resulting
+// config would otherwise not ignore every violation. If groupByRule is set, the
+// printed FileAnnotations are grouped by rule ID.
+func printCheckLintFileAnnotations(
+	writer io.Writer,
+	fileAnnotations []bufanalysis.FileAnnotation,
+	formatString string,
+	maxAnnotations int,
+	groupByRule bool,
+) error {
+	if strings.ToLower(strings.TrimSpace(formatString)) == "config-ignore-yaml" {
+		return buflint.PrintFileAnnotations(writer, fileAnnotations, formatString)
+	}
+	if !groupByRule {
+		if maxAnnotations <= 0 {
+			return buflint.PrintFileAnnotations(writer, fileAnnotations, formatString)
+		}
+		return bufanalysis.PrintFileAnnotationsWithMaxCount(writer, fileAnnotations, formatString, maxAnnotations)
+	}
+	fileAnnotations, numTruncated := bufanalysis.TruncateFileAnnotations(fileAnnotations, maxAnnotations)
+	if err := bufanalysis.PrintFileAnnotationsGroupedByType(writer, fileAnnotations, formatString); err != nil {
+		return err
+	}
+	if numTruncated == 0 || strings.ToLower(strings.TrimSpace(formatString)) == "json" {
+		return nil
+	}
+	_, err := fmt.Fprintf(writer, "... and %d more\n", numTruncated)
+	return err
+}
+
+// errorFormatSink is a file that FileAnnotations are additionally written to.
+type errorFormatSink struct {
+	format string
+	path   string
+}
+
+// getCheckLintInput returns the input with the format option set to inputFormat.
+//
+// If inputFormat is empty, the input is returned unchanged.
+func getCheckLintInput(input string, inputFormat string) (string, error) {
+	if inputFormat == "" {
+		return input, nil
+	}
+	if _, ok := stringutil.SliceToMap(buffetch.ImageFormats)[inputFormat]; !ok {
+		return "", fmt.Errorf("--%s: unknown format %q, must be one of %s", checkLintInputFormatFlagName, inputFormat, buffetch.ImageFormatsString)
+	}
+	if strings.Contains(input, "#") {
+		return input + ",format=" + inputFormat, nil
+	}
+	return input + "#format=" + inputFormat, nil
+}
+
+// parseErrorFormatSinks parses the values of --error-format-sink.
+func parseErrorFormatSinks(values []string) ([]errorFormatSink, error) {
+	errorFormatSinks := make([]errorFormatSink, 0, len(values))
+	for _, value := range values {
+		split := strings.SplitN(value, ":", 2)
+		if len(split) != 2 || split[0] == "" || split[1] == "" {
+			return nil, fmt.Errorf("--%s must be in the form format:path but was %q", checkLintErrorFormatSinkFlagName, value)
+		}
+		format := strings.ToLower(strings.TrimSpace(split[0]))
+		if _, ok := stringutil.SliceToMap(buflint.AllFormatStrings)[format]; !ok {
+			return nil, fmt.Errorf("--%s: unknown format %q, must be one of %s", checkLintErrorFormatSinkFlagName, split[0], stringutil.SliceToString(buflint.AllFormatStrings))
+		}
+		errorFormatSinks = append(
+			errorFormatSinks,
+			errorFormatSink{
+				format: format,
+				path:   split[1],
+			},
+		)
+	}
+	return errorFormatSinks, nil
+}
+
+// writeErrorFormatSinks writes the FileAnnotations to every errorFormatSink.
+//
+// If buildErrors is true, config-ignore-yaml is written as text, as for --error-format.
+func writeErrorFormatSinks(errorFormatSinks []errorFormatSink, fileAnnotations []bufanalysis.FileAnnotation, buildErrors bool) error {
+	for _, errorFormatSink := range errorFormatSinks {
+		format := errorFormatSink.format
+		if buildErrors && format == "config-ignore-yaml" {
+			format = "text"
+		}
+		if err := writeErrorFormatSink(errorFormatSink.path, fileAnnotations, format); err != nil {
 			return err
 		}
-		return errors.New("")
 	}
 	return nil
 }
 
+func writeErrorFormatSink(path string, fileAnnotations []bufanalysis.FileAnnotation, format string) (retErr error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		retErr = multierr.Append(retErr, file.Close())
+	}()
+	return buflint.PrintFileAnnotations(file, fileAnnotations, format)
+}
+
 func checkBreaking(ctx context.Context, container applog.Container, flags *flags) (retErr error) {
 	if flags.AgainstInput == "" {
 		return fmt.Errorf("--%s is required", checkBreakingAgainstInputFlagName)
@@ -319,3 +599,32 @@ func checkLsBreakingCheckers(ctx context.Context, container applog.Container, fl
 		flags.Format,
 	)
 }
+
+// readDiffOnlyPaths reads the newline-separated paths from the file at the
+// given path, or from stdin if the path is "-".
+func readDiffOnlyPaths(container applog.Container, path string) (_ []string, retErr error) {
+	var reader io.Reader
+	if path == "-" {
+		reader = container.Stdin()
+	} else {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("--%s: %v", checkLintDiffOnlyFlagName, err)
+		}
+		defer func() {
+			retErr = multierr.Append(retErr, file.Close())
+		}()
+		reader = file
+	}
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			paths = append(paths, line)
+		}
+	}
+	return paths, nil
+}
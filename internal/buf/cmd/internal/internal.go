@@ -64,6 +64,7 @@ func NewBufwireEnvReader(
 	logger *zap.Logger,
 	inputFlagName string,
 	configOverrideFlagName string,
+	configProviderOptions ...bufconfig.ProviderOption,
 ) bufwire.EnvReader {
 	return bufwire.NewEnvReader(
 		logger,
@@ -76,7 +77,7 @@ func NewBufwireEnvReader(
 			defaultHTTPAuthenticator,
 			git.NewCloner(logger, defaultGitClonerOptions),
 		),
-		bufconfig.NewProvider(logger),
+		bufconfig.NewProvider(logger, configProviderOptions...),
 		bufmod.NewBucketBuilder(logger),
 		bufbuild.NewBuilder(logger),
 		inputFlagName,
@@ -122,9 +123,11 @@ func NewBufwireImageWriter(
 // NewBuflintHandler returns a new buflint.Handler.
 func NewBuflintHandler(
 	logger *zap.Logger,
+	options ...buflint.HandlerOption,
 ) buflint.Handler {
 	return buflint.NewHandler(
 		logger,
+		options...,
 	)
 }
 
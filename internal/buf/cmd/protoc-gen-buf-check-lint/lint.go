@@ -22,6 +22,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/bufbuild/buf/internal/buf/bufanalysis"
 	"github.com/bufbuild/buf/internal/buf/bufcheck/buflint"
 	"github.com/bufbuild/buf/internal/buf/bufcore"
 	"github.com/bufbuild/buf/internal/buf/cmd/internal"
@@ -89,7 +90,13 @@ func handle(
 		if err := buflint.PrintFileAnnotations(buffer, fileAnnotations, externalConfig.ErrorFormat); err != nil {
 			return err
 		}
-		return errors.New(strings.TrimSpace(buffer.String()))
+		if bufanalysis.ContainsSeverity(fileAnnotations, bufanalysis.SeverityError) {
+			return errors.New(strings.TrimSpace(buffer.String()))
+		}
+		// warnings do not fail the plugin, so we only print them
+		if _, err := container.Stderr().Write(buffer.Bytes()); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -277,6 +277,21 @@ func IsDevNull(path string) bool {
 	return path != "" && path == DevNullFilePath
 }
 
+// IsTerminal returns true if the writer is a terminal.
+//
+// This is false for anything other than an *os.File.
+func IsTerminal(writer io.Writer) bool {
+	file, ok := writer.(*os.File)
+	if !ok {
+		return false
+	}
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return fileInfo.Mode()&os.ModeCharDevice != 0
+}
+
 // Main runs the application using the OS Container and calling os.Exit on the return value of Run.
 func Main(ctx context.Context, f func(context.Context, Container) error) {
 	container, err := NewContainerForOS()
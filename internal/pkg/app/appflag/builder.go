@@ -17,19 +17,30 @@ package appflag
 import (
 	"context"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"os"
 	"time"
 
 	"github.com/bufbuild/buf/internal/pkg/app"
 	"github.com/bufbuild/buf/internal/pkg/app/applog"
+	"github.com/bufbuild/buf/internal/pkg/instrument"
 	"github.com/pkg/profile"
 	"github.com/spf13/pflag"
+	"go.uber.org/multierr"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 type builder struct {
 	logLevel  string
 	logFormat string
+	logFile   string
+	// logFormatFlag is used to check if the log format was set explicitly
+	logFormatFlag *pflag.Flag
+
+	timing       bool
+	timingFormat string
 
 	profile           bool
 	profilePath       string
@@ -52,10 +63,14 @@ func newBuilder(options ...BuilderOption) *builder {
 
 func (b *builder) BindRoot(flagSet *pflag.FlagSet) {
 	flagSet.StringVar(&b.logLevel, "log-level", "info", "The log level [debug,info,warn,error].")
-	flagSet.StringVar(&b.logFormat, "log-format", "color", "The log format [text,color,json].")
+	flagSet.StringVar(&b.logFormat, "log-format", "color", "The log format [text,color,json]. Defaults to text if --log-file is set.")
+	b.logFormatFlag = flagSet.Lookup("log-format")
+	flagSet.StringVar(&b.logFile, "log-file", "", "The file to append logs to instead of stderr.")
 	if b.defaultTimeout > 0 {
 		flagSet.DurationVar(&b.timeout, "timeout", b.defaultTimeout, `The duration until timing out.`)
 	}
+	flagSet.BoolVar(&b.timing, "timing", false, "Print a breakdown of how long each stage took to stderr after the run.")
+	flagSet.StringVar(&b.timingFormat, "timing-format", "text", "The timing format [text,json].")
 
 	flagSet.BoolVar(&b.profile, "profile", false, "Run profiling.")
 	_ = flagSet.MarkHidden("profile")
@@ -81,11 +96,45 @@ func (b *builder) run(
 	ctx context.Context,
 	appContainer app.Container,
 	f func(context.Context, applog.Container) error,
-) error {
-	logger, err := applog.NewLogger(appContainer.Stderr(), b.logLevel, b.logFormat)
+) (retErr error) {
+	var logWriter io.Writer = appContainer.Stderr()
+	logFormat := b.logFormat
+	if b.logFile != "" {
+		file, err := os.OpenFile(b.logFile, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+		if err != nil {
+			return err
+		}
+		// registered first so that the file is closed after the end log below
+		defer func() {
+			retErr = multierr.Append(retErr, file.Close())
+		}()
+		logWriter = file
+		// color escape sequences only make sense on a terminal
+		if b.logFormatFlag == nil || !b.logFormatFlag.Changed {
+			logFormat = "text"
+		}
+	}
+	logger, err := applog.NewLogger(logWriter, b.logLevel, logFormat)
 	if err != nil {
 		return err
 	}
+	if b.timing {
+		if err := validateTimingFormat(b.timingFormat); err != nil {
+			return err
+		}
+		recorder := instrument.NewRecorder()
+		logger = logger.WithOptions(
+			zap.WrapCore(
+				func(core zapcore.Core) zapcore.Core {
+					return zapcore.NewTee(core, recorder.Core())
+				},
+			),
+		)
+		// registered before the end log below so that the total duration is included
+		defer func() {
+			retErr = multierr.Append(retErr, printTimings(appContainer.Stderr(), recorder.Timings(), b.timingFormat))
+		}()
+	}
 	start := time.Now()
 	logger.Debug("start")
 	defer func() {
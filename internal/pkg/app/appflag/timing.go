@@ -0,0 +1,109 @@
+// Copyright 2020 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package appflag
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/bufbuild/buf/internal/pkg/instrument"
+	"go.uber.org/multierr"
+)
+
+type externalTiming struct {
+	Name     string                 `json:"name,omitempty"`
+	Duration float64                `json:"duration"`
+	Fields   map[string]interface{} `json:"fields,omitempty"`
+}
+
+func validateTimingFormat(format string) error {
+	switch s := strings.ToLower(strings.TrimSpace(format)); s {
+	case "", "text", "json":
+		return nil
+	default:
+		return fmt.Errorf("unknown timing format [text,json]: %q", s)
+	}
+}
+
+// printTimings prints the timings.
+//
+// The text format is a table of names, durations, and fields.
+// The json format is one object per line with the duration in seconds.
+func printTimings(writer io.Writer, timings []*instrument.Timing, format string) (retErr error) {
+	if len(timings) == 0 {
+		return nil
+	}
+	if strings.ToLower(strings.TrimSpace(format)) == "json" {
+		for _, timing := range timings {
+			data, err := json.Marshal(
+				&externalTiming{
+					Name:     getTimingName(timing),
+					Duration: timing.Duration.Seconds(),
+					Fields:   timing.Fields,
+				},
+			)
+			if err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintln(writer, string(data)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	tabWriter := tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0)
+	defer func() {
+		retErr = multierr.Append(retErr, tabWriter.Flush())
+	}()
+	if _, err := fmt.Fprintln(tabWriter, "NAME\tDURATION\tFIELDS"); err != nil {
+		return err
+	}
+	for _, timing := range timings {
+		if _, err := fmt.Fprintf(
+			tabWriter,
+			"%s\t%v\t%s\n",
+			getTimingName(timing),
+			timing.Duration,
+			getTimingFieldsString(timing),
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func getTimingName(timing *instrument.Timing) string {
+	if timing.Name == "" {
+		return timing.Message
+	}
+	return timing.Name + "." + timing.Message
+}
+
+func getTimingFieldsString(timing *instrument.Timing) string {
+	keys := make([]string, 0, len(timing.Fields))
+	for key := range timing.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	fields := make([]string, len(keys))
+	for i, key := range keys {
+		fields[i] = fmt.Sprintf("%s=%v", key, timing.Fields[key])
+	}
+	return strings.Join(fields, " ")
+}
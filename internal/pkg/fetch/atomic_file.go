@@ -0,0 +1,109 @@
+// Copyright 2020 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fetch
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"go.uber.org/multierr"
+)
+
+// maxAtomicFileCreateAttempts is the number of random temporary file
+// names to try before giving up, mirroring ioutil.TempFile.
+const maxAtomicFileCreateAttempts = 10000
+
+// atomicFileWriteCloser writes to a temporary file in the same directory as
+// the destination path, and renames the temporary file to the destination
+// path on Close.
+//
+// If any Write fails, Close removes the temporary file instead, so that the
+// destination path is never left with partial contents. If the process is
+// interrupted before Close, only the temporary file is left behind.
+//
+// If a file mode is given, the temporary file is chmod'ed to it before any
+// data is written, so the destination never exists with other permissions.
+type atomicFileWriteCloser struct {
+	file     *os.File
+	path     string
+	writeErr error
+}
+
+func newAtomicFileWriteCloser(path string, fileMode *os.FileMode) (*atomicFileWriteCloser, error) {
+	file, err := createAtomicFileTemp(path)
+	if err != nil {
+		return nil, err
+	}
+	if fileMode != nil {
+		if err := file.Chmod(*fileMode); err != nil {
+			return nil, multierr.Combine(err, file.Close(), os.Remove(file.Name()))
+		}
+	}
+	return &atomicFileWriteCloser{
+		file: file,
+		path: path,
+	}, nil
+}
+
+func (a *atomicFileWriteCloser) Write(p []byte) (int, error) {
+	if a.writeErr != nil {
+		return 0, a.writeErr
+	}
+	n, err := a.file.Write(p)
+	if err != nil {
+		a.writeErr = err
+	}
+	return n, err
+}
+
+func (a *atomicFileWriteCloser) Close() error {
+	tempPath := a.file.Name()
+	if a.writeErr != nil {
+		// the write error was already returned to the caller
+		_ = a.file.Close()
+		return os.Remove(tempPath)
+	}
+	if err := multierr.Append(a.file.Sync(), a.file.Close()); err != nil {
+		return multierr.Append(err, os.Remove(tempPath))
+	}
+	if err := os.Rename(tempPath, a.path); err != nil {
+		return multierr.Append(err, os.Remove(tempPath))
+	}
+	return nil
+}
+
+// createAtomicFileTemp creates the temporary file for path.
+//
+// We do not use ioutil.TempFile as it creates files with mode 0600, while
+// we want the same permissions that os.Create would give the destination.
+func createAtomicFileTemp(path string) (*os.File, error) {
+	dirPath, base := filepath.Split(path)
+	var err error
+	for i := 0; i < maxAtomicFileCreateAttempts; i++ {
+		var file *os.File
+		file, err = os.OpenFile(
+			filepath.Join(dirPath, "."+base+"."+strconv.FormatUint(uint64(rand.Uint32()), 10)+".tmp"),
+			os.O_RDWR|os.O_CREATE|os.O_EXCL,
+			0666,
+		)
+		if os.IsExist(err) {
+			continue
+		}
+		return file, err
+	}
+	return nil, err
+}
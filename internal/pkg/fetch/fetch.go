@@ -18,6 +18,7 @@ import (
 	"context"
 	"io"
 	"net/http"
+	"os"
 
 	"github.com/bufbuild/buf/internal/pkg/app"
 	"github.com/bufbuild/buf/internal/pkg/git"
@@ -526,3 +527,14 @@ func WithPutFileNoFileCompression() PutFileOption {
 		putFileOptions.noFileCompression = true
 	}
 }
+
+// WithPutFileMode says to put local files with the given permission bits.
+//
+// The mode is applied as-is, regardless of the umask.
+// The default is to use the same permissions as os.Create.
+// This has no effect for non-local files.
+func WithPutFileMode(fileMode os.FileMode) PutFileOption {
+	return func(putFileOptions *putFileOptions) {
+		putFileOptions.fileMode = &fileMode
+	}
+}
@@ -56,6 +56,49 @@ func TestRoundTripBinZst(t *testing.T) {
 	)
 }
 
+func TestPutFileWriteFailure(t *testing.T) {
+	t.Parallel()
+
+	logger := zap.NewNop()
+	refParser := testNewRefParser(logger)
+	writer := testNewWriter(logger)
+
+	ctx := context.Background()
+	container := app.NewContainer(nil, nil, nil, nil)
+
+	tmpDir, err := tmp.NewDir("")
+	require.NoError(t, err)
+	filePath := filepath.Join(tmpDir.AbsPath(), "file.bin")
+	require.NoError(t, ioutil.WriteFile(filePath, []byte("original"), 0644))
+
+	parsedRef, err := refParser.GetParsedRef(ctx, filePath)
+	require.NoError(t, err)
+	fileRef, ok := parsedRef.(FileRef)
+	require.True(t, ok)
+
+	writeCloser, err := writer.PutFile(ctx, container, fileRef)
+	require.NoError(t, err)
+	_, err = writeCloser.Write([]byte("new"))
+	require.NoError(t, err)
+	// closing the temporary file makes every further write fail
+	atomicFileWriteCloser, ok := writeCloser.(*atomicFileWriteCloser)
+	require.True(t, ok)
+	require.NoError(t, atomicFileWriteCloser.file.Close())
+	_, err = writeCloser.Write([]byte("data"))
+	require.Error(t, err)
+	require.NoError(t, writeCloser.Close())
+
+	data, err := ioutil.ReadFile(filePath)
+	require.NoError(t, err)
+	require.Equal(t, "original", string(data))
+	fileInfos, err := ioutil.ReadDir(tmpDir.AbsPath())
+	require.NoError(t, err)
+	require.Len(t, fileInfos, 1)
+	require.Equal(t, "file.bin", fileInfos[0].Name())
+
+	require.NoError(t, tmpDir.Close())
+}
+
 func testRoundTripLocalFile(
 	t *testing.T,
 	filename string,
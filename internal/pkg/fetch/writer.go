@@ -68,6 +68,7 @@ func (w *writer) PutFile(
 			container,
 			t,
 			putFileOptions.noFileCompression,
+			putFileOptions.fileMode,
 		)
 	case ArchiveRef:
 		return w.putArchiveFile(
@@ -75,6 +76,7 @@ func (w *writer) PutFile(
 			container,
 			t,
 			putFileOptions.noFileCompression,
+			putFileOptions.fileMode,
 		)
 	default:
 		return nil, fmt.Errorf("unknown FileRef type: %T", fileRef)
@@ -86,8 +88,9 @@ func (w *writer) putSingle(
 	container app.EnvStdoutContainer,
 	singleRef SingleRef,
 	noFileCompression bool,
+	fileMode *os.FileMode,
 ) (io.WriteCloser, error) {
-	return w.putFileWriteCloser(ctx, container, singleRef, noFileCompression)
+	return w.putFileWriteCloser(ctx, container, singleRef, noFileCompression, fileMode)
 }
 
 func (w *writer) putArchiveFile(
@@ -95,8 +98,9 @@ func (w *writer) putArchiveFile(
 	container app.EnvStdoutContainer,
 	archiveRef ArchiveRef,
 	noFileCompression bool,
+	fileMode *os.FileMode,
 ) (io.WriteCloser, error) {
-	return w.putFileWriteCloser(ctx, container, archiveRef, noFileCompression)
+	return w.putFileWriteCloser(ctx, container, archiveRef, noFileCompression, fileMode)
 }
 
 func (w *writer) putFileWriteCloser(
@@ -104,8 +108,9 @@ func (w *writer) putFileWriteCloser(
 	container app.EnvStdoutContainer,
 	fileRef FileRef,
 	noFileCompression bool,
+	fileMode *os.FileMode,
 ) (_ io.WriteCloser, retErr error) {
-	writeCloser, err := w.putFileWriteCloserPotentiallyUncompressed(ctx, container, fileRef)
+	writeCloser, err := w.putFileWriteCloserPotentiallyUncompressed(ctx, container, fileRef, fileMode)
 	if err != nil {
 		return nil, err
 	}
@@ -150,6 +155,7 @@ func (w *writer) putFileWriteCloserPotentiallyUncompressed(
 	ctx context.Context,
 	container app.EnvStdoutContainer,
 	fileRef FileRef,
+	fileMode *os.FileMode,
 ) (io.WriteCloser, error) {
 	switch fileScheme := fileRef.FileScheme(); fileScheme {
 	case FileSchemeHTTP:
@@ -166,7 +172,11 @@ func (w *writer) putFileWriteCloserPotentiallyUncompressed(
 		if !w.localEnabled {
 			return nil, newWriteLocalDisabledError()
 		}
-		return os.Create(fileRef.Path())
+		atomicFileWriteCloser, err := newAtomicFileWriteCloser(fileRef.Path(), fileMode)
+		if err != nil {
+			return nil, err
+		}
+		return atomicFileWriteCloser, nil
 	case FileSchemeStdio, FileSchemeStdout:
 		if !w.stdioEnabled {
 			return nil, newWriteStdioDisabledError()
@@ -183,6 +193,8 @@ func (w *writer) putFileWriteCloserPotentiallyUncompressed(
 
 type putFileOptions struct {
 	noFileCompression bool
+	// nil to use the default
+	fileMode *os.FileMode
 }
 
 func newPutFileOptions() *putFileOptions {
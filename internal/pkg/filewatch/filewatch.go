@@ -0,0 +1,209 @@
+// Copyright 2020 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package filewatch watches directories for file changes.
+package filewatch
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	defaultPollInterval = 500 * time.Millisecond
+	defaultDebounce     = 200 * time.Millisecond
+)
+
+// Watch calls f, and then calls f again each time the files in the directories
+// change, until the context is done.
+//
+// The directories are walked recursively. A change is any file being added,
+// removed, or modified, where modifications are detected by a change of the size
+// or modification time. Changes are debounced, that is f is only called again
+// once the files have not changed for the debounce duration.
+//
+// The directories are polled instead of using filesystem notifications, so that
+// this works the same on every platform and for any number of files. Notification
+// APIs such as inotify are not recursive, require a watch per directory that
+// counts against a per-user limit, and coalesce or drop events differently per
+// platform, while polling the few files of a proto input is cheap.
+//
+// Returns the error of the context once the context is done, or the first error returned from f.
+// The directories must exist when Watch is called, but may be removed afterwards.
+func Watch(
+	ctx context.Context,
+	dirPaths []string,
+	f func(context.Context) error,
+	options ...WatchOption,
+) error {
+	watchOptions := newWatchOptions()
+	for _, option := range options {
+		option(watchOptions)
+	}
+	for _, dirPath := range dirPaths {
+		fileInfo, err := os.Stat(dirPath)
+		if err != nil {
+			return err
+		}
+		if !fileInfo.IsDir() {
+			return fmt.Errorf("%s is not a directory", dirPath)
+		}
+	}
+	current, err := newSnapshot(dirPaths, watchOptions.exts)
+	if err != nil {
+		return err
+	}
+	if err := f(ctx); err != nil {
+		return err
+	}
+	ticker := time.NewTicker(watchOptions.pollInterval)
+	defer ticker.Stop()
+	pending := false
+	var lastChange time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		next, err := newSnapshot(dirPaths, watchOptions.exts)
+		if err != nil {
+			return err
+		}
+		if !next.equal(current) {
+			current = next
+			pending = true
+			lastChange = time.Now()
+			continue
+		}
+		if pending && time.Since(lastChange) >= watchOptions.debounce {
+			pending = false
+			if err := f(ctx); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// WatchOption is an option for Watch.
+type WatchOption func(*watchOptions)
+
+// WatchWithExts returns a new WatchOption that only watches the files with
+// one of the given extensions, for example ".proto".
+//
+// The default is to watch all files.
+func WatchWithExts(exts ...string) WatchOption {
+	return func(watchOptions *watchOptions) {
+		watchOptions.exts = append(watchOptions.exts, exts...)
+	}
+}
+
+// WatchWithPollInterval returns a new WatchOption that polls the directories
+// at the given interval.
+//
+// The default is 500ms.
+func WatchWithPollInterval(pollInterval time.Duration) WatchOption {
+	return func(watchOptions *watchOptions) {
+		watchOptions.pollInterval = pollInterval
+	}
+}
+
+// WatchWithDebounce returns a new WatchOption that waits for the files to not
+// change for the given duration before calling the function.
+//
+// The default is 200ms.
+func WatchWithDebounce(debounce time.Duration) WatchOption {
+	return func(watchOptions *watchOptions) {
+		watchOptions.debounce = debounce
+	}
+}
+
+type watchOptions struct {
+	exts         []string
+	pollInterval time.Duration
+	debounce     time.Duration
+}
+
+func newWatchOptions() *watchOptions {
+	return &watchOptions{
+		pollInterval: defaultPollInterval,
+		debounce:     defaultDebounce,
+	}
+}
+
+type fileState struct {
+	size    int64
+	modTime time.Time
+}
+
+// snapshot is a map from file path to state.
+type snapshot map[string]fileState
+
+func newSnapshot(dirPaths []string, exts []string) (snapshot, error) {
+	s := make(snapshot)
+	for _, dirPath := range dirPaths {
+		if err := filepath.Walk(
+			dirPath,
+			func(path string, fileInfo os.FileInfo, err error) error {
+				if err != nil {
+					// files can be removed while walking
+					if os.IsNotExist(err) {
+						return nil
+					}
+					return err
+				}
+				if !fileInfo.Mode().IsRegular() || !hasAnyExt(path, exts) {
+					return nil
+				}
+				s[path] = fileState{
+					size:    fileInfo.Size(),
+					modTime: fileInfo.ModTime(),
+				}
+				return nil
+			},
+		); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+func (s snapshot) equal(other snapshot) bool {
+	if len(s) != len(other) {
+		return false
+	}
+	for path, state := range s {
+		otherState, ok := other[path]
+		if !ok || state.size != otherState.size || !state.modTime.Equal(otherState.modTime) {
+			return false
+		}
+	}
+	return true
+}
+
+func hasAnyExt(path string, exts []string) bool {
+	if len(exts) == 0 {
+		return true
+	}
+	ext := filepath.Ext(path)
+	for _, e := range exts {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,155 @@
+// Copyright 2020 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filewatch
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bufbuild/buf/internal/pkg/tmp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatch(t *testing.T) {
+	t.Parallel()
+	tmpDir, err := tmp.NewDir("")
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, tmpDir.Close())
+	}()
+	dirPath := tmpDir.AbsPath()
+	require.NoError(t, os.MkdirAll(filepath.Join(dirPath, "foo"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dirPath, "a.proto"), []byte("a"), 0600))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := make(chan struct{}, 16)
+	done := make(chan error, 1)
+	go func() {
+		done <- Watch(
+			ctx,
+			[]string{dirPath},
+			func(context.Context) error {
+				calls <- struct{}{}
+				return nil
+			},
+			WatchWithExts(".proto"),
+			WatchWithPollInterval(5*time.Millisecond),
+			WatchWithDebounce(20*time.Millisecond),
+		)
+	}()
+	// initial call
+	testWaitForCall(t, calls)
+
+	// addition in a subdirectory
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dirPath, "foo", "b.proto"), []byte("b"), 0600))
+	testWaitForCall(t, calls)
+	// modification
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dirPath, "a.proto"), []byte("aa"), 0600))
+	testWaitForCall(t, calls)
+	// deletion
+	require.NoError(t, os.Remove(filepath.Join(dirPath, "foo", "b.proto")))
+	testWaitForCall(t, calls)
+	// files without the extensions are not watched
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dirPath, "c.txt"), []byte("c"), 0600))
+	select {
+	case <-calls:
+		assert.Fail(t, "unexpected call for c.txt")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		assert.Equal(t, context.Canceled, err)
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "Watch did not return after the context was done")
+	}
+}
+
+func TestWatchDebounce(t *testing.T) {
+	t.Parallel()
+	tmpDir, err := tmp.NewDir("")
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, tmpDir.Close())
+	}()
+	dirPath := tmpDir.AbsPath()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	calls := make(chan struct{}, 16)
+	go func() {
+		_ = Watch(
+			ctx,
+			[]string{dirPath},
+			func(context.Context) error {
+				calls <- struct{}{}
+				return nil
+			},
+			WatchWithPollInterval(5*time.Millisecond),
+			WatchWithDebounce(200*time.Millisecond),
+		)
+	}()
+	testWaitForCall(t, calls)
+	// changes within the debounce duration result in a single call
+	for i := 0; i < 5; i++ {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dirPath, "a.proto"), make([]byte, i+1), 0600))
+		time.Sleep(20 * time.Millisecond)
+	}
+	testWaitForCall(t, calls)
+	select {
+	case <-calls:
+		assert.Fail(t, "unexpected second call")
+	case <-time.After(400 * time.Millisecond):
+	}
+}
+
+func TestWatchError(t *testing.T) {
+	t.Parallel()
+	tmpDir, err := tmp.NewDir("")
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, tmpDir.Close())
+	}()
+	err = Watch(
+		context.Background(),
+		[]string{tmpDir.AbsPath()},
+		func(context.Context) error {
+			return os.ErrInvalid
+		},
+	)
+	assert.Equal(t, os.ErrInvalid, err)
+	err = Watch(
+		context.Background(),
+		[]string{filepath.Join(tmpDir.AbsPath(), "missing")},
+		func(context.Context) error {
+			return nil
+		},
+	)
+	assert.Error(t, err)
+}
+
+func testWaitForCall(t *testing.T, calls <-chan struct{}) {
+	select {
+	case <-calls:
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "timed out waiting for a call")
+	}
+}
@@ -121,7 +121,13 @@ func (c *cloner) CloneToBucket(
 		cmd.Stderr = buffer
 		if err := cmd.Run(); err != nil {
 			// Suppress printing of temp path
-			return fmt.Errorf("%v\n%v", err, strings.Replace(buffer.String(), tmpDir.AbsPath(), "", -1))
+			// the most common cause is a ref that is not within the cloned history
+			return fmt.Errorf(
+				"%v\n%vnote that only the last %d commits were cloned, so the ref must be within this depth",
+				err,
+				strings.Replace(buffer.String(), tmpDir.AbsPath(), "", -1),
+				depth,
+			)
 		}
 	}
 
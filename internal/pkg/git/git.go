@@ -41,6 +41,10 @@ func NewTagName(tag string) Name {
 }
 
 // NewRefName returns a new Name for the ref.
+//
+// The ref is anything that can be given to git checkout, including revisions
+// relative to the cloned branch such as HEAD~1. The ref must be within the
+// depth of the clone.
 func NewRefName(ref string) Name {
 	return newRef(ref)
 }
@@ -56,6 +60,9 @@ type Cloner interface {
 	//
 	// The url must contain the scheme, including file:// if necessary.
 	// depth must be > 0.
+	//
+	// Only the files in the tree of the cloned revision are copied. Submodules are
+	// only copied if RecurseSubmodules is set, and sparse checkouts are not supported.
 	CloneToBucket(
 		ctx context.Context,
 		envContainer app.EnvContainer,
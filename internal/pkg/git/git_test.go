@@ -17,6 +17,8 @@ package git
 import (
 	"bytes"
 	"context"
+	"fmt"
+	"io/ioutil"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -26,6 +28,7 @@ import (
 	"github.com/bufbuild/buf/internal/pkg/app"
 	"github.com/bufbuild/buf/internal/pkg/storage"
 	"github.com/bufbuild/buf/internal/pkg/storage/storagemem"
+	"github.com/bufbuild/buf/internal/pkg/tmp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
@@ -208,6 +211,113 @@ func TestCloneDefault(t *testing.T) {
 	assert.True(t, storage.IsNotExist(err))
 }
 
+func TestCloneRelativeRefToBucket(t *testing.T) {
+	t.Parallel()
+	tmpDir, err := tmp.NewDir("")
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, tmpDir.Close())
+	}()
+	absGitPath := testNewGitRepository(
+		t,
+		tmpDir.AbsPath(),
+		map[string]string{"a.proto": "one"},
+		map[string]string{"a.proto": "two", "b.proto": "two"},
+		map[string]string{"a.proto": "three"},
+	)
+
+	cloner := NewCloner(zap.NewNop(), ClonerOptions{})
+	envContainer, err := app.NewEnvContainerForOS()
+	require.NoError(t, err)
+	readBucketBuilder := storagemem.NewReadBucketBuilder()
+	err = cloner.CloneToBucket(
+		context.Background(),
+		envContainer,
+		"file://"+absGitPath,
+		50,
+		readBucketBuilder,
+		CloneToBucketOptions{
+			Mapper: storage.MatchPathExt(".proto"),
+			Name:   NewRefName("HEAD~1"),
+		},
+	)
+	require.NoError(t, err)
+	readBucket, err := readBucketBuilder.ToReadBucket()
+	require.NoError(t, err)
+
+	data, err := storage.ReadPath(context.Background(), readBucket, "a.proto")
+	require.NoError(t, err)
+	assert.Equal(t, "two", string(data))
+	data, err = storage.ReadPath(context.Background(), readBucket, "b.proto")
+	require.NoError(t, err)
+	assert.Equal(t, "two", string(data))
+}
+
+func TestCloneRefOutsideDepthToBucket(t *testing.T) {
+	t.Parallel()
+	tmpDir, err := tmp.NewDir("")
+	require.NoError(t, err)
+	defer func() {
+		assert.NoError(t, tmpDir.Close())
+	}()
+	absGitPath := testNewGitRepository(
+		t,
+		tmpDir.AbsPath(),
+		map[string]string{"a.proto": "one"},
+		map[string]string{"a.proto": "two"},
+	)
+
+	cloner := NewCloner(zap.NewNop(), ClonerOptions{})
+	envContainer, err := app.NewEnvContainerForOS()
+	require.NoError(t, err)
+	err = cloner.CloneToBucket(
+		context.Background(),
+		envContainer,
+		"file://"+absGitPath,
+		1,
+		storagemem.NewReadBucketBuilder(),
+		CloneToBucketOptions{
+			Name: NewRefName("HEAD~1"),
+		},
+	)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "only the last 1 commits were cloned")
+}
+
+// testNewGitRepository creates a git repository in dirPath with a commit for
+// each of the given maps from path to content, and returns the path to the .git directory.
+func testNewGitRepository(t *testing.T, dirPath string, commits ...map[string]string) string {
+	envContainer, err := app.NewEnvContainerForOS()
+	require.NoError(t, err)
+	runGit := func(args ...string) {
+		buffer := bytes.NewBuffer(nil)
+		cmd := exec.Command(
+			"git",
+			append(
+				[]string{
+					"-c", "user.name=test",
+					"-c", "user.email=test@example.com",
+					"-c", "commit.gpgsign=false",
+				},
+				args...,
+			)...,
+		)
+		cmd.Env = app.Environ(envContainer)
+		cmd.Dir = dirPath
+		cmd.Stderr = buffer
+		require.NoError(t, cmd.Run(), buffer.String())
+	}
+	runGit("init", "--quiet")
+	for i, pathToContent := range commits {
+		for path, content := range pathToContent {
+			require.NoError(t, ioutil.WriteFile(filepath.Join(dirPath, path), []byte(content), 0600))
+		}
+		runGit("add", "--all")
+		runGit("commit", "--quiet", "--message", fmt.Sprintf("commit %d", i))
+	}
+	return filepath.Join(dirPath, ".git")
+}
+
 func testGetLastGitCommit(t *testing.T) string {
 	envContainer, err := app.NewEnvContainerForOS()
 	require.NoError(t, err)
@@ -64,3 +64,30 @@ func (t *timer) End(extraFields ...zap.Field) {
 type nopTimer struct{}
 
 func (nopTimer) End(...zap.Field) {}
+
+// Timing is a duration recorded by a Timer.
+type Timing struct {
+	// Name is the name of the logger.
+	Name string
+	// Message is the message given to Start.
+	Message string
+	// Fields are the fields given to Start and End.
+	Fields map[string]interface{}
+	// Duration is the duration of the Timer.
+	Duration time.Duration
+}
+
+// Recorder records the durations of Timers.
+type Recorder interface {
+	// Core returns a new Core that records the entries written by Timers.
+	//
+	// This should be added to a logger with zapcore.NewTee.
+	Core() zapcore.Core
+	// Timings returns the Timings recorded so far, in the order the Timers ended.
+	Timings() []*Timing
+}
+
+// NewRecorder returns a new Recorder.
+func NewRecorder() Recorder {
+	return newRecorder()
+}
@@ -0,0 +1,54 @@
+// Copyright 2020 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instrument
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/bufbuild/buf/internal/pkg/zaputil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestRecorder(t *testing.T) {
+	t.Parallel()
+	buffer := bytes.NewBuffer(nil)
+	recorder := NewRecorder()
+	logger := zaputil.NewLogger(buffer, zapcore.InfoLevel, zaputil.NewTextEncoder()).WithOptions(
+		zap.WrapCore(
+			func(core zapcore.Core) zapcore.Core {
+				return zapcore.NewTee(core, recorder.Core())
+			},
+		),
+	)
+	Start(logger.Named("foo"), "one", zap.String("id", "ONE")).End()
+	Start(logger, "two").End(zap.Int("num_files", 2))
+	logger.Info("not_a_timer")
+
+	timings := recorder.Timings()
+	require.Len(t, timings, 2)
+	assert.Equal(t, "foo", timings[0].Name)
+	assert.Equal(t, "one", timings[0].Message)
+	assert.Equal(t, map[string]interface{}{"id": "ONE"}, timings[0].Fields)
+	assert.Equal(t, "", timings[1].Name)
+	assert.Equal(t, "two", timings[1].Message)
+	assert.Equal(t, map[string]interface{}{"num_files": int64(2)}, timings[1].Fields)
+	// the debug entries written by the Timers are not written to the info logger
+	assert.NotContains(t, buffer.String(), "one")
+	assert.Contains(t, buffer.String(), "not_a_timer")
+}
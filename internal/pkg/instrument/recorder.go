@@ -0,0 +1,104 @@
+// Copyright 2020 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package instrument
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+type recorder struct {
+	lock    sync.Mutex
+	timings []*Timing
+}
+
+func newRecorder() *recorder {
+	return &recorder{}
+}
+
+func (r *recorder) Core() zapcore.Core {
+	return newRecorderCore(r, nil)
+}
+
+func (r *recorder) Timings() []*Timing {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	timings := make([]*Timing, len(r.timings))
+	copy(timings, r.timings)
+	return timings
+}
+
+func (r *recorder) record(timing *Timing) {
+	r.lock.Lock()
+	r.timings = append(r.timings, timing)
+	r.lock.Unlock()
+}
+
+type recorderCore struct {
+	recorder *recorder
+	fields   []zapcore.Field
+}
+
+func newRecorderCore(recorder *recorder, fields []zapcore.Field) *recorderCore {
+	return &recorderCore{
+		recorder: recorder,
+		fields:   fields,
+	}
+}
+
+func (*recorderCore) Enabled(zapcore.Level) bool {
+	return true
+}
+
+func (c *recorderCore) With(fields []zapcore.Field) zapcore.Core {
+	return newRecorderCore(c.recorder, append(append([]zapcore.Field{}, c.fields...), fields...))
+}
+
+func (c *recorderCore) Check(entry zapcore.Entry, checkedEntry *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return checkedEntry.AddCore(entry, c)
+}
+
+func (c *recorderCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	var duration time.Duration
+	found := false
+	objectEncoder := zapcore.NewMapObjectEncoder()
+	for _, field := range append(append([]zapcore.Field{}, c.fields...), fields...) {
+		if field.Key == "duration" && field.Type == zapcore.DurationType {
+			duration = time.Duration(field.Integer)
+			found = true
+			continue
+		}
+		field.AddTo(objectEncoder)
+	}
+	// only entries written by Timers are recorded
+	if !found {
+		return nil
+	}
+	c.recorder.record(
+		&Timing{
+			Name:     entry.LoggerName,
+			Message:  entry.Message,
+			Fields:   objectEncoder.Fields,
+			Duration: duration,
+		},
+	)
+	return nil
+}
+
+func (*recorderCore) Sync() error {
+	return nil
+}
@@ -22,6 +22,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	pathpkg "path"
 	"path/filepath"
 	"sort"
 	"strings"
@@ -373,6 +374,27 @@ func MapAllEqualOrContainingPaths(m map[string]struct{}, path string, pathType P
 	return stringutil.MapToSortedSlice(n)
 }
 
+// MatchGlob returns true if the path matches the glob pattern.
+//
+// The pattern syntax is the same as path.Match, with the addition that a
+// "**" component matches zero or more components. The pattern and path are
+// expected to be normalized.
+//
+// The only possible returned error is path.ErrBadPattern, when pattern is malformed.
+// The pattern is validated even if the path does not match.
+func MatchGlob(pattern string, path string) (bool, error) {
+	patternComponents := strings.Split(pattern, "/")
+	for _, patternComponent := range patternComponents {
+		if patternComponent == "**" {
+			continue
+		}
+		if _, err := pathpkg.Match(patternComponent, ""); err != nil {
+			return false, err
+		}
+	}
+	return matchGlobComponents(patternComponents, strings.Split(path, "/")), nil
+}
+
 // StripComponents strips the specified number of components.
 //
 // Path expected to be normalized.
@@ -388,3 +410,28 @@ func StripComponents(path string, countUint32 uint32) (string, bool) {
 	}
 	return Join(components[count:]...), true
 }
+
+// matchGlobComponents assumes all patternComponents are valid.
+func matchGlobComponents(patternComponents []string, pathComponents []string) bool {
+	for len(patternComponents) > 0 {
+		patternComponent := patternComponents[0]
+		if patternComponent == "**" {
+			for i := 0; i <= len(pathComponents); i++ {
+				if matchGlobComponents(patternComponents[1:], pathComponents[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(pathComponents) == 0 {
+			return false
+		}
+		// the pattern was already validated
+		if matched, _ := pathpkg.Match(patternComponent, pathComponents[0]); !matched {
+			return false
+		}
+		patternComponents = patternComponents[1:]
+		pathComponents = pathComponents[1:]
+	}
+	return len(pathComponents) == 0
+}
@@ -442,3 +442,30 @@ func testMapAllEqualOrContainingPathsAbs(t *testing.T, expected []string, path s
 	keyMap := stringutil.SliceToMap(keys)
 	assert.Equal(t, expected, MapAllEqualOrContainingPaths(keyMap, path, Absolute), fmt.Sprintf("%s %v", path, keys))
 }
+
+func TestMatchGlob(t *testing.T) {
+	testMatchGlob(t, true, "a.proto", "a.proto")
+	testMatchGlob(t, true, "*.proto", "a.proto")
+	testMatchGlob(t, false, "*.proto", "a/a.proto")
+	testMatchGlob(t, true, "a/*.proto", "a/a.proto")
+	testMatchGlob(t, false, "a/*.proto", "a/b/a.proto")
+	testMatchGlob(t, true, "a/**", "a/a.proto")
+	testMatchGlob(t, true, "a/**", "a/b/c/a.proto")
+	testMatchGlob(t, false, "a/**", "b/a.proto")
+	testMatchGlob(t, true, "a/**/c.proto", "a/c.proto")
+	testMatchGlob(t, true, "a/**/c.proto", "a/b/b/c.proto")
+	testMatchGlob(t, false, "a/**/c.proto", "a/b/b/d.proto")
+	testMatchGlob(t, true, "**/c.proto", "a/b/c.proto")
+	testMatchGlob(t, true, "**", "a/b/c.proto")
+	testMatchGlob(t, true, "a/?.proto", "a/b.proto")
+	_, err := MatchGlob("a/[", "a/b")
+	assert.Error(t, err)
+	_, err = MatchGlob("[/**", "a/b")
+	assert.Error(t, err)
+}
+
+func testMatchGlob(t *testing.T, expected bool, pattern string, path string) {
+	matched, err := MatchGlob(pattern, path)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, matched, fmt.Sprintf("%s %s", pattern, path))
+}
@@ -20,6 +20,8 @@ type enum struct {
 	values             []EnumValue
 	allowAlias         bool
 	allowAliasPath     []int32
+	deprecated         bool
+	deprecatedPath     []int32
 	reservedEnumRanges []EnumRange
 	reservedNames      []ReservedName
 }
@@ -28,11 +30,15 @@ func newEnum(
 	namedDescriptor namedDescriptor,
 	allowAlias bool,
 	allowAliasPath []int32,
+	deprecated bool,
+	deprecatedPath []int32,
 ) *enum {
 	return &enum{
 		namedDescriptor: namedDescriptor,
 		allowAlias:      allowAlias,
 		allowAliasPath:  allowAliasPath,
+		deprecated:      deprecated,
+		deprecatedPath:  deprecatedPath,
 	}
 }
 
@@ -48,6 +54,14 @@ func (e *enum) AllowAliasLocation() Location {
 	return e.getLocation(e.allowAliasPath)
 }
 
+func (e *enum) Deprecated() bool {
+	return e.deprecated
+}
+
+func (e *enum) DeprecatedLocation() Location {
+	return e.getLocation(e.deprecatedPath)
+}
+
 func (e *enum) ReservedEnumRanges() []EnumRange {
 	return e.reservedEnumRanges
 }
@@ -16,38 +16,47 @@ package protosource
 
 type field struct {
 	namedDescriptor
-
-	message      Message
-	number       int
-	label        FieldDescriptorProtoLabel
-	typ          FieldDescriptorProtoType
-	typeName     string
-	oneofIndex   *int32
-	jsonName     string
-	jsType       FieldOptionsJSType
-	cType        FieldOptionsCType
-	packed       *bool
-	numberPath   []int32
-	typePath     []int32
-	typeNamePath []int32
-	jsonNamePath []int32
-	jsTypePath   []int32
-	cTypePath    []int32
-	packedPath   []int32
+	optionExtensionDescriptor
+
+	message        Message
+	number         int
+	label          FieldDescriptorProtoLabel
+	typ            FieldDescriptorProtoType
+	typeName       string
+	extendee       string
+	oneofIndex     *int32
+	proto3Optional bool
+	jsonName       string
+	jsType         FieldOptionsJSType
+	cType          FieldOptionsCType
+	packed         *bool
+	deprecated     bool
+	numberPath     []int32
+	typePath       []int32
+	typeNamePath   []int32
+	jsonNamePath   []int32
+	jsTypePath     []int32
+	cTypePath      []int32
+	packedPath     []int32
+	deprecatedPath []int32
 }
 
 func newField(
 	namedDescriptor namedDescriptor,
+	optionExtensionDescriptor optionExtensionDescriptor,
 	message Message,
 	number int,
 	label FieldDescriptorProtoLabel,
 	typ FieldDescriptorProtoType,
 	typeName string,
+	extendee string,
 	oneofIndex *int32,
+	proto3Optional bool,
 	jsonName string,
 	jsType FieldOptionsJSType,
 	cType FieldOptionsCType,
 	packed *bool,
+	deprecated bool,
 	numberPath []int32,
 	typePath []int32,
 	typeNamePath []int32,
@@ -55,26 +64,32 @@ func newField(
 	jsTypePath []int32,
 	cTypePath []int32,
 	packedPath []int32,
+	deprecatedPath []int32,
 ) *field {
 	return &field{
-		namedDescriptor: namedDescriptor,
-		message:         message,
-		number:          number,
-		label:           label,
-		typ:             typ,
-		typeName:        typeName,
-		oneofIndex:      oneofIndex,
-		jsonName:        jsonName,
-		jsType:          jsType,
-		cType:           cType,
-		packed:          packed,
-		numberPath:      numberPath,
-		typePath:        typePath,
-		typeNamePath:    typeNamePath,
-		jsonNamePath:    jsonNamePath,
-		jsTypePath:      jsTypePath,
-		cTypePath:       cTypePath,
-		packedPath:      packedPath,
+		namedDescriptor:           namedDescriptor,
+		optionExtensionDescriptor: optionExtensionDescriptor,
+		message:                   message,
+		number:                    number,
+		label:                     label,
+		typ:                       typ,
+		typeName:                  typeName,
+		extendee:                  extendee,
+		oneofIndex:                oneofIndex,
+		proto3Optional:            proto3Optional,
+		jsonName:                  jsonName,
+		jsType:                    jsType,
+		cType:                     cType,
+		packed:                    packed,
+		deprecated:                deprecated,
+		numberPath:                numberPath,
+		typePath:                  typePath,
+		typeNamePath:              typeNamePath,
+		jsonNamePath:              jsonNamePath,
+		jsTypePath:                jsTypePath,
+		cTypePath:                 cTypePath,
+		packedPath:                packedPath,
+		deprecatedPath:            deprecatedPath,
 	}
 }
 
@@ -98,6 +113,10 @@ func (f *field) TypeName() string {
 	return f.typeName
 }
 
+func (f *field) Extendee() string {
+	return f.extendee
+}
+
 func (f *field) OneofIndex() (int, bool) {
 	if f.oneofIndex == nil {
 		return 0, false
@@ -105,6 +124,10 @@ func (f *field) OneofIndex() (int, bool) {
 	return int(*f.oneofIndex), true
 }
 
+func (f *field) Proto3Optional() bool {
+	return f.proto3Optional
+}
+
 func (f *field) JSONName() string {
 	return f.jsonName
 }
@@ -121,6 +144,10 @@ func (f *field) Packed() *bool {
 	return f.packed
 }
 
+func (f *field) Deprecated() bool {
+	return f.deprecated
+}
+
 func (f *field) NumberLocation() Location {
 	return f.getLocation(f.numberPath)
 }
@@ -148,3 +175,7 @@ func (f *field) CTypeLocation() Location {
 func (f *field) PackedLocation() Location {
 	return f.getLocation(f.packedPath)
 }
+
+func (f *field) DeprecatedLocation() Location {
+	return f.getLocation(f.deprecatedPath)
+}
@@ -30,13 +30,26 @@ type file struct {
 	messages            []Message
 	enums               []Enum
 	services            []Service
+	extensions          []Field
 	optimizeMode        FileOptionsOptimizeMode
+	content             []byte
 }
 
 func (f *file) Syntax() Syntax {
 	return f.syntax
 }
 
+func (f *file) IsSyntaxUnspecified() bool {
+	if f.fileDescriptorProto.GetSyntax() != "" {
+		return false
+	}
+	if len(f.fileDescriptorProto.GetSourceCodeInfo().GetLocation()) == 0 {
+		// there is no way to know if proto2 was specified
+		return false
+	}
+	return f.SyntaxLocation() == nil
+}
+
 func (f *file) Package() string {
 	return f.fileDescriptorProto.GetPackage()
 }
@@ -45,6 +58,10 @@ func (f *file) FileImports() []FileImport {
 	return f.fileImports
 }
 
+func (f *file) Content() []byte {
+	return f.content
+}
+
 func (f *file) Messages() []Message {
 	return f.messages
 }
@@ -57,6 +74,10 @@ func (f *file) Services() []Service {
 	return f.services
 }
 
+func (f *file) Extensions() []Field {
+	return f.extensions
+}
+
 func (f *file) CsharpNamespace() string {
 	return f.fileDescriptorProto.GetOptions().GetCsharpNamespace()
 }
@@ -216,6 +237,9 @@ func newFile(inputFile InputFile) (*file, error) {
 		FileInfo:            inputFile,
 		fileDescriptorProto: inputFile.Proto(),
 	}
+	if contentInputFile, ok := inputFile.(*inputFileWithContent); ok {
+		f.content = contentInputFile.content
+	}
 	descriptor := newDescriptor(
 		f,
 		newLocationStore(f.fileDescriptorProto.GetSourceCodeInfo().GetLocation()),
@@ -297,6 +321,16 @@ func newFile(inputFile InputFile) (*file, error) {
 		}
 		f.services = append(f.services, service)
 	}
+	for extensionIndex, fieldDescriptorProto := range f.fileDescriptorProto.GetExtension() {
+		extension, err := f.populateExtension(
+			fieldDescriptorProto,
+			extensionIndex,
+		)
+		if err != nil {
+			return nil, err
+		}
+		f.extensions = append(f.extensions, extension)
+	}
 	optimizeMode, err := getFileOptionsOptimizeMode(f.fileDescriptorProto.GetOptions().GetOptimizeFor())
 	if err != nil {
 		return nil, err
@@ -330,6 +364,8 @@ func (f *file) populateEnum(
 		enumNamedDescriptor,
 		enumDescriptorProto.GetOptions().GetAllowAlias(),
 		getEnumAllowAliasPath(enumIndex, nestedMessageIndexes...),
+		enumDescriptorProto.GetOptions().GetDeprecated(),
+		getEnumDeprecatedPath(enumIndex, nestedMessageIndexes...),
 	)
 
 	for enumValueIndex, enumValueDescriptorProto := range enumDescriptorProto.GetValue() {
@@ -414,6 +450,8 @@ func (f *file) populateMessage(
 		descriptorProto.GetOptions().GetNoStandardDescriptorAccessor(),
 		getMessageMessageSetWireFormatPath(topLevelMessageIndex, nestedMessageIndexes...),
 		getMessageNoStandardDescriptorAccessorPath(topLevelMessageIndex, nestedMessageIndexes...),
+		descriptorProto.GetOptions().GetDeprecated(),
+		getMessageDeprecatedPath(topLevelMessageIndex, nestedMessageIndexes...),
 	)
 	for fieldIndex, fieldDescriptorProto := range descriptorProto.GetField() {
 		// TODO: not working for map entries
@@ -451,16 +489,24 @@ func (f *file) populateMessage(
 		}
 		field := newField(
 			fieldNamedDescriptor,
+			newOptionExtensionDescriptor(
+				fieldDescriptorProto.GetOptions(),
+				getMessageFieldOptionsPath(fieldIndex, topLevelMessageIndex, nestedMessageIndexes...),
+				f.descriptor.locationStore,
+			),
 			message,
 			int(fieldDescriptorProto.GetNumber()),
 			label,
 			typ,
 			fieldDescriptorProto.GetTypeName(),
+			fieldDescriptorProto.GetExtendee(),
 			fieldDescriptorProto.OneofIndex,
+			fieldDescriptorProto.GetProto3Optional(),
 			fieldDescriptorProto.GetJsonName(),
 			jsType,
 			cType,
 			packed,
+			fieldDescriptorProto.GetOptions().GetDeprecated(),
 			getMessageFieldNumberPath(fieldIndex, topLevelMessageIndex, nestedMessageIndexes...),
 			getMessageFieldTypePath(fieldIndex, topLevelMessageIndex, nestedMessageIndexes...),
 			getMessageFieldTypeNamePath(fieldIndex, topLevelMessageIndex, nestedMessageIndexes...),
@@ -468,6 +514,7 @@ func (f *file) populateMessage(
 			getMessageFieldJSTypePath(fieldIndex, topLevelMessageIndex, nestedMessageIndexes...),
 			getMessageFieldCTypePath(fieldIndex, topLevelMessageIndex, nestedMessageIndexes...),
 			getMessageFieldPackedPath(fieldIndex, topLevelMessageIndex, nestedMessageIndexes...),
+			getMessageFieldDeprecatedPath(fieldIndex, topLevelMessageIndex, nestedMessageIndexes...),
 		)
 		message.addField(field)
 	}
@@ -507,16 +554,24 @@ func (f *file) populateMessage(
 		}
 		field := newField(
 			fieldNamedDescriptor,
+			newOptionExtensionDescriptor(
+				fieldDescriptorProto.GetOptions(),
+				getMessageExtensionOptionsPath(fieldIndex, topLevelMessageIndex, nestedMessageIndexes...),
+				f.descriptor.locationStore,
+			),
 			message,
 			int(fieldDescriptorProto.GetNumber()),
 			label,
 			typ,
 			fieldDescriptorProto.GetTypeName(),
+			fieldDescriptorProto.GetExtendee(),
 			fieldDescriptorProto.OneofIndex,
+			fieldDescriptorProto.GetProto3Optional(),
 			fieldDescriptorProto.GetJsonName(),
 			jsType,
 			cType,
 			packed,
+			fieldDescriptorProto.GetOptions().GetDeprecated(),
 			getMessageExtensionNumberPath(fieldIndex, topLevelMessageIndex, nestedMessageIndexes...),
 			getMessageExtensionTypePath(fieldIndex, topLevelMessageIndex, nestedMessageIndexes...),
 			getMessageExtensionTypeNamePath(fieldIndex, topLevelMessageIndex, nestedMessageIndexes...),
@@ -524,6 +579,7 @@ func (f *file) populateMessage(
 			getMessageExtensionJSTypePath(fieldIndex, topLevelMessageIndex, nestedMessageIndexes...),
 			getMessageExtensionCTypePath(fieldIndex, topLevelMessageIndex, nestedMessageIndexes...),
 			getMessageExtensionPackedPath(fieldIndex, topLevelMessageIndex, nestedMessageIndexes...),
+			getMessageExtensionDeprecatedPath(fieldIndex, topLevelMessageIndex, nestedMessageIndexes...),
 		)
 		message.addExtension(field)
 	}
@@ -616,6 +672,73 @@ func (f *file) populateMessage(
 	return message, nil
 }
 
+func (f *file) populateExtension(
+	fieldDescriptorProto *descriptorpb.FieldDescriptorProto,
+	extensionIndex int,
+) (Field, error) {
+	fieldNamedDescriptor, err := newNamedDescriptor(
+		newLocationDescriptor(
+			f.descriptor,
+			getFileExtensionPath(extensionIndex),
+		),
+		fieldDescriptorProto.GetName(),
+		getFileExtensionNamePath(extensionIndex),
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	var packed *bool
+	if fieldDescriptorProto.Options != nil {
+		packed = fieldDescriptorProto.GetOptions().Packed
+	}
+	label, err := getFieldDescriptorProtoLabel(fieldDescriptorProto.GetLabel())
+	if err != nil {
+		return nil, err
+	}
+	typ, err := getFieldDescriptorProtoType(fieldDescriptorProto.GetType())
+	if err != nil {
+		return nil, err
+	}
+	jsType, err := getFieldOptionsJSType(fieldDescriptorProto.GetOptions().GetJstype())
+	if err != nil {
+		return nil, err
+	}
+	cType, err := getFieldOptionsCType(fieldDescriptorProto.GetOptions().GetCtype())
+	if err != nil {
+		return nil, err
+	}
+	return newField(
+		fieldNamedDescriptor,
+		newOptionExtensionDescriptor(
+			fieldDescriptorProto.GetOptions(),
+			getFileExtensionOptionsPath(extensionIndex),
+			f.descriptor.locationStore,
+		),
+		nil,
+		int(fieldDescriptorProto.GetNumber()),
+		label,
+		typ,
+		fieldDescriptorProto.GetTypeName(),
+		fieldDescriptorProto.GetExtendee(),
+		fieldDescriptorProto.OneofIndex,
+		fieldDescriptorProto.GetProto3Optional(),
+		fieldDescriptorProto.GetJsonName(),
+		jsType,
+		cType,
+		packed,
+		fieldDescriptorProto.GetOptions().GetDeprecated(),
+		getFileExtensionNumberPath(extensionIndex),
+		getFileExtensionTypePath(extensionIndex),
+		getFileExtensionTypeNamePath(extensionIndex),
+		getFileExtensionJSONNamePath(extensionIndex),
+		getFileExtensionJSTypePath(extensionIndex),
+		getFileExtensionCTypePath(extensionIndex),
+		getFileExtensionPackedPath(extensionIndex),
+		getFileExtensionDeprecatedPath(extensionIndex),
+	), nil
+}
+
 func (f *file) populateService(
 	serviceDescriptorProto *descriptorpb.ServiceDescriptorProto,
 	serviceIndex int,
@@ -654,6 +777,11 @@ func (f *file) populateService(
 		}
 		method, err := newMethod(
 			methodNamedDescriptor,
+			newOptionExtensionDescriptor(
+				methodDescriptorProto.GetOptions(),
+				getMethodOptionsPath(serviceIndex, methodIndex),
+				f.descriptor.locationStore,
+			),
 			service,
 			methodDescriptorProto.GetInputType(),
 			methodDescriptorProto.GetOutputType(),
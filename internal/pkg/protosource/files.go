@@ -16,6 +16,7 @@ package protosource
 
 import (
 	"context"
+	"sort"
 
 	"github.com/bufbuild/buf/internal/pkg/thread"
 	"go.uber.org/multierr"
@@ -23,6 +24,24 @@ import (
 
 const defaultChunkSizeThreshold = 8
 
+func newFiles(ctx context.Context, inputFiles ...InputFile) ([]File, error) {
+	files, err := newFilesUnstable(ctx, inputFiles...)
+	if err != nil {
+		return nil, err
+	}
+	pathToIndex := make(map[string]int, len(inputFiles))
+	for i, inputFile := range inputFiles {
+		pathToIndex[inputFile.Path()] = i
+	}
+	sort.SliceStable(
+		files,
+		func(i int, j int) bool {
+			return pathToIndex[files[i].Path()] < pathToIndex[files[j].Path()]
+		},
+	)
+	return files, nil
+}
+
 func newFilesUnstable(ctx context.Context, inputFiles ...InputFile) ([]File, error) {
 	if len(inputFiles) == 0 {
 		return nil, nil
@@ -37,3 +37,16 @@ func (i *inputFile) ExternalPath() string {
 func (i *inputFile) Proto() *descriptorpb.FileDescriptorProto {
 	return i.fileDescriptorProto
 }
+
+type inputFileWithContent struct {
+	InputFile
+
+	content []byte
+}
+
+func newInputFileWithContent(inputFile InputFile, content []byte) *inputFileWithContent {
+	return &inputFileWithContent{
+		InputFile: inputFile,
+		content:   content,
+	}
+}
@@ -29,8 +29,10 @@ type message struct {
 	isMapEntry                       bool
 	messageSetWireFormat             bool
 	noStandardDescriptorAccessor     bool
+	deprecated                       bool
 	messageSetWireFormatPath         []int32
 	noStandardDescriptorAccessorPath []int32
+	deprecatedPath                   []int32
 }
 
 func newMessage(
@@ -41,14 +43,19 @@ func newMessage(
 	noStandardDescriptorAccessor bool,
 	messageSetWireFormatPath []int32,
 	noStandardDescriptorAccessorPath []int32,
+	deprecated bool,
+	deprecatedPath []int32,
 ) *message {
 	return &message{
 		namedDescriptor:                  namedDescriptor,
+		parent:                           parent,
 		isMapEntry:                       isMapEntry,
 		messageSetWireFormat:             messageSetWireFormat,
 		noStandardDescriptorAccessor:     noStandardDescriptorAccessor,
 		messageSetWireFormatPath:         messageSetWireFormatPath,
 		noStandardDescriptorAccessorPath: noStandardDescriptorAccessorPath,
+		deprecated:                       deprecated,
+		deprecatedPath:                   deprecatedPath,
 	}
 }
 
@@ -108,6 +115,10 @@ func (m *message) NoStandardDescriptorAccessor() bool {
 	return m.noStandardDescriptorAccessor
 }
 
+func (m *message) Deprecated() bool {
+	return m.deprecated
+}
+
 func (m *message) MessageSetWireFormatLocation() Location {
 	return m.getLocation(m.messageSetWireFormatPath)
 }
@@ -116,6 +127,10 @@ func (m *message) NoStandardDescriptorAccessorLocation() Location {
 	return m.getLocation(m.noStandardDescriptorAccessorPath)
 }
 
+func (m *message) DeprecatedLocation() Location {
+	return m.getLocation(m.deprecatedPath)
+}
+
 func (m *message) addField(field Field) {
 	m.fields = append(m.fields, field)
 }
@@ -0,0 +1,85 @@
+// Copyright 2020 Buf Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protosource
+
+import (
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+type optionExtensionDescriptor struct {
+	message       proto.Message
+	optionsPath   []int32
+	locationStore *locationStore
+}
+
+func newOptionExtensionDescriptor(
+	message proto.Message,
+	optionsPath []int32,
+	locationStore *locationStore,
+) optionExtensionDescriptor {
+	return optionExtensionDescriptor{
+		message:       message,
+		optionsPath:   optionsPath,
+		locationStore: locationStore,
+	}
+}
+
+func (o *optionExtensionDescriptor) HasOptionExtension(fieldNumber int) bool {
+	if o.message == nil {
+		return false
+	}
+	reflectMessage := o.message.ProtoReflect()
+	if !reflectMessage.IsValid() {
+		return false
+	}
+	number := protoreflect.FieldNumber(fieldNumber)
+	found := false
+	reflectMessage.Range(
+		func(fieldDescriptor protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+			if fieldDescriptor.IsExtension() && fieldDescriptor.Number() == number {
+				found = true
+				return false
+			}
+			return true
+		},
+	)
+	if found {
+		return true
+	}
+	// extensions that are not registered are stored as unknown fields
+	unknown := reflectMessage.GetUnknown()
+	for len(unknown) > 0 {
+		unknownNumber, _, n := protowire.ConsumeField(unknown)
+		if n < 0 {
+			return false
+		}
+		if unknownNumber == number {
+			return true
+		}
+		unknown = unknown[n:]
+	}
+	return false
+}
+
+func (o *optionExtensionDescriptor) OptionExtensionLocation(fieldNumber int) Location {
+	if o.locationStore == nil || len(o.optionsPath) == 0 {
+		return nil
+	}
+	path := make([]int32, len(o.optionsPath), len(o.optionsPath)+1)
+	copy(path, o.optionsPath)
+	return o.locationStore.getLocation(append(path, int32(fieldNumber)))
+}
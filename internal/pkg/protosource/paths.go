@@ -61,6 +61,10 @@ func getMessageNoStandardDescriptorAccessorPath(messageIndex int, nestedMessageI
 	return append(getMessagePath(messageIndex, nestedMessageIndexes...), 7, 2)
 }
 
+func getMessageDeprecatedPath(messageIndex int, nestedMessageIndexes ...int) []int32 {
+	return append(getMessagePath(messageIndex, nestedMessageIndexes...), 7, 3)
+}
+
 func getMessageFieldPath(fieldIndex int, topLevelMessageIndex int, nestedMessageIndexes ...int) []int32 {
 	return append(getMessagePath(topLevelMessageIndex, nestedMessageIndexes...), 2, int32(fieldIndex))
 }
@@ -97,6 +101,14 @@ func getMessageFieldPackedPath(fieldIndex int, topLevelMessageIndex int, nestedM
 	return append(getMessageFieldPath(fieldIndex, topLevelMessageIndex, nestedMessageIndexes...), 8, 2)
 }
 
+func getMessageFieldDeprecatedPath(fieldIndex int, topLevelMessageIndex int, nestedMessageIndexes ...int) []int32 {
+	return append(getMessageFieldPath(fieldIndex, topLevelMessageIndex, nestedMessageIndexes...), 8, 3)
+}
+
+func getMessageFieldOptionsPath(fieldIndex int, topLevelMessageIndex int, nestedMessageIndexes ...int) []int32 {
+	return append(getMessageFieldPath(fieldIndex, topLevelMessageIndex, nestedMessageIndexes...), 8)
+}
+
 func getMessageExtensionPath(extensionIndex int, topLevelMessageIndex int, nestedMessageIndexes ...int) []int32 {
 	return append(getMessagePath(topLevelMessageIndex, nestedMessageIndexes...), 6, int32(extensionIndex))
 }
@@ -133,6 +145,14 @@ func getMessageExtensionPackedPath(extensionIndex int, topLevelMessageIndex int,
 	return append(getMessageExtensionPath(extensionIndex, topLevelMessageIndex, nestedMessageIndexes...), 8, 2)
 }
 
+func getMessageExtensionDeprecatedPath(extensionIndex int, topLevelMessageIndex int, nestedMessageIndexes ...int) []int32 {
+	return append(getMessageExtensionPath(extensionIndex, topLevelMessageIndex, nestedMessageIndexes...), 8, 3)
+}
+
+func getMessageExtensionOptionsPath(extensionIndex int, topLevelMessageIndex int, nestedMessageIndexes ...int) []int32 {
+	return append(getMessageExtensionPath(extensionIndex, topLevelMessageIndex, nestedMessageIndexes...), 8)
+}
+
 func getMessageOneofPath(oneofIndex int, topLevelMessageIndex int, nestedMessageIndexes ...int) []int32 {
 	return append(getMessagePath(topLevelMessageIndex, nestedMessageIndexes...), 8, int32(oneofIndex))
 }
@@ -171,6 +191,10 @@ func getEnumAllowAliasPath(enumIndex int, nestedMessageIndexes ...int) []int32 {
 	return append(getEnumPath(enumIndex, nestedMessageIndexes...), 3, 2)
 }
 
+func getEnumDeprecatedPath(enumIndex int, nestedMessageIndexes ...int) []int32 {
+	return append(getEnumPath(enumIndex, nestedMessageIndexes...), 3, 3)
+}
+
 func getEnumValuePath(enumIndex int, enumValueIndex int, nestedMessageIndexes ...int) []int32 {
 	return append(getEnumPath(enumIndex, nestedMessageIndexes...), 2, int32(enumValueIndex))
 }
@@ -191,6 +215,50 @@ func getEnumReservedNamePath(enumIndex int, reservedNameIndex int, nestedMessage
 	return append(getEnumPath(enumIndex, nestedMessageIndexes...), 5, int32(reservedNameIndex))
 }
 
+func getFileExtensionPath(extensionIndex int) []int32 {
+	return []int32{7, int32(extensionIndex)}
+}
+
+func getFileExtensionNamePath(extensionIndex int) []int32 {
+	return append(getFileExtensionPath(extensionIndex), 1)
+}
+
+func getFileExtensionNumberPath(extensionIndex int) []int32 {
+	return append(getFileExtensionPath(extensionIndex), 3)
+}
+
+func getFileExtensionTypePath(extensionIndex int) []int32 {
+	return append(getFileExtensionPath(extensionIndex), 5)
+}
+
+func getFileExtensionTypeNamePath(extensionIndex int) []int32 {
+	return append(getFileExtensionPath(extensionIndex), 6)
+}
+
+func getFileExtensionJSONNamePath(extensionIndex int) []int32 {
+	return append(getFileExtensionPath(extensionIndex), 10)
+}
+
+func getFileExtensionJSTypePath(extensionIndex int) []int32 {
+	return append(getFileExtensionPath(extensionIndex), 8, 6)
+}
+
+func getFileExtensionCTypePath(extensionIndex int) []int32 {
+	return append(getFileExtensionPath(extensionIndex), 8, 1)
+}
+
+func getFileExtensionPackedPath(extensionIndex int) []int32 {
+	return append(getFileExtensionPath(extensionIndex), 8, 2)
+}
+
+func getFileExtensionDeprecatedPath(extensionIndex int) []int32 {
+	return append(getFileExtensionPath(extensionIndex), 8, 3)
+}
+
+func getFileExtensionOptionsPath(extensionIndex int) []int32 {
+	return append(getFileExtensionPath(extensionIndex), 8)
+}
+
 func getServicePath(serviceIndex int) []int32 {
 	return []int32{6, int32(serviceIndex)}
 }
@@ -215,6 +283,10 @@ func getMethodOutputTypePath(serviceIndex int, methodIndex int) []int32 {
 	return append(getMethodPath(serviceIndex, methodIndex), 3)
 }
 
+func getMethodOptionsPath(serviceIndex int, methodIndex int) []int32 {
+	return append(getMethodPath(serviceIndex, methodIndex), 4)
+}
+
 func getMethodIdempotencyLevelPath(serviceIndex int, methodIndex int) []int32 {
 	return append(getMethodPath(serviceIndex, methodIndex), 4, 34)
 }
@@ -103,6 +103,20 @@ type NamedDescriptor interface {
 	NameLocation() Location
 }
 
+// OptionExtensionDescriptor contains extensions on its options.
+type OptionExtensionDescriptor interface {
+	// HasOptionExtension returns true if the option extension with the given
+	// field number is set on the options of this descriptor.
+	//
+	// This works for both registered and unregistered extensions.
+	HasOptionExtension(fieldNumber int) bool
+	// OptionExtensionLocation returns the location of the option extension with
+	// the given field number.
+	//
+	// Can return nil.
+	OptionExtensionLocation(fieldNumber int) Location
+}
+
 // ContainerDescriptor contains Enums and Messages.
 type ContainerDescriptor interface {
 	Enums() []Enum
@@ -154,9 +168,24 @@ type File interface {
 	ContainerDescriptor
 
 	Syntax() Syntax
+	// IsSyntaxUnspecified returns true if the file is known to not have a syntax
+	// statement, in which case the syntax defaults to proto2.
+	//
+	// As proto2 files have no syntax set on the FileDescriptorProto, this requires
+	// source code info for proto2 files, and false is returned without it.
+	IsSyntaxUnspecified() bool
 	Package() string
 	FileImports() []FileImport
 	Services() []Service
+	// Extensions are the extensions declared at the top level of the file.
+	//
+	// Extensions declared within messages are returned by the Extensions of the messages.
+	Extensions() []Field
+	// Content returns the raw content of the file.
+	//
+	// This is nil if the File was not created with NewInputFileWithContent,
+	// for example if the File was created from an Image without access to sources.
+	Content() []byte
 
 	CsharpNamespace() string
 	GoPackage() string
@@ -259,7 +288,9 @@ type Enum interface {
 	ReservedEnumRanges() []EnumRange
 
 	AllowAlias() bool
+	Deprecated() bool
 	AllowAliasLocation() Location
+	DeprecatedLocation() Location
 }
 
 // EnumValue is an enum value descriptor.
@@ -292,26 +323,41 @@ type Message interface {
 
 	MessageSetWireFormat() bool
 	NoStandardDescriptorAccessor() bool
+	Deprecated() bool
 	MessageSetWireFormatLocation() Location
 	NoStandardDescriptorAccessorLocation() Location
+	DeprecatedLocation() Location
 }
 
 // Field is a field descriptor.
 type Field interface {
 	NamedDescriptor
+	OptionExtensionDescriptor
 
+	// Message is the message the field is declared in.
+	//
+	// This is nil for extensions declared at the top level of a file.
 	Message() Message
 	Number() int
 	Label() FieldDescriptorProtoLabel
 	Type() FieldDescriptorProtoType
 	TypeName() string
+	// Extendee is the fully-qualified name of the message that the field extends.
+	//
+	// This is empty if the field is not an extension.
+	Extendee() string
 	OneofIndex() (int, bool)
+	// Proto3Optional is true if the field was declared with the optional label in proto3.
+	//
+	// Such fields are also in a synthetic oneof, so OneofIndex returns true.
+	Proto3Optional() bool
 	JSONName() string
 	JSType() FieldOptionsJSType
 	CType() FieldOptionsCType
 	// Set vs unset matters for packed
 	// See the comments on descriptor.proto
 	Packed() *bool
+	Deprecated() bool
 
 	NumberLocation() Location
 	TypeLocation() Location
@@ -320,6 +366,7 @@ type Field interface {
 	JSTypeLocation() Location
 	CTypeLocation() Location
 	PackedLocation() Location
+	DeprecatedLocation() Location
 }
 
 // Oneof is a oneof descriptor.
@@ -339,6 +386,7 @@ type Service interface {
 // Method is a method descriptor.
 type Method interface {
 	NamedDescriptor
+	OptionExtensionDescriptor
 
 	Service() Service
 	InputTypeName() string
@@ -368,6 +416,12 @@ func NewInputFileForProto(fileDescriptorProto *descriptorpb.FileDescriptorProto)
 	return newInputFile(fileDescriptorProto)
 }
 
+// NewInputFileWithContent returns a new InputFile that wraps the given InputFile
+// and results in a File with the given raw content.
+func NewInputFileWithContent(inputFile InputFile, content []byte) InputFile {
+	return newInputFileWithContent(inputFile, content)
+}
+
 // NewInputFilesForProtos returns new InputFiles for the given FileDescriptorProtos.
 func NewInputFilesForProtos(fileDescriptorProtos ...*descriptorpb.FileDescriptorProto) []InputFile {
 	inputFiles := make([]InputFile, len(fileDescriptorProtos))
@@ -382,6 +436,15 @@ func NewFile(inputFile InputFile) (File, error) {
 	return newFile(inputFile)
 }
 
+// NewFiles converts the input Files into Files.
+//
+// This may be done concurrently, but the returned Files are in the same order
+// as the input Files, so that the result is deterministic. The input Files are
+// expected to have unique paths.
+func NewFiles(ctx context.Context, inputFiles ...InputFile) ([]File, error) {
+	return newFiles(ctx, inputFiles...)
+}
+
 // NewFilesUnstable converts the input Files into Files.
 //
 // This may be done concurrently and the returned Files may not be in the same
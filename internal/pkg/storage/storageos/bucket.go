@@ -19,6 +19,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"math"
 	"os"
 	"path/filepath"
@@ -28,14 +29,19 @@ import (
 	"github.com/bufbuild/buf/internal/pkg/storage/internal"
 )
 
-// errNotDir is the error returned if a path does not dir.
-var errNotDir = errors.New("not a directory")
+var (
+	// errNotDir is the error returned if a path does not dir.
+	errNotDir = errors.New("not a directory")
+	// errSymlinkCycle is the error returned if a symlink resolves to a directory that is already being walked.
+	errSymlinkCycle = errors.New("symlink cycle detected")
+)
 
 type bucket struct {
-	rootPath string
+	rootPath       string
+	followSymlinks bool
 }
 
-func newBucket(rootPath string) (*bucket, error) {
+func newBucket(rootPath string, options ...ReadWriteBucketOption) (*bucket, error) {
 	rootPath = normalpath.Unnormalize(rootPath)
 	fileInfo, err := os.Stat(rootPath)
 	if err != nil {
@@ -50,9 +56,13 @@ func newBucket(rootPath string) (*bucket, error) {
 	// do not validate - allow anything with OS buckets including
 	// absolute paths and jumping context
 	rootPath = normalpath.Normalize(rootPath)
-	return &bucket{
+	bucket := &bucket{
 		rootPath: rootPath,
-	}, nil
+	}
+	for _, option := range options {
+		option(bucket)
+	}
+	return bucket, nil
 }
 
 func (b *bucket) Get(ctx context.Context, path string) (storage.ReadObjectCloser, error) {
@@ -96,6 +106,13 @@ func (b *bucket) Walk(
 		return err
 	}
 	walkChecker := internal.NewWalkChecker()
+	if b.followSymlinks {
+		fileInfo, err := os.Stat(externalPrefix)
+		if err != nil {
+			return err
+		}
+		return b.walkFollowSymlinks(ctx, walkChecker, externalPrefix, fileInfo, make(map[string]struct{}), f)
+	}
 	// Walk does not follow symlinks
 	return filepath.Walk(
 		externalPrefix,
@@ -107,34 +124,94 @@ func (b *bucket) Walk(
 				return err
 			}
 			if fileInfo.Mode().IsRegular() {
-				size, err := getFileInfoSize(fileInfo)
-				if err != nil {
-					return err
-				}
-				path, err := normalpath.Rel(b.rootPath, normalpath.Normalize(externalPath))
-				if err != nil {
-					return err
-				}
-				// just in case
-				path, err = normalpath.NormalizeAndValidate(path)
-				if err != nil {
-					return err
-				}
-				if err := f(
-					internal.NewObjectInfo(
-						size,
-						path,
-						externalPath,
-					),
-				); err != nil {
-					return err
-				}
+				return b.walkRegularFile(externalPath, fileInfo, f)
 			}
 			return nil
 		},
 	)
 }
 
+// walkFollowSymlinks walks the file or directory at externalPath, following symlinks.
+//
+// realDirPaths contains the real paths of all directories currently being walked,
+// and is used to detect symlink cycles.
+func (b *bucket) walkFollowSymlinks(
+	ctx context.Context,
+	walkChecker internal.WalkChecker,
+	externalPath string,
+	fileInfo os.FileInfo,
+	realDirPaths map[string]struct{},
+	f func(storage.ObjectInfo) error,
+) error {
+	if err := walkChecker.Check(ctx); err != nil {
+		return err
+	}
+	if fileInfo.Mode().IsRegular() {
+		return b.walkRegularFile(externalPath, fileInfo, f)
+	}
+	if !fileInfo.IsDir() {
+		return nil
+	}
+	realDirPath, err := filepath.EvalSymlinks(externalPath)
+	if err != nil {
+		return err
+	}
+	if _, ok := realDirPaths[realDirPath]; ok {
+		return newErrSymlinkCycle(externalPath)
+	}
+	realDirPaths[realDirPath] = struct{}{}
+	defer delete(realDirPaths, realDirPath)
+	// ReadDir sorts by name, matching filepath.Walk
+	childFileInfos, err := ioutil.ReadDir(externalPath)
+	if err != nil {
+		return err
+	}
+	for _, childFileInfo := range childFileInfos {
+		childExternalPath := filepath.Join(externalPath, childFileInfo.Name())
+		if childFileInfo.Mode()&os.ModeSymlink != 0 {
+			childFileInfo, err = os.Stat(childExternalPath)
+			if err != nil {
+				if os.IsNotExist(err) {
+					// skip dangling symlinks, as filepath.Walk would
+					continue
+				}
+				return err
+			}
+		}
+		if err := b.walkFollowSymlinks(ctx, walkChecker, childExternalPath, childFileInfo, realDirPaths, f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *bucket) walkRegularFile(
+	externalPath string,
+	fileInfo os.FileInfo,
+	f func(storage.ObjectInfo) error,
+) error {
+	size, err := getFileInfoSize(fileInfo)
+	if err != nil {
+		return err
+	}
+	path, err := normalpath.Rel(b.rootPath, normalpath.Normalize(externalPath))
+	if err != nil {
+		return err
+	}
+	// just in case
+	path, err = normalpath.NormalizeAndValidate(path)
+	if err != nil {
+		return err
+	}
+	return f(
+		internal.NewObjectInfo(
+			size,
+			path,
+			externalPath,
+		),
+	)
+}
+
 func (b *bucket) Put(ctx context.Context, path string, size uint32) (storage.WriteObjectCloser, error) {
 	externalPath, err := b.getExternalPath(path)
 	if err != nil {
@@ -298,6 +375,11 @@ func newErrNotDir(path string) *normalpath.Error {
 	return normalpath.NewError(path, errNotDir)
 }
 
+// newErrSymlinkCycle returns a new Error for a path that is part of a symlink cycle.
+func newErrSymlinkCycle(path string) *normalpath.Error {
+	return normalpath.NewError(path, errSymlinkCycle)
+}
+
 func toStorageError(err error) error {
 	if err == os.ErrClosed {
 		return storage.ErrClosed
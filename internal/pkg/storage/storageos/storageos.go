@@ -29,6 +29,22 @@ import (
 // can be absolute or jump context.
 //
 // Not thread-safe.
-func NewReadWriteBucket(rootPath string) (storage.ReadWriteBucket, error) {
-	return newBucket(rootPath)
+func NewReadWriteBucket(rootPath string, options ...ReadWriteBucketOption) (storage.ReadWriteBucket, error) {
+	return newBucket(rootPath, options...)
+}
+
+// ReadWriteBucketOption is an option for a new ReadWriteBucket.
+type ReadWriteBucketOption func(*bucket)
+
+// ReadWriteBucketWithFollowSymlinks results in Walk following symlinks.
+//
+// Symlinks to regular files are treated as regular files, and symlinks
+// to directories are walked. If a symlink resolves to a directory that is
+// already being walked, that is there is a symlink cycle, Walk returns an error.
+//
+// The default is to not follow symlinks.
+func ReadWriteBucketWithFollowSymlinks() ReadWriteBucketOption {
+	return func(bucket *bucket) {
+		bucket.followSymlinks = true
+	}
 }